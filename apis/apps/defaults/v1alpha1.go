@@ -219,6 +219,15 @@ func SetDefaultsUnitedDeployment(obj *v1alpha1.UnitedDeployment, injectTemplateD
 		obj.Spec.UpdateStrategy.ManualUpdate = &v1alpha1.ManualUpdate{}
 	}
 
+	if obj.Spec.UpdateStrategy.Type == v1alpha1.SubsetRollingUpdateStrategyType {
+		if obj.Spec.UpdateStrategy.SubsetRollingUpdate == nil {
+			obj.Spec.UpdateStrategy.SubsetRollingUpdate = &v1alpha1.SubsetRollingUpdate{}
+		}
+		if obj.Spec.UpdateStrategy.SubsetRollingUpdate.ProgressDeadlineSeconds == nil {
+			obj.Spec.UpdateStrategy.SubsetRollingUpdate.ProgressDeadlineSeconds = ptr.To(int32(600))
+		}
+	}
+
 	if obj.Spec.Template.StatefulSetTemplate != nil {
 		if injectTemplateDefaults {
 			SetDefaultPodSpec(&obj.Spec.Template.StatefulSetTemplate.Spec.Template.Spec)
@@ -101,6 +101,8 @@ type InPlaceUpdateContainerBatch struct {
 // to determine whether the InPlaceUpdate is completed.
 type InPlaceUpdateContainerStatus struct {
 	ImageID string `json:"imageID,omitempty"`
+	// RestartCount is the restart count of the container before in-place update.
+	RestartCount int32 `json:"restartCount,omitempty"`
 }
 
 // InPlaceUpdateStrategy defines the strategies for in-place update.
@@ -19,6 +19,7 @@ package v1alpha1
 import (
 	appspub "github.com/openkruise/kruise/apis/apps/pub"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
@@ -47,8 +48,11 @@ type SidecarSetSpec struct {
 	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
 
 	// InitContainers is the list of init containers to be injected into the selected pod
-	// We will inject those containers by their name in ascending order
+	// We will inject those containers by their name in ascending order, after the init containers of the pod by default,
+	// or before them if the podInjectPolicy is BeforeAppContainer.
 	// We only inject init containers when a new pod is created, it does not apply to any existing pod
+	// The init containers with restartPolicy Always are k8s native sidecar containers, their images can be updated
+	// in-place like the containers, if the pod doesn't run them as native sidecars, the pod is marked not upgradable.
 	// +patchMergeKey=name
 	// +patchStrategy=merge
 	InitContainers []SidecarContainer `json:"initContainers,omitempty" patchStrategy:"merge" patchMergeKey:"name"`
@@ -82,6 +86,29 @@ type SidecarSetSpec struct {
 
 	// SidecarSet support to inject & in-place update metadata in pod.
 	PatchPodMetadata []SidecarSetPatchPodMetadata `json:"patchPodMetadata,omitempty"`
+
+	// Priority is used to resolve the conflicts with other SidecarSets when injecting into the same pod,
+	// e.g. containers or volumes with the same name, or the same pod annotations with different values.
+	// The SidecarSet with the higher priority is injected and the conflicting ones with lower priority are skipped
+	// for the pod, a SidecarSet without priority is regarded as priority 0. If the conflicting SidecarSets have the
+	// same priority and at least one of them sets the priority, the pod creation is rejected.
+	// The conflicts between SidecarSets without priority are only reported, and both are injected as before.
+	// +optional
+	Priority *int32 `json:"priority,omitempty"`
+
+	// StatusBreakdown configures the breakdown of the matched pods reported in SidecarSet status.
+	// +optional
+	StatusBreakdown *SidecarSetStatusBreakdown `json:"statusBreakdown,omitempty"`
+}
+
+// SidecarSetStatusBreakdown configures the breakdown of the matched pods in SidecarSet status.
+type SidecarSetStatusBreakdown struct {
+	// NamespaceTopN, if greater than 0, reports at most N namespaces that have the most pods
+	// not updated to the latest revision in status.namespaceStatuses.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	NamespaceTopN int32 `json:"namespaceTopN,omitempty"`
 }
 
 type SidecarSetPatchPodMetadata struct {
@@ -121,8 +148,7 @@ type SidecarContainer struct {
 	// +kubebuilder:validation:Schemaless
 	corev1.Container `json:",inline"`
 
-	// The rules that injected SidecarContainer into Pod.spec.containers,
-	// not takes effect in initContainers
+	// The rules that injected SidecarContainer into Pod.spec.containers or Pod.spec.initContainers
 	// If BeforeAppContainer, the SidecarContainer will be injected in front of the pod.spec.containers
 	// otherwise it will be injected into the back.
 	// default BeforeAppContainerType
@@ -143,6 +169,63 @@ type SidecarContainer struct {
 	// TransferEnv will transfer env info from other container
 	// SourceContainerName is pod.spec.container[x].name; EnvName is pod.spec.container[x].Env.name
 	TransferEnv []TransferEnvVar `json:"transferEnv,omitempty"`
+
+	// ResourcePolicy calculates the resources of the sidecar container from the containers of the pod
+	// when it is injected, the calculated requests and limits override the ones in the sidecar container.
+	// +optional
+	ResourcePolicy *SidecarResourcePolicy `json:"resourcePolicy,omitempty"`
+
+	// PodMetadataTemplate enables the templates of pod metadata in the env values, args of the sidecar container
+	// and the sources of volumes it mounts, which are rendered when the sidecar container is injected into the pod.
+	// The template is a downward API like field path in double braces, supports `{{ metadata.namespace }}`,
+	// `{{ metadata.labels['<KEY>'] }}` and `{{ metadata.annotations['<KEY>'] }}`, and the key that doesn't exist in the pod
+	// is rendered to empty. `{{ metadata.name }}` is not supported, for it is empty in the pod created with generateName.
+	// +optional
+	PodMetadataTemplate bool `json:"podMetadataTemplate,omitempty"`
+}
+
+// SidecarResourcePolicy defines how to derive the resources of the sidecar container from the pod.
+type SidecarResourcePolicy struct {
+	// TargetContainerNames are the names of pod containers that the resources are derived from,
+	// default is all the containers in pod.spec.containers except the injected sidecar containers.
+	// +optional
+	TargetContainerNames []string `json:"targetContainerNames,omitempty"`
+
+	// TargetContainerMode defines how to aggregate the resources of the target containers, Sum or Max.
+	// default is Sum.
+	// +optional
+	TargetContainerMode SidecarResourceTargetContainerMode `json:"targetContainerMode,omitempty"`
+
+	// Requests describes how to calculate the requests of the sidecar container, resource name -> rule.
+	// +optional
+	Requests map[corev1.ResourceName]SidecarResourceRule `json:"requests,omitempty"`
+
+	// Limits describes how to calculate the limits of the sidecar container, resource name -> rule.
+	// +optional
+	Limits map[corev1.ResourceName]SidecarResourceRule `json:"limits,omitempty"`
+}
+
+type SidecarResourceTargetContainerMode string
+
+const (
+	SidecarResourceTargetContainerModeSum SidecarResourceTargetContainerMode = "Sum"
+	SidecarResourceTargetContainerModeMax SidecarResourceTargetContainerMode = "Max"
+)
+
+// SidecarResourceRule calculates a resource quantity of the sidecar container as a percentage of the target containers.
+type SidecarResourceRule struct {
+	// Percent of the aggregated quantity of the target containers, e.g. 10 means 10%.
+	// +kubebuilder:validation:Minimum=0
+	Percent int32 `json:"percent"`
+
+	// Min is the lower bound of the calculated quantity, it is also used when none of the target containers
+	// sets the resource.
+	// +optional
+	Min *resource.Quantity `json:"min,omitempty"`
+
+	// Max is the upper bound of the calculated quantity.
+	// +optional
+	Max *resource.Quantity `json:"max,omitempty"`
 }
 
 type ShareVolumePolicy struct {
@@ -195,6 +278,30 @@ type SidecarContainerUpgradeStrategy struct {
 	// HotUpgradeEmptyImage is consistent of sidecar container in Command, Args, Liveness probe, etc.
 	// but it does no actual work.
 	HotUpgradeEmptyImage string `json:"hotUpgradeEmptyImage,omitempty"`
+
+	// HotUpgradeHandoffHook is called on the old sidecar container when HotUpgrade, after the new sidecar container started,
+	// to hand off the listeners to the new one. The old one is reset to HotUpgradeEmptyImage after the new one is ready.
+	// It only works with the two hot upgrade containers ({name}-1 and {name}-2) swapped with HotUpgradeEmptyImage,
+	// and the hook is called asynchronously, whose progress is recorded in pod annotation
+	// kruise.io/sidecarset-hotupgrade-handoff-container.
+	// If not set, the sidecar containers should hand off by themselves, e.g. envoy hot restart.
+	HotUpgradeHandoffHook *SidecarHandoffHook `json:"hotUpgradeHandoffHook,omitempty"`
+}
+
+// SidecarHandoffHook defines the action to hand off the listeners of the old sidecar container in hot upgrade.
+// One and only one of the fields must be specified.
+type SidecarHandoffHook struct {
+	// Exec specifies the command executed in the old sidecar container.
+	// It requires the feature-gate SidecarSetHandoffHookExec, with which kruise-manager is granted to create pods/exec.
+	Exec *corev1.ExecAction `json:"exec,omitempty"`
+
+	// HTTPGet specifies the http request to the old sidecar container.
+	// The request is always sent to the pod IP, so the host must not be set.
+	HTTPGet *corev1.HTTPGetAction `json:"httpGet,omitempty"`
+
+	// Number of seconds after which the hook times out, default is 30.
+	// +kubebuilder:validation:Minimum=0
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
 }
 
 // SidecarSetInjectionStrategy indicates the injection strategy of SidecarSet.
@@ -285,6 +392,81 @@ type SidecarSetUpdateStrategy struct {
 	// - Note that pods will be scattered after priority sort. So, although priority strategy and scatter strategy can be applied together, we suggest to use either one of them.
 	// - If scatterStrategy is used, we suggest to just use one term. Otherwise, the update order can be hard to understand.
 	ScatterStrategy UpdateScatterStrategy `json:"scatterStrategy,omitempty"`
+
+	// Waves are the ordered rollout waves of the update. If waves is not empty, the injected pods
+	// will be updated wave by wave, and the next wave will not be started until the pods of current wave,
+	// except the partition of the wave, are all updated and ready.
+	// - A pod belongs to the first wave it matches, and pods not matching any wave will not be updated.
+	// - Partition of updateStrategy does not take effect when waves are set, use the partition of each wave instead.
+	// +optional
+	Waves []SidecarSetUpdateWave `json:"waves,omitempty"`
+
+	// FailurePolicy describes how to detect the failure of updated pods and what to do when the update failed.
+	// If it is nil, the SidecarSet will not check whether the updated pods failed.
+	// +optional
+	FailurePolicy *SidecarSetUpdateFailurePolicy `json:"failurePolicy,omitempty"`
+}
+
+// SidecarSetUpdateFailurePolicy describes how to handle the failure of updated pods.
+// When the number of failed pods reaches FailureThreshold, the SidecarSet will be marked with condition Failed,
+// and stops updating the remaining pods until the SidecarSet is changed to a new revision.
+type SidecarSetUpdateFailurePolicy struct {
+	// FailureThreshold is the number of failed pods to stop the update.
+	// Value can be an absolute number (ex: 5) or a percentage of matched pods (ex: 10%).
+	// Absolute number is calculated from percentage by rounding up.
+	// Default value is 1.
+	// +optional
+	FailureThreshold *intstr.IntOrString `json:"failureThreshold,omitempty"`
+
+	// RestartThreshold is the number of restarts of an updated sidecar container, excluding the restart caused by
+	// the in-place update itself, to regard the pod as failed.
+	// Default value is 3.
+	// +optional
+	RestartThreshold *int32 `json:"restartThreshold,omitempty"`
+
+	// NotReadySeconds is the duration after the sidecar in-place update, for which an updated pod that is still not ready
+	// will be regarded as failed.
+	// Default value is 300.
+	// +optional
+	NotReadySeconds *int32 `json:"notReadySeconds,omitempty"`
+
+	// Rollback indicates whether to revert the failed pods to the sidecar revision they were running
+	// before the update, once the update failed.
+	// +optional
+	Rollback bool `json:"rollback,omitempty"`
+}
+
+// SidecarSetUpdateWave defines a group of pods that will be updated together.
+type SidecarSetUpdateWave struct {
+	// Name is the unique name of the wave in the SidecarSet.
+	Name string `json:"name"`
+
+	// NamespaceSelector selects the namespaces of pods in this wave.
+	// If it is nil, pods in all namespaces are matched.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// Workloads select pods by their owner workload, a pod is matched if any of them matches.
+	// If it is empty, pods of all workloads are matched.
+	// +optional
+	Workloads []SidecarSetWaveWorkload `json:"workloads,omitempty"`
+
+	// Partition is the desired number of pods in old revisions in this wave.
+	// Value can be an absolute number (ex: 5) or a percentage of pods in this wave (ex: 10%).
+	// Default value is 0.
+	// +optional
+	Partition *intstr.IntOrString `json:"partition,omitempty"`
+}
+
+// SidecarSetWaveWorkload indicates the owner workload of pods.
+type SidecarSetWaveWorkload struct {
+	// Kind of the workload, e.g. Deployment, CloneSet, StatefulSet.
+	// Pods of Deployment are matched by the Deployment owning their ReplicaSet.
+	Kind string `json:"kind"`
+
+	// Name of the workload, if it is empty, all workloads of the kind are matched.
+	// +optional
+	Name string `json:"name,omitempty"`
 }
 
 type SidecarSetUpdateStrategyType string
@@ -319,6 +501,104 @@ type SidecarSetStatus struct {
 	// uses this field as a collision avoidance mechanism when it needs to create the name for the
 	// newest ControllerRevision.
 	CollisionCount *int32 `json:"collisionCount,omitempty"`
+
+	// CurrentWave is the name of the wave in updating, it is empty if waves are not set or all waves have finished.
+	CurrentWave string `json:"currentWave,omitempty"`
+
+	// WaveStatuses are the status of each wave defined in updateStrategy.waves.
+	WaveStatuses []SidecarSetWaveStatus `json:"waveStatuses,omitempty"`
+
+	// FailedPods is the number of updated pods that are regarded as failed according to updateStrategy.failurePolicy.
+	FailedPods int32 `json:"failedPods,omitempty"`
+
+	// Conditions represents the latest available observations of a SidecarSet's current state.
+	// +optional
+	Conditions []SidecarSetCondition `json:"conditions,omitempty"`
+
+	// ConflictingSidecarSets are the names of other SidecarSets that conflict with this SidecarSet
+	// when injecting into the matched pods.
+	// +optional
+	ConflictingSidecarSets []string `json:"conflictingSidecarSets,omitempty"`
+
+	// RevisionStatuses are the number of matched pods injected with each revision of the SidecarSet,
+	// the latest revision is always the first one even if no pod is injected with it, and at most 10 revisions
+	// with the most pods are reported.
+	// +optional
+	RevisionStatuses []SidecarSetRevisionStatus `json:"revisionStatuses,omitempty"`
+
+	// NamespaceStatuses are the status of the namespaces that have the most pods not updated to the latest revision,
+	// which is reported only if spec.statusBreakdown.namespaceTopN is set.
+	// +optional
+	NamespaceStatuses []SidecarSetNamespaceStatus `json:"namespaceStatuses,omitempty"`
+}
+
+// SidecarSetRevisionStatus defines the number of pods injected with a revision of SidecarSet.
+type SidecarSetRevisionStatus struct {
+	// Revision is the hash of SidecarSet revision injected in pods.
+	Revision string `json:"revision"`
+
+	// ControllerRevision is the name of the controllerRevision, it may be empty for the pods injected by older versions.
+	ControllerRevision string `json:"controllerRevision,omitempty"`
+
+	// matchedPods is the number of matched pods injected with this revision
+	MatchedPods int32 `json:"matchedPods"`
+
+	// readyPods is the number of matched pods injected with this revision and ready
+	ReadyPods int32 `json:"readyPods"`
+}
+
+// SidecarSetNamespaceStatus defines the observed state of the pods of SidecarSet in a namespace.
+type SidecarSetNamespaceStatus struct {
+	// Namespace of the pods.
+	Namespace string `json:"namespace"`
+
+	// matchedPods is the number of matched pods in this namespace
+	MatchedPods int32 `json:"matchedPods"`
+
+	// updatedPods is the number of pods in this namespace that are injected with the latest SidecarSet's containers
+	UpdatedPods int32 `json:"updatedPods"`
+}
+
+// SidecarSetConditionType indicates valid conditions type of a SidecarSet.
+type SidecarSetConditionType string
+
+const (
+	// SidecarSetConditionFailed means the update of the latest revision failed, the SidecarSet will stop updating pods
+	// until it is changed to a new revision.
+	SidecarSetConditionFailed SidecarSetConditionType = "Failed"
+)
+
+// SidecarSetCondition describes the state of a SidecarSet at a certain point.
+type SidecarSetCondition struct {
+	// Type of SidecarSet condition.
+	Type SidecarSetConditionType `json:"type,omitempty"`
+
+	// Status of the condition, one of True, False, Unknown.
+	Status corev1.ConditionStatus `json:"status,omitempty"`
+
+	// Last time the condition transitioned from one status to another.
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+
+	// The reason for the condition's last transition.
+	Reason string `json:"reason,omitempty"`
+
+	// A human-readable message indicating details about the transition.
+	Message string `json:"message,omitempty"`
+}
+
+// SidecarSetWaveStatus defines the observed state of a wave of SidecarSet.
+type SidecarSetWaveStatus struct {
+	// Name of the wave.
+	Name string `json:"name"`
+
+	// matchedPods is the number of matched pods in this wave
+	MatchedPods int32 `json:"matchedPods"`
+
+	// updatedPods is the number of pods in this wave that are injected with the latest SidecarSet's containers
+	UpdatedPods int32 `json:"updatedPods"`
+
+	// updatedReadyPods is the number of pods in this wave that updated and ready
+	UpdatedReadyPods int32 `json:"updatedReadyPods"`
 }
 
 // +genclient
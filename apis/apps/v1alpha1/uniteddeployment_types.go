@@ -36,6 +36,10 @@ const (
 	// The update progress is able to be controlled by updating the partitions
 	// of each subset.
 	ManualUpdateStrategyType UpdateStrategyType = "Manual"
+	// SubsetRollingUpdateStrategyType indicates that the subsets are updated one by one
+	// in a declared order. A subset starts updating only after all the previous subsets
+	// are fully updated and available.
+	SubsetRollingUpdateStrategyType UpdateStrategyType = "SubsetRolling"
 )
 
 // UnitedDeploymentConditionType indicates valid conditions type of a UnitedDeployment.
@@ -50,6 +54,9 @@ const (
 	SubsetFailure UnitedDeploymentConditionType = "SubsetFailure"
 	// UnitedDeploymentUpdated means currentRevision is equal to updatedRevision.
 	UnitedDeploymentUpdated UnitedDeploymentConditionType = "UnitedDeploymentUpdated"
+	// SubsetRollingPaused is added to a UnitedDeployment when a SubsetRolling update stops progressing
+	// because the updating subset has failed or has not become available within the progress deadline.
+	SubsetRollingPaused UnitedDeploymentConditionType = "SubsetRollingPaused"
 )
 
 // UnitedDeploymentSpec defines the desired state of UnitedDeployment.
@@ -100,6 +107,27 @@ type SubsetTemplate struct {
 	// Deployment template
 	// +optional
 	DeploymentTemplate *DeploymentTemplateSpec `json:"deploymentTemplate,omitempty"`
+
+	// CustomWorkload template, the kind of which must be configured in the UnitedDeployment custom workload
+	// whitelist of kruise-configuration.
+	// +optional
+	CustomWorkloadTemplate *CustomWorkloadTemplateSpec `json:"customWorkloadTemplate,omitempty"`
+}
+
+// CustomWorkloadTemplateSpec defines the subset template of custom workload.
+type CustomWorkloadTemplateSpec struct {
+	// APIVersion of the custom workload, such as argoproj.io/v1alpha1.
+	APIVersion string `json:"apiVersion"`
+	// Kind of the custom workload, such as Rollout.
+	Kind string `json:"kind"`
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Spec of the custom workload. The replicas, selector and pod template in it are set by controller
+	// according to the field paths configured in the whitelist.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	Spec runtime.RawExtension `json:"spec"`
 }
 
 // StatefulSetTemplateSpec defines the subset template of StatefulSet.
@@ -148,6 +176,9 @@ type UnitedDeploymentUpdateStrategy struct {
 	// Includes all of the parameters a Manual update strategy needs.
 	// +optional
 	ManualUpdate *ManualUpdate `json:"manualUpdate,omitempty"`
+	// Includes all of the parameters a SubsetRolling update strategy needs.
+	// +optional
+	SubsetRollingUpdate *SubsetRollingUpdate `json:"subsetRollingUpdate,omitempty"`
 }
 
 // ManualUpdate is a update strategy which allows users to control the update progress
@@ -158,6 +189,31 @@ type ManualUpdate struct {
 	Partitions map[string]int32 `json:"partitions,omitempty"`
 }
 
+// SubsetRollingUpdate is an update strategy which updates the subsets one after another.
+// The subsets waiting for their turn are held at the old revision by their partitions,
+// so it only takes effect on workloads supporting partition.
+type SubsetRollingUpdate struct {
+	// Order of the subsets to update. Subsets not listed are updated after the listed ones
+	// in the order of Topology.Subsets. Defaults to the order of Topology.Subsets.
+	// +optional
+	Order []string `json:"order,omitempty"`
+	// SoakSeconds is the time to wait after a subset is fully updated and available
+	// before starting to update the next one. Defaults to 0.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	SoakSeconds int32 `json:"soakSeconds,omitempty"`
+	// ProgressDeadlineSeconds is the maximum time for a subset to be fully updated and available.
+	// If it is exceeded, or the subset reports a failure, the SubsetRollingPaused condition is added
+	// and the update does not proceed until the subset recovers. Defaults to 600.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty"`
+	// Paused indicates that the update should not start on the next subset.
+	// The subset being updated is not affected.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+}
+
 // Topology defines the spread detail of each subset under UnitedDeployment.
 // A UnitedDeployment manages multiple homogeneous workloads which are called subset.
 // Each of subsets under the UnitedDeployment is described in Topology.
@@ -172,6 +228,46 @@ type Topology struct {
 	// ScheduleStrategy indicates the strategy the UnitedDeployment used to preform the schedule between each of subsets.
 	// +optional
 	ScheduleStrategy UnitedDeploymentScheduleStrategy `json:"scheduleStrategy,omitempty"`
+
+	// SubsetDiscovery makes the controller generate one subset for each distinct value of a node label,
+	// and add or remove subsets as the values appear on or disappear from nodes.
+	// It can not be used together with Subsets.
+	// +optional
+	SubsetDiscovery *SubsetDiscovery `json:"subsetDiscovery,omitempty"`
+}
+
+// SubsetDiscovery defines how subsets are generated from the topology of nodes.
+// Each generated subset is named after the label value, selects nodes with that value,
+// and gets an even share of the replicas.
+// Unschedulable or not ready nodes still count, and a subset whose value is gone from the nodes
+// is kept until all its pods are gone.
+type SubsetDiscovery struct {
+	// TopologyKey is the node label key whose values form the subsets, e.g. topology.kubernetes.io/zone.
+	TopologyKey string `json:"topologyKey"`
+
+	// NodeSelector restricts the nodes taken into account. Defaults to all nodes.
+	// +optional
+	NodeSelector *metav1.LabelSelector `json:"nodeSelector,omitempty"`
+
+	// Indicates the tolerations the pods under all generated subsets have.
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Patch applied to all generated subsets, the same as the patch of a declared subset.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	// +optional
+	Patch runtime.RawExtension `json:"patch,omitempty"`
+}
+
+// IsWeighted returns true if the replicas are distributed to the subsets by weights.
+func (t *Topology) IsWeighted() bool {
+	for i := range t.Subsets {
+		if t.Subsets[i].Weight != nil {
+			return true
+		}
+	}
+	return false
 }
 
 // Subset defines the detail of a subset.
@@ -216,6 +312,20 @@ type Subset struct {
 	// +optional
 	MaxReplicas *intstr.IntOrString `json:"maxReplicas,omitempty"`
 
+	// Indicates the weight of the subset. If weights are set, the replicas of UnitedDeployment are
+	// distributed to the subsets in proportion to their weights, within the bounds of MinReplicas/MaxReplicas.
+	// With the Adaptive schedule strategy, a subset which has unschedulable pods is limited to its schedulable
+	// replicas and the rest are distributed to other subsets by weights, until the subset recovers.
+	// Weight and Replicas are mutually exclusive, and weights must be set for all subsets or none of them.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Weight *int32 `json:"weight,omitempty"`
+
+	// Overrides indicates the typed changes to the template of this subset.
+	// Overrides are applied after NodeSelectorTerm and Tolerations, and before Patch.
+	// +optional
+	Overrides *SubsetOverrides `json:"overrides,omitempty"`
+
 	// Patch indicates patching to the templateSpec.
 	// Patch takes precedence over other fields
 	// If the Patch also modifies the Replicas, NodeSelectorTerm or Tolerations, use value in the Patch
@@ -225,6 +335,60 @@ type Subset struct {
 	Patch runtime.RawExtension `json:"patch,omitempty"`
 }
 
+// SubsetOverrides defines the typed changes to the template of a subset.
+type SubsetOverrides struct {
+	// Containers overrides the containers with the same names in the pod template.
+	// +optional
+	Containers []ContainerOverride `json:"containers,omitempty"`
+
+	// NodeAffinity is merged into the node affinity of the pod template.
+	// Its required terms are combined with the existing ones, so that both of them must be satisfied,
+	// and its preferred terms are appended.
+	// +optional
+	NodeAffinity *corev1.NodeAffinity `json:"nodeAffinity,omitempty"`
+
+	// Tolerations are appended to the tolerations of the pod template.
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// VolumeClaimTemplates overrides the volume claim templates with the same names.
+	// Only workloads with volume claim templates are supported.
+	// +optional
+	VolumeClaimTemplates []VolumeClaimTemplateOverride `json:"volumeClaimTemplates,omitempty"`
+}
+
+// ContainerOverride defines the changes to a container of the pod template.
+type ContainerOverride struct {
+	// Name of the container to override, which must exist in the pod template.
+	Name string `json:"name"`
+
+	// Image replaces the image of the container.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Resources are merged into the resources of the container by resource name.
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// Env is merged into the env of the container by name.
+	// +optional
+	Env []corev1.EnvVar `json:"env,omitempty"`
+}
+
+// VolumeClaimTemplateOverride defines the changes to a volume claim template.
+type VolumeClaimTemplateOverride struct {
+	// Name of the volume claim template to override.
+	Name string `json:"name"`
+
+	// StorageClassName replaces the storage class of the volume claim template.
+	// +optional
+	StorageClassName *string `json:"storageClassName,omitempty"`
+
+	// Resources are merged into the resources of the volume claim template by resource name.
+	// +optional
+	Resources *corev1.VolumeResourceRequirements `json:"resources,omitempty"`
+}
+
 // UnitedDeploymentScheduleStrategyType is a string enumeration type that enumerates
 // all possible schedule strategies for the UnitedDeployment controller.
 // +kubebuilder:validation:Enum=Adaptive;Fixed;""
@@ -275,6 +439,93 @@ type UnitedDeploymentScheduleStrategy struct {
 	// Adaptive is used to communicate parameters when Type is AdaptiveUnitedDeploymentScheduleStrategyType.
 	// +optional
 	Adaptive *AdaptiveUnitedDeploymentStrategy `json:"adaptive,omitempty"`
+
+	// Failover indicates that the replicas of an unhealthy subset are temporarily shifted to the healthy subsets.
+	// +optional
+	Failover *SubsetFailoverStrategy `json:"failover,omitempty"`
+}
+
+const (
+	DefaultFailoverUnhealthyPercent = 50
+	DefaultFailoverUnhealthySeconds = 300
+	DefaultFailoverRecoveryPercent  = 90
+	DefaultFailoverRecoverySeconds  = 600
+	DefaultFailoverMaxSurge         = "25%"
+)
+
+// SubsetFailoverStrategy defines when a subset is failed over and failed back.
+// A subset is failed over once its ready replicas stay below UnhealthyPercent of its replicas for UnhealthySeconds,
+// and extra replicas, at most MaxSurge in total, are created in the healthy subsets to replace the unready ones.
+// Pods of the failed-over subset are kept, so the total replicas may exceed spec.replicas by MaxSurge during failover.
+// The subset is failed back once its ready replicas stay at or above RecoveryPercent for RecoverySeconds,
+// and the extra replicas are removed.
+type SubsetFailoverStrategy struct {
+	// UnhealthyPercent is the percentage of ready replicas below which a subset is considered unhealthy.
+	// Defaults to 50.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	UnhealthyPercent *int32 `json:"unhealthyPercent,omitempty"`
+
+	// UnhealthySeconds is how long a subset has to stay unhealthy before it is failed over. Defaults to 300.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	UnhealthySeconds *int32 `json:"unhealthySeconds,omitempty"`
+
+	// RecoveryPercent is the percentage of ready replicas at or above which a failed-over subset is considered
+	// recovered. It must not be less than UnhealthyPercent. Defaults to 90.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	RecoveryPercent *int32 `json:"recoveryPercent,omitempty"`
+
+	// RecoverySeconds is how long a failed-over subset has to stay recovered before it is failed back. Defaults to 600.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	RecoverySeconds *int32 `json:"recoverySeconds,omitempty"`
+
+	// MaxSurge is the maximum number of extra replicas that can be created in the healthy subsets
+	// above spec.replicas while subsets are failed over.
+	// Value can be an absolute number (ex: 5) or a percentage of spec.replicas (ex: 10%).
+	// Absolute number is calculated from percentage by rounding up. Defaults to 25%.
+	// +optional
+	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"`
+}
+
+func (s *SubsetFailoverStrategy) GetUnhealthyPercent() int32 {
+	if s.UnhealthyPercent == nil {
+		return DefaultFailoverUnhealthyPercent
+	}
+	return *s.UnhealthyPercent
+}
+
+func (s *SubsetFailoverStrategy) GetUnhealthyDuration() time.Duration {
+	if s.UnhealthySeconds == nil {
+		return DefaultFailoverUnhealthySeconds * time.Second
+	}
+	return time.Duration(*s.UnhealthySeconds) * time.Second
+}
+
+func (s *SubsetFailoverStrategy) GetRecoveryPercent() int32 {
+	if s.RecoveryPercent == nil {
+		return DefaultFailoverRecoveryPercent
+	}
+	return *s.RecoveryPercent
+}
+
+func (s *SubsetFailoverStrategy) GetMaxSurge() *intstr.IntOrString {
+	if s.MaxSurge == nil {
+		maxSurge := intstr.FromString(DefaultFailoverMaxSurge)
+		return &maxSurge
+	}
+	return s.MaxSurge
+}
+
+func (s *SubsetFailoverStrategy) GetRecoveryDuration() time.Duration {
+	if s.RecoverySeconds == nil {
+		return DefaultFailoverRecoverySeconds * time.Second
+	}
+	return time.Duration(*s.RecoverySeconds) * time.Second
 }
 
 func (s *UnitedDeploymentScheduleStrategy) IsAdaptive() bool {
@@ -348,6 +599,18 @@ type UnitedDeploymentStatus struct {
 
 	// LabelSelector is label selectors for query over pods that should match the replica count used by HPA.
 	LabelSelector string `json:"labelSelector,omitempty"`
+
+	// Records the subsets generated by SubsetDiscovery.
+	// +optional
+	DiscoveredSubsets []DiscoveredSubset `json:"discoveredSubsets,omitempty"`
+}
+
+// DiscoveredSubset records a subset generated by SubsetDiscovery.
+type DiscoveredSubset struct {
+	// Name of the generated subset.
+	Name string `json:"name"`
+	// The value of the topology key selected by the subset.
+	TopologyValue string `json:"topologyValue"`
 }
 
 func (s *UnitedDeploymentStatus) GetSubsetStatus(subset string) *UnitedDeploymentSubsetStatus {
@@ -386,6 +649,29 @@ type UpdateStatus struct {
 	// Records the current partition.
 	// +optional
 	CurrentPartitions map[string]int32 `json:"currentPartitions,omitempty"`
+
+	// Records the progress of the SubsetRolling update strategy.
+	// +optional
+	SubsetRolling *SubsetRollingStatus `json:"subsetRolling,omitempty"`
+}
+
+// SubsetRollingStatus defines the observed progress of the SubsetRolling update strategy.
+type SubsetRollingStatus struct {
+	// The subsets which have been fully updated and available, in the update order.
+	// +optional
+	UpdatedSubsets []string `json:"updatedSubsets,omitempty"`
+
+	// The subset being updated.
+	// +optional
+	CurrentSubset string `json:"currentSubset,omitempty"`
+
+	// The time when the current subset started updating.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// The time when the current subset became fully updated and available.
+	// +optional
+	AvailableTime *metav1.Time `json:"availableTime,omitempty"`
 }
 
 type UnitedDeploymentSubsetStatus struct {
@@ -399,6 +685,9 @@ type UnitedDeploymentSubsetStatus struct {
 	Partition int32 `json:"partition,omitempty"`
 	// Records the reserved pods in the subset.
 	ReservedPods int32 `json:"reservedPods,omitempty"`
+	// Records the hash of the effective pod template of the subset, with overrides and patch applied.
+	// +optional
+	TemplateHash string `json:"templateHash,omitempty"`
 	// Conditions is an array of current observed subset conditions.
 	Conditions []UnitedDeploymentSubsetCondition `json:"conditions,omitempty"`
 }
@@ -438,6 +727,10 @@ type UnitedDeploymentSubsetConditionType string
 const (
 	// UnitedDeploymentSubsetSchedulable means new pods allocated into the subset will keep pending.
 	UnitedDeploymentSubsetSchedulable UnitedDeploymentSubsetConditionType = "Schedulable"
+	// UnitedDeploymentSubsetHealthy indicates the health of the subset in failover strategy.
+	// It is Unknown when the subset has become unhealthy or recovered but the duration has not been reached yet,
+	// and False when the subset has been failed over.
+	UnitedDeploymentSubsetHealthy UnitedDeploymentSubsetConditionType = "Healthy"
 )
 
 type UnitedDeploymentSubsetCondition struct {
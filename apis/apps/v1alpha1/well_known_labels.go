@@ -20,6 +20,10 @@ const (
 	ImagePreDownloadIgnoredKey = "apps.kruise.io/image-predownload-ignored"
 	// AnnotationSubsetPatchKey indicates the patch for every subset
 	AnnotationSubsetPatchKey = "apps.kruise.io/subset-patch"
+	// AnnotationSubsetOverridesKey indicates the typed overrides for every subset
+	AnnotationSubsetOverridesKey = "apps.kruise.io/subset-overrides"
+	// AnnotationSubsetTemplateHashKey indicates the hash of the effective pod template of every subset
+	AnnotationSubsetTemplateHashKey = "apps.kruise.io/subset-template-hash"
 )
 
 // Sidecar container environment variable definitions which are used to enable SidecarTerminator to take effect on the sidecar container.
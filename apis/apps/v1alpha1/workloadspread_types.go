@@ -17,6 +17,8 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"time"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -128,6 +130,29 @@ type WorkloadSpreadScheduleStrategy struct {
 	// Adaptive is used to communicate parameters when Type is AdaptiveWorkloadSpreadScheduleStrategyType.
 	// +optional
 	Adaptive *AdaptiveWorkloadSpreadStrategy `json:"adaptive,omitempty"`
+
+	// Rebalance enables controller to evict the existing pods in over-populated subsets gradually, so that
+	// the pods converge to the distribution of subsets after they are changed. Pods are evicted through the
+	// eviction API, which means PodDisruptionBudget and PodUnavailableBudget are honored.
+	// Only supported for Deployment, ReplicaSet and CloneSet.
+	// +optional
+	Rebalance *WorkloadSpreadRebalanceStrategy `json:"rebalance,omitempty"`
+}
+
+// WorkloadSpreadRebalanceStrategy defines how controller evicts the pods in over-populated subsets.
+type WorkloadSpreadRebalanceStrategy struct {
+	// MaxEvictionsPerInterval is the max number of pods evicted in each interval.
+	// Defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxEvictionsPerInterval *int32 `json:"maxEvictionsPerInterval,omitempty"`
+
+	// IntervalSeconds is the min duration between two batches of evictions. Controller also waits for
+	// the evicted pods to be recreated before the next batch.
+	// Defaults to 60.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	IntervalSeconds *int32 `json:"intervalSeconds,omitempty"`
 }
 
 // AdaptiveWorkloadSpreadStrategy is used to communicate parameters when Type is AdaptiveWorkloadSpreadScheduleStrategyType.
@@ -144,6 +169,68 @@ type AdaptiveWorkloadSpreadStrategy struct {
 	// over RescheduleCriticalSeconds duration, the controller will reschedule it to a suitable subset.
 	// +optional
 	RescheduleCriticalSeconds *int32 `json:"rescheduleCriticalSeconds,omitempty"`
+
+	// MigrateBack enables controller to migrate pods back from the latter subsets to the former subsets gradually,
+	// when the former subsets regain capacity, e.g. after pods were rescheduled from spot nodes to on-demand nodes
+	// and the spot nodes come back. Pods are evicted through the eviction API and recreated by the workload in the
+	// former subsets. Pods are only migrated to a former subset when its Ready and schedulable nodes have enough
+	// allocatable resources for them. If a migrated pod fails to be scheduled, the subset is marked unschedulable
+	// again and the next migration is backed off exponentially. RescheduleCriticalSeconds must be set, and only
+	// Deployment, ReplicaSet and CloneSet are supported.
+	// +optional
+	MigrateBack *WorkloadSpreadMigrateBackStrategy `json:"migrateBack,omitempty"`
+}
+
+// WorkloadSpreadMigrateBackStrategy defines how controller migrates pods back to the former subsets.
+type WorkloadSpreadMigrateBackStrategy struct {
+	// MaxMigrationsPerInterval is the max number of pods migrated in each interval.
+	// Defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxMigrationsPerInterval *int32 `json:"maxMigrationsPerInterval,omitempty"`
+
+	// IntervalSeconds is the minimum duration between two migrations. It is doubled for each consecutive failed
+	// migration, up to 32 times.
+	// Defaults to 300.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	IntervalSeconds *int32 `json:"intervalSeconds,omitempty"`
+}
+
+const (
+	DefaultRebalanceMaxEvictionsPerInterval = 1
+	DefaultRebalanceIntervalSeconds         = 60
+
+	DefaultMigrateBackMaxMigrationsPerInterval = 1
+	DefaultMigrateBackIntervalSeconds          = 300
+)
+
+func (s *WorkloadSpreadRebalanceStrategy) GetMaxEvictionsPerInterval() int32 {
+	if s.MaxEvictionsPerInterval == nil {
+		return DefaultRebalanceMaxEvictionsPerInterval
+	}
+	return *s.MaxEvictionsPerInterval
+}
+
+func (s *WorkloadSpreadRebalanceStrategy) GetInterval() time.Duration {
+	if s.IntervalSeconds == nil {
+		return DefaultRebalanceIntervalSeconds * time.Second
+	}
+	return time.Duration(*s.IntervalSeconds) * time.Second
+}
+
+func (s *WorkloadSpreadMigrateBackStrategy) GetMaxMigrationsPerInterval() int32 {
+	if s.MaxMigrationsPerInterval == nil {
+		return DefaultMigrateBackMaxMigrationsPerInterval
+	}
+	return *s.MaxMigrationsPerInterval
+}
+
+func (s *WorkloadSpreadMigrateBackStrategy) GetInterval() time.Duration {
+	if s.IntervalSeconds == nil {
+		return DefaultMigrateBackIntervalSeconds * time.Second
+	}
+	return time.Duration(*s.IntervalSeconds) * time.Second
 }
 
 // WorkloadSpreadSubset defines the details of a subset.
@@ -167,6 +254,14 @@ type WorkloadSpreadSubset struct {
 	// +optional
 	MaxReplicas *intstr.IntOrString `json:"maxReplicas,omitempty"`
 
+	// Weight indicates the relative proportion of pods in this subset. If weights are set, each new pod is
+	// assigned to the subset which is most below its weighted share of the workload replicas, and pods are
+	// deleted in a way that keeps the proportions when scaling down.
+	// Weight and MaxReplicas are mutually exclusive, and weights must be set for all subsets or none of them.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Weight *int32 `json:"weight,omitempty"`
+
 	// Patch indicates patching podTemplate to the Pod.
 	// +optional
 	// +kubebuilder:pruning:PreserveUnknownFields
@@ -174,6 +269,16 @@ type WorkloadSpreadSubset struct {
 	Patch runtime.RawExtension `json:"patch,omitempty"`
 }
 
+// IsWeighted returns true if the pods are spread to the subsets by weights.
+func (s *WorkloadSpreadSpec) IsWeighted() bool {
+	for i := range s.Subsets {
+		if s.Subsets[i].Weight != nil {
+			return true
+		}
+	}
+	return false
+}
+
 // WorkloadSpreadStatus defines the observed state of WorkloadSpread.
 type WorkloadSpreadStatus struct {
 	// ObservedGeneration is the most recent generation observed for this WorkloadSpread. It corresponds to the
@@ -192,6 +297,52 @@ type WorkloadSpreadStatus struct {
 	// may be earlier than deletion of old-version pod. We have to calculate the pod subset distribution for
 	// each version.
 	VersionedSubsetStatuses map[string][]WorkloadSpreadSubsetStatus `json:"versionedSubsetStatuses,omitempty"`
+
+	// Rebalance contains the progress of rebalancing existing pods, only if rebalance strategy is set.
+	// +optional
+	Rebalance *WorkloadSpreadRebalanceStatus `json:"rebalance,omitempty"`
+
+	// MigrateBack contains the progress of migrating pods back to the former subsets, only if migrateBack strategy is set.
+	// +optional
+	MigrateBack *WorkloadSpreadMigrateBackStatus `json:"migrateBack,omitempty"`
+}
+
+// WorkloadSpreadRebalanceStatus defines the progress of rebalancing existing pods.
+type WorkloadSpreadRebalanceStatus struct {
+	// ExcessReplicas is the number of pods in over-populated subsets, or in no subset, that remain to be evicted.
+	ExcessReplicas int32 `json:"excessReplicas"`
+
+	// EvictedReplicas is the number of pods that controller has requested to evict in the current or last rebalance.
+	EvictedReplicas int32 `json:"evictedReplicas"`
+
+	// LastEvictionTime is the last time controller requested to evict pods.
+	// +optional
+	LastEvictionTime *metav1.Time `json:"lastEvictionTime,omitempty"`
+
+	// Message describes why rebalancing is blocked, if it is.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// WorkloadSpreadMigrateBackStatus defines the progress of migrating pods back to the former subsets.
+type WorkloadSpreadMigrateBackStatus struct {
+	// MigratableReplicas is the number of pods in the latter subsets that can be migrated to the former subsets.
+	MigratableReplicas int32 `json:"migratableReplicas"`
+
+	// MigratedReplicas is the number of pods that controller has requested to evict in the current or last migration.
+	MigratedReplicas int32 `json:"migratedReplicas"`
+
+	// LastMigrationTime is the last time controller requested to evict pods for migration.
+	// +optional
+	LastMigrationTime *metav1.Time `json:"lastMigrationTime,omitempty"`
+
+	// FailedMigrations is the number of consecutive migrations after which a subset became unschedulable again.
+	// +optional
+	FailedMigrations int32 `json:"failedMigrations,omitempty"`
+
+	// LastFailureTime is the last time a subset became unschedulable after a migration.
+	// +optional
+	LastFailureTime *metav1.Time `json:"lastFailureTime,omitempty"`
 }
 
 type WorkloadSpreadSubsetConditionType string
@@ -63,6 +63,11 @@ func (in *AdaptiveWorkloadSpreadStrategy) DeepCopyInto(out *AdaptiveWorkloadSpre
 		*out = new(int32)
 		**out = **in
 	}
+	if in.MigrateBack != nil {
+		in, out := &in.MigrateBack, &out.MigrateBack
+		*out = new(WorkloadSpreadMigrateBackStrategy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdaptiveWorkloadSpreadStrategy.
@@ -604,6 +609,33 @@ func (in *CompletionPolicy) DeepCopy() *CompletionPolicy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerOverride) DeepCopyInto(out *ContainerOverride) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerOverride.
+func (in *ContainerOverride) DeepCopy() *ContainerOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ContainerProbe) DeepCopyInto(out *ContainerProbe) {
 	*out = *in
@@ -898,6 +930,23 @@ func (in *CronJobTemplate) DeepCopy() *CronJobTemplate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CustomWorkloadTemplateSpec) DeepCopyInto(out *CustomWorkloadTemplateSpec) {
+	*out = *in
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomWorkloadTemplateSpec.
+func (in *CustomWorkloadTemplateSpec) DeepCopy() *CustomWorkloadTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CustomWorkloadTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DaemonSet) DeepCopyInto(out *DaemonSet) {
 	*out = *in
@@ -1058,6 +1107,21 @@ func (in *DeploymentTemplateSpec) DeepCopy() *DeploymentTemplateSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiscoveredSubset) DeepCopyInto(out *DiscoveredSubset) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiscoveredSubset.
+func (in *DiscoveredSubset) DeepCopy() *DiscoveredSubset {
+	if in == nil {
+		return nil
+	}
+	out := new(DiscoveredSubset)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EphemeralContainerTemplateSpec) DeepCopyInto(out *EphemeralContainerTemplateSpec) {
 	*out = *in
@@ -2709,7 +2773,7 @@ func (in *ShareVolumePolicy) DeepCopy() *ShareVolumePolicy {
 func (in *SidecarContainer) DeepCopyInto(out *SidecarContainer) {
 	*out = *in
 	in.Container.DeepCopyInto(&out.Container)
-	out.UpgradeStrategy = in.UpgradeStrategy
+	in.UpgradeStrategy.DeepCopyInto(&out.UpgradeStrategy)
 	out.ShareVolumePolicy = in.ShareVolumePolicy
 	if in.ShareVolumeDevicePolicy != nil {
 		in, out := &in.ShareVolumeDevicePolicy, &out.ShareVolumeDevicePolicy
@@ -2723,6 +2787,11 @@ func (in *SidecarContainer) DeepCopyInto(out *SidecarContainer) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ResourcePolicy != nil {
+		in, out := &in.ResourcePolicy, &out.ResourcePolicy
+		*out = new(SidecarResourcePolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarContainer.
@@ -2738,6 +2807,11 @@ func (in *SidecarContainer) DeepCopy() *SidecarContainer {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SidecarContainerUpgradeStrategy) DeepCopyInto(out *SidecarContainerUpgradeStrategy) {
 	*out = *in
+	if in.HotUpgradeHandoffHook != nil {
+		in, out := &in.HotUpgradeHandoffHook, &out.HotUpgradeHandoffHook
+		*out = new(SidecarHandoffHook)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarContainerUpgradeStrategy.
@@ -2750,6 +2824,90 @@ func (in *SidecarContainerUpgradeStrategy) DeepCopy() *SidecarContainerUpgradeSt
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SidecarHandoffHook) DeepCopyInto(out *SidecarHandoffHook) {
+	*out = *in
+	if in.Exec != nil {
+		in, out := &in.Exec, &out.Exec
+		*out = new(corev1.ExecAction)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HTTPGet != nil {
+		in, out := &in.HTTPGet, &out.HTTPGet
+		*out = new(corev1.HTTPGetAction)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarHandoffHook.
+func (in *SidecarHandoffHook) DeepCopy() *SidecarHandoffHook {
+	if in == nil {
+		return nil
+	}
+	out := new(SidecarHandoffHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SidecarResourcePolicy) DeepCopyInto(out *SidecarResourcePolicy) {
+	*out = *in
+	if in.TargetContainerNames != nil {
+		in, out := &in.TargetContainerNames, &out.TargetContainerNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Requests != nil {
+		in, out := &in.Requests, &out.Requests
+		*out = make(map[corev1.ResourceName]SidecarResourceRule, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.Limits != nil {
+		in, out := &in.Limits, &out.Limits
+		*out = make(map[corev1.ResourceName]SidecarResourceRule, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarResourcePolicy.
+func (in *SidecarResourcePolicy) DeepCopy() *SidecarResourcePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(SidecarResourcePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SidecarResourceRule) DeepCopyInto(out *SidecarResourceRule) {
+	*out = *in
+	if in.Min != nil {
+		in, out := &in.Min, &out.Min
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Max != nil {
+		in, out := &in.Max, &out.Max
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarResourceRule.
+func (in *SidecarResourceRule) DeepCopy() *SidecarResourceRule {
+	if in == nil {
+		return nil
+	}
+	out := new(SidecarResourceRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SidecarSet) DeepCopyInto(out *SidecarSet) {
 	*out = *in
@@ -2777,6 +2935,22 @@ func (in *SidecarSet) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SidecarSetCondition) DeepCopyInto(out *SidecarSetCondition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarSetCondition.
+func (in *SidecarSetCondition) DeepCopy() *SidecarSetCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(SidecarSetCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SidecarSetInjectRevision) DeepCopyInto(out *SidecarSetInjectRevision) {
 	*out = *in
@@ -2854,6 +3028,21 @@ func (in *SidecarSetList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SidecarSetNamespaceStatus) DeepCopyInto(out *SidecarSetNamespaceStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarSetNamespaceStatus.
+func (in *SidecarSetNamespaceStatus) DeepCopy() *SidecarSetNamespaceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SidecarSetNamespaceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SidecarSetPatchPodMetadata) DeepCopyInto(out *SidecarSetPatchPodMetadata) {
 	*out = *in
@@ -2876,6 +3065,21 @@ func (in *SidecarSetPatchPodMetadata) DeepCopy() *SidecarSetPatchPodMetadata {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SidecarSetRevisionStatus) DeepCopyInto(out *SidecarSetRevisionStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarSetRevisionStatus.
+func (in *SidecarSetRevisionStatus) DeepCopy() *SidecarSetRevisionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SidecarSetRevisionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SidecarSetSpec) DeepCopyInto(out *SidecarSetSpec) {
 	*out = *in
@@ -2929,6 +3133,16 @@ func (in *SidecarSetSpec) DeepCopyInto(out *SidecarSetSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Priority != nil {
+		in, out := &in.Priority, &out.Priority
+		*out = new(int32)
+		**out = **in
+	}
+	if in.StatusBreakdown != nil {
+		in, out := &in.StatusBreakdown, &out.StatusBreakdown
+		*out = new(SidecarSetStatusBreakdown)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarSetSpec.
@@ -2949,6 +3163,33 @@ func (in *SidecarSetStatus) DeepCopyInto(out *SidecarSetStatus) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.WaveStatuses != nil {
+		in, out := &in.WaveStatuses, &out.WaveStatuses
+		*out = make([]SidecarSetWaveStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]SidecarSetCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ConflictingSidecarSets != nil {
+		in, out := &in.ConflictingSidecarSets, &out.ConflictingSidecarSets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RevisionStatuses != nil {
+		in, out := &in.RevisionStatuses, &out.RevisionStatuses
+		*out = make([]SidecarSetRevisionStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.NamespaceStatuses != nil {
+		in, out := &in.NamespaceStatuses, &out.NamespaceStatuses
+		*out = make([]SidecarSetNamespaceStatus, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarSetStatus.
@@ -2961,6 +3202,51 @@ func (in *SidecarSetStatus) DeepCopy() *SidecarSetStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SidecarSetStatusBreakdown) DeepCopyInto(out *SidecarSetStatusBreakdown) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarSetStatusBreakdown.
+func (in *SidecarSetStatusBreakdown) DeepCopy() *SidecarSetStatusBreakdown {
+	if in == nil {
+		return nil
+	}
+	out := new(SidecarSetStatusBreakdown)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SidecarSetUpdateFailurePolicy) DeepCopyInto(out *SidecarSetUpdateFailurePolicy) {
+	*out = *in
+	if in.FailureThreshold != nil {
+		in, out := &in.FailureThreshold, &out.FailureThreshold
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.RestartThreshold != nil {
+		in, out := &in.RestartThreshold, &out.RestartThreshold
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NotReadySeconds != nil {
+		in, out := &in.NotReadySeconds, &out.NotReadySeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarSetUpdateFailurePolicy.
+func (in *SidecarSetUpdateFailurePolicy) DeepCopy() *SidecarSetUpdateFailurePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(SidecarSetUpdateFailurePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SidecarSetUpdateStrategy) DeepCopyInto(out *SidecarSetUpdateStrategy) {
 	*out = *in
@@ -2989,6 +3275,18 @@ func (in *SidecarSetUpdateStrategy) DeepCopyInto(out *SidecarSetUpdateStrategy)
 		*out = make(UpdateScatterStrategy, len(*in))
 		copy(*out, *in)
 	}
+	if in.Waves != nil {
+		in, out := &in.Waves, &out.Waves
+		*out = make([]SidecarSetUpdateWave, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.FailurePolicy != nil {
+		in, out := &in.FailurePolicy, &out.FailurePolicy
+		*out = new(SidecarSetUpdateFailurePolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarSetUpdateStrategy.
@@ -3001,6 +3299,66 @@ func (in *SidecarSetUpdateStrategy) DeepCopy() *SidecarSetUpdateStrategy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SidecarSetUpdateWave) DeepCopyInto(out *SidecarSetUpdateWave) {
+	*out = *in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Workloads != nil {
+		in, out := &in.Workloads, &out.Workloads
+		*out = make([]SidecarSetWaveWorkload, len(*in))
+		copy(*out, *in)
+	}
+	if in.Partition != nil {
+		in, out := &in.Partition, &out.Partition
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarSetUpdateWave.
+func (in *SidecarSetUpdateWave) DeepCopy() *SidecarSetUpdateWave {
+	if in == nil {
+		return nil
+	}
+	out := new(SidecarSetUpdateWave)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SidecarSetWaveStatus) DeepCopyInto(out *SidecarSetWaveStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarSetWaveStatus.
+func (in *SidecarSetWaveStatus) DeepCopy() *SidecarSetWaveStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SidecarSetWaveStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SidecarSetWaveWorkload) DeepCopyInto(out *SidecarSetWaveWorkload) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarSetWaveWorkload.
+func (in *SidecarSetWaveWorkload) DeepCopy() *SidecarSetWaveWorkload {
+	if in == nil {
+		return nil
+	}
+	out := new(SidecarSetWaveWorkload)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SourceContainerNameSource) DeepCopyInto(out *SourceContainerNameSource) {
 	*out = *in
@@ -3209,6 +3567,16 @@ func (in *Subset) DeepCopyInto(out *Subset) {
 		*out = new(intstr.IntOrString)
 		**out = **in
 	}
+	if in.Weight != nil {
+		in, out := &in.Weight, &out.Weight
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = new(SubsetOverrides)
+		(*in).DeepCopyInto(*out)
+	}
 	in.Patch.DeepCopyInto(&out.Patch)
 }
 
@@ -3222,6 +3590,168 @@ func (in *Subset) DeepCopy() *Subset {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubsetDiscovery) DeepCopyInto(out *SubsetDiscovery) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Patch.DeepCopyInto(&out.Patch)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubsetDiscovery.
+func (in *SubsetDiscovery) DeepCopy() *SubsetDiscovery {
+	if in == nil {
+		return nil
+	}
+	out := new(SubsetDiscovery)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubsetFailoverStrategy) DeepCopyInto(out *SubsetFailoverStrategy) {
+	*out = *in
+	if in.UnhealthyPercent != nil {
+		in, out := &in.UnhealthyPercent, &out.UnhealthyPercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.UnhealthySeconds != nil {
+		in, out := &in.UnhealthySeconds, &out.UnhealthySeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RecoveryPercent != nil {
+		in, out := &in.RecoveryPercent, &out.RecoveryPercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RecoverySeconds != nil {
+		in, out := &in.RecoverySeconds, &out.RecoverySeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxSurge != nil {
+		in, out := &in.MaxSurge, &out.MaxSurge
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubsetFailoverStrategy.
+func (in *SubsetFailoverStrategy) DeepCopy() *SubsetFailoverStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(SubsetFailoverStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubsetOverrides) DeepCopyInto(out *SubsetOverrides) {
+	*out = *in
+	if in.Containers != nil {
+		in, out := &in.Containers, &out.Containers
+		*out = make([]ContainerOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NodeAffinity != nil {
+		in, out := &in.NodeAffinity, &out.NodeAffinity
+		*out = new(corev1.NodeAffinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VolumeClaimTemplates != nil {
+		in, out := &in.VolumeClaimTemplates, &out.VolumeClaimTemplates
+		*out = make([]VolumeClaimTemplateOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubsetOverrides.
+func (in *SubsetOverrides) DeepCopy() *SubsetOverrides {
+	if in == nil {
+		return nil
+	}
+	out := new(SubsetOverrides)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubsetRollingStatus) DeepCopyInto(out *SubsetRollingStatus) {
+	*out = *in
+	if in.UpdatedSubsets != nil {
+		in, out := &in.UpdatedSubsets, &out.UpdatedSubsets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.AvailableTime != nil {
+		in, out := &in.AvailableTime, &out.AvailableTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubsetRollingStatus.
+func (in *SubsetRollingStatus) DeepCopy() *SubsetRollingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SubsetRollingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubsetRollingUpdate) DeepCopyInto(out *SubsetRollingUpdate) {
+	*out = *in
+	if in.Order != nil {
+		in, out := &in.Order, &out.Order
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ProgressDeadlineSeconds != nil {
+		in, out := &in.ProgressDeadlineSeconds, &out.ProgressDeadlineSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubsetRollingUpdate.
+func (in *SubsetRollingUpdate) DeepCopy() *SubsetRollingUpdate {
+	if in == nil {
+		return nil
+	}
+	out := new(SubsetRollingUpdate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SubsetTemplate) DeepCopyInto(out *SubsetTemplate) {
 	*out = *in
@@ -3245,6 +3775,11 @@ func (in *SubsetTemplate) DeepCopyInto(out *SubsetTemplate) {
 		*out = new(DeploymentTemplateSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.CustomWorkloadTemplate != nil {
+		in, out := &in.CustomWorkloadTemplate, &out.CustomWorkloadTemplate
+		*out = new(CustomWorkloadTemplateSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubsetTemplate.
@@ -3324,6 +3859,11 @@ func (in *Topology) DeepCopyInto(out *Topology) {
 		}
 	}
 	in.ScheduleStrategy.DeepCopyInto(&out.ScheduleStrategy)
+	if in.SubsetDiscovery != nil {
+		in, out := &in.SubsetDiscovery, &out.SubsetDiscovery
+		*out = new(SubsetDiscovery)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Topology.
@@ -3444,6 +3984,11 @@ func (in *UnitedDeploymentScheduleStrategy) DeepCopyInto(out *UnitedDeploymentSc
 		*out = new(AdaptiveUnitedDeploymentStrategy)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Failover != nil {
+		in, out := &in.Failover, &out.Failover
+		*out = new(SubsetFailoverStrategy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UnitedDeploymentScheduleStrategy.
@@ -3523,6 +4068,11 @@ func (in *UnitedDeploymentStatus) DeepCopyInto(out *UnitedDeploymentStatus) {
 		*out = new(UpdateStatus)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.DiscoveredSubsets != nil {
+		in, out := &in.DiscoveredSubsets, &out.DiscoveredSubsets
+		*out = make([]DiscoveredSubset, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UnitedDeploymentStatus.
@@ -3581,6 +4131,11 @@ func (in *UnitedDeploymentUpdateStrategy) DeepCopyInto(out *UnitedDeploymentUpda
 		*out = new(ManualUpdate)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.SubsetRollingUpdate != nil {
+		in, out := &in.SubsetRollingUpdate, &out.SubsetRollingUpdate
+		*out = new(SubsetRollingUpdate)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UnitedDeploymentUpdateStrategy.
@@ -3657,6 +4212,11 @@ func (in *UpdateStatus) DeepCopyInto(out *UpdateStatus) {
 			(*out)[key] = val
 		}
 	}
+	if in.SubsetRolling != nil {
+		in, out := &in.SubsetRolling, &out.SubsetRolling
+		*out = new(SubsetRollingStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpdateStatus.
@@ -3669,6 +4229,31 @@ func (in *UpdateStatus) DeepCopy() *UpdateStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeClaimTemplateOverride) DeepCopyInto(out *VolumeClaimTemplateOverride) {
+	*out = *in
+	if in.StorageClassName != nil {
+		in, out := &in.StorageClassName, &out.StorageClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.VolumeResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeClaimTemplateOverride.
+func (in *VolumeClaimTemplateOverride) DeepCopy() *VolumeClaimTemplateOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeClaimTemplateOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkloadSpread) DeepCopyInto(out *WorkloadSpread) {
 	*out = *in
@@ -3728,6 +4313,98 @@ func (in *WorkloadSpreadList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadSpreadMigrateBackStatus) DeepCopyInto(out *WorkloadSpreadMigrateBackStatus) {
+	*out = *in
+	if in.LastMigrationTime != nil {
+		in, out := &in.LastMigrationTime, &out.LastMigrationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastFailureTime != nil {
+		in, out := &in.LastFailureTime, &out.LastFailureTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadSpreadMigrateBackStatus.
+func (in *WorkloadSpreadMigrateBackStatus) DeepCopy() *WorkloadSpreadMigrateBackStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadSpreadMigrateBackStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadSpreadMigrateBackStrategy) DeepCopyInto(out *WorkloadSpreadMigrateBackStrategy) {
+	*out = *in
+	if in.MaxMigrationsPerInterval != nil {
+		in, out := &in.MaxMigrationsPerInterval, &out.MaxMigrationsPerInterval
+		*out = new(int32)
+		**out = **in
+	}
+	if in.IntervalSeconds != nil {
+		in, out := &in.IntervalSeconds, &out.IntervalSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadSpreadMigrateBackStrategy.
+func (in *WorkloadSpreadMigrateBackStrategy) DeepCopy() *WorkloadSpreadMigrateBackStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadSpreadMigrateBackStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadSpreadRebalanceStatus) DeepCopyInto(out *WorkloadSpreadRebalanceStatus) {
+	*out = *in
+	if in.LastEvictionTime != nil {
+		in, out := &in.LastEvictionTime, &out.LastEvictionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadSpreadRebalanceStatus.
+func (in *WorkloadSpreadRebalanceStatus) DeepCopy() *WorkloadSpreadRebalanceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadSpreadRebalanceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadSpreadRebalanceStrategy) DeepCopyInto(out *WorkloadSpreadRebalanceStrategy) {
+	*out = *in
+	if in.MaxEvictionsPerInterval != nil {
+		in, out := &in.MaxEvictionsPerInterval, &out.MaxEvictionsPerInterval
+		*out = new(int32)
+		**out = **in
+	}
+	if in.IntervalSeconds != nil {
+		in, out := &in.IntervalSeconds, &out.IntervalSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadSpreadRebalanceStrategy.
+func (in *WorkloadSpreadRebalanceStrategy) DeepCopy() *WorkloadSpreadRebalanceStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadSpreadRebalanceStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkloadSpreadScheduleStrategy) DeepCopyInto(out *WorkloadSpreadScheduleStrategy) {
 	*out = *in
@@ -3736,6 +4413,11 @@ func (in *WorkloadSpreadScheduleStrategy) DeepCopyInto(out *WorkloadSpreadSchedu
 		*out = new(AdaptiveWorkloadSpreadStrategy)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Rebalance != nil {
+		in, out := &in.Rebalance, &out.Rebalance
+		*out = new(WorkloadSpreadRebalanceStrategy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadSpreadScheduleStrategy.
@@ -3809,6 +4491,16 @@ func (in *WorkloadSpreadStatus) DeepCopyInto(out *WorkloadSpreadStatus) {
 			(*out)[key] = outVal
 		}
 	}
+	if in.Rebalance != nil {
+		in, out := &in.Rebalance, &out.Rebalance
+		*out = new(WorkloadSpreadRebalanceStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MigrateBack != nil {
+		in, out := &in.MigrateBack, &out.MigrateBack
+		*out = new(WorkloadSpreadMigrateBackStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadSpreadStatus.
@@ -3848,6 +4540,11 @@ func (in *WorkloadSpreadSubset) DeepCopyInto(out *WorkloadSpreadSubset) {
 		*out = new(intstr.IntOrString)
 		**out = **in
 	}
+	if in.Weight != nil {
+		in, out := &in.Weight, &out.Weight
+		*out = new(int32)
+		**out = **in
+	}
 	in.Patch.DeepCopyInto(&out.Patch)
 }
 
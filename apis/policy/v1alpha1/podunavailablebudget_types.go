@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
@@ -70,6 +71,81 @@ type PodUnavailableBudgetSpec struct {
 	// Delete pod, evict pod or update pod specification is allowed if at least "minAvailable" pods selected by
 	// "selector" or "targetRef" will still be available after the above operation for pod.
 	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+
+	// TopologyBudgets limit the unavailable pods in each topology domain, such as zone, in addition to
+	// the budget for all pods selected by "selector" or "targetRef". The operations on the pods in a topology domain
+	// are denied until its budget is calculated in status.
+	// +optional
+	TopologyBudgets []PodUnavailableBudgetTopologyBudget `json:"topologyBudgets,omitempty"`
+
+	// ScheduledBudgets override "maxUnavailable" or "minAvailable" in the time windows scheduled by cron,
+	// such as the nightly maintenance window. If several time windows are active at the same time,
+	// the first one in the list takes effect.
+	// +optional
+	ScheduledBudgets []PodUnavailableBudgetScheduledBudget `json:"scheduledBudgets,omitempty"`
+
+	// AvailabilityPolicy defines what an available pod is, such as the pod whose condition reported by
+	// PodProbeMarker is True. If it is not set, a pod is available when it is running and ready.
+	// +optional
+	AvailabilityPolicy *PodUnavailableBudgetAvailabilityPolicy `json:"availabilityPolicy,omitempty"`
+
+	// DryRun means the pub doesn't deny any pod operation, but records the operations that would have been denied
+	// in events, metrics and status, which helps to observe the effect of the pub before enforcing it.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// PodUnavailableBudgetAvailabilityPolicy defines the pod availability by pod conditions and labels instead of
+// the kubelet readiness. A pod is available when it is running, all the conditions are True and
+// the labels match the selector.
+type PodUnavailableBudgetAvailabilityPolicy struct {
+	// Conditions are the types of pod conditions that must all be True for an available pod.
+	// The Ready condition is not required unless it is contained.
+	// +optional
+	Conditions []corev1.PodConditionType `json:"conditions,omitempty"`
+
+	// LabelSelector must match the labels of an available pod.
+	// +optional
+	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
+}
+
+// PodUnavailableBudgetScheduledBudget is the budget that takes effect in a time window.
+type PodUnavailableBudgetScheduledBudget struct {
+	// Name is the unique name of the scheduled budget.
+	Name string `json:"name"`
+
+	// Schedule is the start time of the time window in Cron format, see https://en.wikipedia.org/wiki/Cron.
+	Schedule string `json:"schedule"`
+
+	// The time zone name for the given schedule, see https://en.wikipedia.org/wiki/List_of_tz_database_time_zones.
+	// If not specified, this will default to the time zone of the kruise-controller-manager process.
+	// +optional
+	TimeZone *string `json:"timeZone,omitempty"`
+
+	// Duration is the length of the time window, such as "2h".
+	Duration metav1.Duration `json:"duration"`
+
+	// MaxUnavailable overrides spec.maxUnavailable in the time window.
+	// MaxUnavailable and MinAvailable are mutually exclusive.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+
+	// MinAvailable overrides spec.minAvailable in the time window.
+	// +optional
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+}
+
+// PodUnavailableBudgetTopologyBudget defines the budget for the pods in each topology domain.
+type PodUnavailableBudgetTopologyBudget struct {
+	// TopologyKey is the key of node labels. Nodes that have a label with this key and identical values
+	// are considered to be in the same topology domain. Pods on the nodes without this label are not
+	// limited by this budget.
+	TopologyKey string `json:"topologyKey"`
+
+	// Delete pod, evict pod or update pod specification is allowed if at most "maxUnavailable" pods in the
+	// same topology domain are unavailable after the above operation for pod. The percentage is calculated
+	// from the number of pods in the topology domain.
+	MaxUnavailable intstr.IntOrString `json:"maxUnavailable"`
 }
 
 // TargetReference contains enough information to let you identify an workload for PodUnavailableBudget
@@ -110,6 +186,62 @@ type PodUnavailableBudgetStatus struct {
 
 	// TotalReplicas total number of pods counted by this unavailable budget
 	TotalReplicas int32 `json:"totalReplicas"`
+
+	// TopologyStatuses contains the status of each topology domain for topologyBudgets.
+	// +optional
+	TopologyStatuses []PodUnavailableBudgetTopologyStatus `json:"topologyStatuses,omitempty"`
+
+	// ActiveScheduledBudget is the name of the scheduled budget that currently takes effect,
+	// empty means "maxUnavailable" or "minAvailable" in spec takes effect.
+	// +optional
+	ActiveScheduledBudget string `json:"activeScheduledBudget,omitempty"`
+
+	// DryRunDeniedOperations counts the pod operations that would have been denied in dryRun mode,
+	// keyed by the operation type. The operations are aggregated and counted every few seconds, and the
+	// dry-run requests are not counted. It is cleared once dryRun is disabled.
+	// +optional
+	DryRunDeniedOperations map[PubOperation]int32 `json:"dryRunDeniedOperations,omitempty"`
+
+	// LastDryRunDeniedOperation is the latest pod operation that would have been denied in dryRun mode.
+	// +optional
+	LastDryRunDeniedOperation *PodUnavailableBudgetDeniedOperation `json:"lastDryRunDeniedOperation,omitempty"`
+}
+
+// PodUnavailableBudgetDeniedOperation is a pod operation denied by the pub.
+type PodUnavailableBudgetDeniedOperation struct {
+	// PodName is the name of the pod
+	PodName string `json:"podName"`
+	// Operation is the type of the operation, such as UPDATE, DELETE, EVICT and RESIZE
+	Operation PubOperation `json:"operation"`
+	// Username is the user who requested the operation
+	// +optional
+	Username string `json:"username,omitempty"`
+	// Reason is the reason why the operation is denied
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Time is the time when the operation is denied
+	Time metav1.Time `json:"time"`
+}
+
+// PodUnavailableBudgetTopologyStatus defines the observed state of the pods in a topology domain.
+type PodUnavailableBudgetTopologyStatus struct {
+	// TopologyKey is the topologyKey of the budget
+	TopologyKey string `json:"topologyKey"`
+
+	// Value is the value of topologyKey in node labels, which identifies the topology domain
+	Value string `json:"value"`
+
+	// TotalReplicas total number of active pods in the topology domain
+	TotalReplicas int32 `json:"totalReplicas"`
+
+	// CurrentAvailable current number of available pods in the topology domain
+	CurrentAvailable int32 `json:"currentAvailable"`
+
+	// Unavailable current number of unavailable pods in the topology domain
+	Unavailable int32 `json:"unavailable"`
+
+	// UnavailableAllowed number of pod unavailable that are currently allowed in the topology domain
+	UnavailableAllowed int32 `json:"unavailableAllowed"`
 }
 
 // +genclient
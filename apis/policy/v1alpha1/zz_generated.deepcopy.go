@@ -21,6 +21,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -53,6 +54,47 @@ func (in *PodUnavailableBudget) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodUnavailableBudgetAvailabilityPolicy) DeepCopyInto(out *PodUnavailableBudgetAvailabilityPolicy) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]corev1.PodConditionType, len(*in))
+		copy(*out, *in)
+	}
+	if in.LabelSelector != nil {
+		in, out := &in.LabelSelector, &out.LabelSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodUnavailableBudgetAvailabilityPolicy.
+func (in *PodUnavailableBudgetAvailabilityPolicy) DeepCopy() *PodUnavailableBudgetAvailabilityPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(PodUnavailableBudgetAvailabilityPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodUnavailableBudgetDeniedOperation) DeepCopyInto(out *PodUnavailableBudgetDeniedOperation) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodUnavailableBudgetDeniedOperation.
+func (in *PodUnavailableBudgetDeniedOperation) DeepCopy() *PodUnavailableBudgetDeniedOperation {
+	if in == nil {
+		return nil
+	}
+	out := new(PodUnavailableBudgetDeniedOperation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PodUnavailableBudgetList) DeepCopyInto(out *PodUnavailableBudgetList) {
 	*out = *in
@@ -85,6 +127,37 @@ func (in *PodUnavailableBudgetList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodUnavailableBudgetScheduledBudget) DeepCopyInto(out *PodUnavailableBudgetScheduledBudget) {
+	*out = *in
+	if in.TimeZone != nil {
+		in, out := &in.TimeZone, &out.TimeZone
+		*out = new(string)
+		**out = **in
+	}
+	out.Duration = in.Duration
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MinAvailable != nil {
+		in, out := &in.MinAvailable, &out.MinAvailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodUnavailableBudgetScheduledBudget.
+func (in *PodUnavailableBudgetScheduledBudget) DeepCopy() *PodUnavailableBudgetScheduledBudget {
+	if in == nil {
+		return nil
+	}
+	out := new(PodUnavailableBudgetScheduledBudget)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PodUnavailableBudgetSpec) DeepCopyInto(out *PodUnavailableBudgetSpec) {
 	*out = *in
@@ -108,6 +181,23 @@ func (in *PodUnavailableBudgetSpec) DeepCopyInto(out *PodUnavailableBudgetSpec)
 		*out = new(intstr.IntOrString)
 		**out = **in
 	}
+	if in.TopologyBudgets != nil {
+		in, out := &in.TopologyBudgets, &out.TopologyBudgets
+		*out = make([]PodUnavailableBudgetTopologyBudget, len(*in))
+		copy(*out, *in)
+	}
+	if in.ScheduledBudgets != nil {
+		in, out := &in.ScheduledBudgets, &out.ScheduledBudgets
+		*out = make([]PodUnavailableBudgetScheduledBudget, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AvailabilityPolicy != nil {
+		in, out := &in.AvailabilityPolicy, &out.AvailabilityPolicy
+		*out = new(PodUnavailableBudgetAvailabilityPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodUnavailableBudgetSpec.
@@ -137,6 +227,23 @@ func (in *PodUnavailableBudgetStatus) DeepCopyInto(out *PodUnavailableBudgetStat
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.TopologyStatuses != nil {
+		in, out := &in.TopologyStatuses, &out.TopologyStatuses
+		*out = make([]PodUnavailableBudgetTopologyStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.DryRunDeniedOperations != nil {
+		in, out := &in.DryRunDeniedOperations, &out.DryRunDeniedOperations
+		*out = make(map[PubOperation]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.LastDryRunDeniedOperation != nil {
+		in, out := &in.LastDryRunDeniedOperation, &out.LastDryRunDeniedOperation
+		*out = new(PodUnavailableBudgetDeniedOperation)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodUnavailableBudgetStatus.
@@ -149,6 +256,37 @@ func (in *PodUnavailableBudgetStatus) DeepCopy() *PodUnavailableBudgetStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodUnavailableBudgetTopologyBudget) DeepCopyInto(out *PodUnavailableBudgetTopologyBudget) {
+	*out = *in
+	out.MaxUnavailable = in.MaxUnavailable
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodUnavailableBudgetTopologyBudget.
+func (in *PodUnavailableBudgetTopologyBudget) DeepCopy() *PodUnavailableBudgetTopologyBudget {
+	if in == nil {
+		return nil
+	}
+	out := new(PodUnavailableBudgetTopologyBudget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodUnavailableBudgetTopologyStatus) DeepCopyInto(out *PodUnavailableBudgetTopologyStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodUnavailableBudgetTopologyStatus.
+func (in *PodUnavailableBudgetTopologyStatus) DeepCopy() *PodUnavailableBudgetTopologyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PodUnavailableBudgetTopologyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TargetReference) DeepCopyInto(out *TargetReference) {
 	*out = *in
@@ -33,8 +33,8 @@ var kclient client.Client
 type pubControl interface {
 	// IsPodReady indicates whether pod is fully ready
 	// 1. pod.Status.Phase == v1.PodRunning
-	// 2. pod.condition PodReady == true
-	IsPodReady(pod *corev1.Pod) bool
+	// 2. pod.condition PodReady == true, or the pod matches pub.spec.availabilityPolicy if it is set
+	IsPodReady(pod *corev1.Pod, pub *policyv1alpha1.PodUnavailableBudget) bool
 	// IsPodStateConsistent indicates whether pod.spec and pod.status are consistent after updating containers
 	IsPodStateConsistent(pod *corev1.Pod) bool
 	// GetPodsForPub returns Pods protected by the pub object.
@@ -44,8 +44,9 @@ type pubControl interface {
 	GetPodsForPub(pub *policyv1alpha1.PodUnavailableBudget) ([]*corev1.Pod, int32, error)
 
 	// webhook
-	// determine if this change to pod might cause unavailability
-	IsPodUnavailableChanged(oldPod, newPod *corev1.Pod) bool
+	// determine if this change to pod might cause unavailability,
+	// including the pod becoming unavailable by pub.spec.availabilityPolicy
+	IsPodUnavailableChanged(oldPod, newPod *corev1.Pod, pub *policyv1alpha1.PodUnavailableBudget) bool
 	// determine if this change can resize inplace
 	CanResizeInplace(oldPod, newPod *corev1.Pod) bool
 	// get pub for pod
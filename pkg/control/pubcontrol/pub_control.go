@@ -28,9 +28,11 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/rand"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
+	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
 	v1qos "k8s.io/kubernetes/pkg/apis/core/v1/helper/qos"
 	kubecontroller "k8s.io/kubernetes/pkg/controller"
 	"k8s.io/kubernetes/pkg/kubelet/types"
@@ -50,10 +52,14 @@ type commonControl struct {
 	controllerFinder *controllerfinder.ControllerFinder
 }
 
-func (c *commonControl) IsPodReady(pod *corev1.Pod) bool {
-	// 1. pod.Status.Phase == v1.PodRunning
-	// 2. pod.condition PodReady == true
-	if !util.IsRunningAndReady(pod) {
+func (c *commonControl) IsPodReady(pod *corev1.Pod, pub *policyv1alpha1.PodUnavailableBudget) bool {
+	if pub != nil && pub.Spec.AvailabilityPolicy != nil {
+		if !isPodAvailableByPolicy(pod, pub.Spec.AvailabilityPolicy) {
+			return false
+		}
+		// 1. pod.Status.Phase == v1.PodRunning
+		// 2. pod.condition PodReady == true
+	} else if !util.IsRunningAndReady(pod) {
 		return false
 	}
 
@@ -61,7 +67,27 @@ func (c *commonControl) IsPodReady(pod *corev1.Pod) bool {
 	return !appspub.HasUnavailableLabel(pod.Labels)
 }
 
-func (c *commonControl) IsPodUnavailableChanged(oldPod, newPod *corev1.Pod) bool {
+// isPodAvailableByPolicy indicates whether pod is running, the conditions are all True and the labels match the selector.
+func isPodAvailableByPolicy(pod *corev1.Pod, policy *policyv1alpha1.PodUnavailableBudgetAvailabilityPolicy) bool {
+	if pod.Status.Phase != corev1.PodRunning || !pod.DeletionTimestamp.IsZero() {
+		return false
+	}
+	for _, conditionType := range policy.Conditions {
+		_, condition := podutil.GetPodCondition(&pod.Status, conditionType)
+		if condition == nil || condition.Status != corev1.ConditionTrue {
+			return false
+		}
+	}
+	if policy.LabelSelector != nil {
+		selector, err := util.ValidatedLabelSelectorAsSelector(policy.LabelSelector)
+		if err != nil || !selector.Matches(labels.Set(pod.Labels)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *commonControl) IsPodUnavailableChanged(oldPod, newPod *corev1.Pod, pub *policyv1alpha1.PodUnavailableBudget) bool {
 	// If pod.spec changed, pod may be in unavailable condition
 	if !reflect.DeepEqual(oldPod.Spec, newPod.Spec) {
 		klog.V(3).InfoS("Pod specification changed, and maybe cause unavailability", "pod", klog.KObj(newPod))
@@ -72,6 +98,12 @@ func (c *commonControl) IsPodUnavailableChanged(oldPod, newPod *corev1.Pod) bool
 		klog.V(3).InfoS("Pod add unavailable label, and maybe cause unavailability", "pod", klog.KObj(newPod))
 		return true
 	}
+	// pod becomes unavailable by the availability policy, e.g. its labels no longer match the selector
+	if pub != nil && pub.Spec.AvailabilityPolicy != nil &&
+		isPodAvailableByPolicy(oldPod, pub.Spec.AvailabilityPolicy) && !isPodAvailableByPolicy(newPod, pub.Spec.AvailabilityPolicy) {
+		klog.V(3).InfoS("Pod becomes unavailable by availability policy, and maybe cause unavailability", "pod", klog.KObj(newPod), "pub", klog.KObj(pub))
+		return true
+	}
 	// pod other changes will not cause unavailability situation, then return false
 
 	klog.V(3).InfoS("Pod other changes, and maybe not cause unavailability", "pod", klog.KObj(newPod))
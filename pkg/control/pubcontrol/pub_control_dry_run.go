@@ -0,0 +1,109 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pubcontrol
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+
+	policyv1alpha1 "github.com/openkruise/kruise/apis/policy/v1alpha1"
+	kubeClient "github.com/openkruise/kruise/pkg/client"
+)
+
+// dryRunDeniedFlushInterval is how long the denied operations of a pub in dryRun mode are aggregated
+// before they are written into pub status.
+var dryRunDeniedFlushInterval = 10 * time.Second
+
+// dryRunDeniedRecords aggregates the pod operations denied by the pubs in dryRun mode.
+var dryRunDeniedRecords = &dryRunDeniedAggregator{pending: map[types.NamespacedName]*dryRunDenied{}}
+
+// dryRunDeniedAggregator aggregates the denied operations of each pub, and writes them into pub status at most once
+// per dryRunDeniedFlushInterval, so that the pub status is not updated on every pod operation.
+type dryRunDeniedAggregator struct {
+	mu      sync.Mutex
+	pending map[types.NamespacedName]*dryRunDenied
+}
+
+type dryRunDenied struct {
+	operations map[policyv1alpha1.PubOperation]int32
+	last       *policyv1alpha1.PodUnavailableBudgetDeniedOperation
+}
+
+// add records the denied operation of pub, and schedules to flush it if there is no pending record of pub.
+func (a *dryRunDeniedAggregator) add(pub *policyv1alpha1.PodUnavailableBudget, denied *policyv1alpha1.PodUnavailableBudgetDeniedOperation) {
+	key := types.NamespacedName{Namespace: pub.Namespace, Name: pub.Name}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	record, ok := a.pending[key]
+	if !ok {
+		record = &dryRunDenied{operations: map[policyv1alpha1.PubOperation]int32{}}
+		a.pending[key] = record
+		time.AfterFunc(dryRunDeniedFlushInterval, func() { a.flush(key) })
+	}
+	record.operations[denied.Operation]++
+	record.last = denied
+}
+
+// flush writes the pending denied operations of the pub into its status.
+func (a *dryRunDeniedAggregator) flush(key types.NamespacedName) {
+	a.mu.Lock()
+	record := a.pending[key]
+	delete(a.pending, key)
+	a.mu.Unlock()
+	if record == nil {
+		return
+	}
+
+	refresh := false
+	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		pub := &policyv1alpha1.PodUnavailableBudget{}
+		var err error
+		if refresh {
+			pub, err = kubeClient.GetGenericClient().KruiseClient.PolicyV1alpha1().
+				PodUnavailableBudgets(key.Namespace).Get(context.TODO(), key.Name, metav1.GetOptions{})
+		} else {
+			err = kclient.Get(context.TODO(), key, pub)
+		}
+		if err != nil {
+			return err
+		}
+		// the records are dropped if dryRun has been disabled
+		if !pub.Spec.DryRun {
+			return nil
+		}
+		if pub.Status.DryRunDeniedOperations == nil {
+			pub.Status.DryRunDeniedOperations = map[policyv1alpha1.PubOperation]int32{}
+		}
+		for operation, count := range record.operations {
+			pub.Status.DryRunDeniedOperations[operation] += count
+		}
+		pub.Status.LastDryRunDeniedOperation = record.last
+		err = kclient.Status().Update(context.TODO(), pub)
+		refresh = err != nil
+		return err
+	})
+	if err != nil && !errors.IsNotFound(err) {
+		klog.ErrorS(err, "Failed to record the operations denied by pub in dryRun mode", "pub", key)
+	}
+}
@@ -31,8 +31,19 @@ var (
 			// username = client useragent
 		}, []string{"kind_namespace_name", "username"},
 	)
+
+	PodUnavailableBudgetDryRunDeniedMetrics = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "pod_unavailable_budget_dry_run_denied",
+			Help: "Pod operations that would have been denied by Pod Unavailable Budget in dryRun mode",
+			// namespace_name = pub.namespace and pub.name
+			// operation = UPDATE, DELETE, EVICT or RESIZE
+			// username = the user who requested the operation
+		}, []string{"namespace_name", "operation", "username"},
+	)
 )
 
 func init() {
 	metrics.Registry.MustRegister(PodUnavailableBudgetMetrics)
+	metrics.Registry.MustRegister(PodUnavailableBudgetDryRunDeniedMetrics)
 }
@@ -22,6 +22,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/kubernetes/pkg/kubelet/types"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
@@ -411,6 +412,46 @@ func TestIsPodUnavailableChanged(t *testing.T) {
 			},
 			expect: true,
 		},
+		{
+			name: "labels no longer match availability policy",
+			getOldPod: func() *corev1.Pod {
+				demo := podDemo.DeepCopy()
+				demo.Labels["serving"] = "true"
+				return demo
+			},
+			getNewPod: func() *corev1.Pod {
+				demo := podDemo.DeepCopy()
+				return demo
+			},
+			getPub: func() *policyv1alpha1.PodUnavailableBudget {
+				pub := pubDemo.DeepCopy()
+				pub.Spec.AvailabilityPolicy = &policyv1alpha1.PodUnavailableBudgetAvailabilityPolicy{
+					LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"serving": "true"}},
+				}
+				return pub
+			},
+			expect: true,
+		},
+		{
+			name: "labels become matching availability policy",
+			getOldPod: func() *corev1.Pod {
+				demo := podDemo.DeepCopy()
+				return demo
+			},
+			getNewPod: func() *corev1.Pod {
+				demo := podDemo.DeepCopy()
+				demo.Labels["serving"] = "true"
+				return demo
+			},
+			getPub: func() *policyv1alpha1.PodUnavailableBudget {
+				pub := pubDemo.DeepCopy()
+				pub.Spec.AvailabilityPolicy = &policyv1alpha1.PodUnavailableBudgetAvailabilityPolicy{
+					LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"serving": "true"}},
+				}
+				return pub
+			},
+			expect: false,
+		},
 	}
 
 	for _, cs := range cases {
@@ -422,7 +463,7 @@ func TestIsPodUnavailableChanged(t *testing.T) {
 				Client:           fakeClient,
 				controllerFinder: finder,
 			}
-			is := control.IsPodUnavailableChanged(cs.getOldPod(), cs.getNewPod())
+			is := control.IsPodUnavailableChanged(cs.getOldPod(), cs.getNewPod(), cs.getPub())
 			if cs.expect != is {
 				t.Fatalf("IsPodUnavailableChanged failed")
 			}
@@ -532,9 +573,14 @@ func TestIsResourceChanged(t *testing.T) {
 }
 
 func TestIsPodReady(t *testing.T) {
+	availabilityPolicy := &policyv1alpha1.PodUnavailableBudgetAvailabilityPolicy{
+		Conditions:    []corev1.PodConditionType{"game.io/idle"},
+		LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"serving": "true"}},
+	}
 	cases := []struct {
 		name   string
 		getPod func() *corev1.Pod
+		getPub func() *policyv1alpha1.PodUnavailableBudget
 		expect bool
 	}{
 		{
@@ -563,12 +609,77 @@ func TestIsPodReady(t *testing.T) {
 			},
 			expect: false,
 		},
+		{
+			name: "pod not ready, but available by availabilityPolicy",
+			getPod: func() *corev1.Pod {
+				demo := podDemo.DeepCopy()
+				demo.Status.Conditions[0].Status = corev1.ConditionFalse
+				demo.Status.Conditions = append(demo.Status.Conditions, corev1.PodCondition{Type: "game.io/idle", Status: corev1.ConditionTrue})
+				demo.Labels["serving"] = "true"
+				return demo
+			},
+			getPub: func() *policyv1alpha1.PodUnavailableBudget {
+				demo := pubDemo.DeepCopy()
+				demo.Spec.AvailabilityPolicy = availabilityPolicy
+				return demo
+			},
+			expect: true,
+		},
+		{
+			name: "pod ready, but condition of availabilityPolicy is false",
+			getPod: func() *corev1.Pod {
+				demo := podDemo.DeepCopy()
+				demo.Status.Conditions = append(demo.Status.Conditions, corev1.PodCondition{Type: "game.io/idle", Status: corev1.ConditionFalse})
+				demo.Labels["serving"] = "true"
+				return demo
+			},
+			getPub: func() *policyv1alpha1.PodUnavailableBudget {
+				demo := pubDemo.DeepCopy()
+				demo.Spec.AvailabilityPolicy = availabilityPolicy
+				return demo
+			},
+			expect: false,
+		},
+		{
+			name: "pod ready, but labels don't match availabilityPolicy",
+			getPod: func() *corev1.Pod {
+				demo := podDemo.DeepCopy()
+				demo.Status.Conditions = append(demo.Status.Conditions, corev1.PodCondition{Type: "game.io/idle", Status: corev1.ConditionTrue})
+				return demo
+			},
+			getPub: func() *policyv1alpha1.PodUnavailableBudget {
+				demo := pubDemo.DeepCopy()
+				demo.Spec.AvailabilityPolicy = availabilityPolicy
+				return demo
+			},
+			expect: false,
+		},
+		{
+			name: "pod not running, but conditions and labels match availabilityPolicy",
+			getPod: func() *corev1.Pod {
+				demo := podDemo.DeepCopy()
+				demo.Status.Phase = corev1.PodPending
+				demo.Status.Conditions = append(demo.Status.Conditions, corev1.PodCondition{Type: "game.io/idle", Status: corev1.ConditionTrue})
+				demo.Labels["serving"] = "true"
+				return demo
+			},
+			getPub: func() *policyv1alpha1.PodUnavailableBudget {
+				demo := pubDemo.DeepCopy()
+				demo.Spec.AvailabilityPolicy = availabilityPolicy
+				return demo
+			},
+			expect: false,
+		},
 	}
 
 	for _, cs := range cases {
 		t.Run(cs.name, func(t *testing.T) {
 			control := commonControl{}
-			is := control.IsPodReady(cs.getPod())
+			var pubObj *policyv1alpha1.PodUnavailableBudget
+			if cs.getPub != nil {
+				pubObj = cs.getPub()
+			}
+			is := control.IsPodReady(cs.getPod(), pubObj)
 			if cs.expect != is {
 				t.Fatalf("IsPodReady failed")
 			}
@@ -32,6 +32,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	policyv1alpha1 "github.com/openkruise/kruise/apis/policy/v1alpha1"
 	kubeClient "github.com/openkruise/kruise/pkg/client"
@@ -67,21 +68,25 @@ func PodUnavailableBudgetValidatePod(pod *corev1.Pod, operation policyv1alpha1.P
 	if pod.Annotations[policyv1alpha1.PodPubNoProtectionAnnotation] == "true" {
 		klog.V(3).InfoS("Pod contained annotations=true, then didn't need check pub", "pod", klog.KObj(pod), "annotations", policyv1alpha1.PodPubNoProtectionAnnotation)
 		return true, "", nil
-		// If the pod is not ready or state is inconsistent, it doesn't count towards healthy and we should not decrement
-	} else if !PubControl.IsPodReady(pod) || !PubControl.IsPodStateConsistent(pod) {
-		klog.V(3).InfoS("Pod was not ready or state was inconsistent, then didn't need check pub", "pod", klog.KObj(pod))
-		return true, "", nil
 	}
 
 	// pub for pod
 	pub, err := PubControl.GetPubForPod(pod)
 	if err != nil {
+		// the pub in dryRun mode doesn't deny the operation, which is checked with the pub in local cache
+		if allowedByDryRunPub(pod, getLocalCachedPubForPod(pod), operation, username, dryRun, err) {
+			return true, "", nil
+		}
 		return false, "", err
 		// if there is no matching PodUnavailableBudget, just return true
 	} else if pub == nil {
 		return true, "", nil
-		// if desired available == 0, then allow all request
-	} else if pub.Status.DesiredAvailable == 0 {
+		// If the pod is not ready or state is inconsistent, it doesn't count towards healthy and we should not decrement
+	} else if !PubControl.IsPodReady(pod, pub) || !PubControl.IsPodStateConsistent(pod) {
+		klog.V(3).InfoS("Pod was not ready or state was inconsistent, then didn't need check pub", "pod", klog.KObj(pod))
+		return true, "", nil
+		// if desired available == 0 and there is no topology budget, then allow all request
+	} else if pub.Status.DesiredAvailable == 0 && len(pub.Spec.TopologyBudgets) == 0 {
 		return true, "", nil
 	} else if !isNeedPubProtection(pub, operation) {
 		klog.V(3).InfoS("Pod operation was not in pub protection", "pod", klog.KObj(pod), "operation", operation, "pubName", pub.Name)
@@ -91,6 +96,14 @@ func PodUnavailableBudgetValidatePod(pod *corev1.Pod, operation policyv1alpha1.P
 		klog.V(3).InfoS("Pod was already recorded in pub", "pod", klog.KObj(pod), "pub", klog.KObj(pub))
 		return true, "", nil
 	}
+	// topology domains of pod for the topology budgets
+	domains, err := getPodTopologyDomains(pub, pod)
+	if err != nil {
+		if allowedByDryRunPub(pod, pub, operation, username, dryRun, err) {
+			return true, "", nil
+		}
+		return false, "", err
+	}
 	// check and decrement pub quota
 	var conflictTimes int
 	var costOfGet, costOfUpdate time.Duration
@@ -138,8 +151,11 @@ func PodUnavailableBudgetValidatePod(pod *corev1.Pod, operation policyv1alpha1.P
 
 		// Try to verify-and-decrement
 		// If it was false already, or if it becomes false during the course of our retries,
-		err = checkAndDecrement(pod.Name, pubClone, operation)
-		if err != nil {
+		err = checkAndDecrement(pod.Name, pubClone, operation, domains)
+		if err != nil && allowedByDryRunPub(pod, pubClone, operation, username, dryRun, err) {
+			// The pub quota is not changed, so there is no need to update pub status here.
+			return nil
+		} else if err != nil {
 			var kind, namespace, name string
 			if ref := PubControl.GetPodControllerOf(pod); ref != nil {
 				kind = ref.Kind
@@ -189,10 +205,16 @@ func PodUnavailableBudgetValidatePod(pod *corev1.Pod, operation policyv1alpha1.P
 	if err != nil && err != wait.ErrWaitTimeout {
 		klog.V(3).InfoS("Pod operation for pub failed", "pod", klog.KObj(pod), "operation", operation,
 			"pub", klog.KObj(pub), "error", err)
+		if allowedByDryRunPub(pod, pub, operation, username, dryRun, err) {
+			return true, "", nil
+		}
 		return false, err.Error(), nil
 	} else if err == wait.ErrWaitTimeout {
 		err = errors.NewTimeoutError(fmt.Sprintf("couldn't update PodUnavailableBudget %s due to conflicts", pub.Name), 10)
 		klog.ErrorS(err, "Pod operation failed", "pod", klog.KObj(pod), "operation", operation)
+		if allowedByDryRunPub(pod, pub, operation, username, dryRun, err) {
+			return true, "", nil
+		}
 		return false, err.Error(), nil
 	}
 
@@ -201,15 +223,95 @@ func PodUnavailableBudgetValidatePod(pod *corev1.Pod, operation policyv1alpha1.P
 	return true, "", nil
 }
 
-func checkAndDecrement(podName string, pub *policyv1alpha1.PodUnavailableBudget, operation policyv1alpha1.PubOperation) error {
-	if pub.Status.UnavailableAllowed <= 0 {
+// allowedByDryRunPub returns true if the pub is in dryRun mode, which doesn't deny the operation for the error,
+// and records the operation unless the request itself is a dry-run.
+func allowedByDryRunPub(pod *corev1.Pod, pub *policyv1alpha1.PodUnavailableBudget, operation policyv1alpha1.PubOperation,
+	username string, dryRun bool, err error) bool {
+	if pub == nil || !pub.Spec.DryRun {
+		return false
+	}
+	if !dryRun {
+		recordDryRunDenied(pod, pub, operation, username, err)
+	}
+	return true
+}
+
+// getLocalCachedPubForPod returns the pub of pod in the local cache of the pubs updated by webhook,
+// or nil if it is not cached.
+func getLocalCachedPubForPod(pod *corev1.Pod) *policyv1alpha1.PodUnavailableBudget {
+	pubName := pod.Annotations[PodRelatedPubAnnotation]
+	if pubName == "" {
+		return nil
+	}
+	for _, item := range util.GlobalCache.List() {
+		if pub, ok := item.(*policyv1alpha1.PodUnavailableBudget); ok && pub.Namespace == pod.Namespace && pub.Name == pubName {
+			return pub
+		}
+	}
+	return nil
+}
+
+// recordDryRunDenied emits the event and metrics for the pod operation that would have been denied by the pub
+// in dryRun mode, and counts it in pub status later, see dryRunDeniedAggregator.
+func recordDryRunDenied(pod *corev1.Pod, pub *policyv1alpha1.PodUnavailableBudget, operation policyv1alpha1.PubOperation, username string, err error) {
+	reason := err.Error()
+	if statusErr, ok := err.(errors.APIStatus); ok {
+		reason = statusErr.Status().Message
+	}
+	klog.InfoS("Pod operation would have been denied by pub in dryRun mode", "pod", klog.KObj(pod), "operation", operation,
+		"username", username, "pub", klog.KObj(pub), "reason", reason)
+	PodUnavailableBudgetDryRunDeniedMetrics.WithLabelValues(fmt.Sprintf("%s_%s", pub.Namespace, pub.Name), string(operation), username).Add(1)
+	recorder.Eventf(pub, corev1.EventTypeWarning, "PubDryRunDenied",
+		"%s pod %s by %s would have been denied: %s", operation, pod.Name, username, reason)
+
+	dryRunDeniedRecords.add(pub, &policyv1alpha1.PodUnavailableBudgetDeniedOperation{
+		PodName:   pod.Name,
+		Operation: operation,
+		Username:  username,
+		Reason:    reason,
+		Time:      metav1.Now(),
+	})
+}
+
+func checkAndDecrement(podName string, pub *policyv1alpha1.PodUnavailableBudget, operation policyv1alpha1.PubOperation, domains map[string]string) error {
+	// if desired available == 0, only the topology budgets limit the operation
+	if pub.Status.DesiredAvailable > 0 && pub.Status.UnavailableAllowed <= 0 {
 		return errors.NewForbidden(policyv1alpha1.Resource("podunavailablebudget"), pub.Name, fmt.Errorf("pub unavailable allowed is negative"))
 	}
+	// the pod must not exceed the budgets of its topology domains
+	var topologyStatuses []*policyv1alpha1.PodUnavailableBudgetTopologyStatus
+	for _, budget := range pub.Spec.TopologyBudgets {
+		value, ok := domains[budget.TopologyKey]
+		if !ok {
+			continue
+		}
+		var status *policyv1alpha1.PodUnavailableBudgetTopologyStatus
+		for i := range pub.Status.TopologyStatuses {
+			if pub.Status.TopologyStatuses[i].TopologyKey == budget.TopologyKey && pub.Status.TopologyStatuses[i].Value == value {
+				status = &pub.Status.TopologyStatuses[i]
+				break
+			}
+		}
+		// the budget of a new topology domain is unknown until the pub controller calculates it
+		if status == nil {
+			return errors.NewForbidden(policyv1alpha1.Resource("podunavailablebudget"), pub.Name,
+				fmt.Errorf("pub unavailable allowed in topology %s=%s is not calculated yet", budget.TopologyKey, value))
+		}
+		if status.UnavailableAllowed <= 0 {
+			return errors.NewForbidden(policyv1alpha1.Resource("podunavailablebudget"), pub.Name,
+				fmt.Errorf("pub unavailable allowed in topology %s=%s is negative", status.TopologyKey, status.Value))
+		}
+		topologyStatuses = append(topologyStatuses, status)
+	}
 	if len(pub.Status.DisruptedPods)+len(pub.Status.UnavailablePods) > MaxUnavailablePodSize {
 		return errors.NewForbidden(policyv1alpha1.Resource("podunavailablebudget"), pub.Name, fmt.Errorf("DisruptedPods and UnavailablePods map too big - too many unavailable not confirmed by PUB controller"))
 	}
 
 	pub.Status.UnavailableAllowed--
+	for _, status := range topologyStatuses {
+		status.UnavailableAllowed--
+		status.Unavailable++
+	}
 
 	if pub.Status.DisruptedPods == nil {
 		pub.Status.DisruptedPods = make(map[string]metav1.Time)
@@ -228,6 +330,30 @@ func checkAndDecrement(podName string, pub *policyv1alpha1.PodUnavailableBudget,
 	return nil
 }
 
+// getPodTopologyDomains returns the topology domains of pod for the topology budgets of pub.
+func getPodTopologyDomains(pub *policyv1alpha1.PodUnavailableBudget, pod *corev1.Pod) (map[string]string, error) {
+	if len(pub.Spec.TopologyBudgets) == 0 || pod.Spec.NodeName == "" {
+		return nil, nil
+	}
+	node := &corev1.Node{}
+	if err := kclient.Get(context.TODO(), types.NamespacedName{Name: pod.Spec.NodeName}, node); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	return GetTopologyDomains(pub, node), nil
+}
+
+// GetTopologyDomains returns the values of the topologyKeys of pub.spec.topologyBudgets in node labels,
+// the topologyKey that node doesn't have is not contained.
+func GetTopologyDomains(pub *policyv1alpha1.PodUnavailableBudget, node *corev1.Node) map[string]string {
+	domains := make(map[string]string, len(pub.Spec.TopologyBudgets))
+	for _, budget := range pub.Spec.TopologyBudgets {
+		if value, ok := node.Labels[budget.TopologyKey]; ok {
+			domains[budget.TopologyKey] = value
+		}
+	}
+	return domains
+}
+
 func isPodRecordedInPub(podName string, pub *policyv1alpha1.PodUnavailableBudget) bool {
 	if _, ok := pub.Status.UnavailablePods[podName]; ok {
 		return true
@@ -17,7 +17,9 @@ limitations under the License.
 package pubcontrol
 
 import (
+	"context"
 	"fmt"
+	"reflect"
 	"testing"
 	"time"
 
@@ -31,12 +33,15 @@ import (
 	"k8s.io/client-go/tools/record"
 	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
 	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 
 	"github.com/openkruise/kruise/apis/apps/pub"
 	appspub "github.com/openkruise/kruise/apis/apps/pub"
 	policyv1alpha1 "github.com/openkruise/kruise/apis/policy/v1alpha1"
 	"github.com/openkruise/kruise/pkg/features"
+	"github.com/openkruise/kruise/pkg/util"
 	"github.com/openkruise/kruise/pkg/util/controllerfinder"
 	"github.com/openkruise/kruise/pkg/util/feature"
 )
@@ -306,11 +311,147 @@ func TestPodUnavailableBudgetValidatePod(t *testing.T) {
 			operation:   policyv1alpha1.PubDeleteOperation,
 			expectAllow: true,
 		},
+		{
+			name: "valid delete pod, pod not ready but available by availabilityPolicy, reject",
+			getPod: func() *corev1.Pod {
+				pod := podDemo.DeepCopy()
+				podReadyCondition := podutil.GetPodReadyCondition(pod.Status)
+				podReadyCondition.Status = corev1.ConditionFalse
+				pod.Status.Conditions = append(pod.Status.Conditions, corev1.PodCondition{Type: "game.io/idle", Status: corev1.ConditionTrue})
+				return pod
+			},
+			getPub: func() *policyv1alpha1.PodUnavailableBudget {
+				pub := pubDemo.DeepCopy()
+				pub.Spec.AvailabilityPolicy = &policyv1alpha1.PodUnavailableBudgetAvailabilityPolicy{
+					Conditions: []corev1.PodConditionType{"game.io/idle"},
+				}
+				return pub
+			},
+			operation:   policyv1alpha1.PubDeleteOperation,
+			expectAllow: false,
+		},
+		{
+			name: "valid delete pod, topology budget allow",
+			getPod: func() *corev1.Pod {
+				pod := podDemo.DeepCopy()
+				pod.Spec.NodeName = "node-a"
+				return pod
+			},
+			getPub: func() *policyv1alpha1.PodUnavailableBudget {
+				pub := pubDemo.DeepCopy()
+				pub.Spec.TopologyBudgets = []policyv1alpha1.PodUnavailableBudgetTopologyBudget{
+					{TopologyKey: corev1.LabelTopologyZone, MaxUnavailable: intstr.FromInt32(1)},
+				}
+				pub.Status.UnavailableAllowed = 2
+				pub.Status.TopologyStatuses = []policyv1alpha1.PodUnavailableBudgetTopologyStatus{
+					{TopologyKey: corev1.LabelTopologyZone, Value: "zone-a", TotalReplicas: 2, CurrentAvailable: 2, UnavailableAllowed: 1},
+					{TopologyKey: corev1.LabelTopologyZone, Value: "zone-b", TotalReplicas: 2, CurrentAvailable: 1, Unavailable: 1},
+				}
+				return pub
+			},
+			operation:   policyv1alpha1.PubDeleteOperation,
+			expectAllow: true,
+		},
+		{
+			name: "valid delete pod, topology budget reject",
+			getPod: func() *corev1.Pod {
+				pod := podDemo.DeepCopy()
+				pod.Spec.NodeName = "node-b"
+				return pod
+			},
+			getPub: func() *policyv1alpha1.PodUnavailableBudget {
+				pub := pubDemo.DeepCopy()
+				pub.Spec.TopologyBudgets = []policyv1alpha1.PodUnavailableBudgetTopologyBudget{
+					{TopologyKey: corev1.LabelTopologyZone, MaxUnavailable: intstr.FromInt32(1)},
+				}
+				pub.Status.UnavailableAllowed = 2
+				pub.Status.TopologyStatuses = []policyv1alpha1.PodUnavailableBudgetTopologyStatus{
+					{TopologyKey: corev1.LabelTopologyZone, Value: "zone-a", TotalReplicas: 2, CurrentAvailable: 2, UnavailableAllowed: 1},
+					{TopologyKey: corev1.LabelTopologyZone, Value: "zone-b", TotalReplicas: 2, CurrentAvailable: 1, Unavailable: 1},
+				}
+				return pub
+			},
+			operation:   policyv1alpha1.PubDeleteOperation,
+			expectAllow: false,
+		},
+		{
+			name: "valid delete pod, topology budget and pub desiredAvailable is 0, reject",
+			getPod: func() *corev1.Pod {
+				pod := podDemo.DeepCopy()
+				pod.Spec.NodeName = "node-b"
+				return pod
+			},
+			getPub: func() *policyv1alpha1.PodUnavailableBudget {
+				pub := pubDemo.DeepCopy()
+				pub.Spec.MaxUnavailable = ptr.To(intstr.FromString("100%"))
+				pub.Spec.TopologyBudgets = []policyv1alpha1.PodUnavailableBudgetTopologyBudget{
+					{TopologyKey: corev1.LabelTopologyZone, MaxUnavailable: intstr.FromInt32(1)},
+				}
+				pub.Status.DesiredAvailable = 0
+				pub.Status.UnavailableAllowed = 3
+				pub.Status.TopologyStatuses = []policyv1alpha1.PodUnavailableBudgetTopologyStatus{
+					{TopologyKey: corev1.LabelTopologyZone, Value: "zone-b", TotalReplicas: 2, CurrentAvailable: 1, Unavailable: 1},
+				}
+				return pub
+			},
+			operation:   policyv1alpha1.PubDeleteOperation,
+			expectAllow: false,
+		},
+		{
+			name: "valid delete pod, pub desiredAvailable is 0 without unavailable allowed, topology budget allow",
+			getPod: func() *corev1.Pod {
+				pod := podDemo.DeepCopy()
+				pod.Spec.NodeName = "node-a"
+				return pod
+			},
+			getPub: func() *policyv1alpha1.PodUnavailableBudget {
+				pub := pubDemo.DeepCopy()
+				pub.Spec.MaxUnavailable = ptr.To(intstr.FromString("100%"))
+				pub.Spec.TopologyBudgets = []policyv1alpha1.PodUnavailableBudgetTopologyBudget{
+					{TopologyKey: corev1.LabelTopologyZone, MaxUnavailable: intstr.FromInt32(1)},
+				}
+				pub.Status.DesiredAvailable = 0
+				pub.Status.UnavailableAllowed = 0
+				pub.Status.TopologyStatuses = []policyv1alpha1.PodUnavailableBudgetTopologyStatus{
+					{TopologyKey: corev1.LabelTopologyZone, Value: "zone-a", TotalReplicas: 2, CurrentAvailable: 2, UnavailableAllowed: 1},
+				}
+				return pub
+			},
+			operation:   policyv1alpha1.PubDeleteOperation,
+			expectAllow: true,
+		},
+		{
+			name: "valid delete pod, topology domain not calculated in status, reject",
+			getPod: func() *corev1.Pod {
+				pod := podDemo.DeepCopy()
+				pod.Spec.NodeName = "node-a"
+				return pod
+			},
+			getPub: func() *policyv1alpha1.PodUnavailableBudget {
+				pub := pubDemo.DeepCopy()
+				pub.Spec.TopologyBudgets = []policyv1alpha1.PodUnavailableBudgetTopologyBudget{
+					{TopologyKey: corev1.LabelTopologyZone, MaxUnavailable: intstr.FromInt32(1)},
+				}
+				pub.Status.UnavailableAllowed = 2
+				pub.Status.TopologyStatuses = []policyv1alpha1.PodUnavailableBudgetTopologyStatus{
+					{TopologyKey: corev1.LabelTopologyZone, Value: "zone-b", TotalReplicas: 2, CurrentAvailable: 2, UnavailableAllowed: 1},
+				}
+				return pub
+			},
+			operation:   policyv1alpha1.PubDeleteOperation,
+			expectAllow: false,
+		},
 	}
 
+	nodes := []*corev1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-a", Labels: map[string]string{corev1.LabelTopologyZone: "zone-a"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-b", Labels: map[string]string{corev1.LabelTopologyZone: "zone-b"}}},
+	}
 	for _, cs := range cases {
 		t.Run(cs.name, func(t *testing.T) {
-			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cs.getPub()).
+			pubObj := cs.getPub()
+			defer util.GlobalCache.Delete(pubObj)
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pubObj, nodes[0], nodes[1]).
 				WithStatusSubresource(&policyv1alpha1.PodUnavailableBudget{}).Build()
 			finder := &controllerfinder.ControllerFinder{Client: fakeClient}
 			InitPubControl(fakeClient, finder, record.NewFakeRecorder(10))
@@ -325,6 +466,162 @@ func TestPodUnavailableBudgetValidatePod(t *testing.T) {
 	}
 }
 
+func TestPodUnavailableBudgetValidatePodDryRun(t *testing.T) {
+	cases := []struct {
+		name                   string
+		dryRun                 bool
+		expectAllow            bool
+		expectDeniedOperations map[policyv1alpha1.PubOperation]int32
+	}{
+		{
+			name:        "pub not in dryRun mode, reject",
+			expectAllow: false,
+		},
+		{
+			name:                   "pub in dryRun mode, allow and record the denied operation",
+			dryRun:                 true,
+			expectAllow:            true,
+			expectDeniedOperations: map[policyv1alpha1.PubOperation]int32{policyv1alpha1.PubEvictOperation: 2, policyv1alpha1.PubDeleteOperation: 1},
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			pubObj := pubDemo.DeepCopy()
+			pubObj.Spec.DryRun = cs.dryRun
+			pubObj.Status.DryRunDeniedOperations = map[policyv1alpha1.PubOperation]int32{policyv1alpha1.PubEvictOperation: 1}
+			defer util.GlobalCache.Delete(pubObj)
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pubObj).
+				WithStatusSubresource(&policyv1alpha1.PodUnavailableBudget{}).Build()
+			fakeRecorder := record.NewFakeRecorder(10)
+			InitPubControl(fakeClient, &controllerfinder.ControllerFinder{Client: fakeClient}, fakeRecorder)
+			allow, _, err := PodUnavailableBudgetValidatePod(podDemo.DeepCopy(), policyv1alpha1.PubEvictOperation, "fake-user", false)
+			if err != nil {
+				t.Fatalf("PodUnavailableBudgetValidatePod failed: %s", err.Error())
+			}
+			if cs.expectAllow != allow {
+				t.Fatalf("expect allow %v, but got %v", cs.expectAllow, allow)
+			}
+			if !cs.dryRun {
+				return
+			}
+
+			// the operations of dry-run requests are not recorded
+			if _, _, err = PodUnavailableBudgetValidatePod(podDemo.DeepCopy(), policyv1alpha1.PubDeleteOperation, "fake-user", true); err != nil {
+				t.Fatalf("PodUnavailableBudgetValidatePod failed: %s", err.Error())
+			}
+			if _, _, err = PodUnavailableBudgetValidatePod(podDemo.DeepCopy(), policyv1alpha1.PubDeleteOperation, "fake-user", false); err != nil {
+				t.Fatalf("PodUnavailableBudgetValidatePod failed: %s", err.Error())
+			}
+			// the denied operations are aggregated before written into status
+			newPub := &policyv1alpha1.PodUnavailableBudget{}
+			if err = fakeClient.Get(context.TODO(), client.ObjectKeyFromObject(pubObj), newPub); err != nil {
+				t.Fatalf("get pub failed: %s", err.Error())
+			}
+			if !reflect.DeepEqual(newPub.Status.DryRunDeniedOperations, pubObj.Status.DryRunDeniedOperations) {
+				t.Fatalf("expect denied operations not flushed, but got %v", newPub.Status.DryRunDeniedOperations)
+			}
+			dryRunDeniedRecords.flush(client.ObjectKeyFromObject(pubObj))
+			if err = fakeClient.Get(context.TODO(), client.ObjectKeyFromObject(pubObj), newPub); err != nil {
+				t.Fatalf("get pub failed: %s", err.Error())
+			}
+			if !reflect.DeepEqual(newPub.Status.DryRunDeniedOperations, cs.expectDeniedOperations) {
+				t.Fatalf("expect denied operations %v, but got %v", cs.expectDeniedOperations, newPub.Status.DryRunDeniedOperations)
+			}
+			last := newPub.Status.LastDryRunDeniedOperation
+			if last == nil || last.PodName != podDemo.Name || last.Operation != policyv1alpha1.PubDeleteOperation || last.Username != "fake-user" {
+				t.Fatalf("unexpected last denied operation %v", last)
+			}
+			if newPub.Status.UnavailableAllowed != 0 || len(newPub.Status.DisruptedPods) != 0 {
+				t.Fatalf("pub quota should not be changed in dryRun mode")
+			}
+			if len(fakeRecorder.Events) != 2 {
+				t.Fatalf("expect 2 events, but got %d", len(fakeRecorder.Events))
+			}
+		})
+	}
+}
+
+type errGetPubControl struct {
+	pubControl
+	err error
+}
+
+func (c *errGetPubControl) GetPubForPod(_ *corev1.Pod) (*policyv1alpha1.PodUnavailableBudget, error) {
+	return nil, c.err
+}
+
+func TestPodUnavailableBudgetValidatePodDryRunErrors(t *testing.T) {
+	cases := []struct {
+		name        string
+		dryRun      bool
+		failGetPub  bool
+		failGetNode bool
+		expectAllow bool
+	}{
+		{
+			name:        "failed to get pub, reject",
+			failGetPub:  true,
+			expectAllow: false,
+		},
+		{
+			name:        "failed to get pub in dryRun mode, allow",
+			dryRun:      true,
+			failGetPub:  true,
+			expectAllow: true,
+		},
+		{
+			name:        "failed to get node of pod, reject",
+			failGetNode: true,
+			expectAllow: false,
+		},
+		{
+			name:        "failed to get node of pod for pub in dryRun mode, allow",
+			dryRun:      true,
+			failGetNode: true,
+			expectAllow: true,
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			pubObj := pubDemo.DeepCopy()
+			pubObj.Spec.DryRun = cs.dryRun
+			pubObj.Spec.TopologyBudgets = []policyv1alpha1.PodUnavailableBudgetTopologyBudget{
+				{TopologyKey: corev1.LabelTopologyZone, MaxUnavailable: intstr.FromInt32(1)},
+			}
+			pubObj.Status.UnavailableAllowed = 1
+			defer util.GlobalCache.Delete(pubObj)
+			if err := util.GlobalCache.Add(pubObj); err != nil {
+				t.Fatalf("add pub cache failed: %s", err.Error())
+			}
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pubObj).
+				WithStatusSubresource(&policyv1alpha1.PodUnavailableBudget{}).
+				WithInterceptorFuncs(interceptor.Funcs{Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+					if _, ok := obj.(*corev1.Node); ok && cs.failGetNode {
+						return fmt.Errorf("failed to get node")
+					}
+					return c.Get(ctx, key, obj, opts...)
+				}}).Build()
+			fakeRecorder := record.NewFakeRecorder(10)
+			InitPubControl(fakeClient, &controllerfinder.ControllerFinder{Client: fakeClient}, fakeRecorder)
+			defer dryRunDeniedRecords.flush(client.ObjectKeyFromObject(pubObj))
+			if cs.failGetPub {
+				PubControl = &errGetPubControl{pubControl: PubControl, err: fmt.Errorf("failed to get pub")}
+			}
+			pod := podDemo.DeepCopy()
+			pod.Spec.NodeName = "node-a"
+			allow, _, err := PodUnavailableBudgetValidatePod(pod, policyv1alpha1.PubDeleteOperation, "fake-user", false)
+			if cs.expectAllow != allow {
+				t.Fatalf("expect allow %v, but got %v, err %v", cs.expectAllow, allow, err)
+			}
+			if cs.dryRun && len(fakeRecorder.Events) != 1 {
+				t.Fatalf("expect the denied operation recorded, but got %d events", len(fakeRecorder.Events))
+			}
+		})
+	}
+}
+
 func TestGetPodUnavailableBudgetForPod(t *testing.T) {
 	cases := []struct {
 		name          string
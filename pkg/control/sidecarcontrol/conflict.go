@@ -0,0 +1,230 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sidecarcontrol
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+)
+
+// SidecarSetConflictResult is the result of a conflict between SidecarSets.
+type SidecarSetConflictResult string
+
+const (
+	// SidecarSetConflictSkipped means the SidecarSet is skipped for the conflict with a higher priority one.
+	SidecarSetConflictSkipped SidecarSetConflictResult = "Skipped"
+	// SidecarSetConflictRejected means the SidecarSets have the same priority, and the pod should be rejected.
+	SidecarSetConflictRejected SidecarSetConflictResult = "Rejected"
+	// SidecarSetConflictIgnored means neither of the SidecarSets sets the priority, both of them are injected.
+	SidecarSetConflictIgnored SidecarSetConflictResult = "Ignored"
+)
+
+// SidecarSetConflict describes a conflict between two SidecarSets injecting into the same pod.
+type SidecarSetConflict struct {
+	// SidecarSet is the name of the SidecarSet that conflicts with an injected one
+	SidecarSet string
+	// With is the name of the injected SidecarSet
+	With string
+	// Kind of the conflicting item, container, env, volume or annotation
+	Kind string
+	// Name of the conflicting item
+	Name string
+	// Result of the conflict
+	Result SidecarSetConflictResult
+}
+
+func (c SidecarSetConflict) String() string {
+	return fmt.Sprintf("%s %s of sidecarSet %s conflicts with sidecarSet %s", c.Kind, c.Name, c.SidecarSet, c.With)
+}
+
+// GetSidecarSetPriority returns the priority of sidecarSet to resolve injection conflicts, default is 0.
+func GetSidecarSetPriority(sidecarSet *appsv1alpha1.SidecarSet) int32 {
+	if sidecarSet.Spec.Priority == nil {
+		return 0
+	}
+	return *sidecarSet.Spec.Priority
+}
+
+// ResolveSidecarSetConflicts detects the conflicts between the SidecarSets that match the same pod.
+// The SidecarSets are checked in descending order of priority, a SidecarSet conflicting with one of higher priority
+// is skipped. The conflicts between SidecarSets of the same priority can not be resolved, they are rejected if any of
+// them sets the priority, otherwise they are ignored and both are injected as before.
+// The order of the injected SidecarSets is the same as the input.
+func ResolveSidecarSetConflicts(controls []SidecarControl) (injected []SidecarControl, conflicts []SidecarSetConflict) {
+	ordered := make([]SidecarControl, len(controls))
+	copy(ordered, controls)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		a, b := ordered[i].GetSidecarset(), ordered[j].GetSidecarset()
+		if GetSidecarSetPriority(a) != GetSidecarSetPriority(b) {
+			return GetSidecarSetPriority(a) > GetSidecarSetPriority(b)
+		}
+		return a.Name < b.Name
+	})
+
+	var accepted []*appsv1alpha1.SidecarSet
+	skippedNames := sets.NewString()
+	for _, control := range ordered {
+		sidecarSet := control.GetSidecarset()
+		var withHigher, withSame []SidecarSetConflict
+		for _, other := range accepted {
+			found := getSidecarSetConflicts(sidecarSet, other)
+			for i := range found {
+				switch {
+				case GetSidecarSetPriority(other) > GetSidecarSetPriority(sidecarSet):
+					found[i].Result = SidecarSetConflictSkipped
+					withHigher = append(withHigher, found[i])
+				case sidecarSet.Spec.Priority != nil || other.Spec.Priority != nil:
+					found[i].Result = SidecarSetConflictRejected
+					withSame = append(withSame, found[i])
+				default:
+					found[i].Result = SidecarSetConflictIgnored
+					withSame = append(withSame, found[i])
+				}
+			}
+		}
+		if len(withHigher) > 0 {
+			conflicts = append(conflicts, withHigher...)
+			skippedNames.Insert(sidecarSet.Name)
+			continue
+		}
+		conflicts = append(conflicts, withSame...)
+		accepted = append(accepted, sidecarSet)
+	}
+
+	for _, control := range controls {
+		if !skippedNames.Has(control.GetSidecarset().Name) {
+			injected = append(injected, control)
+		}
+	}
+	return injected, conflicts
+}
+
+// getSidecarSetConflicts returns the conflicts of sidecarSet with the injected one, the conflicts include:
+// 1. containers or initContainers with the same name
+// 2. env vars with the same name but different definitions in the containers with the same name
+// 3. volumes with the same name but different definitions
+// 4. pod annotations with different values, and one of them is patched with the Overwrite policy
+func getSidecarSetConflicts(sidecarSet, injected *appsv1alpha1.SidecarSet) []SidecarSetConflict {
+	var conflicts []SidecarSetConflict
+	newConflict := func(kind, name string) {
+		conflicts = append(conflicts, SidecarSetConflict{SidecarSet: sidecarSet.Name, With: injected.Name, Kind: kind, Name: name})
+	}
+
+	containersInjected := make(map[string]*corev1.Container)
+	for i := range injected.Spec.InitContainers {
+		containersInjected[injected.Spec.InitContainers[i].Name] = &injected.Spec.InitContainers[i].Container
+	}
+	for i := range injected.Spec.Containers {
+		containersInjected[injected.Spec.Containers[i].Name] = &injected.Spec.Containers[i].Container
+	}
+	checkContainer := func(container *corev1.Container) {
+		other, ok := containersInjected[container.Name]
+		if !ok {
+			return
+		}
+		newConflict("container", container.Name)
+		envsInjected := make(map[string]*corev1.EnvVar, len(other.Env))
+		for i := range other.Env {
+			envsInjected[other.Env[i].Name] = &other.Env[i]
+		}
+		for i := range container.Env {
+			env := &container.Env[i]
+			if otherEnv, ok := envsInjected[env.Name]; ok && !reflect.DeepEqual(env, otherEnv) {
+				newConflict("env", fmt.Sprintf("%s/%s", container.Name, env.Name))
+			}
+		}
+	}
+	for i := range sidecarSet.Spec.InitContainers {
+		checkContainer(&sidecarSet.Spec.InitContainers[i].Container)
+	}
+	for i := range sidecarSet.Spec.Containers {
+		checkContainer(&sidecarSet.Spec.Containers[i].Container)
+	}
+
+	volumesInjected := make(map[string]*corev1.Volume, len(injected.Spec.Volumes))
+	for i := range injected.Spec.Volumes {
+		volumesInjected[injected.Spec.Volumes[i].Name] = &injected.Spec.Volumes[i]
+	}
+	for i := range sidecarSet.Spec.Volumes {
+		volume := &sidecarSet.Spec.Volumes[i]
+		if other, ok := volumesInjected[volume.Name]; ok && !reflect.DeepEqual(volume, other) {
+			newConflict("volume", volume.Name)
+		}
+	}
+
+	annotationsInjected := make(map[string]appsv1alpha1.SidecarSetPatchPodMetadata)
+	for _, patch := range injected.Spec.PatchPodMetadata {
+		for key := range patch.Annotations {
+			annotationsInjected[key] = patch
+		}
+	}
+	for _, patch := range sidecarSet.Spec.PatchPodMetadata {
+		for key, value := range patch.Annotations {
+			other, ok := annotationsInjected[key]
+			if !ok || other.Annotations[key] == value {
+				continue
+			}
+			if patch.PatchPolicy == appsv1alpha1.SidecarSetOverwritePatchPolicy || other.PatchPolicy == appsv1alpha1.SidecarSetOverwritePatchPolicy {
+				newConflict("annotation", key)
+			}
+		}
+	}
+	return conflicts
+}
+
+// GetPodSidecarSetConflicts returns the conflicts between SidecarSets recorded in the pod,
+// sidecarSet name -> names of the conflicting sidecarSets that were injected before it.
+func GetPodSidecarSetConflicts(pod *corev1.Pod) map[string][]string {
+	conflicts := make(map[string][]string)
+	value := pod.Annotations[SidecarSetInjectionConflictAnnotation]
+	if value == "" {
+		return conflicts
+	}
+	if err := json.Unmarshal([]byte(value), &conflicts); err != nil {
+		klog.ErrorS(err, "Failed to parse pod annotations value", "pod", klog.KObj(pod),
+			"annotation", SidecarSetInjectionConflictAnnotation, "value", value)
+	}
+	return conflicts
+}
+
+// EncodeSidecarSetConflicts encodes the skipped and ignored conflicts into the value of SidecarSetInjectionConflictAnnotation.
+func EncodeSidecarSetConflicts(conflicts []SidecarSetConflict) string {
+	others := make(map[string]sets.String)
+	for _, conflict := range conflicts {
+		if conflict.Result == SidecarSetConflictRejected {
+			continue
+		}
+		if others[conflict.SidecarSet] == nil {
+			others[conflict.SidecarSet] = sets.NewString()
+		}
+		others[conflict.SidecarSet].Insert(conflict.With)
+	}
+	value := make(map[string][]string, len(others))
+	for name, names := range others {
+		value[name] = names.List()
+	}
+	by, _ := json.Marshal(value)
+	return string(by)
+}
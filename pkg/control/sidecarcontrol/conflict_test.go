@@ -0,0 +1,154 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sidecarcontrol
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+)
+
+func TestResolveSidecarSetConflicts(t *testing.T) {
+	newSidecarSet := func(name string, priority *int32, containers []string, volumes []corev1.Volume, annotations map[string]string) *appsv1alpha1.SidecarSet {
+		sidecarSet := &appsv1alpha1.SidecarSet{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       appsv1alpha1.SidecarSetSpec{Priority: priority, Volumes: volumes},
+		}
+		for _, container := range containers {
+			sidecarSet.Spec.Containers = append(sidecarSet.Spec.Containers, appsv1alpha1.SidecarContainer{Container: corev1.Container{Name: container}})
+		}
+		if annotations != nil {
+			sidecarSet.Spec.PatchPodMetadata = []appsv1alpha1.SidecarSetPatchPodMetadata{
+				{Annotations: annotations, PatchPolicy: appsv1alpha1.SidecarSetOverwritePatchPolicy},
+			}
+		}
+		return sidecarSet
+	}
+	withEnvs := func(sidecarSet *appsv1alpha1.SidecarSet, envs ...corev1.EnvVar) *appsv1alpha1.SidecarSet {
+		sidecarSet.Spec.Containers[0].Env = envs
+		return sidecarSet
+	}
+	hostPathVolume := func(name, path string) corev1.Volume {
+		return corev1.Volume{Name: name, VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: path}}}
+	}
+
+	cases := []struct {
+		name            string
+		sidecarSets     []*appsv1alpha1.SidecarSet
+		expectInjected  []string
+		expectConflicts []string
+	}{
+		{
+			name: "no conflicts",
+			sidecarSets: []*appsv1alpha1.SidecarSet{
+				newSidecarSet("a", nil, []string{"envoy"}, []corev1.Volume{hostPathVolume("log", "/var/log")}, map[string]string{"key": "a"}),
+				newSidecarSet("b", nil, []string{"log-agent"}, []corev1.Volume{hostPathVolume("log", "/var/log")}, map[string]string{"key": "a"}),
+			},
+			expectInjected: []string{"a", "b"},
+		},
+		{
+			name: "conflicts resolved by priority",
+			sidecarSets: []*appsv1alpha1.SidecarSet{
+				newSidecarSet("a", nil, []string{"envoy"}, []corev1.Volume{hostPathVolume("log", "/var/log")}, nil),
+				newSidecarSet("b", ptr.To[int32](10), []string{"envoy"}, []corev1.Volume{hostPathVolume("log", "/home/log")}, nil),
+				newSidecarSet("c", nil, []string{"log-agent"}, nil, nil),
+			},
+			expectInjected: []string{"b", "c"},
+			expectConflicts: []string{
+				"Skipped: container envoy of sidecarSet a conflicts with sidecarSet b",
+				"Skipped: volume log of sidecarSet a conflicts with sidecarSet b",
+			},
+		},
+		{
+			name: "conflicts with the same priority",
+			sidecarSets: []*appsv1alpha1.SidecarSet{
+				newSidecarSet("b", ptr.To[int32](0), []string{"log-agent"}, nil, map[string]string{"key": "b"}),
+				newSidecarSet("a", nil, []string{"envoy"}, nil, map[string]string{"key": "a"}),
+			},
+			expectInjected:  []string{"b", "a"},
+			expectConflicts: []string{"Rejected: annotation key of sidecarSet b conflicts with sidecarSet a"},
+		},
+		{
+			name: "conflicts without priority",
+			sidecarSets: []*appsv1alpha1.SidecarSet{
+				newSidecarSet("b", nil, []string{"envoy"}, nil, nil),
+				newSidecarSet("a", nil, []string{"envoy"}, nil, nil),
+			},
+			expectInjected:  []string{"b", "a"},
+			expectConflicts: []string{"Ignored: container envoy of sidecarSet b conflicts with sidecarSet a"},
+		},
+		{
+			name: "env conflicts in the same container",
+			sidecarSets: []*appsv1alpha1.SidecarSet{
+				withEnvs(newSidecarSet("a", nil, []string{"envoy"}, nil, nil),
+					corev1.EnvVar{Name: "LOG_LEVEL", Value: "info"}, corev1.EnvVar{Name: "PORT", Value: "15000"}),
+				withEnvs(newSidecarSet("b", ptr.To[int32](10), []string{"envoy"}, nil, nil),
+					corev1.EnvVar{Name: "LOG_LEVEL", Value: "debug"}, corev1.EnvVar{Name: "PORT", Value: "15000"}),
+			},
+			expectInjected: []string{"b"},
+			expectConflicts: []string{
+				"Skipped: container envoy of sidecarSet a conflicts with sidecarSet b",
+				"Skipped: env envoy/LOG_LEVEL of sidecarSet a conflicts with sidecarSet b",
+			},
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			var controls []SidecarControl
+			for _, sidecarSet := range cs.sidecarSets {
+				controls = append(controls, New(sidecarSet))
+			}
+			injected, conflicts := ResolveSidecarSetConflicts(controls)
+			var injectedNames, conflictMessages []string
+			for _, control := range injected {
+				injectedNames = append(injectedNames, control.GetSidecarset().Name)
+			}
+			for _, conflict := range conflicts {
+				conflictMessages = append(conflictMessages, fmt.Sprintf("%s: %s", conflict.Result, conflict.String()))
+			}
+			if !reflect.DeepEqual(injectedNames, cs.expectInjected) {
+				t.Fatalf("expect injected %v, but got %v", cs.expectInjected, injectedNames)
+			}
+			if fmt.Sprint(conflictMessages) != fmt.Sprint(cs.expectConflicts) {
+				t.Fatalf("expect conflicts %v, but got %v", cs.expectConflicts, conflictMessages)
+			}
+		})
+	}
+}
+
+func TestSidecarSetConflictsAnnotation(t *testing.T) {
+	conflicts := []SidecarSetConflict{
+		{SidecarSet: "a", With: "c", Kind: "container", Name: "envoy", Result: SidecarSetConflictSkipped},
+		{SidecarSet: "a", With: "b", Kind: "volume", Name: "log", Result: SidecarSetConflictIgnored},
+		{SidecarSet: "a", With: "b", Kind: "container", Name: "log-agent", Result: SidecarSetConflictIgnored},
+		{SidecarSet: "d", With: "b", Kind: "container", Name: "log-agent", Result: SidecarSetConflictRejected},
+	}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Annotations: map[string]string{SidecarSetInjectionConflictAnnotation: EncodeSidecarSetConflicts(conflicts)},
+	}}
+	expected := map[string][]string{"a": {"b", "c"}}
+	if got := GetPodSidecarSetConflicts(pod); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expect conflicts %v, but got %v", expected, got)
+	}
+}
@@ -120,14 +120,17 @@ func (c *commonControl) UpdatePodAnnotationsInUpgrade(changedContainers []string
 		inPlaceUpdateState.LastContainerStatuses = make(map[string]pub.InPlaceUpdateContainerStatus)
 	}
 
-	cStatus := make(map[string]string, len(pod.Status.ContainerStatuses))
-	for i := range pod.Status.ContainerStatuses {
-		c := &pod.Status.ContainerStatuses[i]
-		cStatus[c.Name] = c.ImageID
+	containerStatuses := GetPodSidecarContainerStatuses(pod)
+	cStatus := make(map[string]*v1.ContainerStatus, len(containerStatuses))
+	for i := range containerStatuses {
+		c := &containerStatuses[i]
+		cStatus[c.Name] = c
 	}
 	for _, cName := range changedContainers {
-		updateStatus := pub.InPlaceUpdateContainerStatus{
-			ImageID: cStatus[cName],
+		updateStatus := pub.InPlaceUpdateContainerStatus{}
+		if c, ok := cStatus[cName]; ok {
+			updateStatus.ImageID = c.ImageID
+			updateStatus.RestartCount = c.RestartCount
 		}
 		// record status.ImageId before update pods in store
 		inPlaceUpdateState.LastContainerStatuses[cName] = updateStatus
@@ -146,13 +149,19 @@ func (c *commonControl) IsPodStateConsistent(pod *v1.Pod, sidecarContainers sets
 	}
 
 	sidecarset := c.GetSidecarset()
+	if len(GetNativeSidecarInitContainers(sidecarset)) > 0 && len(pod.Spec.InitContainers) != len(pod.Status.InitContainerStatuses) {
+		return false
+	}
 	if sidecarContainers.Len() == 0 {
 		sidecarContainers = GetSidecarContainersInPod(sidecarset)
 	}
 
 	allDigestImage := true
 	cImageIDs := util.GetPodContainerImageIDs(pod)
-	for _, container := range pod.Spec.Containers {
+	containers := make([]v1.Container, 0, len(pod.Spec.InitContainers)+len(pod.Spec.Containers))
+	containers = append(containers, pod.Spec.InitContainers...)
+	containers = append(containers, pod.Spec.Containers...)
+	for _, container := range containers {
 		// only check whether sidecar container is consistent
 		if !sidecarContainers.Has(container.Name) {
 			continue
@@ -189,10 +198,16 @@ func (c *commonControl) IsSidecarSetUpgradable(pod *v1.Pod) (canUpgrade, consist
 	if GetPodSidecarSetWithoutImageRevision(sidecarSet.Name, pod) != GetSidecarSetWithoutImageRevision(sidecarSet) {
 		return false, false
 	}
+	// native sidecar initContainers can only be upgraded in-place when the pod runs them as sidecars
+	if names := GetNotUpgradableNativeSidecars(sidecarSet, pod); len(names) > 0 {
+		klog.V(3).InfoS("Pod native sidecar initContainers can not be upgraded in-place", "pod", klog.KObj(pod),
+			"sidecarSet", klog.KObj(sidecarSet), "containerNames", names)
+		return false, false
+	}
 
 	// cStatus: container.name -> containerStatus.Ready
 	cStatus := map[string]bool{}
-	for _, status := range pod.Status.ContainerStatuses {
+	for _, status := range GetPodSidecarContainerStatuses(pod) {
 		cStatus[status.Name] = status.Ready
 	}
 	sidecarContainerList := GetSidecarContainersInPod(sidecarSet)
@@ -240,13 +255,17 @@ func IsSidecarContainerUpdateCompleted(pod *v1.Pod, sidecarSets, containers sets
 		}
 	}
 
-	containerImages := make(map[string]string, len(pod.Spec.Containers))
+	containerImages := make(map[string]string, len(pod.Spec.InitContainers)+len(pod.Spec.Containers))
+	for i := range pod.Spec.InitContainers {
+		c := &pod.Spec.InitContainers[i]
+		containerImages[c.Name] = c.Image
+	}
 	for i := range pod.Spec.Containers {
 		c := &pod.Spec.Containers[i]
 		containerImages[c.Name] = c.Image
 	}
 
-	for _, cs := range pod.Status.ContainerStatuses {
+	for _, cs := range GetPodSidecarContainerStatuses(pod) {
 		// only check containers set
 		if !containers.Has(cs.Name) {
 			continue
@@ -0,0 +1,168 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sidecarcontrol
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/kubernetes/pkg/fieldpath"
+
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+)
+
+// podMetadataTemplateRegex matches the pod metadata templates, e.g. {{ metadata.labels['app'] }}
+var podMetadataTemplateRegex = regexp.MustCompile(`{{\s*([^{}]*?)\s*}}`)
+
+// GetPodMetadataTemplateFieldPaths returns the field paths of pod metadata templates in the value,
+// and returns error if there are unclosed templates.
+func GetPodMetadataTemplateFieldPaths(value string) ([]string, error) {
+	matches := podMetadataTemplateRegex.FindAllStringSubmatch(value, -1)
+	if rest := podMetadataTemplateRegex.ReplaceAllString(value, ""); strings.Contains(rest, "{{") || strings.Contains(rest, "}}") {
+		return nil, fmt.Errorf("invalid template in %q", value)
+	}
+	paths := make([]string, 0, len(matches))
+	for _, match := range matches {
+		paths = append(paths, match[1])
+	}
+	return paths, nil
+}
+
+// RenderPodMetadataTemplate replaces the pod metadata templates in the value with the fields of pod.
+// The template of a key that doesn't exist in the pod is rendered to empty.
+func RenderPodMetadataTemplate(value string, pod *corev1.Pod) (string, error) {
+	if !strings.Contains(value, "{{") {
+		return value, nil
+	}
+	var renderErr error
+	rendered := podMetadataTemplateRegex.ReplaceAllStringFunc(value, func(template string) string {
+		path := podMetadataTemplateRegex.FindStringSubmatch(template)[1]
+		fieldValue, err := extractPodMetadataFieldPath(pod, path)
+		if err != nil && renderErr == nil {
+			renderErr = fmt.Errorf("failed to render template %s: %v", template, err)
+		}
+		return fieldValue
+	})
+	return rendered, renderErr
+}
+
+func extractPodMetadataFieldPath(pod *corev1.Pod, path string) (string, error) {
+	if fieldPath, subscript, ok := fieldpath.SplitMaybeSubscriptedPath(path); ok {
+		switch fieldPath {
+		case "metadata.labels":
+			return pod.Labels[subscript], nil
+		case "metadata.annotations":
+			return pod.Annotations[subscript], nil
+		default:
+			return "", fmt.Errorf("unsupported subscripted fieldPath: %s", fieldPath)
+		}
+	}
+	return fieldpath.ExtractFieldPathAsString(pod, path)
+}
+
+// GetSidecarContainerTemplateValues returns the values that may contain pod metadata templates in the sidecar container,
+// including the env values and args.
+func GetSidecarContainerTemplateValues(sidecarContainer *appsv1alpha1.SidecarContainer) []string {
+	var values []string
+	for _, env := range sidecarContainer.Env {
+		values = append(values, env.Value)
+	}
+	return append(values, sidecarContainer.Args...)
+}
+
+// RenderSidecarContainerTemplates renders the pod metadata templates in the env values and args of the sidecar container.
+func RenderSidecarContainerTemplates(sidecarContainer *appsv1alpha1.SidecarContainer, pod *corev1.Pod) error {
+	if !sidecarContainer.PodMetadataTemplate {
+		return nil
+	}
+	var err error
+	for i := range sidecarContainer.Env {
+		if sidecarContainer.Env[i].Value, err = RenderPodMetadataTemplate(sidecarContainer.Env[i].Value, pod); err != nil {
+			return err
+		}
+	}
+	for i := range sidecarContainer.Args {
+		if sidecarContainer.Args[i], err = RenderPodMetadataTemplate(sidecarContainer.Args[i], pod); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetVolumeTemplateValues returns the string values in the source of volume, which may contain pod metadata templates.
+func GetVolumeTemplateValues(volume *corev1.Volume) []string {
+	var values []string
+	_, _ = walkVolumeSourceStrings(volume, func(value string) (string, error) {
+		values = append(values, value)
+		return value, nil
+	})
+	return values
+}
+
+// RenderVolumeTemplates returns the volume whose source is rendered with the pod metadata templates.
+func RenderVolumeTemplates(volume *corev1.Volume, pod *corev1.Pod) (*corev1.Volume, error) {
+	return walkVolumeSourceStrings(volume, func(value string) (string, error) {
+		return RenderPodMetadataTemplate(value, pod)
+	})
+}
+
+// walkVolumeSourceStrings calls fn for each string field in the volume source, and returns the volume with replaced values.
+func walkVolumeSourceStrings(volume *corev1.Volume, fn func(string) (string, error)) (*corev1.Volume, error) {
+	by, err := json.Marshal(volume.VolumeSource)
+	if err != nil {
+		return nil, err
+	}
+	var source interface{}
+	if err = json.Unmarshal(by, &source); err != nil {
+		return nil, err
+	}
+	if source, err = walkStrings(source, fn); err != nil {
+		return nil, err
+	}
+	if by, err = json.Marshal(source); err != nil {
+		return nil, err
+	}
+	rendered := &corev1.Volume{Name: volume.Name}
+	if err = json.Unmarshal(by, &rendered.VolumeSource); err != nil {
+		return nil, err
+	}
+	return rendered, nil
+}
+
+func walkStrings(obj interface{}, fn func(string) (string, error)) (interface{}, error) {
+	var err error
+	switch o := obj.(type) {
+	case string:
+		return fn(o)
+	case map[string]interface{}:
+		for k, v := range o {
+			if o[k], err = walkStrings(v, fn); err != nil {
+				return nil, err
+			}
+		}
+	case []interface{}:
+		for i, v := range o {
+			if o[i], err = walkStrings(v, fn); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return obj, nil
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sidecarcontrol
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRenderPodMetadataTemplate(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "pod-1",
+			Namespace:   "ns-1",
+			Labels:      map[string]string{"app": "nginx"},
+			Annotations: map[string]string{"cluster.io/name": "cluster-1", "empty": ""},
+		},
+	}
+	cases := []struct {
+		name            string
+		value           string
+		expectPaths     []string
+		expectValue     string
+		expectErr       bool
+		expectRenderErr bool
+	}{
+		{
+			name:        "no template",
+			value:       "--log-level=info",
+			expectPaths: []string{},
+			expectValue: "--log-level=info",
+		},
+		{
+			name:        "multiple templates",
+			value:       "{{ metadata.labels['app'] }}.{{metadata.namespace}}.{{ metadata.annotations['cluster.io/name'] }}",
+			expectPaths: []string{"metadata.labels['app']", "metadata.namespace", "metadata.annotations['cluster.io/name']"},
+			expectValue: "nginx.ns-1.cluster-1",
+		},
+		{
+			name:        "missing key",
+			value:       "{{ metadata.namespace }}-{{ metadata.labels['version'] }}",
+			expectPaths: []string{"metadata.namespace", "metadata.labels['version']"},
+			expectValue: "ns-1-",
+		},
+		{
+			name:        "empty value of existing key",
+			value:       "{{ metadata.labels['app'] }}-{{ metadata.annotations['empty'] }}",
+			expectPaths: []string{"metadata.labels['app']", "metadata.annotations['empty']"},
+			expectValue: "nginx-",
+		},
+		{
+			name:            "unsupported subscripted field path",
+			value:           "{{ metadata.uid['x'] }}",
+			expectPaths:     []string{"metadata.uid['x']"},
+			expectRenderErr: true,
+		},
+		{
+			name:      "unclosed template",
+			value:     "{{ metadata.namespace }",
+			expectErr: true,
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			paths, err := GetPodMetadataTemplateFieldPaths(cs.value)
+			if cs.expectErr {
+				if err == nil {
+					t.Fatalf("expect error, but got nil")
+				}
+				return
+			}
+			if err != nil || !reflect.DeepEqual(paths, cs.expectPaths) {
+				t.Fatalf("expect paths %v, but got %v, err %v", cs.expectPaths, paths, err)
+			}
+			value, err := RenderPodMetadataTemplate(cs.value, pod)
+			if cs.expectRenderErr {
+				if err == nil {
+					t.Fatalf("expect render error, but got value %s", value)
+				}
+				return
+			}
+			if err != nil || value != cs.expectValue {
+				t.Fatalf("expect value %s, but got %s, err %v", cs.expectValue, value, err)
+			}
+		})
+	}
+}
+
+func TestRenderVolumeTemplates(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod-1", Namespace: "ns-1", Labels: map[string]string{"app": "nginx"}},
+	}
+	volume := &corev1.Volume{
+		Name: "config",
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "{{ metadata.labels['app'] }}-config"},
+				Items:                []corev1.KeyToPath{{Key: "config", Path: "{{ metadata.namespace }}.yaml"}},
+			},
+		},
+	}
+	if values := GetVolumeTemplateValues(volume); len(values) != 3 {
+		t.Fatalf("expect 3 values in volume, but got %v", values)
+	}
+	rendered, err := RenderVolumeTemplates(volume, pod)
+	if err != nil {
+		t.Fatalf("render volume failed: %s", err.Error())
+	}
+	expect := &corev1.Volume{
+		Name: "config",
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "nginx-config"},
+				Items:                []corev1.KeyToPath{{Key: "config", Path: "ns-1.yaml"}},
+			},
+		},
+	}
+	if !reflect.DeepEqual(rendered, expect) {
+		t.Fatalf("expect volume %v, but got %v", expect, rendered)
+	}
+	// the original volume is not changed
+	if volume.ConfigMap.Name != "{{ metadata.labels['app'] }}-config" {
+		t.Fatalf("expect the original volume unchanged")
+	}
+}
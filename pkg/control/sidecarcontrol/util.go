@@ -56,6 +56,11 @@ const (
 	// SidecarSetListAnnotation represent sidecarset list that injected pods
 	SidecarSetListAnnotation = "kruise.io/sidecarset-injected-list"
 
+	// SidecarSetInjectionConflictAnnotation records the conflicts between SidecarSets when they are injected into the pod,
+	// the value is a json map: sidecarSet name -> names of the conflicting sidecarSets that were injected before it.
+	// The SidecarSets in the map but not in SidecarSetListAnnotation are skipped for the conflicts.
+	SidecarSetInjectionConflictAnnotation = "kruise.io/sidecarset-injection-conflicts"
+
 	// SidecarEnvKey specifies the environment variable which record a container as injected
 	SidecarEnvKey = "IS_INJECTED"
 
@@ -83,6 +88,8 @@ type SidecarSetUpgradeSpec struct {
 	SidecarSetName               string      `json:"sidecarSetName"`
 	SidecarList                  []string    `json:"sidecarList"`                  // sidecarSet container list
 	SidecarSetControllerRevision string      `json:"controllerRevision,omitempty"` // sidecarSet controllerRevision name
+	// sidecarSet controllerRevision name before the latest update, which is used to roll back the failed update
+	PreviousControllerRevision string `json:"previousControllerRevision,omitempty"`
 }
 
 // PodMatchSidecarSet determines if pod match Selector of sidecar.
@@ -245,19 +252,36 @@ func UpdatePodSidecarSetHash(pod *corev1.Pod, sidecarSet *appsv1alpha1.SidecarSe
 	}
 
 	sidecarList := listSidecarNameInSidecarSet(sidecarSet)
+	// record the controllerRevision before this update
+	previousRevision := sidecarSetHash[sidecarSet.Name].PreviousControllerRevision
+	if oldRevision := sidecarSetHash[sidecarSet.Name].SidecarSetControllerRevision; oldRevision != "" && oldRevision != sidecarSet.Status.LatestRevision {
+		previousRevision = oldRevision
+	}
 	sidecarSetHash[sidecarSet.Name] = SidecarSetUpgradeSpec{
 		UpdateTimestamp:              metav1.Now(),
 		SidecarSetHash:               GetSidecarSetRevision(sidecarSet),
 		SidecarSetName:               sidecarSet.Name,
 		SidecarList:                  sidecarList.List(),
 		SidecarSetControllerRevision: sidecarSet.Status.LatestRevision,
+		PreviousControllerRevision:   previousRevision,
 	}
 	newHash, _ := json.Marshal(sidecarSetHash)
 	pod.Annotations[hashKey] = string(newHash)
 }
 
+// GetSidecarContainersInPod returns the names of sidecar containers injected into pods by sidecarSet,
+// including the native sidecar initContainers and the hot upgrade containers.
 func GetSidecarContainersInPod(sidecarSet *appsv1alpha1.SidecarSet) sets.String {
 	names := sets.NewString()
+	for _, sidecarContainer := range GetNativeSidecarInitContainers(sidecarSet) {
+		if IsHotUpgradeContainer(&sidecarContainer) {
+			name1, name2 := GetHotUpgradeContainerName(sidecarContainer.Name)
+			names.Insert(name2)
+			names.Insert(name1)
+		} else {
+			names.Insert(sidecarContainer.Name)
+		}
+	}
 	for _, sidecarContainer := range sidecarSet.Spec.Containers {
 		if IsHotUpgradeContainer(&sidecarContainer) {
 			name1, name2 := GetHotUpgradeContainerName(sidecarContainer.Name)
@@ -605,6 +629,48 @@ func IsSidecarContainer(container corev1.Container) bool {
 	return false
 }
 
+// GetNativeSidecarInitContainers returns the initContainers of sidecarSet which are k8s native sidecar containers,
+// they keep running along with the pod and can be upgraded in-place like the sidecar containers.
+func GetNativeSidecarInitContainers(sidecarSet *appsv1alpha1.SidecarSet) []appsv1alpha1.SidecarContainer {
+	var initContainers []appsv1alpha1.SidecarContainer
+	for _, sidecar := range sidecarSet.Spec.InitContainers {
+		if IsSidecarContainer(sidecar.Container) {
+			initContainers = append(initContainers, sidecar)
+		}
+	}
+	return initContainers
+}
+
+// GetNotUpgradableNativeSidecars returns the native sidecar initContainers of sidecarSet that can not be upgraded in-place in pod:
+// 1. the initContainer in pod is not restartable, which means the native sidecar feature is not supported by the cluster,
+// and the initContainer has exited when the pod started.
+// 2. the initContainer is hot upgraded, which is only supported by the sidecar containers now.
+func GetNotUpgradableNativeSidecars(sidecarSet *appsv1alpha1.SidecarSet, pod *corev1.Pod) []string {
+	initContainersInPod := make(map[string]*corev1.Container, len(pod.Spec.InitContainers))
+	for i := range pod.Spec.InitContainers {
+		initContainersInPod[pod.Spec.InitContainers[i].Name] = &pod.Spec.InitContainers[i]
+	}
+	var names []string
+	for _, sidecar := range GetNativeSidecarInitContainers(sidecarSet) {
+		if IsHotUpgradeContainer(&sidecar) {
+			names = append(names, sidecar.Name)
+			continue
+		}
+		if container, ok := initContainersInPod[sidecar.Name]; ok && !IsSidecarContainer(*container) {
+			names = append(names, sidecar.Name)
+		}
+	}
+	return names
+}
+
+// GetPodSidecarContainerStatuses returns the statuses of both initContainers and containers in pod,
+// the statuses of native sidecar initContainers are in the pod.status.initContainerStatuses.
+func GetPodSidecarContainerStatuses(pod *corev1.Pod) []corev1.ContainerStatus {
+	statuses := make([]corev1.ContainerStatus, 0, len(pod.Status.InitContainerStatuses)+len(pod.Status.ContainerStatuses))
+	statuses = append(statuses, pod.Status.InitContainerStatuses...)
+	return append(statuses, pod.Status.ContainerStatuses...)
+}
+
 // listSidecarNameInSidecarSet list always init containers and sidecar containers
 func listSidecarNameInSidecarSet(sidecarSet *appsv1alpha1.SidecarSet) sets.String {
 	sidecarList := sets.NewString()
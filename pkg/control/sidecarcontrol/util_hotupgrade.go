@@ -25,6 +25,7 @@ import (
 	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 )
@@ -32,6 +33,8 @@ import (
 const (
 	// SidecarSetWorkingHotUpgradeContainer records which hot upgrade container is working currently
 	SidecarSetWorkingHotUpgradeContainer = "kruise.io/sidecarset-working-hotupgrade-container"
+	// SidecarSetHotUpgradeHandoffContainer records the progress of handing off the listeners to hot upgrade container by the handoff hook
+	SidecarSetHotUpgradeHandoffContainer = "kruise.io/sidecarset-hotupgrade-handoff-container"
 
 	// hotUpgrade container name suffix
 	hotUpgradeNameSuffix1 = "-1"
@@ -81,6 +84,40 @@ func GetPodHotUpgradeInfoInAnnotations(pod *corev1.Pod) map[string]string {
 	return hotUpgradeWorkContainer
 }
 
+// HotUpgradeHandoffPhase is the phase of the handoff hook called on the old hot upgrade sidecar container
+type HotUpgradeHandoffPhase string
+
+const (
+	HotUpgradeHandoffRunning   HotUpgradeHandoffPhase = "Running"
+	HotUpgradeHandoffSucceeded HotUpgradeHandoffPhase = "Succeeded"
+	HotUpgradeHandoffFailed    HotUpgradeHandoffPhase = "Failed"
+)
+
+// HotUpgradeHandoffState records the progress of handing off the listeners to the hot upgrade sidecar container
+type HotUpgradeHandoffState struct {
+	// Container is the hot upgrade sidecar container which the listeners are handed off to
+	Container string                 `json:"container"`
+	Phase     HotUpgradeHandoffPhase `json:"phase"`
+	Message   string                 `json:"message,omitempty"`
+	// LastTransitionTime is the last time the phase changed
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
+}
+
+// GetPodHotUpgradeHandoffInAnnotations checks the handoff progress of hot upgrade sidecar containers
+// format: sidecarset.spec.container[x].name -> HotUpgradeHandoffState
+func GetPodHotUpgradeHandoffInAnnotations(pod *corev1.Pod) map[string]HotUpgradeHandoffState {
+	handoffStates := make(map[string]HotUpgradeHandoffState)
+	currentStr, ok := pod.Annotations[SidecarSetHotUpgradeHandoffContainer]
+	if !ok {
+		return handoffStates
+	}
+	if err := json.Unmarshal([]byte(currentStr), &handoffStates); err != nil {
+		klog.ErrorS(err, "Failed to parse pod annotations value failed", "pod", klog.KObj(pod),
+			"annotations", SidecarSetHotUpgradeHandoffContainer, "value", currentStr)
+	}
+	return handoffStates
+}
+
 // GetPodHotUpgradeContainers return two hot upgrade sidecar containers
 // workContainer: currently working sidecar container, record in pod annotations[kruise.io/sidecarset-working-hotupgrade-container]
 // otherContainer:
@@ -0,0 +1,128 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sidecarcontrol
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+)
+
+// GetSidecarResources returns the resources of the sidecar container calculated by its ResourcePolicy,
+// the resources that are not derived from the pod are kept as in the sidecar container.
+// If the calculated request is larger than the limit of the same resource, the request is reduced to the limit.
+func GetSidecarResources(sidecarContainer *appsv1alpha1.SidecarContainer, pod *corev1.Pod) corev1.ResourceRequirements {
+	resources := *sidecarContainer.Resources.DeepCopy()
+	policy := sidecarContainer.ResourcePolicy
+	if policy == nil {
+		return resources
+	}
+	targets := getResourcePolicyTargetContainers(policy, pod)
+
+	for name, rule := range policy.Limits {
+		if q, ok := calculateSidecarResource(name, rule, policy.TargetContainerMode, targets, func(c *corev1.Container) corev1.ResourceList {
+			return c.Resources.Limits
+		}); ok {
+			if resources.Limits == nil {
+				resources.Limits = corev1.ResourceList{}
+			}
+			resources.Limits[name] = q
+		}
+	}
+	for name, rule := range policy.Requests {
+		if q, ok := calculateSidecarResource(name, rule, policy.TargetContainerMode, targets, func(c *corev1.Container) corev1.ResourceList {
+			return c.Resources.Requests
+		}); ok {
+			if resources.Requests == nil {
+				resources.Requests = corev1.ResourceList{}
+			}
+			resources.Requests[name] = q
+		}
+	}
+	for name, request := range resources.Requests {
+		if limit, ok := resources.Limits[name]; ok && request.Cmp(limit) > 0 {
+			resources.Requests[name] = limit.DeepCopy()
+		}
+	}
+	return resources
+}
+
+// getResourcePolicyTargetContainers returns the pod containers that the sidecar resources are derived from,
+// the injected sidecar containers are always excluded.
+func getResourcePolicyTargetContainers(policy *appsv1alpha1.SidecarResourcePolicy, pod *corev1.Pod) []*corev1.Container {
+	names := sets.NewString(policy.TargetContainerNames...)
+	var targets []*corev1.Container
+	for i := range pod.Spec.Containers {
+		container := &pod.Spec.Containers[i]
+		if IsInjectedSidecarContainerInPod(container) {
+			continue
+		}
+		if names.Len() > 0 && !names.Has(container.Name) {
+			continue
+		}
+		targets = append(targets, container)
+	}
+	return targets
+}
+
+// calculateSidecarResource aggregates the resource of the target containers, scales it by the percent and clamps
+// it into [min, max]. The returned bool is false if the resource should not be set.
+func calculateSidecarResource(name corev1.ResourceName, rule appsv1alpha1.SidecarResourceRule, mode appsv1alpha1.SidecarResourceTargetContainerMode,
+	targets []*corev1.Container, getResourceList func(*corev1.Container) corev1.ResourceList) (resource.Quantity, bool) {
+
+	var total resource.Quantity
+	var found bool
+	for _, container := range targets {
+		q, ok := getResourceList(container)[name]
+		if !ok {
+			continue
+		}
+		found = true
+		if mode == appsv1alpha1.SidecarResourceTargetContainerModeMax {
+			if q.Cmp(total) > 0 {
+				total = q.DeepCopy()
+			}
+		} else {
+			total.Add(q)
+		}
+	}
+	if !found {
+		if rule.Min != nil {
+			return rule.Min.DeepCopy(), true
+		}
+		return resource.Quantity{}, false
+	}
+
+	result := scaleQuantity(name, total, rule.Percent)
+	if rule.Min != nil && result.Cmp(*rule.Min) < 0 {
+		result = rule.Min.DeepCopy()
+	}
+	if rule.Max != nil && result.Cmp(*rule.Max) > 0 {
+		result = rule.Max.DeepCopy()
+	}
+	return result, true
+}
+
+// scaleQuantity returns percent% of the quantity, cpu is calculated in milli-cores and the others in units.
+func scaleQuantity(name corev1.ResourceName, q resource.Quantity, percent int32) resource.Quantity {
+	if name == corev1.ResourceCPU {
+		return *resource.NewMilliQuantity(q.MilliValue()*int64(percent)/100, resource.DecimalSI)
+	}
+	return *resource.NewQuantity(q.Value()*int64(percent)/100, q.Format)
+}
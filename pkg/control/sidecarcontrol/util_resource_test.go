@@ -0,0 +1,153 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sidecarcontrol
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
+
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+)
+
+func TestGetSidecarResources(t *testing.T) {
+	newContainer := func(name, cpuRequest, memoryRequest, cpuLimit string) corev1.Container {
+		container := corev1.Container{Name: name, Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{}, Limits: corev1.ResourceList{},
+		}}
+		if cpuRequest != "" {
+			container.Resources.Requests[corev1.ResourceCPU] = resource.MustParse(cpuRequest)
+		}
+		if memoryRequest != "" {
+			container.Resources.Requests[corev1.ResourceMemory] = resource.MustParse(memoryRequest)
+		}
+		if cpuLimit != "" {
+			container.Resources.Limits[corev1.ResourceCPU] = resource.MustParse(cpuLimit)
+		}
+		return container
+	}
+	injectedSidecar := newContainer("sidecar", "8", "8Gi", "")
+	injectedSidecar.Env = []corev1.EnvVar{{Name: SidecarEnvKey, Value: "true"}}
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				newContainer("main", "2", "4Gi", "4"),
+				newContainer("helper", "1", "1Gi", ""),
+				injectedSidecar,
+			},
+		},
+	}
+
+	cases := []struct {
+		name      string
+		resources corev1.ResourceRequirements
+		policy    *appsv1alpha1.SidecarResourcePolicy
+		expected  corev1.ResourceRequirements
+	}{
+		{
+			name: "no resource policy",
+			resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+			},
+			expected: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+			},
+		},
+		{
+			name: "sum of all the containers except sidecars",
+			policy: &appsv1alpha1.SidecarResourcePolicy{
+				Requests: map[corev1.ResourceName]appsv1alpha1.SidecarResourceRule{
+					corev1.ResourceCPU:    {Percent: 10},
+					corev1.ResourceMemory: {Percent: 20},
+				},
+			},
+			expected: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("300m"),
+					corev1.ResourceMemory: *resource.NewQuantity(1073741824, resource.BinarySI),
+				},
+			},
+		},
+		{
+			name: "max of the target containers with clamps",
+			policy: &appsv1alpha1.SidecarResourcePolicy{
+				TargetContainerMode: appsv1alpha1.SidecarResourceTargetContainerModeMax,
+				Requests: map[corev1.ResourceName]appsv1alpha1.SidecarResourceRule{
+					corev1.ResourceCPU:    {Percent: 1, Min: ptr.To(resource.MustParse("100m"))},
+					corev1.ResourceMemory: {Percent: 50, Max: ptr.To(resource.MustParse("1Gi"))},
+				},
+			},
+			expected: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("100m"),
+					corev1.ResourceMemory: resource.MustParse("1Gi"),
+				},
+			},
+		},
+		{
+			name: "target container without the resource",
+			resources: corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+			},
+			policy: &appsv1alpha1.SidecarResourcePolicy{
+				TargetContainerNames: []string{"helper"},
+				Limits: map[corev1.ResourceName]appsv1alpha1.SidecarResourceRule{
+					corev1.ResourceCPU:    {Percent: 10, Min: ptr.To(resource.MustParse("200m"))},
+					corev1.ResourceMemory: {Percent: 10},
+				},
+			},
+			expected: corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("200m"),
+					corev1.ResourceMemory: resource.MustParse("1Gi"),
+				},
+			},
+		},
+		{
+			name: "request is limited by the limit",
+			policy: &appsv1alpha1.SidecarResourcePolicy{
+				TargetContainerNames: []string{"main"},
+				Requests: map[corev1.ResourceName]appsv1alpha1.SidecarResourceRule{
+					corev1.ResourceCPU: {Percent: 50},
+				},
+				Limits: map[corev1.ResourceName]appsv1alpha1.SidecarResourceRule{
+					corev1.ResourceCPU: {Percent: 10},
+				},
+			},
+			expected: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("400m")},
+				Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("400m")},
+			},
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			sidecar := &appsv1alpha1.SidecarContainer{
+				Container:      corev1.Container{Name: "sidecar", Resources: cs.resources},
+				ResourcePolicy: cs.policy,
+			}
+			resources := GetSidecarResources(sidecar, pod)
+			if !apiequality.Semantic.DeepEqual(resources, cs.expected) {
+				t.Fatalf("expect resources %v, but got %v", cs.expected, resources)
+			}
+		})
+	}
+}
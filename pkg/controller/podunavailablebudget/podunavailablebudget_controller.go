@@ -20,8 +20,11 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/robfig/cron/v3"
 	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
@@ -209,6 +212,7 @@ type ReconcilePodUnavailableBudget struct {
 // +kubebuilder:rbac:groups=policy.kruise.io,resources=podunavailablebudgets/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=policy.kruise.io,resources=podunavailablebudgets/finalizers,verbs=update
 // +kubebuilder:rbac:groups=*,resources=*/scale,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
 
 // pkg/controller/cloneset/cloneset_controller.go Watch for changes to CloneSet
 func (r *ReconcilePodUnavailableBudget) Reconcile(_ context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -265,11 +269,21 @@ func (r *ReconcilePodUnavailableBudget) syncPodUnavailableBudget(pub *policyv1al
 	}
 
 	klog.V(3).InfoS("PodUnavailableBudget controller pods expectedCount", "podUnavailableBudget", klog.KObj(pub), "podCount", len(pods), "expectedCount", expectedCount)
-	desiredAvailable, err := r.getDesiredAvailableForPub(pub, expectedCount)
+	activeBudget, scheduleRecheckTime := getActiveScheduledBudget(pub, currentTime)
+	desiredAvailable, err := r.getDesiredAvailableForPub(pub, activeBudget, expectedCount)
 	if err != nil {
 		r.recorder.Eventf(pub, corev1.EventTypeWarning, "CalculateExpectedPodCountFailed", "Failed to calculate the number of expected pods: %v", err)
 		return nil, err
 	}
+	var activeBudgetName string
+	if activeBudget != nil {
+		activeBudgetName = activeBudget.Name
+	}
+
+	podDomains, err := r.getPodTopologyDomains(pub, pods)
+	if err != nil {
+		return nil, err
+	}
 
 	// for debug
 	var conflictTimes int
@@ -318,10 +332,14 @@ func (r *ReconcilePodUnavailableBudget) syncPodUnavailableBudget(pub *policyv1al
 		// unavailablePods contains information about pods whose specification changed(in-place update), in case of informer cache latency, after 5 seconds to remove it.
 		var disruptedPods, unavailablePods map[string]metav1.Time
 		disruptedPods, unavailablePods, recheckTime = r.buildDisruptedAndUnavailablePods(pods, pubClone, currentTime)
-		currentAvailable := countAvailablePods(pods, disruptedPods, unavailablePods)
+		currentAvailable := countAvailablePods(pubClone, pods, disruptedPods, unavailablePods)
+		topologyStatuses, err := calculateTopologyStatuses(pubClone, pods, podDomains, disruptedPods, unavailablePods)
+		if err != nil {
+			return err
+		}
 
 		start = time.Now()
-		updateErr := r.updatePubStatus(pubClone, currentAvailable, desiredAvailable, expectedCount, disruptedPods, unavailablePods)
+		updateErr := r.updatePubStatus(pubClone, currentAvailable, desiredAvailable, expectedCount, disruptedPods, unavailablePods, topologyStatuses, activeBudgetName)
 		costOfUpdate += time.Since(start)
 		if updateErr == nil {
 			return nil
@@ -336,6 +354,10 @@ func (r *ReconcilePodUnavailableBudget) syncPodUnavailableBudget(pub *policyv1al
 	if err != nil {
 		klog.ErrorS(err, "Failed to update PodUnavailableBudget status", "podUnavailableBudget", klog.KObj(pub))
 	}
+	// recheck when the active scheduled budget may change
+	if scheduleRecheckTime != nil && (recheckTime == nil || scheduleRecheckTime.Before(*recheckTime)) {
+		recheckTime = scheduleRecheckTime
+	}
 	return recheckTime, err
 }
 
@@ -360,7 +382,21 @@ func (r *ReconcilePodUnavailableBudget) patchRelatedPubAnnotationInPod(pub *poli
 	return nil
 }
 
-func countAvailablePods(pods []*corev1.Pod, disruptedPods, unavailablePods map[string]metav1.Time) (currentAvailable int32) {
+func countAvailablePods(pub *policyv1alpha1.PodUnavailableBudget, pods []*corev1.Pod, disruptedPods, unavailablePods map[string]metav1.Time) (currentAvailable int32) {
+	recordPods := getRecordPods(disruptedPods, unavailablePods)
+	for _, pod := range pods {
+		if !kubecontroller.IsPodActive(pod) {
+			continue
+		}
+		if isPodAvailable(pub, pod, recordPods) {
+			currentAvailable++
+		}
+	}
+
+	return
+}
+
+func getRecordPods(disruptedPods, unavailablePods map[string]metav1.Time) sets.String {
 	recordPods := sets.String{}
 	for pName := range disruptedPods {
 		recordPods.Insert(pName)
@@ -368,28 +404,148 @@ func countAvailablePods(pods []*corev1.Pod, disruptedPods, unavailablePods map[s
 	for pName := range unavailablePods {
 		recordPods.Insert(pName)
 	}
+	return recordPods
+}
+
+func isPodAvailable(pub *policyv1alpha1.PodUnavailableBudget, pod *corev1.Pod, recordPods sets.String) bool {
+	// ignore disrupted or unavailable pods, where the Pod is considered unavailable
+	if recordPods.Has(pod.Name) {
+		return false
+	}
+	// pod consistent and ready
+	return pubcontrol.PubControl.IsPodStateConsistent(pod) && pubcontrol.PubControl.IsPodReady(pod, pub)
+}
 
+// getPodTopologyDomains returns the topology domains of the pods for pub.spec.topologyBudgets,
+// the pods not scheduled or on the node not found are not contained.
+func (r *ReconcilePodUnavailableBudget) getPodTopologyDomains(pub *policyv1alpha1.PodUnavailableBudget, pods []*corev1.Pod) (map[string]map[string]string, error) {
+	if len(pub.Spec.TopologyBudgets) == 0 {
+		return nil, nil
+	}
+	podDomains := make(map[string]map[string]string, len(pods))
+	nodeDomains := make(map[string]map[string]string)
 	for _, pod := range pods {
-		if !kubecontroller.IsPodActive(pod) {
+		if pod.Spec.NodeName == "" {
 			continue
 		}
-		// ignore disrupted or unavailable pods, where the Pod is considered unavailable
-		if recordPods.Has(pod.Name) {
+		domains, ok := nodeDomains[pod.Spec.NodeName]
+		if !ok {
+			node := &corev1.Node{}
+			if err := r.Get(context.TODO(), types.NamespacedName{Name: pod.Spec.NodeName}, node); err != nil {
+				if !errors.IsNotFound(err) {
+					return nil, err
+				}
+				node = nil
+			}
+			if node != nil {
+				domains = pubcontrol.GetTopologyDomains(pub, node)
+			}
+			nodeDomains[pod.Spec.NodeName] = domains
+		}
+		if domains != nil {
+			podDomains[pod.Name] = domains
+		}
+	}
+	return podDomains, nil
+}
+
+// calculateTopologyStatuses calculates the available and unavailable pods in each topology domain of pub.spec.topologyBudgets.
+func calculateTopologyStatuses(pub *policyv1alpha1.PodUnavailableBudget, pods []*corev1.Pod, podDomains map[string]map[string]string,
+	disruptedPods, unavailablePods map[string]metav1.Time) ([]policyv1alpha1.PodUnavailableBudgetTopologyStatus, error) {
+	if len(pub.Spec.TopologyBudgets) == 0 {
+		return nil, nil
+	}
+	recordPods := getRecordPods(disruptedPods, unavailablePods)
+	var topologyStatuses []policyv1alpha1.PodUnavailableBudgetTopologyStatus
+	for _, budget := range pub.Spec.TopologyBudgets {
+		statuses := map[string]*policyv1alpha1.PodUnavailableBudgetTopologyStatus{}
+		for _, pod := range pods {
+			if !kubecontroller.IsPodActive(pod) {
+				continue
+			}
+			value, ok := podDomains[pod.Name][budget.TopologyKey]
+			if !ok {
+				continue
+			}
+			status, ok := statuses[value]
+			if !ok {
+				status = &policyv1alpha1.PodUnavailableBudgetTopologyStatus{TopologyKey: budget.TopologyKey, Value: value}
+				statuses[value] = status
+			}
+			status.TotalReplicas++
+			if isPodAvailable(pub, pod, recordPods) {
+				status.CurrentAvailable++
+			}
+		}
+
+		values := make([]string, 0, len(statuses))
+		for value := range statuses {
+			values = append(values, value)
+		}
+		sort.Strings(values)
+		for _, value := range values {
+			status := statuses[value]
+			maxUnavailable, err := intstr.GetScaledValueFromIntOrPercent(&budget.MaxUnavailable, int(status.TotalReplicas), true)
+			if err != nil {
+				return nil, err
+			}
+			desiredAvailable := status.TotalReplicas - int32(maxUnavailable)
+			status.UnavailableAllowed = max(status.CurrentAvailable-max(desiredAvailable, 0), 0)
+			status.Unavailable = status.TotalReplicas - status.CurrentAvailable
+			topologyStatuses = append(topologyStatuses, *status)
+		}
+	}
+	return topologyStatuses, nil
+}
+
+// getActiveScheduledBudget returns the first scheduled budget whose time window contains now,
+// and the time when the active scheduled budget may change.
+func getActiveScheduledBudget(pub *policyv1alpha1.PodUnavailableBudget, now time.Time) (*policyv1alpha1.PodUnavailableBudgetScheduledBudget, *time.Time) {
+	var activeBudget *policyv1alpha1.PodUnavailableBudgetScheduledBudget
+	var recheckTime *time.Time
+	for i := range pub.Spec.ScheduledBudgets {
+		budget := &pub.Spec.ScheduledBudgets[i]
+		sched, err := cron.ParseStandard(formatSchedule(budget))
+		if err != nil {
+			klog.ErrorS(err, "Failed to parse schedule of PodUnavailableBudget", "podUnavailableBudget", klog.KObj(pub),
+				"scheduledBudget", budget.Name, "schedule", budget.Schedule)
 			continue
 		}
-		// pod consistent and ready
-		if pubcontrol.PubControl.IsPodStateConsistent(pod) && pubcontrol.PubControl.IsPodReady(pod) {
-			currentAvailable++
+		// the latest time window which starts after now-duration is active if it starts before now
+		start := sched.Next(now.Add(-budget.Duration.Duration))
+		changeTime := start
+		if !start.After(now) {
+			changeTime = start.Add(budget.Duration.Duration)
+			if activeBudget == nil {
+				activeBudget = budget
+			}
+		}
+		if recheckTime == nil || changeTime.Before(*recheckTime) {
+			recheckTime = &changeTime
 		}
 	}
+	return activeBudget, recheckTime
+}
 
-	return
+func formatSchedule(budget *policyv1alpha1.PodUnavailableBudgetScheduledBudget) string {
+	if strings.Contains(budget.Schedule, "TZ") {
+		return budget.Schedule
+	}
+	if budget.TimeZone != nil {
+		return fmt.Sprintf("TZ=%s %s", *budget.TimeZone, budget.Schedule)
+	}
+	return budget.Schedule
 }
 
-func (r *ReconcilePodUnavailableBudget) getDesiredAvailableForPub(pub *policyv1alpha1.PodUnavailableBudget, expectedCount int32) (desiredAvailable int32, err error) {
-	if pub.Spec.MaxUnavailable != nil {
+func (r *ReconcilePodUnavailableBudget) getDesiredAvailableForPub(pub *policyv1alpha1.PodUnavailableBudget,
+	activeBudget *policyv1alpha1.PodUnavailableBudgetScheduledBudget, expectedCount int32) (desiredAvailable int32, err error) {
+	maxUnavailableBudget, minAvailableBudget := pub.Spec.MaxUnavailable, pub.Spec.MinAvailable
+	if activeBudget != nil {
+		maxUnavailableBudget, minAvailableBudget = activeBudget.MaxUnavailable, activeBudget.MinAvailable
+	}
+	if maxUnavailableBudget != nil {
 		var maxUnavailable int
-		maxUnavailable, err = intstr.GetScaledValueFromIntOrPercent(pub.Spec.MaxUnavailable, int(expectedCount), true)
+		maxUnavailable, err = intstr.GetScaledValueFromIntOrPercent(maxUnavailableBudget, int(expectedCount), true)
 		if err != nil {
 			return
 		}
@@ -398,12 +554,12 @@ func (r *ReconcilePodUnavailableBudget) getDesiredAvailableForPub(pub *policyv1a
 		if desiredAvailable < 0 {
 			desiredAvailable = 0
 		}
-	} else if pub.Spec.MinAvailable != nil {
-		if pub.Spec.MinAvailable.Type == intstr.Int {
-			desiredAvailable = pub.Spec.MinAvailable.IntVal
-		} else if pub.Spec.MinAvailable.Type == intstr.String {
+	} else if minAvailableBudget != nil {
+		if minAvailableBudget.Type == intstr.Int {
+			desiredAvailable = minAvailableBudget.IntVal
+		} else if minAvailableBudget.Type == intstr.String {
 			var minAvailable int
-			minAvailable, err = intstr.GetScaledValueFromIntOrPercent(pub.Spec.MinAvailable, int(expectedCount), true)
+			minAvailable, err = intstr.GetScaledValueFromIntOrPercent(minAvailableBudget, int(expectedCount), true)
 			if err != nil {
 				return
 			}
@@ -467,12 +623,20 @@ func (r *ReconcilePodUnavailableBudget) buildDisruptedAndUnavailablePods(pods []
 }
 
 func (r *ReconcilePodUnavailableBudget) updatePubStatus(pub *policyv1alpha1.PodUnavailableBudget, currentAvailable, desiredAvailable, expectedCount int32,
-	disruptedPods, unavailablePods map[string]metav1.Time) error {
+	disruptedPods, unavailablePods map[string]metav1.Time, topologyStatuses []policyv1alpha1.PodUnavailableBudgetTopologyStatus,
+	activeScheduledBudget string) error {
 
 	unavailableAllowed := currentAvailable - desiredAvailable
 	if unavailableAllowed <= 0 {
 		unavailableAllowed = 0
 	}
+	// the operations denied in dryRun mode are recorded by webhook, and cleared once dryRun is disabled
+	var dryRunDeniedOperations map[policyv1alpha1.PubOperation]int32
+	var lastDryRunDeniedOperation *policyv1alpha1.PodUnavailableBudgetDeniedOperation
+	if pub.Spec.DryRun {
+		dryRunDeniedOperations = pub.Status.DryRunDeniedOperations
+		lastDryRunDeniedOperation = pub.Status.LastDryRunDeniedOperation
+	}
 
 	if pub.Status.CurrentAvailable == currentAvailable &&
 		pub.Status.DesiredAvailable == desiredAvailable &&
@@ -480,18 +644,26 @@ func (r *ReconcilePodUnavailableBudget) updatePubStatus(pub *policyv1alpha1.PodU
 		pub.Status.UnavailableAllowed == unavailableAllowed &&
 		pub.Status.ObservedGeneration == pub.Generation &&
 		apiequality.Semantic.DeepEqual(pub.Status.DisruptedPods, disruptedPods) &&
-		apiequality.Semantic.DeepEqual(pub.Status.UnavailablePods, unavailablePods) {
+		apiequality.Semantic.DeepEqual(pub.Status.UnavailablePods, unavailablePods) &&
+		apiequality.Semantic.DeepEqual(pub.Status.TopologyStatuses, topologyStatuses) &&
+		pub.Status.ActiveScheduledBudget == activeScheduledBudget &&
+		apiequality.Semantic.DeepEqual(pub.Status.DryRunDeniedOperations, dryRunDeniedOperations) &&
+		apiequality.Semantic.DeepEqual(pub.Status.LastDryRunDeniedOperation, lastDryRunDeniedOperation) {
 		return nil
 	}
 
 	pub.Status = policyv1alpha1.PodUnavailableBudgetStatus{
-		CurrentAvailable:   currentAvailable,
-		DesiredAvailable:   desiredAvailable,
-		TotalReplicas:      expectedCount,
-		UnavailableAllowed: unavailableAllowed,
-		DisruptedPods:      disruptedPods,
-		UnavailablePods:    unavailablePods,
-		ObservedGeneration: pub.Generation,
+		CurrentAvailable:          currentAvailable,
+		DesiredAvailable:          desiredAvailable,
+		TotalReplicas:             expectedCount,
+		UnavailableAllowed:        unavailableAllowed,
+		DisruptedPods:             disruptedPods,
+		UnavailablePods:           unavailablePods,
+		TopologyStatuses:          topologyStatuses,
+		ActiveScheduledBudget:     activeScheduledBudget,
+		DryRunDeniedOperations:    dryRunDeniedOperations,
+		LastDryRunDeniedOperation: lastDryRunDeniedOperation,
+		ObservedGeneration:        pub.Generation,
 	}
 	err := r.Client.Status().Update(context.TODO(), pub)
 	if err != nil {
@@ -1258,14 +1258,163 @@ func TestPubReconcile(t *testing.T) {
 				return *status
 			},
 		},
+		{
+			name: "select matched deployment(replicas=10), selector and maxUnavailable 30%, topologyBudgets zone maxUnavailable 1",
+			getPods: func(rs ...*apps.ReplicaSet) []*corev1.Pod {
+				var matchedPods []*corev1.Pod
+				for i := 0; int32(i) < 10; i++ {
+					pod := podDemo.DeepCopy()
+					pod.OwnerReferences = []metav1.OwnerReference{
+						{
+							APIVersion: "apps/v1",
+							Kind:       "ReplicaSet",
+							Name:       rs[0].Name,
+							UID:        rs[0].UID,
+							Controller: ptr.To(true),
+						},
+					}
+					pod.Name = fmt.Sprintf("%s-%d", pod.Name, i)
+					// pods 0-3 in zone-a, pods 4-7 in zone-b, pods 8-9 on the node without zone label
+					pod.Spec.NodeName = []string{"node-a", "node-b", "node-c"}[min(i/4, 2)]
+					matchedPods = append(matchedPods, pod)
+				}
+				return matchedPods
+			},
+			getDeployment: func() *apps.Deployment {
+				obj := deploymentDemo.DeepCopy()
+				obj.Spec.Replicas = utilpointer.Int32(10)
+				return obj
+			},
+			getReplicaSet: func() []*apps.ReplicaSet {
+				obj1 := replicaSetDemo.DeepCopy()
+				obj1.Name = "nginx-rs-1"
+				return []*apps.ReplicaSet{obj1}
+			},
+			getPub: func() *policyv1alpha1.PodUnavailableBudget {
+				pub := pubDemo.DeepCopy()
+				pub.Spec.TopologyBudgets = []policyv1alpha1.PodUnavailableBudgetTopologyBudget{
+					{TopologyKey: corev1.LabelTopologyZone, MaxUnavailable: intstr.FromInt32(1)},
+				}
+				pub.Status.UnavailablePods["test-pod-5"] = metav1.Now()
+				return pub
+			},
+			expectPubStatus: func() policyv1alpha1.PodUnavailableBudgetStatus {
+				return policyv1alpha1.PodUnavailableBudgetStatus{
+					UnavailablePods:    map[string]metav1.Time{"test-pod-5": metav1.Now()},
+					UnavailableAllowed: 2,
+					CurrentAvailable:   9,
+					DesiredAvailable:   7,
+					TotalReplicas:      10,
+					TopologyStatuses: []policyv1alpha1.PodUnavailableBudgetTopologyStatus{
+						{TopologyKey: corev1.LabelTopologyZone, Value: "zone-a", TotalReplicas: 4, CurrentAvailable: 4, UnavailableAllowed: 1},
+						{TopologyKey: corev1.LabelTopologyZone, Value: "zone-b", TotalReplicas: 4, CurrentAvailable: 3, Unavailable: 1},
+					},
+				}
+			},
+		},
+		{
+			name: "select matched deployment(replicas=2), dryRun keeps the denied operations in status",
+			getPods: func(rs ...*apps.ReplicaSet) []*corev1.Pod {
+				var matchedPods []*corev1.Pod
+				for i := 0; int32(i) < 2; i++ {
+					pod := podDemo.DeepCopy()
+					pod.OwnerReferences = []metav1.OwnerReference{
+						{
+							APIVersion: "apps/v1",
+							Kind:       "ReplicaSet",
+							Name:       rs[0].Name,
+							UID:        rs[0].UID,
+							Controller: ptr.To(true),
+						},
+					}
+					pod.Name = fmt.Sprintf("%s-%d", pod.Name, i)
+					matchedPods = append(matchedPods, pod)
+				}
+				return matchedPods
+			},
+			getDeployment: func() *apps.Deployment {
+				obj := deploymentDemo.DeepCopy()
+				obj.Spec.Replicas = utilpointer.Int32(2)
+				return obj
+			},
+			getReplicaSet: func() []*apps.ReplicaSet {
+				obj1 := replicaSetDemo.DeepCopy()
+				obj1.Name = "nginx-rs-1"
+				return []*apps.ReplicaSet{obj1}
+			},
+			getPub: func() *policyv1alpha1.PodUnavailableBudget {
+				pub := pubDemo.DeepCopy()
+				pub.Spec.DryRun = true
+				pub.Status.DryRunDeniedOperations = map[policyv1alpha1.PubOperation]int32{policyv1alpha1.PubEvictOperation: 1}
+				return pub
+			},
+			expectPubStatus: func() policyv1alpha1.PodUnavailableBudgetStatus {
+				return policyv1alpha1.PodUnavailableBudgetStatus{
+					CurrentAvailable:       2,
+					DesiredAvailable:       1,
+					TotalReplicas:          2,
+					UnavailableAllowed:     1,
+					DryRunDeniedOperations: map[policyv1alpha1.PubOperation]int32{policyv1alpha1.PubEvictOperation: 1},
+				}
+			},
+		},
+		{
+			name: "select matched deployment(replicas=2), the denied operations are cleared once dryRun is disabled",
+			getPods: func(rs ...*apps.ReplicaSet) []*corev1.Pod {
+				var matchedPods []*corev1.Pod
+				for i := 0; int32(i) < 2; i++ {
+					pod := podDemo.DeepCopy()
+					pod.OwnerReferences = []metav1.OwnerReference{
+						{
+							APIVersion: "apps/v1",
+							Kind:       "ReplicaSet",
+							Name:       rs[0].Name,
+							UID:        rs[0].UID,
+							Controller: ptr.To(true),
+						},
+					}
+					pod.Name = fmt.Sprintf("%s-%d", pod.Name, i)
+					matchedPods = append(matchedPods, pod)
+				}
+				return matchedPods
+			},
+			getDeployment: func() *apps.Deployment {
+				obj := deploymentDemo.DeepCopy()
+				obj.Spec.Replicas = utilpointer.Int32(2)
+				return obj
+			},
+			getReplicaSet: func() []*apps.ReplicaSet {
+				obj1 := replicaSetDemo.DeepCopy()
+				obj1.Name = "nginx-rs-1"
+				return []*apps.ReplicaSet{obj1}
+			},
+			getPub: func() *policyv1alpha1.PodUnavailableBudget {
+				pub := pubDemo.DeepCopy()
+				pub.Status.DryRunDeniedOperations = map[policyv1alpha1.PubOperation]int32{policyv1alpha1.PubEvictOperation: 1}
+				return pub
+			},
+			expectPubStatus: func() policyv1alpha1.PodUnavailableBudgetStatus {
+				return policyv1alpha1.PodUnavailableBudgetStatus{
+					CurrentAvailable:   2,
+					DesiredAvailable:   1,
+					TotalReplicas:      2,
+					UnavailableAllowed: 1,
+				}
+			},
+		},
 	}
 
+	nodes := []*corev1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-a", Labels: map[string]string{corev1.LabelTopologyZone: "zone-a"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-b", Labels: map[string]string{corev1.LabelTopologyZone: "zone-b"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-c"}},
+	}
 	for _, cs := range cases {
 		t.Run(cs.name, func(t *testing.T) {
 			pub := cs.getPub()
 			defer util.GlobalCache.Delete(pub)
 
-			builder := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cs.getDeployment(), pub)
+			builder := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cs.getDeployment(), pub, nodes[0], nodes[1], nodes[2])
 			builder.WithIndex(&corev1.Pod{}, fieldindex.IndexNameForOwnerRefUID, func(obj client.Object) []string {
 				var owners []string
 				for _, ref := range obj.GetOwnerReferences() {
@@ -1310,6 +1459,7 @@ func TestDesiredAvailableForPub(t *testing.T) {
 	cases := []struct {
 		name             string
 		getPub           func() *policyv1alpha1.PodUnavailableBudget
+		activeBudget     *policyv1alpha1.PodUnavailableBudgetScheduledBudget
 		totalReplicas    int32
 		desiredAvailable int32
 	}{
@@ -1326,12 +1476,40 @@ func TestDesiredAvailableForPub(t *testing.T) {
 			totalReplicas:    15,
 			desiredAvailable: 13,
 		},
+		{
+			name: "DesiredAvailableForPub, maxUnavailable 10%, active scheduled budget maxUnavailable 50%, total 15",
+			getPub: func() *policyv1alpha1.PodUnavailableBudget {
+				demo := pubDemo.DeepCopy()
+				demo.Spec.MaxUnavailable = &intstr.IntOrString{
+					Type:   intstr.String,
+					StrVal: "10%",
+				}
+				return demo
+			},
+			activeBudget:     &policyv1alpha1.PodUnavailableBudgetScheduledBudget{MaxUnavailable: ptr.To(intstr.FromString("50%"))},
+			totalReplicas:    15,
+			desiredAvailable: 7,
+		},
+		{
+			name: "DesiredAvailableForPub, maxUnavailable 10%, active scheduled budget minAvailable 5, total 15",
+			getPub: func() *policyv1alpha1.PodUnavailableBudget {
+				demo := pubDemo.DeepCopy()
+				demo.Spec.MaxUnavailable = &intstr.IntOrString{
+					Type:   intstr.String,
+					StrVal: "10%",
+				}
+				return demo
+			},
+			activeBudget:     &policyv1alpha1.PodUnavailableBudgetScheduledBudget{MinAvailable: ptr.To(intstr.FromInt32(5))},
+			totalReplicas:    15,
+			desiredAvailable: 5,
+		},
 	}
 
 	rec := ReconcilePodUnavailableBudget{}
 	for _, cs := range cases {
 		t.Run(cs.name, func(t *testing.T) {
-			expect, _ := rec.getDesiredAvailableForPub(cs.getPub(), cs.totalReplicas)
+			expect, _ := rec.getDesiredAvailableForPub(cs.getPub(), cs.activeBudget, cs.totalReplicas)
 			if expect != cs.desiredAvailable {
 				t.Fatalf("expect %d, but get %d", cs.desiredAvailable, expect)
 			}
@@ -1366,3 +1544,70 @@ func isPubStatusEqual(expectStatus, nowStatus policyv1alpha1.PodUnavailableBudge
 
 	return reflect.DeepEqual(expectStatus, nowStatus)
 }
+
+func TestGetActiveScheduledBudget(t *testing.T) {
+	// 2025-01-01 is Wednesday
+	now := time.Date(2025, 1, 1, 2, 30, 0, 0, time.UTC)
+	cases := []struct {
+		name              string
+		scheduledBudgets  []policyv1alpha1.PodUnavailableBudgetScheduledBudget
+		expectActive      string
+		expectRecheckTime *time.Time
+	}{
+		{
+			name: "no scheduled budget",
+		},
+		{
+			name: "in the time window",
+			scheduledBudgets: []policyv1alpha1.PodUnavailableBudgetScheduledBudget{
+				{Name: "nightly", Schedule: "0 1 * * *", TimeZone: ptr.To("UTC"), Duration: metav1.Duration{Duration: 2 * time.Hour}},
+			},
+			expectActive:      "nightly",
+			expectRecheckTime: ptr.To(time.Date(2025, 1, 1, 3, 0, 0, 0, time.UTC)),
+		},
+		{
+			name: "out of the time window",
+			scheduledBudgets: []policyv1alpha1.PodUnavailableBudgetScheduledBudget{
+				{Name: "nightly", Schedule: "0 1 * * *", TimeZone: ptr.To("UTC"), Duration: metav1.Duration{Duration: time.Hour}},
+			},
+			expectRecheckTime: ptr.To(time.Date(2025, 1, 2, 1, 0, 0, 0, time.UTC)),
+		},
+		{
+			name: "time zone of the schedule",
+			scheduledBudgets: []policyv1alpha1.PodUnavailableBudgetScheduledBudget{
+				{Name: "nightly", Schedule: "0 10 * * *", TimeZone: ptr.To("Asia/Shanghai"), Duration: metav1.Duration{Duration: time.Hour}},
+			},
+			expectActive:      "nightly",
+			expectRecheckTime: ptr.To(time.Date(2025, 1, 1, 3, 0, 0, 0, time.UTC)),
+		},
+		{
+			name: "the first active one takes effect",
+			scheduledBudgets: []policyv1alpha1.PodUnavailableBudgetScheduledBudget{
+				{Name: "weekend", Schedule: "0 0 * * 6", TimeZone: ptr.To("UTC"), Duration: metav1.Duration{Duration: 48 * time.Hour}},
+				{Name: "daily", Schedule: "CRON_TZ=UTC 0 2 * * *", Duration: metav1.Duration{Duration: 4 * time.Hour}},
+				{Name: "nightly", Schedule: "0 1 * * *", TimeZone: ptr.To("UTC"), Duration: metav1.Duration{Duration: 2 * time.Hour}},
+			},
+			expectActive:      "daily",
+			expectRecheckTime: ptr.To(time.Date(2025, 1, 1, 3, 0, 0, 0, time.UTC)),
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			pub := pubDemo.DeepCopy()
+			pub.Spec.ScheduledBudgets = cs.scheduledBudgets
+			active, recheckTime := getActiveScheduledBudget(pub, now)
+			var activeName string
+			if active != nil {
+				activeName = active.Name
+			}
+			if activeName != cs.expectActive {
+				t.Fatalf("expect active scheduled budget %q, but get %q", cs.expectActive, activeName)
+			}
+			if (recheckTime == nil) != (cs.expectRecheckTime == nil) ||
+				(recheckTime != nil && !recheckTime.Equal(*cs.expectRecheckTime)) {
+				t.Fatalf("expect recheck time %v, but get %v", cs.expectRecheckTime, recheckTime)
+			}
+		})
+	}
+}
@@ -173,8 +173,8 @@ func isPodAvailableChanged(oldPod, newPod *corev1.Pod, pub *policyv1alpha1.PodUn
 	// will move from the unready endpoints set to the ready endpoints.
 	// So for the purposes of an endpoint, a readiness change on a pod
 	// means we have a changed pod.
-	oldReady := control.IsPodReady(oldPod) && control.IsPodStateConsistent(oldPod)
-	newReady := control.IsPodReady(newPod) && control.IsPodStateConsistent(newPod)
+	oldReady := control.IsPodReady(oldPod, pub) && control.IsPodStateConsistent(oldPod)
+	newReady := control.IsPodReady(newPod, pub) && control.IsPodStateConsistent(newPod)
 	if oldReady != newReady {
 		klog.V(3).InfoS("Pod ConsistentAndReady changed, and reconcile PodUnavailableBudget", "pod", klog.KObj(newPod), "oldReady", oldReady,
 			"newReady", newReady, "podUnavailableBudget", klog.KObj(pub))
@@ -0,0 +1,201 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sidecarset
+
+import (
+	"context"
+	"time"
+
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+	policyv1alpha1 "github.com/openkruise/kruise/apis/policy/v1alpha1"
+	"github.com/openkruise/kruise/pkg/control/pubcontrol"
+	"github.com/openkruise/kruise/pkg/control/sidecarcontrol"
+	"github.com/openkruise/kruise/pkg/util/controllerfinder"
+)
+
+// deferredPodsRequeueDuration is the duration to requeue sidecarSet when some pods are deferred to upgrade
+const deferredPodsRequeueDuration = 10 * time.Second
+
+// availabilityAwareStrategy selects the pods to upgrade like spreadingStrategy, besides:
+//  1. the pods protected by PodUnavailableBudget are upgraded only when the budget allows,
+//  2. the pods whose CloneSet or StatefulSet is in rollout are deferred until the rollout completes,
+//
+// so that the sidecar upgrade and the app rollout never combine to breach the availability.
+type availabilityAwareStrategy struct {
+	client client.Client
+	// deferredPods are the pods that should be upgraded but deferred in this round
+	deferredPods []*corev1.Pod
+}
+
+func newAvailabilityAwareStrategy(c client.Client) *availabilityAwareStrategy {
+	return &availabilityAwareStrategy{client: c}
+}
+
+func (s *availabilityAwareStrategy) GetNextUpgradePods(control sidecarcontrol.SidecarControl, pods []*corev1.Pod) (upgradePods []*corev1.Pod, notUpgradablePods []*corev1.Pod) {
+	guard := &availabilityGuard{
+		client:            s.client,
+		workloadInRollout: map[types.UID]bool{},
+		pubBudgets:        map[types.NamespacedName]*pubBudget{},
+	}
+	upgradePods, notUpgradablePods = getNextUpgradePods(control, pods, guard.admit)
+	s.deferredPods = guard.deferredPods
+	return upgradePods, notUpgradablePods
+}
+
+type pubBudget struct {
+	pub       *policyv1alpha1.PodUnavailableBudget
+	remaining int32
+}
+
+// availabilityGuard decides whether the pod can be upgraded now without breaching the availability of its workload.
+type availabilityGuard struct {
+	client client.Client
+	// workload uid -> whether the workload is in rollout
+	workloadInRollout map[types.UID]bool
+	// pub namespace/name -> the remaining unavailable budget in this round, nil indicates no protection
+	pubBudgets   map[types.NamespacedName]*pubBudget
+	deferredPods []*corev1.Pod
+}
+
+func (g *availabilityGuard) admit(pod *corev1.Pod, podReady bool) bool {
+	if inRollout, err := g.isWorkloadInRollout(pod); err != nil {
+		klog.ErrorS(err, "Failed to check the workload rollout of pod", "pod", klog.KObj(pod))
+		g.deferredPods = append(g.deferredPods, pod)
+		return false
+	} else if inRollout {
+		klog.V(3).InfoS("Deferred sidecar upgrade of pod because its workload is in rollout", "pod", klog.KObj(pod))
+		g.deferredPods = append(g.deferredPods, pod)
+		return false
+	}
+
+	// not ready pods don't count towards the available pods of pub
+	if !podReady || pod.Annotations[policyv1alpha1.PodPubNoProtectionAnnotation] == "true" {
+		return true
+	}
+	budget, err := g.getPubBudget(pod)
+	if err != nil {
+		klog.ErrorS(err, "Failed to get the podUnavailableBudget of pod", "pod", klog.KObj(pod))
+		g.deferredPods = append(g.deferredPods, pod)
+		return false
+	} else if budget == nil || isPodInPubStatus(pod.Name, budget.pub) {
+		return true
+	}
+	if budget.remaining <= 0 {
+		klog.V(3).InfoS("Deferred sidecar upgrade of pod because of podUnavailableBudget", "pod", klog.KObj(pod), "pub", klog.KObj(budget.pub))
+		g.deferredPods = append(g.deferredPods, pod)
+		return false
+	}
+	budget.remaining--
+	return true
+}
+
+func (g *availabilityGuard) getPubBudget(pod *corev1.Pod) (*pubBudget, error) {
+	pubName := pod.Annotations[pubcontrol.PodRelatedPubAnnotation]
+	if pubName == "" {
+		return nil, nil
+	}
+	key := types.NamespacedName{Namespace: pod.Namespace, Name: pubName}
+	if budget, ok := g.pubBudgets[key]; ok {
+		return budget, nil
+	}
+	pub := &policyv1alpha1.PodUnavailableBudget{}
+	if err := g.client.Get(context.TODO(), key, pub); err != nil {
+		if errors.IsNotFound(err) {
+			g.pubBudgets[key] = nil
+			return nil, nil
+		}
+		return nil, err
+	}
+	var budget *pubBudget
+	// if desired available == 0, then allow all the pods
+	if pub.Status.DesiredAvailable > 0 {
+		budget = &pubBudget{pub: pub, remaining: pub.Status.UnavailableAllowed}
+	}
+	g.pubBudgets[key] = budget
+	return budget, nil
+}
+
+// isPodInPubStatus returns true if the pod has been counted as unavailable or disrupted in the pub
+func isPodInPubStatus(podName string, pub *policyv1alpha1.PodUnavailableBudget) bool {
+	if _, ok := pub.Status.DisruptedPods[podName]; ok {
+		return true
+	}
+	_, ok := pub.Status.UnavailablePods[podName]
+	return ok
+}
+
+// isWorkloadInRollout returns true if the CloneSet or StatefulSet owning the pod hasn't finished the rollout.
+func (g *availabilityGuard) isWorkloadInRollout(pod *corev1.Pod) (bool, error) {
+	ref := metav1.GetControllerOf(pod)
+	if ref == nil {
+		return false, nil
+	}
+	if inRollout, ok := g.workloadInRollout[ref.UID]; ok {
+		return inRollout, nil
+	}
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		return false, nil
+	}
+	key := types.NamespacedName{Namespace: pod.Namespace, Name: ref.Name}
+	var workload client.Object
+	var isInRollout func() bool
+	switch gv.WithKind(ref.Kind).GroupKind() {
+	case controllerfinder.ControllerKruiseKindCS.GroupKind():
+		cloneSet := &appsv1alpha1.CloneSet{}
+		workload = cloneSet
+		isInRollout = func() bool {
+			return cloneSet.Generation != cloneSet.Status.ObservedGeneration ||
+				cloneSet.Status.CurrentRevision != cloneSet.Status.UpdateRevision
+		}
+	case controllerfinder.ControllerKruiseKindSS.GroupKind():
+		statefulSet := &appsv1beta1.StatefulSet{}
+		workload = statefulSet
+		isInRollout = func() bool {
+			return statefulSet.Generation != statefulSet.Status.ObservedGeneration ||
+				statefulSet.Status.CurrentRevision != statefulSet.Status.UpdateRevision
+		}
+	case controllerfinder.ControllerKindSS.GroupKind():
+		statefulSet := &apps.StatefulSet{}
+		workload = statefulSet
+		isInRollout = func() bool {
+			return statefulSet.Generation != statefulSet.Status.ObservedGeneration ||
+				statefulSet.Status.CurrentRevision != statefulSet.Status.UpdateRevision
+		}
+	default:
+		return false, nil
+	}
+	if err = g.client.Get(context.TODO(), key, workload); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	inRollout := workload.GetUID() == ref.UID && isInRollout()
+	g.workloadInRollout[ref.UID] = inRollout
+	return inRollout, nil
+}
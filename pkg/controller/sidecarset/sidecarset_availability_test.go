@@ -0,0 +1,128 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sidecarset
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+	policyv1alpha1 "github.com/openkruise/kruise/apis/policy/v1alpha1"
+	"github.com/openkruise/kruise/pkg/control/pubcontrol"
+	"github.com/openkruise/kruise/pkg/control/sidecarcontrol"
+)
+
+func TestAvailabilityAwareStrategy(t *testing.T) {
+	testScheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(testScheme))
+	utilruntime.Must(appsv1alpha1.AddToScheme(testScheme))
+	utilruntime.Must(policyv1alpha1.AddToScheme(testScheme))
+
+	newCloneSet := func(name string, inRollout bool) *appsv1alpha1.CloneSet {
+		cs := &appsv1alpha1.CloneSet{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: types.UID("uid-" + name), Generation: 1},
+			Status: appsv1alpha1.CloneSetStatus{
+				ObservedGeneration: 1,
+				CurrentRevision:    "v1",
+				UpdateRevision:     "v1",
+			},
+		}
+		if inRollout {
+			cs.Status.UpdateRevision = "v2"
+		}
+		return cs
+	}
+	ownedBy := func(pod *corev1.Pod, cs *appsv1alpha1.CloneSet) {
+		pod.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(cs, appsv1alpha1.SchemeGroupVersion.WithKind("CloneSet"))}
+	}
+	pub := &policyv1alpha1.PodUnavailableBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: "pub", Namespace: "default"},
+		Status: policyv1alpha1.PodUnavailableBudgetStatus{
+			DesiredAvailable:   4,
+			UnavailableAllowed: 2,
+			UnavailablePods:    map[string]metav1.Time{"pod-5": metav1.Now()},
+		},
+	}
+
+	cases := []struct {
+		name          string
+		getPods       func(rolling, stable *appsv1alpha1.CloneSet) []*corev1.Pod
+		expectUpgrade int
+		expectDefer   int
+	}{
+		{
+			name: "pods of workload in rollout are deferred",
+			getPods: func(rolling, stable *appsv1alpha1.CloneSet) []*corev1.Pod {
+				pods := factoryPods(6, 0, 0)
+				for i := range pods {
+					if i < 4 {
+						ownedBy(pods[i], rolling)
+					} else {
+						ownedBy(pods[i], stable)
+					}
+				}
+				return pods
+			},
+			expectUpgrade: 2,
+			expectDefer:   4,
+		},
+		{
+			name: "pods protected by pub are limited by unavailableAllowed",
+			getPods: func(rolling, stable *appsv1alpha1.CloneSet) []*corev1.Pod {
+				pods := factoryPods(6, 0, 0)
+				for i := range pods {
+					ownedBy(pods[i], stable)
+					pods[i].Annotations[pubcontrol.PodRelatedPubAnnotation] = pub.Name
+				}
+				// pod-4 is not protected, pod-5 has been recorded in pub
+				pods[4].Annotations[policyv1alpha1.PodPubNoProtectionAnnotation] = "true"
+				return pods
+			},
+			expectUpgrade: 4,
+			expectDefer:   2,
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			rolling, stable := newCloneSet("rolling", true), newCloneSet("stable", false)
+			pods := cs.getPods(rolling, stable)
+			for _, pod := range pods {
+				pod.Namespace = "default"
+			}
+			fakeClient := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(rolling, stable, pub.DeepCopy()).Build()
+			sidecarSet := factorySidecarSet()
+			sidecarSet.Spec.UpdateStrategy.MaxUnavailable = ptr.To(intstr.FromInt32(10))
+
+			strategy := newAvailabilityAwareStrategy(fakeClient)
+			upgradePods, _ := strategy.GetNextUpgradePods(sidecarcontrol.New(sidecarSet), pods)
+			if len(upgradePods) != cs.expectUpgrade || len(strategy.deferredPods) != cs.expectDefer {
+				t.Fatalf("expect upgrade %d and defer %d pods, but got upgrade %d and defer %d",
+					cs.expectUpgrade, cs.expectDefer, len(upgradePods), len(strategy.deferredPods))
+			}
+		})
+	}
+}
@@ -34,6 +34,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+	kruiseclient "github.com/openkruise/kruise/pkg/client"
 	"github.com/openkruise/kruise/pkg/util"
 	utilclient "github.com/openkruise/kruise/pkg/util/client"
 	utildiscovery "github.com/openkruise/kruise/pkg/util/discovery"
@@ -67,10 +68,14 @@ func Add(mgr manager.Manager) error {
 func newReconciler(mgr manager.Manager) reconcile.Reconciler {
 	recorder := mgr.GetEventRecorderFor("sidecarset-controller")
 	cli := utilclient.NewClientFromManager(mgr, "sidecarset-controller")
+	processor := NewSidecarSetProcessor(cli, recorder)
+	if genericClient := kruiseclient.GetGenericClientWithName("sidecarset-controller"); genericClient != nil {
+		processor.hookRunner = newHandoffHookRunner(mgr.GetConfig(), genericClient.KubeClient)
+	}
 	return &ReconcileSidecarSet{
 		Client:    cli,
 		scheme:    mgr.GetScheme(),
-		processor: NewSidecarSetProcessor(cli, recorder),
+		processor: processor,
 	}
 }
 
@@ -121,6 +126,10 @@ type ReconcileSidecarSet struct {
 // +kubebuilder:rbac:groups=apps.kruise.io,resources=sidecarsets/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=apps.kruise.io,resources=sidecarsets/finalizers,verbs=update
 // +kubebuilder:rbac:groups=apps,resources=controllerrevisions,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=pods/exec,verbs=create
+// +kubebuilder:rbac:groups=policy.kruise.io,resources=podunavailablebudgets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps.kruise.io,resources=clonesets;statefulsets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch
 
 // Reconcile reads that state of the cluster for a SidecarSet object and makes changes based on the state read
 // and what is in the SidecarSet.Spec
@@ -0,0 +1,280 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sidecarset
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	appspub "github.com/openkruise/kruise/apis/apps/pub"
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+	"github.com/openkruise/kruise/pkg/control/sidecarcontrol"
+	"github.com/openkruise/kruise/pkg/util"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+)
+
+const (
+	defaultFailureRestartThreshold int32 = 3
+	defaultFailureNotReadySeconds  int32 = 300
+
+	// sidecarSetFailedReason is the reason of Failed condition when too many updated pods failed
+	sidecarSetFailedReason = "TooManyFailedPods"
+	// sidecarSetHealthyReason is the reason of Failed condition when the update is healthy
+	sidecarSetHealthyReason = "UpdateHealthy"
+)
+
+// calculateFailedPods returns the updated pods that failed according to the failure policy, and the duration after which
+// the not ready pods should be checked again.
+// Only the pods which are in-place updated from another revision are checked, newly created pods are always not failed.
+func calculateFailedPods(control sidecarcontrol.SidecarControl, pods []*corev1.Pod, now time.Time) (failedPods []*corev1.Pod, requeueAfter time.Duration) {
+	sidecarSet := control.GetSidecarset()
+	policy := sidecarSet.Spec.UpdateStrategy.FailurePolicy
+	if policy == nil {
+		return nil, 0
+	}
+	restartThreshold := defaultFailureRestartThreshold
+	if policy.RestartThreshold != nil {
+		restartThreshold = *policy.RestartThreshold
+	}
+	notReadyTimeout := time.Duration(defaultFailureNotReadySeconds) * time.Second
+	if policy.NotReadySeconds != nil {
+		notReadyTimeout = time.Duration(*policy.NotReadySeconds) * time.Second
+	}
+
+	sidecarContainers := sidecarcontrol.GetSidecarContainersInPod(sidecarSet)
+	for _, pod := range pods {
+		if !sidecarcontrol.IsPodSidecarUpdated(sidecarSet, pod) {
+			continue
+		}
+		upgradeSpec := sidecarcontrol.GetPodSidecarSetUpgradeSpecInAnnotations(sidecarSet.Name, sidecarcontrol.SidecarSetHashAnnotation, pod)
+		if upgradeSpec.PreviousControllerRevision == "" {
+			continue
+		}
+		// 1. sidecar containers restart after the in-place update
+		if restarts := getSidecarRestartsAfterUpdate(sidecarSet.Name, pod, sidecarContainers.List()); restarts >= restartThreshold {
+			klog.V(3).InfoS("SidecarSet updated pod failed because of sidecar restarts", "sidecarSet", klog.KObj(sidecarSet), "pod", klog.KObj(pod), "restarts", restarts)
+			failedPods = append(failedPods, pod)
+			continue
+		}
+		// 2. pod is not ready for a long time after the in-place update
+		if control.IsPodStateConsistent(pod, nil) && control.IsPodReady(pod) {
+			continue
+		}
+		if left := upgradeSpec.UpdateTimestamp.Add(notReadyTimeout).Sub(now); left > 0 {
+			if requeueAfter == 0 || left < requeueAfter {
+				requeueAfter = left
+			}
+			continue
+		}
+		klog.V(3).InfoS("SidecarSet updated pod failed because of not ready", "sidecarSet", klog.KObj(sidecarSet), "pod", klog.KObj(pod))
+		failedPods = append(failedPods, pod)
+	}
+	return failedPods, requeueAfter
+}
+
+// getSidecarRestartsAfterUpdate returns the max restarts of sidecar containers after the last in-place update,
+// the restart caused by the in-place update itself is excluded.
+func getSidecarRestartsAfterUpdate(sidecarSetName string, pod *corev1.Pod, sidecarContainers []string) int32 {
+	stateStr := pod.Annotations[sidecarcontrol.SidecarsetInplaceUpdateStateKey]
+	if stateStr == "" {
+		return 0
+	}
+	sidecarUpdateStates := make(map[string]*appspub.InPlaceUpdateState)
+	if err := json.Unmarshal([]byte(stateStr), &sidecarUpdateStates); err != nil {
+		klog.ErrorS(err, "Failed to parse pod annotations value", "pod", klog.KObj(pod),
+			"annotation", sidecarcontrol.SidecarsetInplaceUpdateStateKey, "value", stateStr)
+		return 0
+	}
+	state := sidecarUpdateStates[sidecarSetName]
+	if state == nil {
+		return 0
+	}
+
+	var maxRestarts int32
+	for _, name := range sidecarContainers {
+		lastStatus, ok := state.LastContainerStatuses[name]
+		if !ok {
+			continue
+		}
+		cStatus := util.GetContainerStatus(name, pod)
+		if cStatus == nil {
+			continue
+		}
+		restarts := cStatus.RestartCount - lastStatus.RestartCount
+		// the container is restarted once by kubelet when its image is updated
+		if cStatus.ImageID != lastStatus.ImageID {
+			restarts--
+		}
+		if restarts > maxRestarts {
+			maxRestarts = restarts
+		}
+	}
+	return maxRestarts
+}
+
+// calculateFailureThreshold returns the number of failed pods to stop the update.
+func calculateFailureThreshold(policy *appsv1alpha1.SidecarSetUpdateFailurePolicy, matchedPods int) int {
+	threshold := 1
+	if policy.FailureThreshold != nil {
+		threshold, _ = util.GetScaledValueFromIntOrPercent(policy.FailureThreshold, matchedPods, true)
+	}
+	if threshold < 1 {
+		threshold = 1
+	}
+	return threshold
+}
+
+// updateFailedCondition updates the Failed condition in the new status.
+// The Failed condition is kept once it becomes true, until the SidecarSet is changed to a new revision.
+func updateFailedCondition(sidecarSet *appsv1alpha1.SidecarSet, status *appsv1alpha1.SidecarSetStatus) (becomeFailed bool) {
+	status.Conditions = sidecarSet.Status.DeepCopy().Conditions
+	policy := sidecarSet.Spec.UpdateStrategy.FailurePolicy
+	if policy == nil {
+		removeSidecarSetCondition(status, appsv1alpha1.SidecarSetConditionFailed)
+		return false
+	}
+	oldCondition := getSidecarSetCondition(&sidecarSet.Status, appsv1alpha1.SidecarSetConditionFailed)
+	if oldCondition != nil && oldCondition.Status == corev1.ConditionTrue && sidecarSet.Status.LatestRevision == status.LatestRevision {
+		return false
+	}
+
+	threshold := calculateFailureThreshold(policy, int(status.MatchedPods))
+	if int(status.FailedPods) >= threshold {
+		setSidecarSetCondition(status, newSidecarSetCondition(appsv1alpha1.SidecarSetConditionFailed, corev1.ConditionTrue, sidecarSetFailedReason,
+			fmt.Sprintf("%d updated pod(s) of revision %s failed, reached the failure threshold %d", status.FailedPods, status.LatestRevision, threshold)))
+		return true
+	}
+	if oldCondition != nil {
+		setSidecarSetCondition(status, newSidecarSetCondition(appsv1alpha1.SidecarSetConditionFailed, corev1.ConditionFalse, sidecarSetHealthyReason, ""))
+	}
+	return false
+}
+
+// isSidecarSetUpdateFailed returns true if the update of the latest revision failed.
+func isSidecarSetUpdateFailed(status *appsv1alpha1.SidecarSetStatus) bool {
+	condition := getSidecarSetCondition(status, appsv1alpha1.SidecarSetConditionFailed)
+	return condition != nil && condition.Status == corev1.ConditionTrue
+}
+
+// rollbackPods reverts the sidecar containers of the failed pods to the revisions before the update.
+// The rollbacks are limited by maxUnavailable of the update strategy, but not by partition.
+func (p *Processor) rollbackPods(control sidecarcontrol.SidecarControl, pods, failedPods []*corev1.Pod) error {
+	sidecarSet := control.GetSidecarset()
+	hc := sidecarcontrol.NewHistoryControl(p.Client)
+	failedPodNames := sets.NewString()
+	for _, pod := range failedPods {
+		failedPodNames.Insert(pod.Name)
+	}
+	// revision name -> history sidecarSet control
+	historyControls := map[string]sidecarcontrol.SidecarControl{}
+	// pod index -> revision name to roll back
+	podRevisions := map[int]string{}
+	var waitRollbackIndexes []int
+	for index, pod := range pods {
+		if !failedPodNames.Has(pod.Name) {
+			continue
+		}
+		revision := sidecarcontrol.GetPodSidecarSetUpgradeSpecInAnnotations(sidecarSet.Name, sidecarcontrol.SidecarSetHashAnnotation, pod).PreviousControllerRevision
+		if revision == "" {
+			continue
+		}
+		historyControl, ok := historyControls[revision]
+		if !ok {
+			historySidecarSet, err := hc.GetHistorySidecarSet(sidecarSet, &appsv1alpha1.SidecarSetInjectRevision{RevisionName: &revision})
+			if err != nil || historySidecarSet == nil {
+				klog.ErrorS(err, "Failed to get history SidecarSet to roll back", "sidecarSet", klog.KObj(sidecarSet), "revision", revision)
+				continue
+			}
+			historyControl = sidecarcontrol.New(historySidecarSet)
+			historyControls[revision] = historyControl
+		}
+		// only image can be rolled back in-place
+		if canUpgrade, _ := historyControl.IsSidecarSetUpgradable(pod); !canUpgrade {
+			klog.InfoS("SidecarSet can not roll back pod in-place", "sidecarSet", klog.KObj(sidecarSet), "pod", klog.KObj(pod), "revision", revision)
+			continue
+		}
+		podRevisions[index] = revision
+		waitRollbackIndexes = append(waitRollbackIndexes, index)
+	}
+	waitRollbackIndexes = SortUpdateIndexes(sidecarSet.Spec.UpdateStrategy, pods, waitRollbackIndexes)
+	// partition is not applied, otherwise the failed pods within partition would never be rolled back
+	rollbackIndexes := limitIndexesByMaxUnavailable(control, waitRollbackIndexes, pods, nil)
+
+	for _, index := range rollbackIndexes {
+		pod, revision := pods[index], podRevisions[index]
+		if err := p.updatePodSidecarAndHash(historyControls[revision], pod); err != nil {
+			klog.ErrorS(err, "Failed to roll back pod sidecar", "sidecarSet", klog.KObj(sidecarSet), "pod", klog.KObj(pod), "revision", revision)
+			return err
+		}
+		klog.V(3).InfoS("SidecarSet rolled back pod", "sidecarSet", klog.KObj(sidecarSet), "pod", klog.KObj(pod), "revision", revision)
+	}
+	if len(rollbackIndexes) > 0 {
+		p.recorder.Eventf(sidecarSet, corev1.EventTypeWarning, "RollbackFailedPods", "SidecarSet rolled back %d of %d failed pod(s) to the previous revision",
+			len(rollbackIndexes), len(waitRollbackIndexes))
+	}
+	return nil
+}
+
+func newSidecarSetCondition(condType appsv1alpha1.SidecarSetConditionType, status corev1.ConditionStatus, reason, message string) *appsv1alpha1.SidecarSetCondition {
+	return &appsv1alpha1.SidecarSetCondition{
+		Type:               condType,
+		Status:             status,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reason,
+		Message:            message,
+	}
+}
+
+func getSidecarSetCondition(status *appsv1alpha1.SidecarSetStatus, condType appsv1alpha1.SidecarSetConditionType) *appsv1alpha1.SidecarSetCondition {
+	for i := range status.Conditions {
+		c := status.Conditions[i]
+		if c.Type == condType {
+			return &c
+		}
+	}
+	return nil
+}
+
+// setSidecarSetCondition updates the status to include the provided condition. If the condition that
+// we are about to add already exists and has the same status and reason then we are not going to update.
+func setSidecarSetCondition(status *appsv1alpha1.SidecarSetStatus, condition *appsv1alpha1.SidecarSetCondition) {
+	currentCond := getSidecarSetCondition(status, condition.Type)
+	if currentCond != nil && currentCond.Status == condition.Status && currentCond.Reason == condition.Reason {
+		return
+	}
+	if currentCond != nil && currentCond.Status == condition.Status {
+		condition.LastTransitionTime = currentCond.LastTransitionTime
+	}
+	removeSidecarSetCondition(status, condition.Type)
+	status.Conditions = append(status.Conditions, *condition)
+}
+
+func removeSidecarSetCondition(status *appsv1alpha1.SidecarSetStatus, condType appsv1alpha1.SidecarSetConditionType) {
+	var newConditions []appsv1alpha1.SidecarSetCondition
+	for _, c := range status.Conditions {
+		if c.Type == condType {
+			continue
+		}
+		newConditions = append(newConditions, c)
+	}
+	status.Conditions = newConditions
+}
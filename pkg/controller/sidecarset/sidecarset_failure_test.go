@@ -0,0 +1,292 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sidecarset
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+	"github.com/openkruise/kruise/pkg/control/sidecarcontrol"
+	webhookutil "github.com/openkruise/kruise/pkg/webhook/util"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// setPodPreviousRevision marks the pod as in-place updated from the previous revision at updateTime.
+func setPodPreviousRevision(pod *corev1.Pod, sidecarSetName, previous string, updateTime time.Time) {
+	hash := map[string]sidecarcontrol.SidecarSetUpgradeSpec{}
+	_ = json.Unmarshal([]byte(pod.Annotations[sidecarcontrol.SidecarSetHashAnnotation]), &hash)
+	spec := hash[sidecarSetName]
+	spec.PreviousControllerRevision = previous
+	spec.UpdateTimestamp = metav1.NewTime(updateTime)
+	hash[sidecarSetName] = spec
+	by, _ := json.Marshal(hash)
+	pod.Annotations[sidecarcontrol.SidecarSetHashAnnotation] = string(by)
+}
+
+func TestCalculateFailedPods(t *testing.T) {
+	// the update timestamp in annotations is in seconds
+	now := time.Now().Truncate(time.Second)
+	cases := []struct {
+		name              string
+		getPod            func() *corev1.Pod
+		expectFailed      bool
+		expectRequeueTime time.Duration
+	}{
+		{
+			name: "updated pod is healthy",
+			getPod: func() *corev1.Pod {
+				pod := factoryPods(1, 1, 1)[0]
+				setPodPreviousRevision(pod, "test-sidecarset", "revision-1", now.Add(-time.Hour))
+				return pod
+			},
+		},
+		{
+			name: "newly created pod is not ready",
+			getPod: func() *corev1.Pod {
+				pod := factoryPods(1, 1, 1)[0]
+				pod.Status.Conditions[0].Status = corev1.ConditionFalse
+				return pod
+			},
+		},
+		{
+			name: "updated pod is not ready for a long time",
+			getPod: func() *corev1.Pod {
+				pod := factoryPods(1, 1, 1)[0]
+				pod.Status.Conditions[0].Status = corev1.ConditionFalse
+				setPodPreviousRevision(pod, "test-sidecarset", "revision-1", now.Add(-time.Hour))
+				return pod
+			},
+			expectFailed: true,
+		},
+		{
+			name: "updated pod is not ready in a short time",
+			getPod: func() *corev1.Pod {
+				pod := factoryPods(1, 1, 1)[0]
+				pod.Status.Conditions[0].Status = corev1.ConditionFalse
+				setPodPreviousRevision(pod, "test-sidecarset", "revision-1", now.Add(-time.Minute))
+				return pod
+			},
+			expectRequeueTime: 4 * time.Minute,
+		},
+		{
+			name: "updated sidecar restarts too many times",
+			getPod: func() *corev1.Pod {
+				pod := factoryPods(1, 1, 1)[0]
+				setPodPreviousRevision(pod, "test-sidecarset", "revision-1", now)
+				// restarted once by in-place update, and 3 times by crash
+				pod.Status.ContainerStatuses[1].RestartCount = 4
+				return pod
+			},
+			expectFailed: true,
+		},
+		{
+			name: "updated sidecar restarts only by in-place update",
+			getPod: func() *corev1.Pod {
+				pod := factoryPods(1, 1, 1)[0]
+				setPodPreviousRevision(pod, "test-sidecarset", "revision-1", now)
+				pod.Status.ContainerStatuses[1].RestartCount = 1
+				return pod
+			},
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			sidecarSet := factorySidecarSet()
+			sidecarSet.Spec.UpdateStrategy.FailurePolicy = &appsv1alpha1.SidecarSetUpdateFailurePolicy{}
+			failedPods, requeueAfter := calculateFailedPods(sidecarcontrol.New(sidecarSet), []*corev1.Pod{cs.getPod()}, now)
+			if (len(failedPods) > 0) != cs.expectFailed {
+				t.Fatalf("expect failed(%v), but got failed pods %d", cs.expectFailed, len(failedPods))
+			}
+			if requeueAfter != cs.expectRequeueTime {
+				t.Fatalf("expect requeue after %v, but got %v", cs.expectRequeueTime, requeueAfter)
+			}
+		})
+	}
+}
+
+func TestUpdateFailedCondition(t *testing.T) {
+	cases := []struct {
+		name          string
+		getSidecarSet func() *appsv1alpha1.SidecarSet
+		failedPods    int32
+		latest        string
+		expectFailed  bool
+		expectEvent   bool
+	}{
+		{
+			name: "failed pods below the threshold",
+			getSidecarSet: func() *appsv1alpha1.SidecarSet {
+				sidecarSet := factorySidecarSet()
+				sidecarSet.Spec.UpdateStrategy.FailurePolicy = &appsv1alpha1.SidecarSetUpdateFailurePolicy{
+					FailureThreshold: ptr.To(intstr.FromString("10%")),
+				}
+				return sidecarSet
+			},
+			failedPods: 1,
+			latest:     "revision-2",
+		},
+		{
+			name: "failed pods reach the threshold",
+			getSidecarSet: func() *appsv1alpha1.SidecarSet {
+				sidecarSet := factorySidecarSet()
+				sidecarSet.Spec.UpdateStrategy.FailurePolicy = &appsv1alpha1.SidecarSetUpdateFailurePolicy{
+					FailureThreshold: ptr.To(intstr.FromString("10%")),
+				}
+				return sidecarSet
+			},
+			failedPods:   2,
+			latest:       "revision-2",
+			expectFailed: true,
+			expectEvent:  true,
+		},
+		{
+			name: "failed condition is kept in the same revision",
+			getSidecarSet: func() *appsv1alpha1.SidecarSet {
+				sidecarSet := factorySidecarSet()
+				sidecarSet.Spec.UpdateStrategy.FailurePolicy = &appsv1alpha1.SidecarSetUpdateFailurePolicy{}
+				sidecarSet.Status.LatestRevision = "revision-2"
+				sidecarSet.Status.Conditions = []appsv1alpha1.SidecarSetCondition{
+					{Type: appsv1alpha1.SidecarSetConditionFailed, Status: corev1.ConditionTrue, Reason: sidecarSetFailedReason},
+				}
+				return sidecarSet
+			},
+			latest:       "revision-2",
+			expectFailed: true,
+		},
+		{
+			name: "failed condition is reset in the new revision",
+			getSidecarSet: func() *appsv1alpha1.SidecarSet {
+				sidecarSet := factorySidecarSet()
+				sidecarSet.Spec.UpdateStrategy.FailurePolicy = &appsv1alpha1.SidecarSetUpdateFailurePolicy{}
+				sidecarSet.Status.LatestRevision = "revision-2"
+				sidecarSet.Status.Conditions = []appsv1alpha1.SidecarSetCondition{
+					{Type: appsv1alpha1.SidecarSetConditionFailed, Status: corev1.ConditionTrue, Reason: sidecarSetFailedReason},
+				}
+				return sidecarSet
+			},
+			latest: "revision-3",
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			sidecarSet := cs.getSidecarSet()
+			status := &appsv1alpha1.SidecarSetStatus{MatchedPods: 20, FailedPods: cs.failedPods, LatestRevision: cs.latest}
+			if becomeFailed := updateFailedCondition(sidecarSet, status); becomeFailed != cs.expectEvent {
+				t.Fatalf("expect become failed(%v), but got %v", cs.expectEvent, becomeFailed)
+			}
+			if isSidecarSetUpdateFailed(status) != cs.expectFailed {
+				t.Fatalf("expect failed(%v), but got conditions %v", cs.expectFailed, status.Conditions)
+			}
+		})
+	}
+}
+
+func TestRollbackFailedPods(t *testing.T) {
+	cases := []struct {
+		name           string
+		maxUnavailable *intstr.IntOrString
+		partition      *intstr.IntOrString
+		expectRollback int
+	}{
+		{
+			name:           "roll back one pod by default maxUnavailable",
+			expectRollback: 1,
+		},
+		{
+			name:           "roll back pods limited by maxUnavailable",
+			maxUnavailable: ptr.To(intstr.FromInt32(2)),
+			expectRollback: 2,
+		},
+		{
+			name:           "roll back pods regardless of partition",
+			maxUnavailable: ptr.To(intstr.FromInt32(2)),
+			partition:      ptr.To(intstr.FromInt32(2)),
+			expectRollback: 2,
+		},
+		{
+			name:           "roll back pods when partition covers all failed pods",
+			maxUnavailable: ptr.To(intstr.FromInt32(3)),
+			partition:      ptr.To(intstr.FromInt32(3)),
+			expectRollback: 3,
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			kubeSysNs := &corev1.Namespace{}
+			kubeSysNs.SetName(webhookutil.GetNamespace())
+			kubeSysNs.SetNamespace(webhookutil.GetNamespace())
+			oldSidecarSet := factorySidecarSet()
+			oldSidecarSet.SetUID("1223344")
+			oldSidecarSet.Annotations[sidecarcontrol.SidecarSetHashAnnotation] = "aaa"
+			oldSidecarSet.Spec.Containers[0].Image = "test-image:v1"
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(oldSidecarSet, kubeSysNs).Build()
+			processor := NewSidecarSetProcessor(fakeClient, record.NewFakeRecorder(10))
+			oldRevision, _, err := processor.registerLatestRevision(oldSidecarSet, nil)
+			if err != nil {
+				t.Fatalf("register old revision failed: %s", err.Error())
+			}
+
+			sidecarSet := factorySidecarSet()
+			sidecarSet.SetUID("1223344")
+			sidecarSet.Spec.UpdateStrategy.FailurePolicy = &appsv1alpha1.SidecarSetUpdateFailurePolicy{Rollback: true}
+			sidecarSet.Spec.UpdateStrategy.MaxUnavailable = cs.maxUnavailable
+			sidecarSet.Spec.UpdateStrategy.Partition = cs.partition
+			pods := factoryPods(3, 3, 3)
+			for _, pod := range pods {
+				pod.Namespace = "default"
+				setPodPreviousRevision(pod, sidecarSet.Name, oldRevision.Name, time.Now())
+				if err = fakeClient.Create(context.TODO(), pod); err != nil {
+					t.Fatalf("create pod failed: %s", err.Error())
+				}
+			}
+
+			if err = processor.rollbackPods(sidecarcontrol.New(sidecarSet), pods, pods); err != nil {
+				t.Fatalf("rollback pods failed: %s", err.Error())
+			}
+			var rollbackCount int
+			for _, pod := range pods {
+				podOutput, err := getLatestPod(fakeClient, pod)
+				if err != nil {
+					t.Fatalf("get latest pod failed: %s", err.Error())
+				}
+				if podOutput.Spec.Containers[1].Image != "test-image:v1" {
+					continue
+				}
+				rollbackCount++
+				upgradeSpec := sidecarcontrol.GetPodSidecarSetUpgradeSpecInAnnotations(sidecarSet.Name, sidecarcontrol.SidecarSetHashAnnotation, podOutput)
+				if upgradeSpec.SidecarSetHash != "aaa" || upgradeSpec.SidecarSetControllerRevision != oldRevision.Name {
+					t.Fatalf("expect pod sidecarSet hash(aaa) and revision(%s), but got %v", oldRevision.Name, upgradeSpec)
+				}
+			}
+			if rollbackCount != cs.expectRollback {
+				t.Fatalf("expect %d pods rolled back, but got %d", cs.expectRollback, rollbackCount)
+			}
+		})
+	}
+}
@@ -0,0 +1,285 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sidecarset
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/probe"
+	httpprobe "k8s.io/kubernetes/pkg/probe/http"
+
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+	"github.com/openkruise/kruise/pkg/control/sidecarcontrol"
+	"github.com/openkruise/kruise/pkg/features"
+	"github.com/openkruise/kruise/pkg/util"
+	utilfeature "github.com/openkruise/kruise/pkg/util/feature"
+)
+
+const (
+	defaultHandoffHookTimeoutSeconds = 30
+	// maxConcurrentHandoffHooks is the max number of handoff hooks running at the same time
+	maxConcurrentHandoffHooks = 10
+	// handoffRetryInterval is the interval to retry the failed handoff hook
+	handoffRetryInterval = 10 * time.Second
+	// handoffRequeueDuration is the interval to check the progress of the handoff hooks in running
+	handoffRequeueDuration = 2 * time.Second
+)
+
+// handoffHookRunner runs the handoff hook of hot upgrade in the container of pod.
+type handoffHookRunner interface {
+	Run(pod *corev1.Pod, container *corev1.Container, hook *appsv1alpha1.SidecarHandoffHook) error
+}
+
+type defaultHandoffHookRunner struct {
+	config     *rest.Config
+	kubeClient kubernetes.Interface
+	http       httpprobe.Prober
+}
+
+func newHandoffHookRunner(config *rest.Config, kubeClient kubernetes.Interface) handoffHookRunner {
+	return &defaultHandoffHookRunner{
+		config:     config,
+		kubeClient: kubeClient,
+		http:       httpprobe.New(false),
+	}
+}
+
+func (r *defaultHandoffHookRunner) Run(pod *corev1.Pod, container *corev1.Container, hook *appsv1alpha1.SidecarHandoffHook) error {
+	timeout := time.Duration(defaultHandoffHookTimeoutSeconds) * time.Second
+	if hook.TimeoutSeconds > 0 {
+		timeout = time.Duration(hook.TimeoutSeconds) * time.Second
+	}
+	switch {
+	case hook.Exec != nil:
+		if !utilfeature.DefaultFeatureGate.Enabled(features.SidecarSetHandoffHookExec) {
+			return fmt.Errorf("exec handoff hook requires the feature-gate %s to be enabled", features.SidecarSetHandoffHookExec)
+		}
+		return r.runExec(pod, container.Name, hook.Exec.Command, timeout)
+	case hook.HTTPGet != nil:
+		// always send the request to the pod IP, regardless of the host in hook
+		action := hook.HTTPGet.DeepCopy()
+		action.Host = ""
+		req, err := httpprobe.NewRequestForHTTPGetAction(action, container, pod.Status.PodIP, "sidecarset-handoff")
+		if err != nil {
+			return err
+		}
+		result, output, err := r.http.Probe(req, timeout)
+		if err != nil {
+			return err
+		}
+		if result != probe.Success && result != probe.Warning {
+			return fmt.Errorf("http handoff hook failed: %s", output)
+		}
+		return nil
+	}
+	return fmt.Errorf("missing handoff hook handler for container %s", container.Name)
+}
+
+func (r *defaultHandoffHookRunner) runExec(pod *corev1.Pod, containerName string, command []string, timeout time.Duration) error {
+	if r.kubeClient == nil {
+		return fmt.Errorf("kube client is not initialized")
+	}
+	req := r.kubeClient.CoreV1().RESTClient().Post().
+		Resource("pods").Namespace(pod.Namespace).Name(pod.Name).SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: containerName,
+			Command:   command,
+			Stdout:    true,
+			Stderr:    true,
+		}, clientgoscheme.ParameterCodec)
+	executor, err := remotecommand.NewSPDYExecutor(r.config, "POST", req.URL())
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.TODO(), timeout)
+	defer cancel()
+	var output bytes.Buffer
+	if err = executor.StreamWithContext(ctx, remotecommand.StreamOptions{Stdout: &output, Stderr: &output}); err != nil {
+		return fmt.Errorf("exec handoff hook failed: %v, output: %s", err, output.String())
+	}
+	return nil
+}
+
+// handoffWorkers runs the handoff hooks asynchronously with bounded concurrency,
+// so that the slow hooks won't block the reconciliation of SidecarSet.
+type handoffWorkers struct {
+	tokens chan struct{}
+	lock   sync.Mutex
+	// running records the keys of the hooks in running
+	running sets.Set[string]
+	wg      sync.WaitGroup
+}
+
+func newHandoffWorkers(concurrency int) *handoffWorkers {
+	return &handoffWorkers{
+		tokens:  make(chan struct{}, concurrency),
+		running: sets.New[string](),
+	}
+}
+
+func (w *handoffWorkers) isRunning(key string) bool {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.running.Has(key)
+}
+
+// tryRun runs fn in background if there is a free worker and the key is not in running.
+func (w *handoffWorkers) tryRun(key string, fn func()) bool {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.running.Has(key) {
+		return false
+	}
+	select {
+	case w.tokens <- struct{}{}:
+	default:
+		return false
+	}
+	w.running.Insert(key)
+	w.wg.Add(1)
+	go func() {
+		defer func() {
+			w.lock.Lock()
+			w.running.Delete(key)
+			w.lock.Unlock()
+			<-w.tokens
+			w.wg.Done()
+		}()
+		fn()
+	}()
+	return true
+}
+
+// handoffHotUpgradingContainers starts the handoff hooks on the old sidecar containers in hot upgrading in background,
+// once the new sidecar containers are running. It returns handedOff=true if all the listeners have been handed off,
+// then the old sidecar containers can be reset to empty after the new ones are ready; it returns pending=true if
+// some hooks are running or waiting to retry, then the SidecarSet should be requeued to check the progress.
+func (p *Processor) handoffHotUpgradingContainers(control sidecarcontrol.SidecarControl, pod *corev1.Pod) (handedOff, pending bool) {
+	sidecarSet := control.GetSidecarset()
+	handoffStates := sidecarcontrol.GetPodHotUpgradeHandoffInAnnotations(pod)
+	containerStatuses := make(map[string]*corev1.ContainerStatus, len(pod.Status.ContainerStatuses))
+	for i := range pod.Status.ContainerStatuses {
+		containerStatuses[pod.Status.ContainerStatuses[i].Name] = &pod.Status.ContainerStatuses[i]
+	}
+
+	handedOff = true
+	for i := range sidecarSet.Spec.Containers {
+		sidecarContainer := &sidecarSet.Spec.Containers[i]
+		hook := sidecarContainer.UpgradeStrategy.HotUpgradeHandoffHook
+		if !sidecarcontrol.IsHotUpgradeContainer(sidecarContainer) || hook == nil {
+			continue
+		}
+		workContainer, oldContainer := sidecarcontrol.GetPodHotUpgradeContainers(sidecarContainer.Name, pod)
+		container := util.GetContainer(oldContainer, pod)
+		if container == nil || container.Image == sidecarContainer.UpgradeStrategy.HotUpgradeEmptyImage {
+			continue
+		}
+		state, ok := handoffStates[sidecarContainer.Name]
+		if ok && state.Container == workContainer && state.Phase == sidecarcontrol.HotUpgradeHandoffSucceeded {
+			continue
+		}
+		handedOff = false
+		// the listeners can only be handed off after the new sidecar container started
+		if status := containerStatuses[workContainer]; status == nil || status.State.Running == nil {
+			continue
+		}
+		pending = true
+		key := fmt.Sprintf("%s/%s/%s", pod.UID, sidecarContainer.Name, workContainer)
+		if p.handoffWorkers.isRunning(key) {
+			continue
+		}
+		// back off the failed hook before retrying
+		if ok && state.Container == workContainer && state.Phase == sidecarcontrol.HotUpgradeHandoffFailed &&
+			time.Since(state.LastTransitionTime.Time) < handoffRetryInterval {
+			continue
+		}
+		podCopy, sidecarName, hookContainer := pod.DeepCopy(), sidecarContainer.Name, container.DeepCopy()
+		if !p.handoffWorkers.tryRun(key, func() {
+			p.runHandoffHook(sidecarSet, podCopy, sidecarName, hookContainer, workContainer, hook)
+		}) {
+			klog.V(3).InfoS("SidecarSet handoff workers are busy, will retry later", "sidecarSet", klog.KObj(sidecarSet),
+				"pod", klog.KObj(pod), "container", oldContainer)
+		}
+	}
+	return handedOff, pending
+}
+
+// runHandoffHook calls the handoff hook on the old sidecar container, and records the progress in pod annotations.
+func (p *Processor) runHandoffHook(sidecarSet *appsv1alpha1.SidecarSet, pod *corev1.Pod, sidecarName string,
+	container *corev1.Container, workContainer string, hook *appsv1alpha1.SidecarHandoffHook) {
+	if err := p.updateHandoffState(pod, sidecarName, sidecarcontrol.HotUpgradeHandoffState{
+		Container: workContainer,
+		Phase:     sidecarcontrol.HotUpgradeHandoffRunning,
+	}); err != nil {
+		klog.ErrorS(err, "Failed to record sidecar handoff state", "pod", klog.KObj(pod), "container", container.Name)
+		return
+	}
+
+	state := sidecarcontrol.HotUpgradeHandoffState{
+		Container: workContainer,
+		Phase:     sidecarcontrol.HotUpgradeHandoffSucceeded,
+	}
+	if err := p.hookRunner.Run(pod, container, hook); err != nil {
+		state.Phase = sidecarcontrol.HotUpgradeHandoffFailed
+		state.Message = err.Error()
+		p.recorder.Eventf(pod, corev1.EventTypeWarning, "HandoffHookFailed",
+			"hand off sidecar container %s to %s failed: %s", container.Name, workContainer, err.Error())
+	} else {
+		klog.V(3).InfoS("SidecarSet handed off hot upgrade container", "sidecarSet", klog.KObj(sidecarSet), "pod", klog.KObj(pod),
+			"oldContainer", container.Name, "newContainer", workContainer)
+		p.recorder.Eventf(pod, corev1.EventTypeNormal, "HandoffHookSucceed", "hand off sidecar container %s to %s successfully", container.Name, workContainer)
+	}
+	if err := p.updateHandoffState(pod, sidecarName, state); err != nil {
+		klog.ErrorS(err, "Failed to record sidecar handoff state", "pod", klog.KObj(pod), "container", container.Name)
+	}
+}
+
+func (p *Processor) updateHandoffState(pod *corev1.Pod, sidecarName string, state sidecarcontrol.HotUpgradeHandoffState) error {
+	state.LastTransitionTime = metav1.Now()
+	podClone := &corev1.Pod{}
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		if err := p.Client.Get(context.TODO(), types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}, podClone); err != nil {
+			return err
+		}
+		if podClone.UID != pod.UID {
+			return nil
+		}
+		handoffStates := sidecarcontrol.GetPodHotUpgradeHandoffInAnnotations(podClone)
+		handoffStates[sidecarName] = state
+		by, _ := json.Marshal(handoffStates)
+		if podClone.Annotations == nil {
+			podClone.Annotations = map[string]string{}
+		}
+		podClone.Annotations[sidecarcontrol.SidecarSetHotUpgradeHandoffContainer] = string(by)
+		return p.Client.Update(context.TODO(), podClone)
+	})
+}
@@ -17,8 +17,13 @@ limitations under the License.
 package sidecarset
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"reflect"
+	"sync"
 	"testing"
+	"time"
 
 	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
 	"github.com/openkruise/kruise/pkg/control/sidecarcontrol"
@@ -295,3 +300,142 @@ func testUpdateHotUpgradeSidecar(t *testing.T, hotUpgradeEmptyImage string, side
 		})
 	}
 }
+
+type fakeHandoffHookRunner struct {
+	lock       sync.Mutex
+	containers []string
+	err        error
+}
+
+func (r *fakeHandoffHookRunner) Run(pod *corev1.Pod, container *corev1.Container, hook *appsv1alpha1.SidecarHandoffHook) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.containers = append(r.containers, container.Name)
+	return r.err
+}
+
+func TestHotUpgradeHandoffHook(t *testing.T) {
+	sidecarcontrol.UpdateExpectations.DeleteExpectations(sidecarSetHotUpgrade.Name)
+	sidecarSet := sidecarSetHotUpgrade.DeepCopy()
+	sidecarSet.Spec.Containers[0].UpgradeStrategy.HotUpgradeHandoffHook = &appsv1alpha1.SidecarHandoffHook{
+		Exec: &corev1.ExecAction{Command: []string{"/bin/sh", "-c", "handoff"}},
+	}
+	pod := podHotUpgrade.DeepCopy()
+	pod.UID = "test-pod-handoff"
+	defer sidecarcontrol.ResourceVersionExpectations.Delete(pod)
+	pod.Annotations[sidecarcontrol.SidecarSetHashAnnotation] = `{"test-sidecarset":{"hash":"bbb","sidecarList":["test-sidecar"]}}`
+	pod.Annotations[sidecarcontrol.SidecarSetWorkingHotUpgradeContainer] = `{"test-sidecar":"test-sidecar-2"}`
+	pod.Spec.Containers[2].Image = "test-image:v2"
+	pod.Status.ContainerStatuses[2].Image = "test-image:v2"
+	pod.Status.ContainerStatuses[2].ImageID = testImageV2ImageID
+	pod.Status.ContainerStatuses[2].Ready = false
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(sidecarSet, pod).
+		WithStatusSubresource(&appsv1alpha1.SidecarSet{}, &corev1.Pod{}).Build()
+	runner := &fakeHandoffHookRunner{}
+	processor := NewSidecarSetProcessor(fakeClient, record.NewFakeRecorder(10))
+	processor.hookRunner = runner
+
+	steps := []struct {
+		name               string
+		running            bool
+		ready              bool
+		hookErr            error
+		backdateFailure    bool
+		expectRequeue      bool
+		expectHookCalls    []string
+		expectHandoffPhase sidecarcontrol.HotUpgradeHandoffPhase
+		expectOlderSidecar string
+	}{
+		{
+			name:               "new sidecar container is not running",
+			expectOlderSidecar: "test-image:v1",
+		},
+		{
+			name:               "new sidecar container is running, and hand off failed",
+			running:            true,
+			hookErr:            fmt.Errorf("connection refused"),
+			expectRequeue:      true,
+			expectHookCalls:    []string{"test-sidecar-1"},
+			expectHandoffPhase: sidecarcontrol.HotUpgradeHandoffFailed,
+			expectOlderSidecar: "test-image:v1",
+		},
+		{
+			name:               "the failed hook is backed off",
+			running:            true,
+			expectRequeue:      true,
+			expectHookCalls:    []string{"test-sidecar-1"},
+			expectHandoffPhase: sidecarcontrol.HotUpgradeHandoffFailed,
+			expectOlderSidecar: "test-image:v1",
+		},
+		{
+			name:               "the failed hook is retried, and hand off the listeners",
+			running:            true,
+			backdateFailure:    true,
+			expectRequeue:      true,
+			expectHookCalls:    []string{"test-sidecar-1", "test-sidecar-1"},
+			expectHandoffPhase: sidecarcontrol.HotUpgradeHandoffSucceeded,
+			expectOlderSidecar: "test-image:v1",
+		},
+		{
+			name:               "new sidecar container is ready, and reset the older one",
+			running:            true,
+			ready:              true,
+			expectHookCalls:    []string{"test-sidecar-1", "test-sidecar-1"},
+			expectHandoffPhase: sidecarcontrol.HotUpgradeHandoffSucceeded,
+			expectOlderSidecar: hotUpgradeEmptyImage,
+		},
+	}
+	for _, step := range steps {
+		latest, err := getLatestPod(fakeClient, pod)
+		if err != nil {
+			t.Fatalf("get latest pod failed: %s", err.Error())
+		}
+		if step.backdateFailure {
+			states := sidecarcontrol.GetPodHotUpgradeHandoffInAnnotations(latest)
+			state := states["test-sidecar"]
+			state.LastTransitionTime = metav1.NewTime(time.Now().Add(-handoffRetryInterval))
+			states["test-sidecar"] = state
+			by, _ := json.Marshal(states)
+			latest.Annotations[sidecarcontrol.SidecarSetHotUpgradeHandoffContainer] = string(by)
+			if err = fakeClient.Update(context.TODO(), latest); err != nil {
+				t.Fatalf("update pod failed: %s", err.Error())
+			}
+		}
+		latest.Status.ContainerStatuses[2].Ready = step.ready
+		if step.running {
+			latest.Status.ContainerStatuses[2].State.Running = &corev1.ContainerStateRunning{}
+		}
+		if step.ready {
+			latest.Status.Conditions[0].Status = corev1.ConditionTrue
+		} else {
+			latest.Status.Conditions[0].Status = corev1.ConditionFalse
+		}
+		if err = fakeClient.Status().Update(context.TODO(), latest); err != nil {
+			t.Fatalf("update pod failed: %s", err.Error())
+		}
+		runner.err = step.hookErr
+		result, err := processor.UpdateSidecarSet(sidecarSet.DeepCopy())
+		if err != nil {
+			t.Fatalf("%s: processor update sidecarset failed: %s", step.name, err.Error())
+		}
+		// wait for the handoff hooks in background
+		processor.handoffWorkers.wg.Wait()
+		if step.expectRequeue && result.RequeueAfter != handoffRequeueDuration {
+			t.Fatalf("%s: expect requeue after %v, but got %v", step.name, handoffRequeueDuration, result.RequeueAfter)
+		}
+		podOutput, _ := getLatestPod(fakeClient, pod)
+		if !reflect.DeepEqual(runner.containers, step.expectHookCalls) {
+			t.Fatalf("%s: expect hook calls %v, but got %v", step.name, step.expectHookCalls, runner.containers)
+		}
+		state := sidecarcontrol.GetPodHotUpgradeHandoffInAnnotations(podOutput)["test-sidecar"]
+		if state.Phase != step.expectHandoffPhase {
+			t.Fatalf("%s: expect handoff phase %s, but got %s", step.name, step.expectHandoffPhase, state.Phase)
+		}
+		if step.expectHandoffPhase != "" && state.Container != "test-sidecar-2" {
+			t.Fatalf("%s: expect handoff to test-sidecar-2, but got %s", step.name, state.Container)
+		}
+		if image := util.GetContainer("test-sidecar-1", podOutput).Image; image != step.expectOlderSidecar {
+			t.Fatalf("%s: expect older sidecar image %s, but got %s", step.name, step.expectOlderSidecar, image)
+		}
+	}
+}
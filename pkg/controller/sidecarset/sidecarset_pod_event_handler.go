@@ -81,6 +81,14 @@ func (p *enqueueRequestForPod) addPod(q workqueue.TypedRateLimitingInterface[rec
 			},
 		})
 	}
+	// the sidecarSets skipped for conflicts are not injected, but their status need to be updated
+	for sidecarSetName := range sidecarcontrol.GetPodSidecarSetConflicts(pod) {
+		q.Add(reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name: sidecarSetName,
+			},
+		})
+	}
 }
 
 func (p *enqueueRequestForPod) updatePod(q workqueue.TypedRateLimitingInterface[reconcile.Request], old, cur runtime.Object) {
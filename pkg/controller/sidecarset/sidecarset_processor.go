@@ -20,14 +20,18 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"reflect"
+	"sort"
 	"strings"
 	"time"
 
 	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
 	"github.com/openkruise/kruise/pkg/control/sidecarcontrol"
 	controlutil "github.com/openkruise/kruise/pkg/controller/util"
+	"github.com/openkruise/kruise/pkg/features"
 	"github.com/openkruise/kruise/pkg/util"
 	utilclient "github.com/openkruise/kruise/pkg/util/client"
+	utilfeature "github.com/openkruise/kruise/pkg/util/feature"
 	"github.com/openkruise/kruise/pkg/util/fieldindex"
 	historyutil "github.com/openkruise/kruise/pkg/util/history"
 	webhookutil "github.com/openkruise/kruise/pkg/webhook/util"
@@ -54,6 +58,8 @@ type Processor struct {
 	Client            client.Client
 	recorder          record.EventRecorder
 	historyController history.Interface
+	hookRunner        handoffHookRunner
+	handoffWorkers    *handoffWorkers
 }
 
 func NewSidecarSetProcessor(cli client.Client, rec record.EventRecorder) *Processor {
@@ -61,6 +67,8 @@ func NewSidecarSetProcessor(cli client.Client, rec record.EventRecorder) *Proces
 		Client:            cli,
 		recorder:          rec,
 		historyController: historyutil.NewHistory(cli),
+		hookRunner:        newHandoffHookRunner(nil, nil),
+		handoffWorkers:    newHandoffWorkers(maxConcurrentHandoffHooks),
 	}
 }
 
@@ -71,7 +79,7 @@ func (p *Processor) UpdateSidecarSet(sidecarSet *appsv1alpha1.SidecarSet) (recon
 		return reconcile.Result{}, nil
 	}
 	// 1. get matching pods with the sidecarSet
-	pods, err := p.getMatchingPods(sidecarSet)
+	pods, conflictingSidecarSets, err := p.getMatchingPods(sidecarSet)
 	if err != nil {
 		klog.ErrorS(err, "SidecarSet get matching pods error", "sidecarSet", klog.KObj(sidecarSet))
 		return reconcile.Result{}, err
@@ -87,6 +95,23 @@ func (p *Processor) UpdateSidecarSet(sidecarSet *appsv1alpha1.SidecarSet) (recon
 
 	// 2. calculate SidecarSet status based on pod and revision information
 	status := calculateStatus(control, pods, latestRevision, collisionCount)
+	status.ConflictingSidecarSets = conflictingSidecarSets
+	// when the update is executed in waves, calculate the status of each wave
+	var wavePods [][]*corev1.Pod
+	currentWave := -1
+	if waves := sidecarSet.Spec.UpdateStrategy.Waves; len(waves) > 0 {
+		wavePods = p.groupPodsByWave(sidecarSet, pods)
+		status.WaveStatuses, currentWave = calculateWaveStatus(control, wavePods)
+		if currentWave >= 0 {
+			status.CurrentWave = waves[currentWave].Name
+		}
+	}
+	// check whether the updated pods failed according to the failure policy
+	failedPods, requeueAfter := calculateFailedPods(control, pods, time.Now())
+	status.FailedPods = int32(len(failedPods))
+	if updateFailedCondition(sidecarSet, status) {
+		p.recorder.Eventf(sidecarSet, corev1.EventTypeWarning, "UpdateFailed", "SidecarSet stopped updating pods because %d updated pod(s) failed", len(failedPods))
+	}
 	//update sidecarSet status in store
 	if err := p.updateSidecarSetStatus(sidecarSet, status); err != nil {
 		return reconcile.Result{}, err
@@ -119,14 +144,16 @@ func (p *Processor) UpdateSidecarSet(sidecarSet *appsv1alpha1.SidecarSet) (recon
 		return reconcile.Result{}, nil
 	}
 
-	// 5. If sidecar container hot upgrade complete, then set the other one(empty sidecar container) image to HotUpgradeEmptyImage
+	// 5. If sidecar container hot upgrade complete, then set the other one(empty sidecar container) image to HotUpgradeEmptyImage,
+	// it is also done when the update failed, otherwise the pods in hot upgrading will be stuck with both sidecar containers
 	if isSidecarSetHasHotUpgradeContainer(sidecarSet) {
 		var podsInHotUpgrading []*corev1.Pod
 		for _, pod := range pods {
 			// flip other hot sidecar container to empty, in the following:
 			// 1. the empty sidecar container image isn't equal HotUpgradeEmptyImage
 			// 2. all containers with exception of empty sidecar containers is updated and consistent
-			// 3. all containers with exception of empty sidecar containers is ready
+			// 3. the old sidecar containers have handed off the listeners to the new ones, if the handoff hook is set
+			// 4. all containers with exception of empty sidecar containers is ready
 
 			// don't contain sidecar empty containers
 			sidecarContainers := sidecarcontrol.GetSidecarContainersInPod(sidecarSet)
@@ -136,8 +163,18 @@ func (p *Processor) UpdateSidecarSet(sidecarSet *appsv1alpha1.SidecarSet) (recon
 					sidecarContainers.Delete(emptyContainer)
 				}
 			}
-			if isPodSidecarInHotUpgrading(sidecarSet, pod) && control.IsPodStateConsistent(pod, sidecarContainers) &&
-				isHotUpgradingReady(sidecarSet, pod) {
+			if !isPodSidecarInHotUpgrading(sidecarSet, pod) || !control.IsPodStateConsistent(pod, sidecarContainers) {
+				continue
+			}
+			// the handoff hooks run in background, requeue to check their progress
+			handedOff, pending := p.handoffHotUpgradingContainers(control, pod)
+			if pending && (requeueAfter == 0 || requeueAfter > handoffRequeueDuration) {
+				requeueAfter = handoffRequeueDuration
+			}
+			if !handedOff {
+				continue
+			}
+			if isHotUpgradingReady(sidecarSet, pod) {
 				podsInHotUpgrading = append(podsInHotUpgrading, pod)
 			}
 		}
@@ -145,31 +182,73 @@ func (p *Processor) UpdateSidecarSet(sidecarSet *appsv1alpha1.SidecarSet) (recon
 			if err := p.flipHotUpgradingContainers(control, podsInHotUpgrading); err != nil {
 				return reconcile.Result{}, err
 			}
-			return reconcile.Result{}, nil
+			return reconcile.Result{RequeueAfter: requeueAfter}, nil
+		}
+	}
+
+	// 6. stop updating pods when the update of latest revision failed, and roll back the failed pods if necessary
+	if isSidecarSetUpdateFailed(status) {
+		klog.V(3).InfoS("SidecarSet update failed, and will not update the remaining pods", "sidecarSet", klog.KObj(sidecarSet), "failedPods", len(failedPods))
+		if sidecarSet.Spec.UpdateStrategy.FailurePolicy.Rollback && len(failedPods) > 0 {
+			if err := p.rollbackPods(control, pods, failedPods); err != nil {
+				return reconcile.Result{}, err
+			}
 		}
+		return reconcile.Result{RequeueAfter: requeueAfter}, nil
 	}
 
-	// 6. sidecarset already updates all matched pods, then return
+	// 7. sidecarset already updates all matched pods, then return
 	if isSidecarSetUpdateFinish(status) {
 		klog.V(3).InfoS("SidecarSet matched pods were latest, and don't need update", "sidecarSet", klog.KObj(sidecarSet), "matchedPodCount", len(pods))
-		return reconcile.Result{}, nil
+		return reconcile.Result{RequeueAfter: requeueAfter}, nil
 	}
 
-	// 7. upgrade pod sidecar
-	if err := p.updatePods(control, pods); err != nil {
+	// 8. only upgrade the pods in current wave when the update is executed in waves
+	if waves := sidecarSet.Spec.UpdateStrategy.Waves; len(waves) > 0 {
+		if currentWave < 0 {
+			klog.V(3).InfoS("SidecarSet all waves were finished", "sidecarSet", klog.KObj(sidecarSet))
+			return reconcile.Result{RequeueAfter: requeueAfter}, nil
+		}
+		klog.V(3).InfoS("SidecarSet is updating wave", "sidecarSet", klog.KObj(sidecarSet), "wave", waves[currentWave].Name,
+			"wavePodCount", len(wavePods[currentWave]))
+		control = sidecarcontrol.New(newWaveSidecarSet(sidecarSet, &waves[currentWave]))
+		pods = wavePods[currentWave]
+	}
+
+	// 9. upgrade pod sidecar
+	deferred, err := p.updatePods(control, pods)
+	if err != nil {
 		return reconcile.Result{}, err
 	}
-	return reconcile.Result{}, nil
+	// the deferred pods will be upgraded when the workload rollout completes or the pub budget is released
+	if deferred && (requeueAfter == 0 || requeueAfter > deferredPodsRequeueDuration) {
+		requeueAfter = deferredPodsRequeueDuration
+	}
+	return reconcile.Result{RequeueAfter: requeueAfter}, nil
 }
 
-func (p *Processor) updatePods(control sidecarcontrol.SidecarControl, pods []*corev1.Pod) error {
+// updatePods upgrades the sidecar containers of the next pods selected by update strategy,
+// and returns true if some pods are deferred because of the availability of their workloads.
+func (p *Processor) updatePods(control sidecarcontrol.SidecarControl, pods []*corev1.Pod) (bool, error) {
 	sidecarset := control.GetSidecarset()
 	// compute next updated pods based on the sidecarset upgrade strategy
-	upgradePods, notUpgradablePods := NewStrategy().GetNextUpgradePods(control, pods)
+	strategy := NewStrategy()
+	var availabilityStrategy *availabilityAwareStrategy
+	if utilfeature.DefaultFeatureGate.Enabled(features.SidecarSetUpdateAvailabilityAware) {
+		availabilityStrategy = newAvailabilityAwareStrategy(p.Client)
+		strategy = availabilityStrategy
+	}
+	upgradePods, notUpgradablePods := strategy.GetNextUpgradePods(control, pods)
+	var deferred bool
+	if availabilityStrategy != nil && len(availabilityStrategy.deferredPods) > 0 {
+		deferred = true
+		p.recorder.Eventf(sidecarset, corev1.EventTypeNormal, "DeferredPods", "SidecarSet in-place update deferred %d pod(s) in this round "+
+			"because of PodUnavailableBudget or workload rollout.", len(availabilityStrategy.deferredPods))
+	}
 	for _, pod := range notUpgradablePods {
 		if err := p.updatePodSidecarSetUpgradableCondition(sidecarset, pod, false); err != nil {
 			klog.ErrorS(err, "Failed to update NotUpgradable PodCondition", "sidecarSet", klog.KObj(sidecarset), "pod", klog.KObj(pod))
-			return err
+			return deferred, err
 		}
 		// Since the pod sidecarSet hash is not updated here, it cannot be called ExpectUpdated
 		// TODO: add ResourceVersionExpectation instead of UpdateExpectations
@@ -180,7 +259,7 @@ func (p *Processor) updatePods(control sidecarcontrol.SidecarControl, pods []*co
 
 	if len(upgradePods) == 0 {
 		klog.V(3).InfoS("SidecarSet next update was nil, skip this round", "sidecarSet", klog.KObj(sidecarset))
-		return nil
+		return deferred, nil
 	}
 	// mark upgrade pods list
 	podNames := make([]string, 0, len(upgradePods))
@@ -189,13 +268,13 @@ func (p *Processor) updatePods(control sidecarcontrol.SidecarControl, pods []*co
 		podNames = append(podNames, pod.Name)
 		if err := p.updatePodSidecarAndHash(control, pod); err != nil {
 			klog.ErrorS(err, "UpdatePodSidecarAndHash error", "sidecarSet", klog.KObj(sidecarset), "pod", klog.KObj(pod))
-			return err
+			return deferred, err
 		}
 		sidecarcontrol.UpdateExpectations.ExpectUpdated(sidecarset.Name, sidecarcontrol.GetSidecarSetRevision(sidecarset), pod)
 	}
 
 	klog.V(3).InfoS("SidecarSet updated pods", "sidecarSet", klog.KObj(sidecarset), "podNames", strings.Join(podNames, ","))
-	return nil
+	return deferred, nil
 }
 
 func (p *Processor) updatePodSidecarAndHash(control sidecarcontrol.SidecarControl, pod *corev1.Pod) error {
@@ -285,21 +364,23 @@ func (p *Processor) updateSidecarSetStatus(sidecarSet *appsv1alpha1.SidecarSet,
 	}
 
 	klog.V(3).InfoS("SidecarSet updated status success", "sidecarSet", klog.KObj(sidecarSet), "matchedPods", status.MatchedPods,
-		"updatedPods", status.UpdatedPods, "readyPods", status.ReadyPods, "updateReadyPods", status.UpdatedReadyPods)
+		"updatedPods", status.UpdatedPods, "readyPods", status.ReadyPods, "updateReadyPods", status.UpdatedReadyPods, "currentWave", status.CurrentWave)
 	return nil
 }
 
 // If you need update the pod object, you must DeepCopy it
-func (p *Processor) getMatchingPods(s *appsv1alpha1.SidecarSet) ([]*corev1.Pod, error) {
+// getMatchingPods returns the pods injected with the sidecarSet, and the names of the other sidecarSets
+// that conflict with the sidecarSet in the selected pods.
+func (p *Processor) getMatchingPods(s *appsv1alpha1.SidecarSet) ([]*corev1.Pod, []string, error) {
 	// get more faster selector
 	selector, err := util.ValidatedLabelSelectorAsSelector(s.Spec.Selector)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	scopedNamespaces := sets.NewString()
 	if s.Spec.Namespace != "" || s.Spec.NamespaceSelector != nil {
 		if scopedNamespaces, err = sidecarcontrol.FetchSidecarSetMatchedNamespace(p.Client, s); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		// If sidecarSet.Spec.Namespace is empty, then select in cluster
 	} else {
@@ -308,20 +389,33 @@ func (p *Processor) getMatchingPods(s *appsv1alpha1.SidecarSet) ([]*corev1.Pod,
 	}
 	selectedPods, err := p.getSelectedPods(scopedNamespaces, selector)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// filter out pods that don't require updated, include the following:
 	// 1. inActive pod
 	// 2. never be injected sidecar container
 	var filteredPods []*corev1.Pod
+	conflictingSidecarSets := sets.NewString()
 	for _, pod := range selectedPods {
-		if sidecarcontrol.IsActivePod(pod) && sidecarcontrol.IsPodInjectedSidecarSet(pod, s) &&
-			sidecarcontrol.IsPodConsistentWithSidecarSet(pod, s) {
+		if !sidecarcontrol.IsActivePod(pod) {
+			continue
+		}
+		for skipped, others := range sidecarcontrol.GetPodSidecarSetConflicts(pod) {
+			if skipped == s.Name {
+				conflictingSidecarSets.Insert(others...)
+			} else if sets.NewString(others...).Has(s.Name) {
+				conflictingSidecarSets.Insert(skipped)
+			}
+		}
+		if sidecarcontrol.IsPodInjectedSidecarSet(pod, s) && sidecarcontrol.IsPodConsistentWithSidecarSet(pod, s) {
 			filteredPods = append(filteredPods, pod)
 		}
 	}
-	return filteredPods, nil
+	if conflictingSidecarSets.Len() == 0 {
+		return filteredPods, nil, nil
+	}
+	return filteredPods, conflictingSidecarSets.List(), nil
 }
 
 // get selected pods(DisableDeepCopy:true, indicates must be deep copy before update pod objection)
@@ -522,24 +616,63 @@ func replaceRevision(revisions []*apps.ControllerRevision, oldOne, newOne *apps.
 // ReadyPods: ready pods number
 // UpdatedReadyPods: updated and ready pods number
 // UnavailablePods: MatchedPods - UpdatedReadyPods
+// RevisionStatuses: matched and ready pods number of each revision, including the latest revision without pods
+// NamespaceStatuses: matched and updated pods number of the top N namespaces with the most pods not updated
 func calculateStatus(control sidecarcontrol.SidecarControl, pods []*corev1.Pod, latestRevision *apps.ControllerRevision, collisionCount int32,
 ) *appsv1alpha1.SidecarSetStatus {
 	sidecarset := control.GetSidecarset()
 	var matchedPods, updatedPods, readyPods, updatedAndReady int32
 	matchedPods = int32(len(pods))
+	// revision hash -> revision status
+	revisionStatuses := map[string]*appsv1alpha1.SidecarSetRevisionStatus{}
+	// namespace -> namespace status
+	namespaceStatuses := map[string]*appsv1alpha1.SidecarSetNamespaceStatus{}
 	for _, pod := range pods {
 		updated := sidecarcontrol.IsPodSidecarUpdated(sidecarset, pod)
 		if updated {
 			updatedPods++
 		}
-		if control.IsPodStateConsistent(pod, nil) && control.IsPodReady(pod) {
+		ready := control.IsPodStateConsistent(pod, nil) && control.IsPodReady(pod)
+		if ready {
 			readyPods++
 			if updated {
 				updatedAndReady++
 			}
 		}
+
+		revision := sidecarcontrol.GetPodSidecarSetRevision(sidecarset.Name, pod)
+		revisionStatus, ok := revisionStatuses[revision]
+		if !ok {
+			revisionStatus = &appsv1alpha1.SidecarSetRevisionStatus{
+				Revision:           revision,
+				ControllerRevision: sidecarcontrol.GetPodSidecarSetControllerRevision(sidecarset.Name, pod),
+			}
+			revisionStatuses[revision] = revisionStatus
+		}
+		revisionStatus.MatchedPods++
+		if ready {
+			revisionStatus.ReadyPods++
+		}
+
+		namespaceStatus, ok := namespaceStatuses[pod.Namespace]
+		if !ok {
+			namespaceStatus = &appsv1alpha1.SidecarSetNamespaceStatus{Namespace: pod.Namespace}
+			namespaceStatuses[pod.Namespace] = namespaceStatus
+		}
+		namespaceStatus.MatchedPods++
+		if updated {
+			namespaceStatus.UpdatedPods++
+		}
+	}
+	// the latest revision is always reported, even if no pod is injected with it
+	latestRevisionHash := sidecarcontrol.GetSidecarSetRevision(sidecarset)
+	if _, ok := revisionStatuses[latestRevisionHash]; !ok {
+		revisionStatuses[latestRevisionHash] = &appsv1alpha1.SidecarSetRevisionStatus{
+			Revision:           latestRevisionHash,
+			ControllerRevision: latestRevision.Name,
+		}
 	}
-	return &appsv1alpha1.SidecarSetStatus{
+	status := &appsv1alpha1.SidecarSetStatus{
 		ObservedGeneration: sidecarset.Generation,
 		MatchedPods:        matchedPods,
 		UpdatedPods:        updatedPods,
@@ -547,7 +680,58 @@ func calculateStatus(control sidecarcontrol.SidecarControl, pods []*corev1.Pod,
 		UpdatedReadyPods:   updatedAndReady,
 		LatestRevision:     latestRevision.Name,
 		CollisionCount:     pointer.Int32Ptr(collisionCount),
+		RevisionStatuses:   sortRevisionStatuses(latestRevisionHash, revisionStatuses),
 	}
+	if breakdown := sidecarset.Spec.StatusBreakdown; breakdown != nil && breakdown.NamespaceTopN > 0 {
+		status.NamespaceStatuses = topNamespaceStatuses(namespaceStatuses, int(breakdown.NamespaceTopN))
+	}
+	return status
+}
+
+// maxRevisionStatuses is the max number of revisions reported in SidecarSet status
+const maxRevisionStatuses = 10
+
+// sortRevisionStatuses returns the revision statuses with the latest revision first, then sorted by the number of pods.
+func sortRevisionStatuses(latestRevision string, revisionStatuses map[string]*appsv1alpha1.SidecarSetRevisionStatus) []appsv1alpha1.SidecarSetRevisionStatus {
+	statuses := make([]appsv1alpha1.SidecarSetRevisionStatus, 0, len(revisionStatuses))
+	for _, revisionStatus := range revisionStatuses {
+		statuses = append(statuses, *revisionStatus)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		if (statuses[i].Revision == latestRevision) != (statuses[j].Revision == latestRevision) {
+			return statuses[i].Revision == latestRevision
+		}
+		if statuses[i].MatchedPods != statuses[j].MatchedPods {
+			return statuses[i].MatchedPods > statuses[j].MatchedPods
+		}
+		return statuses[i].Revision < statuses[j].Revision
+	})
+	if len(statuses) > maxRevisionStatuses {
+		statuses = statuses[:maxRevisionStatuses]
+	}
+	return statuses
+}
+
+// topNamespaceStatuses returns at most n namespaces that have the most pods not updated.
+func topNamespaceStatuses(namespaceStatuses map[string]*appsv1alpha1.SidecarSetNamespaceStatus, n int) []appsv1alpha1.SidecarSetNamespaceStatus {
+	var statuses []appsv1alpha1.SidecarSetNamespaceStatus
+	for _, namespaceStatus := range namespaceStatuses {
+		if namespaceStatus.MatchedPods > namespaceStatus.UpdatedPods {
+			statuses = append(statuses, *namespaceStatus)
+		}
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		notUpdatedI := statuses[i].MatchedPods - statuses[i].UpdatedPods
+		notUpdatedJ := statuses[j].MatchedPods - statuses[j].UpdatedPods
+		if notUpdatedI != notUpdatedJ {
+			return notUpdatedI > notUpdatedJ
+		}
+		return statuses[i].Namespace < statuses[j].Namespace
+	})
+	if len(statuses) > n {
+		statuses = statuses[:n]
+	}
+	return statuses
 }
 
 func isSidecarSetNotUpdate(s *appsv1alpha1.SidecarSet) bool {
@@ -559,6 +743,12 @@ func isSidecarSetNotUpdate(s *appsv1alpha1.SidecarSet) bool {
 }
 
 func updateContainerInPod(container corev1.Container, pod *corev1.Pod) {
+	for i := range pod.Spec.InitContainers {
+		if pod.Spec.InitContainers[i].Name == container.Name {
+			pod.Spec.InitContainers[i] = container
+			return
+		}
+	}
 	for i := range pod.Spec.Containers {
 		if pod.Spec.Containers[i].Name == container.Name {
 			pod.Spec.Containers[i] = container
@@ -570,9 +760,10 @@ func updateContainerInPod(container corev1.Container, pod *corev1.Pod) {
 func updatePodSidecarContainer(control sidecarcontrol.SidecarControl, pod *corev1.Pod) {
 	sidecarSet := control.GetSidecarset()
 
-	// upgrade sidecar containers
+	// upgrade native sidecar initContainers and sidecar containers
 	var changedContainers []string
-	for _, sidecarContainer := range sidecarSet.Spec.Containers {
+	sidecarContainers := append(sidecarcontrol.GetNativeSidecarInitContainers(sidecarSet), sidecarSet.Spec.Containers...)
+	for _, sidecarContainer := range sidecarContainers {
 		//sidecarContainer := &sidecarset.Spec.Containers[i]
 		// volumeMounts that injected into sidecar container
 		// when volumeMounts SubPathExpr contains expansions, then need copy container EnvVars(injectEnvs)
@@ -637,7 +828,14 @@ func inconsistentStatus(sidecarSet *appsv1alpha1.SidecarSet, status *appsv1alpha
 		status.ReadyPods != sidecarSet.Status.ReadyPods ||
 		status.UpdatedReadyPods != sidecarSet.Status.UpdatedReadyPods ||
 		status.LatestRevision != sidecarSet.Status.LatestRevision ||
-		!pointer.Int32Equal(sidecarSet.Status.CollisionCount, status.CollisionCount)
+		!pointer.Int32Equal(sidecarSet.Status.CollisionCount, status.CollisionCount) ||
+		status.CurrentWave != sidecarSet.Status.CurrentWave ||
+		!reflect.DeepEqual(status.WaveStatuses, sidecarSet.Status.WaveStatuses) ||
+		status.FailedPods != sidecarSet.Status.FailedPods ||
+		!reflect.DeepEqual(status.Conditions, sidecarSet.Status.Conditions) ||
+		!reflect.DeepEqual(status.ConflictingSidecarSets, sidecarSet.Status.ConflictingSidecarSets) ||
+		!reflect.DeepEqual(status.RevisionStatuses, sidecarSet.Status.RevisionStatuses) ||
+		!reflect.DeepEqual(status.NamespaceStatuses, sidecarSet.Status.NamespaceStatuses)
 }
 
 func isSidecarSetUpdateFinish(status *appsv1alpha1.SidecarSetStatus) bool {
@@ -680,6 +878,10 @@ func (p *Processor) updatePodSidecarSetUpgradableCondition(sidecarset *appsv1alp
 	} else {
 		condition.Status = corev1.ConditionFalse
 		condition.Reason = "UpdateImmutableField"
+		// the native sidecar initContainers can't be upgraded in-place, e.g. the cluster doesn't support native sidecars
+		if !upgradable && len(sidecarcontrol.GetNotUpgradableNativeSidecars(sidecarset, pod)) > 0 {
+			condition.Reason = "NativeSidecarNotUpgradable"
+		}
 	}
 
 	controlutil.UpdateMessageKvCondition(messageKv, condition)
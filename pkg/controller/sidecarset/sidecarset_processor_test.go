@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"reflect"
 	"strconv"
 	"testing"
 
@@ -254,7 +255,7 @@ func TestScopeNamespacePods(t *testing.T) {
 		fakeClient.Create(context.TODO(), pod)
 	}
 	processor := NewSidecarSetProcessor(fakeClient, record.NewFakeRecorder(10))
-	pods, err := processor.getMatchingPods(sidecarSet)
+	pods, _, err := processor.getMatchingPods(sidecarSet)
 	if err != nil {
 		t.Fatalf("getMatchingPods failed: %s", err.Error())
 		return
@@ -265,6 +266,33 @@ func TestScopeNamespacePods(t *testing.T) {
 	}
 }
 
+func TestGetConflictingSidecarSets(t *testing.T) {
+	sidecarSet := sidecarSetDemo.DeepCopy()
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(sidecarSet).Build()
+	conflicts := []string{
+		fmt.Sprintf(`{"%s":["sidecarset-b"]}`, sidecarSet.Name),
+		fmt.Sprintf(`{"sidecarset-c":["%s","sidecarset-b"]}`, sidecarSet.Name),
+		`{"sidecarset-d":["sidecarset-b"]}`,
+	}
+	for i, conflict := range conflicts {
+		pod := podDemo.DeepCopy()
+		pod.Name = fmt.Sprintf("%s-%d", pod.Name, i)
+		pod.Annotations[sidecarcontrol.SidecarSetInjectionConflictAnnotation] = conflict
+		if err := fakeClient.Create(context.TODO(), pod); err != nil {
+			t.Fatalf("create pod failed: %s", err.Error())
+		}
+	}
+	processor := NewSidecarSetProcessor(fakeClient, record.NewFakeRecorder(10))
+	_, conflictingSidecarSets, err := processor.getMatchingPods(sidecarSet)
+	if err != nil {
+		t.Fatalf("getMatchingPods failed: %s", err.Error())
+	}
+	expected := []string{"sidecarset-b", "sidecarset-c"}
+	if !reflect.DeepEqual(conflictingSidecarSets, expected) {
+		t.Fatalf("expect conflicting sidecarSets %v, but got %v", expected, conflictingSidecarSets)
+	}
+}
+
 func TestCanUpgradePods(t *testing.T) {
 	sidecarSet := factorySidecarSet()
 	sidecarSet.Annotations[sidecarcontrol.SidecarSetHashWithoutImageAnnotation] = "without-bbb"
@@ -309,6 +337,68 @@ func TestCanUpgradePods(t *testing.T) {
 	}
 }
 
+func TestUpdateNativeSidecarInitContainer(t *testing.T) {
+	always := corev1.ContainerRestartPolicyAlways
+	cases := []struct {
+		name          string
+		restartPolicy *corev1.ContainerRestartPolicy
+		expectImage   string
+		expectReason  string
+	}{
+		{
+			name:          "native sidecar is upgraded in-place",
+			restartPolicy: &always,
+			expectImage:   "init-image:v2",
+			expectReason:  "AllSidecarsetUpgradable",
+		},
+		{
+			name:         "native sidecar isn't supported in pod",
+			expectImage:  "init-image:v1",
+			expectReason: "NativeSidecarNotUpgradable",
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			sidecarcontrol.UpdateExpectations.DeleteExpectations(sidecarSetDemo.Name)
+			sidecarSet := sidecarSetDemo.DeepCopy()
+			sidecarSet.Spec.InitContainers = []appsv1alpha1.SidecarContainer{
+				{Container: corev1.Container{Name: "init-sidecar", Image: "init-image:v2", RestartPolicy: &always}},
+			}
+			pod := podDemo.DeepCopy()
+			pod.Spec.InitContainers = []corev1.Container{
+				{Name: "init-sidecar", Image: "init-image:v1", RestartPolicy: cs.restartPolicy},
+			}
+			pod.Status.InitContainerStatuses = []corev1.ContainerStatus{
+				{Name: "init-sidecar", Image: "init-image:v1", ImageID: testImageV1ImageID, Ready: cs.restartPolicy != nil},
+			}
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(sidecarSet, pod).
+				WithStatusSubresource(&appsv1alpha1.SidecarSet{}, &corev1.Pod{}).Build()
+			processor := NewSidecarSetProcessor(fakeClient, record.NewFakeRecorder(10))
+			if _, err := processor.UpdateSidecarSet(sidecarSet); err != nil {
+				t.Fatalf("processor update sidecarset failed: %s", err.Error())
+			}
+
+			podOutput, err := getLatestPod(fakeClient, pod)
+			if err != nil {
+				t.Fatalf("get latest pod(%s) failed: %s", pod.Name, err.Error())
+			}
+			if podOutput.Spec.InitContainers[0].Image != cs.expectImage {
+				t.Fatalf("expect init container image(%s), but get image(%s)", cs.expectImage, podOutput.Spec.InitContainers[0].Image)
+			}
+			var reason string
+			for _, condition := range podOutput.Status.Conditions {
+				if condition.Type == sidecarcontrol.SidecarSetUpgradable {
+					reason = condition.Reason
+				}
+			}
+			if reason != cs.expectReason {
+				t.Fatalf("expect condition reason(%s), but get reason(%s)", cs.expectReason, reason)
+			}
+		})
+	}
+}
+
 func TestGetActiveRevisions(t *testing.T) {
 	sidecarSet := factorySidecarSet()
 	sidecarSet.SetUID("1223344")
@@ -518,3 +608,52 @@ func TestTruncateHistory(t *testing.T) {
 		t.Fatalf("expected name %s, actual : %s", getName(15), rvs[9].Name)
 	}
 }
+
+func TestCalculateRevisionStatusesWithoutLatestPods(t *testing.T) {
+	sidecarSet := factorySidecarSet()
+	pods := factoryPods(3, 0, 0)
+	latestRevision := &apps.ControllerRevision{}
+	latestRevision.Name = "test-sidecarset-revision"
+
+	status := calculateStatus(sidecarcontrol.New(sidecarSet), pods, latestRevision, 0)
+	expectRevisionStatuses := []appsv1alpha1.SidecarSetRevisionStatus{
+		{Revision: "bbb", ControllerRevision: "test-sidecarset-revision", MatchedPods: 0, ReadyPods: 0},
+		{Revision: "aaa", MatchedPods: 3, ReadyPods: 3},
+	}
+	if !reflect.DeepEqual(status.RevisionStatuses, expectRevisionStatuses) {
+		t.Fatalf("expect revision statuses %+v, but got %+v", expectRevisionStatuses, status.RevisionStatuses)
+	}
+}
+
+func TestCalculateStatusBreakdown(t *testing.T) {
+	sidecarSet := factorySidecarSet()
+	sidecarSet.Spec.StatusBreakdown = &appsv1alpha1.SidecarSetStatusBreakdown{NamespaceTopN: 1}
+	pods := factoryPods(6, 2, 0)
+	for i, ns := range []string{"ns-a", "ns-a", "ns-a", "ns-b", "ns-b", "ns-c"} {
+		pods[i].Namespace = ns
+	}
+	latestRevision := &apps.ControllerRevision{}
+	latestRevision.Name = "test-sidecarset-revision"
+
+	status := calculateStatus(sidecarcontrol.New(sidecarSet), pods, latestRevision, 0)
+	expectRevisionStatuses := []appsv1alpha1.SidecarSetRevisionStatus{
+		{Revision: "bbb", MatchedPods: 2, ReadyPods: 0},
+		{Revision: "aaa", MatchedPods: 4, ReadyPods: 4},
+	}
+	if !reflect.DeepEqual(status.RevisionStatuses, expectRevisionStatuses) {
+		t.Fatalf("expect revision statuses %+v, but got %+v", expectRevisionStatuses, status.RevisionStatuses)
+	}
+	expectNamespaceStatuses := []appsv1alpha1.SidecarSetNamespaceStatus{
+		{Namespace: "ns-b", MatchedPods: 2, UpdatedPods: 0},
+	}
+	if !reflect.DeepEqual(status.NamespaceStatuses, expectNamespaceStatuses) {
+		t.Fatalf("expect namespace statuses %+v, but got %+v", expectNamespaceStatuses, status.NamespaceStatuses)
+	}
+
+	// namespace breakdown is disabled by default
+	sidecarSet.Spec.StatusBreakdown = nil
+	status = calculateStatus(sidecarcontrol.New(sidecarSet), pods, latestRevision, 0)
+	if status.NamespaceStatuses != nil {
+		t.Fatalf("expect no namespace statuses, but got %+v", status.NamespaceStatuses)
+	}
+}
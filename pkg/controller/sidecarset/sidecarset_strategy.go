@@ -37,6 +37,13 @@ func NewStrategy() Strategy {
 }
 
 func (p *spreadingStrategy) GetNextUpgradePods(control sidecarcontrol.SidecarControl, pods []*corev1.Pod) (upgradePods []*corev1.Pod, notUpgradablePods []*corev1.Pod) {
+	return getNextUpgradePods(control, pods, nil)
+}
+
+// admitFunc returns whether the pod can be upgraded in this round, podReady indicates whether the pod is ready now.
+type admitFunc func(pod *corev1.Pod, podReady bool) bool
+
+func getNextUpgradePods(control sidecarcontrol.SidecarControl, pods []*corev1.Pod, admit admitFunc) (upgradePods []*corev1.Pod, notUpgradablePods []*corev1.Pod) {
 	sidecarset := control.GetSidecarset()
 	// wait to upgrade pod index
 	var waitUpgradedIndexes []int
@@ -87,11 +94,8 @@ func (p *spreadingStrategy) GetNextUpgradePods(control sidecarcontrol.SidecarCon
 	//2. sort Pods with default sequence and scatter
 	waitUpgradedIndexes = SortUpdateIndexes(strategy, pods, waitUpgradedIndexes)
 
-	//3. calculate to be upgraded pods number for the time
-	needToUpgradeCount := calculateUpgradeCount(control, waitUpgradedIndexes, pods)
-	if needToUpgradeCount < len(waitUpgradedIndexes) {
-		waitUpgradedIndexes = waitUpgradedIndexes[:needToUpgradeCount]
-	}
+	//3. calculate to be upgraded pods for the time
+	waitUpgradedIndexes = calculateUpgradeIndexes(control, waitUpgradedIndexes, pods, admit)
 
 	//4. injectPods will be upgraded in the following process
 	for _, idx := range waitUpgradedIndexes {
@@ -131,7 +135,9 @@ func SortUpdateIndexes(strategy appsv1alpha1.SidecarSetUpdateStrategy, pods []*c
 	return waitUpdateIndexes
 }
 
-func calculateUpgradeCount(coreControl sidecarcontrol.SidecarControl, waitUpdateIndexes []int, pods []*corev1.Pod) int {
+// calculateUpgradeIndexes returns the indexes of pods to be upgraded for the time, limited by partition and maxUnavailable.
+// If admit is not nil, the pods that are not admitted will be skipped.
+func calculateUpgradeIndexes(coreControl sidecarcontrol.SidecarControl, waitUpdateIndexes []int, pods []*corev1.Pod, admit admitFunc) []int {
 	totalReplicas := len(pods)
 	sidecarSet := coreControl.GetSidecarset()
 	strategy := sidecarSet.Spec.UpdateStrategy
@@ -144,9 +150,18 @@ func calculateUpgradeCount(coreControl sidecarcontrol.SidecarControl, waitUpdate
 	}
 	// indicates the partition pods will not be upgraded for the time
 	if len(waitUpdateIndexes)-partition <= 0 {
-		return 0
+		return nil
 	}
 	waitUpdateIndexes = waitUpdateIndexes[:(len(waitUpdateIndexes) - partition)]
+	return limitIndexesByMaxUnavailable(coreControl, waitUpdateIndexes, pods, admit)
+}
+
+// limitIndexesByMaxUnavailable returns the leading indexes of waitUpdateIndexes whose pods can be updated for the time
+// without exceeding maxUnavailable. If admit is not nil, the pods that are not admitted will be skipped.
+func limitIndexesByMaxUnavailable(coreControl sidecarcontrol.SidecarControl, waitUpdateIndexes []int, pods []*corev1.Pod, admit admitFunc) []int {
+	totalReplicas := len(pods)
+	sidecarSet := coreControl.GetSidecarset()
+	strategy := sidecarSet.Spec.UpdateStrategy
 
 	// max unavailable pods number, default is 1
 	maxUnavailable := 1
@@ -163,20 +178,22 @@ func calculateUpgradeCount(coreControl sidecarcontrol.SidecarControl, waitUpdate
 			upgradeAndNotReadyCount++
 		}
 	}
-	var needUpgradeCount int
+	var needUpgradeIndexes []int
 	for _, i := range waitUpdateIndexes {
-		// If pod is not ready, then not included in the calculation of maxUnavailable
-		if !coreControl.IsPodReady(pods[i]) {
-			needUpgradeCount++
+		podReady := coreControl.IsPodReady(pods[i])
+		if podReady && upgradeAndNotReadyCount >= maxUnavailable {
+			break
+		}
+		if admit != nil && !admit(pods[i], podReady) {
 			continue
 		}
-		if upgradeAndNotReadyCount >= maxUnavailable {
-			break
+		// If pod is not ready, then not included in the calculation of maxUnavailable
+		if podReady {
+			upgradeAndNotReadyCount++
 		}
-		upgradeAndNotReadyCount++
-		needUpgradeCount++
+		needUpgradeIndexes = append(needUpgradeIndexes, i)
 	}
-	return needUpgradeCount
+	return needUpgradeIndexes
 }
 
 func parseUpdateScatterTerms(scatter appsv1alpha1.UpdateScatterStrategy, pods []*corev1.Pod) appsv1alpha1.UpdateScatterStrategy {
@@ -0,0 +1,152 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sidecarset
+
+import (
+	"context"
+
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+	"github.com/openkruise/kruise/pkg/control/sidecarcontrol"
+	"github.com/openkruise/kruise/pkg/util"
+
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// groupPodsByWave returns the pods of each wave in sidecarSet.Spec.UpdateStrategy.Waves,
+// a pod belongs to the first wave it matches, and pods that match no wave are dropped.
+func (p *Processor) groupPodsByWave(sidecarSet *appsv1alpha1.SidecarSet, pods []*corev1.Pod) [][]*corev1.Pod {
+	waves := sidecarSet.Spec.UpdateStrategy.Waves
+	if len(waves) == 0 {
+		return nil
+	}
+	wavePods := make([][]*corev1.Pod, len(waves))
+	// namespace -> whether matched, for each wave
+	nsMatched := make([]map[string]bool, len(waves))
+	for i := range nsMatched {
+		nsMatched[i] = map[string]bool{}
+	}
+	for _, pod := range pods {
+		kind, name := p.getPodWorkload(pod)
+		for i := range waves {
+			wave := &waves[i]
+			if wave.NamespaceSelector != nil {
+				matched, ok := nsMatched[i][pod.Namespace]
+				if !ok {
+					matched = sidecarcontrol.IsSelectorNamespace(p.Client, pod.Namespace, wave.NamespaceSelector)
+					nsMatched[i][pod.Namespace] = matched
+				}
+				if !matched {
+					continue
+				}
+			}
+			if !isWorkloadMatchedWave(wave, kind, name) {
+				continue
+			}
+			wavePods[i] = append(wavePods[i], pod)
+			break
+		}
+	}
+	return wavePods
+}
+
+func isWorkloadMatchedWave(wave *appsv1alpha1.SidecarSetUpdateWave, kind, name string) bool {
+	if len(wave.Workloads) == 0 {
+		return true
+	}
+	for _, workload := range wave.Workloads {
+		if workload.Kind == kind && (workload.Name == "" || workload.Name == name) {
+			return true
+		}
+	}
+	return false
+}
+
+// getPodWorkload returns the kind and name of the workload owning the pod,
+// pods created by ReplicaSet are regarded as owned by the Deployment of the ReplicaSet.
+func (p *Processor) getPodWorkload(pod *corev1.Pod) (string, string) {
+	ref := metav1.GetControllerOf(pod)
+	if ref == nil {
+		return "", ""
+	}
+	if ref.Kind != "ReplicaSet" {
+		return ref.Kind, ref.Name
+	}
+	rs := &apps.ReplicaSet{}
+	if err := p.Client.Get(context.TODO(), client.ObjectKey{Namespace: pod.Namespace, Name: ref.Name}, rs); err != nil {
+		if !errors.IsNotFound(err) {
+			klog.ErrorS(err, "Failed to get ReplicaSet of pod", "pod", klog.KObj(pod), "replicaSet", ref.Name)
+		}
+		return ref.Kind, ref.Name
+	}
+	if rsRef := metav1.GetControllerOf(rs); rsRef != nil && rsRef.Kind == "Deployment" {
+		return rsRef.Kind, rsRef.Name
+	}
+	return ref.Kind, ref.Name
+}
+
+// calculateWaveStatus calculates the status of each wave and returns the index of the current wave,
+// the current wave is the first wave that has not finished, and -1 means all waves have finished.
+func calculateWaveStatus(control sidecarcontrol.SidecarControl, wavePods [][]*corev1.Pod) ([]appsv1alpha1.SidecarSetWaveStatus, int) {
+	sidecarSet := control.GetSidecarset()
+	waves := sidecarSet.Spec.UpdateStrategy.Waves
+	current := -1
+	statuses := make([]appsv1alpha1.SidecarSetWaveStatus, 0, len(waves))
+	for i := range waves {
+		status := appsv1alpha1.SidecarSetWaveStatus{Name: waves[i].Name}
+		var pods []*corev1.Pod
+		if i < len(wavePods) {
+			pods = wavePods[i]
+		}
+		status.MatchedPods = int32(len(pods))
+		for _, pod := range pods {
+			if !sidecarcontrol.IsPodSidecarUpdated(sidecarSet, pod) {
+				continue
+			}
+			status.UpdatedPods++
+			if control.IsPodStateConsistent(pod, nil) && control.IsPodReady(pod) {
+				status.UpdatedReadyPods++
+			}
+		}
+		if current == -1 && !isWaveFinished(&waves[i], &status) {
+			current = i
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, current
+}
+
+// isWaveFinished returns true when all the pods in the wave, except the partition, are updated and ready.
+func isWaveFinished(wave *appsv1alpha1.SidecarSetUpdateWave, status *appsv1alpha1.SidecarSetWaveStatus) bool {
+	var partition int
+	if wave.Partition != nil {
+		partition, _ = util.CalculatePartitionReplicas(wave.Partition, &status.MatchedPods)
+	}
+	return int(status.MatchedPods-status.UpdatedPods) <= partition && status.UpdatedReadyPods >= status.UpdatedPods
+}
+
+// newWaveSidecarSet returns a copy of sidecarSet whose partition is replaced by the partition of the wave,
+// so that the strategy can calculate the pods to upgrade in the wave.
+func newWaveSidecarSet(sidecarSet *appsv1alpha1.SidecarSet, wave *appsv1alpha1.SidecarSetUpdateWave) *appsv1alpha1.SidecarSet {
+	clone := sidecarSet.DeepCopy()
+	clone.Spec.UpdateStrategy.Partition = wave.Partition
+	return clone
+}
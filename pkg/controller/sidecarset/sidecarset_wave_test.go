@@ -0,0 +1,231 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sidecarset
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+	"github.com/openkruise/kruise/pkg/control/sidecarcontrol"
+
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGroupPodsByWave(t *testing.T) {
+	sidecarSet := factorySidecarSet()
+	sidecarSet.Spec.UpdateStrategy.Waves = []appsv1alpha1.SidecarSetUpdateWave{
+		{
+			Name: "canary",
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"tier": "canary"},
+			},
+		},
+		{
+			Name:      "deployments",
+			Workloads: []appsv1alpha1.SidecarSetWaveWorkload{{Kind: "Deployment", Name: "web"}},
+		},
+		{
+			Name:      "clonesets",
+			Workloads: []appsv1alpha1.SidecarSetWaveWorkload{{Kind: "CloneSet"}},
+		},
+	}
+	canaryNs := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-canary", Labels: map[string]string{"tier": "canary"}}}
+	prodNs := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-prod"}}
+	rs := &apps.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web-rs",
+			Namespace: "ns-prod",
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "apps/v1", Kind: "Deployment", Name: "web", UID: "dep-uid", Controller: ptr.To(true)},
+			},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(canaryNs, prodNs, rs).Build()
+	processor := NewSidecarSetProcessor(fakeClient, record.NewFakeRecorder(10))
+
+	newPod := func(name, ns, kind, owner string) *corev1.Pod {
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns}}
+		if kind != "" {
+			pod.OwnerReferences = []metav1.OwnerReference{{Kind: kind, Name: owner, Controller: ptr.To(true)}}
+		}
+		return pod
+	}
+	pods := []*corev1.Pod{
+		newPod("canary-cs", "ns-canary", "CloneSet", "cs"),
+		newPod("prod-dep", "ns-prod", "ReplicaSet", "web-rs"),
+		newPod("prod-cs", "ns-prod", "CloneSet", "cs"),
+		newPod("prod-sts", "ns-prod", "StatefulSet", "sts"),
+	}
+
+	wavePods := processor.groupPodsByWave(sidecarSet, pods)
+	expected := [][]string{{"canary-cs"}, {"prod-dep"}, {"prod-cs"}}
+	if len(wavePods) != len(expected) {
+		t.Fatalf("expect %d waves, but got %d", len(expected), len(wavePods))
+	}
+	for i := range expected {
+		var names []string
+		for _, pod := range wavePods[i] {
+			names = append(names, pod.Name)
+		}
+		if fmt.Sprint(names) != fmt.Sprint(expected[i]) {
+			t.Fatalf("expect pods %v in wave %d, but got %v", expected[i], i, names)
+		}
+	}
+}
+
+func TestCalculateWaveStatus(t *testing.T) {
+	cases := []struct {
+		name            string
+		partitions      []*intstr.IntOrString
+		getWavePods     func() [][]*corev1.Pod
+		expectCurrent   int
+		expectWaveCount []appsv1alpha1.SidecarSetWaveStatus
+	}{
+		{
+			name:       "first wave is updating",
+			partitions: []*intstr.IntOrString{nil, nil},
+			getWavePods: func() [][]*corev1.Pod {
+				return [][]*corev1.Pod{factoryPods(10, 5, 5), factoryPods(10, 0, 0)}
+			},
+			expectCurrent: 0,
+			expectWaveCount: []appsv1alpha1.SidecarSetWaveStatus{
+				{Name: "wave-0", MatchedPods: 10, UpdatedPods: 5, UpdatedReadyPods: 5},
+				{Name: "wave-1", MatchedPods: 10},
+			},
+		},
+		{
+			name:       "first wave is finished with partition",
+			partitions: []*intstr.IntOrString{ptr.To(intstr.FromString("50%")), nil},
+			getWavePods: func() [][]*corev1.Pod {
+				return [][]*corev1.Pod{factoryPods(10, 5, 5), factoryPods(10, 0, 0)}
+			},
+			expectCurrent: 1,
+			expectWaveCount: []appsv1alpha1.SidecarSetWaveStatus{
+				{Name: "wave-0", MatchedPods: 10, UpdatedPods: 5, UpdatedReadyPods: 5},
+				{Name: "wave-1", MatchedPods: 10},
+			},
+		},
+		{
+			name:       "first wave is updated but not ready",
+			partitions: []*intstr.IntOrString{nil, nil},
+			getWavePods: func() [][]*corev1.Pod {
+				return [][]*corev1.Pod{factoryPods(10, 10, 8), factoryPods(10, 0, 0)}
+			},
+			expectCurrent: 0,
+			expectWaveCount: []appsv1alpha1.SidecarSetWaveStatus{
+				{Name: "wave-0", MatchedPods: 10, UpdatedPods: 10, UpdatedReadyPods: 8},
+				{Name: "wave-1", MatchedPods: 10},
+			},
+		},
+		{
+			name:       "all waves are finished",
+			partitions: []*intstr.IntOrString{nil, nil},
+			getWavePods: func() [][]*corev1.Pod {
+				return [][]*corev1.Pod{factoryPods(10, 10, 10), nil}
+			},
+			expectCurrent: -1,
+			expectWaveCount: []appsv1alpha1.SidecarSetWaveStatus{
+				{Name: "wave-0", MatchedPods: 10, UpdatedPods: 10, UpdatedReadyPods: 10},
+				{Name: "wave-1"},
+			},
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			sidecarSet := factorySidecarSet()
+			for i, partition := range cs.partitions {
+				sidecarSet.Spec.UpdateStrategy.Waves = append(sidecarSet.Spec.UpdateStrategy.Waves, appsv1alpha1.SidecarSetUpdateWave{
+					Name:      fmt.Sprintf("wave-%d", i),
+					Partition: partition,
+				})
+			}
+			statuses, current := calculateWaveStatus(sidecarcontrol.New(sidecarSet), cs.getWavePods())
+			if current != cs.expectCurrent {
+				t.Fatalf("expect current wave %d, but got %d", cs.expectCurrent, current)
+			}
+			if fmt.Sprint(statuses) != fmt.Sprint(cs.expectWaveCount) {
+				t.Fatalf("expect wave status %v, but got %v", cs.expectWaveCount, statuses)
+			}
+		})
+	}
+}
+
+func TestUpdateSidecarSetInWaves(t *testing.T) {
+	sidecarSet := factorySidecarSet()
+	sidecarSet.Spec.UpdateStrategy.MaxUnavailable = ptr.To(intstr.FromInt32(100))
+	sidecarSet.Spec.UpdateStrategy.Waves = []appsv1alpha1.SidecarSetUpdateWave{
+		{
+			Name:      "canary",
+			Workloads: []appsv1alpha1.SidecarSetWaveWorkload{{Kind: "CloneSet", Name: "canary"}},
+		},
+		{
+			Name: "all",
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(sidecarSet).
+		WithStatusSubresource(&appsv1alpha1.SidecarSet{}).Build()
+	pods := factoryPodsCommon(10, 0, sidecarSet)
+	for i := range pods {
+		pods[i].Namespace = "default"
+		pods[i].Annotations[sidecarcontrol.SidecarSetListAnnotation] = `test-sidecarset`
+		owner := "canary"
+		if i >= 3 {
+			owner = "prod"
+		}
+		pods[i].OwnerReferences = []metav1.OwnerReference{{Kind: "CloneSet", Name: owner, Controller: ptr.To(true)}}
+		if err := fakeClient.Create(context.TODO(), pods[i]); err != nil {
+			t.Fatalf("create pod failed: %s", err.Error())
+		}
+	}
+
+	// clean up the expectations left by other cases
+	sidecarcontrol.UpdateExpectations.DeleteExpectations(sidecarSet.Name)
+	processor := NewSidecarSetProcessor(fakeClient, record.NewFakeRecorder(10))
+	if _, err := processor.UpdateSidecarSet(sidecarSet); err != nil {
+		t.Fatalf("processor update sidecarset failed: %s", err.Error())
+	}
+	for i := range pods {
+		podOutput, err := getLatestPod(fakeClient, pods[i])
+		if err != nil {
+			t.Fatalf("get latest pod(%s) failed: %s", pods[i].Name, err.Error())
+		}
+		expectImage := "test-image:v1"
+		if i < 3 {
+			expectImage = "test-image:v2"
+		}
+		if podOutput.Spec.Containers[1].Image != expectImage {
+			t.Fatalf("expect pod(%d) image(%s), but get image(%s)", i, expectImage, podOutput.Spec.Containers[1].Image)
+		}
+	}
+	sidecarSetOutput, err := getLatestSidecarSet(fakeClient, sidecarSet)
+	if err != nil {
+		t.Fatalf("get latest sidecarset failed: %s", err.Error())
+	}
+	if sidecarSetOutput.Status.CurrentWave != "canary" || len(sidecarSetOutput.Status.WaveStatuses) != 2 ||
+		sidecarSetOutput.Status.WaveStatuses[0].MatchedPods != 3 || sidecarSetOutput.Status.WaveStatuses[1].MatchedPods != 7 {
+		t.Fatalf("unexpected wave status: %s, %v", sidecarSetOutput.Status.CurrentWave, sidecarSetOutput.Status.WaveStatuses)
+	}
+}
@@ -21,15 +21,20 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/scale/scheme/appsv1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
 	"github.com/openkruise/kruise/apis/apps/v1beta1"
+	"github.com/openkruise/kruise/pkg/util/configuration"
 )
 
+var rolloutGVK = schema.GroupVersionKind{Group: "argoproj.io", Version: "v1alpha1", Kind: "Rollout"}
+
 func TestPostUpdate(t *testing.T) {
 	fakeClient, scheme := getClientAndScheme()
 	testCases := []struct {
@@ -132,6 +137,14 @@ func TestApplySubsetTemplate(t *testing.T) {
 				Scheme: scheme,
 			},
 		},
+		{
+			name: "CustomWorkload",
+			adapter: &CustomWorkloadAdapter{
+				Client:   fakeClient,
+				Scheme:   scheme,
+				Workload: configuration.UDCustomWorkload{GroupVersionKind: rolloutGVK},
+			},
+		},
 	}
 	for _, testCase := range testCases {
 		t.Run(testCase.name, func(t *testing.T) {
@@ -156,6 +169,9 @@ func TestApplySubsetTemplate(t *testing.T) {
 				appsv1alpha1.AnnotationSubsetPatchKey: `{"metadata":{"annotations":{"patched-key":"patched-value"}}}`,
 			}, t)
 			compareMap(getPodAnnotationsFromSubset(subset), map[string]string{"patched-key": "patched-value"}, t)
+			if subset.GetAnnotations()[appsv1alpha1.AnnotationSubsetTemplateHashKey] == "" {
+				t.Errorf("template hash annotation is not set")
+			}
 		})
 	}
 }
@@ -192,6 +208,9 @@ func getPodAnnotationsFromSubset(object client.Object) map[string]string {
 		return object.(*appsv1.Deployment).Spec.Template.Annotations
 	case *appsv1.StatefulSet:
 		return object.(*appsv1.StatefulSet).Spec.Template.Annotations
+	case *unstructured.Unstructured:
+		annotations, _, _ := unstructured.NestedStringMap(object.(*unstructured.Unstructured).Object, "spec", "template", "metadata", "annotations")
+		return annotations
 	}
 	return nil
 }
@@ -235,6 +254,10 @@ func newUnitedDeploymentWithAdapter(adapter Adapter) *appsv1alpha1.UnitedDeploym
 		ud.Spec.Template.StatefulSetTemplate = &appsv1alpha1.StatefulSetTemplateSpec{}
 		ud.Spec.Template.StatefulSetTemplate.Labels = map[string]string{"custom-label-1": "custom-value-1"}
 		ud.Spec.Template.StatefulSetTemplate.Annotations = map[string]string{"annotation-key": "annotation-value"}
+	case *unstructured.Unstructured:
+		ud.Spec.Template.CustomWorkloadTemplate = &appsv1alpha1.CustomWorkloadTemplateSpec{APIVersion: rolloutGVK.GroupVersion().String(), Kind: rolloutGVK.Kind}
+		ud.Spec.Template.CustomWorkloadTemplate.Labels = map[string]string{"custom-label-1": "custom-value-1"}
+		ud.Spec.Template.CustomWorkloadTemplate.Annotations = map[string]string{"annotation-key": "annotation-value"}
 	}
 	return ud
 }
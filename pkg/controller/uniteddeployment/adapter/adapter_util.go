@@ -17,6 +17,7 @@ limitations under the License.
 package adapter
 
 import (
+	"encoding/json"
 	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
@@ -24,6 +25,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
 	"k8s.io/kubernetes/pkg/controller"
+	"k8s.io/utils/ptr"
 
 	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
 )
@@ -81,6 +83,128 @@ func attachTolerations(podSpec *corev1.PodSpec, subsetConfig *appsv1alpha1.Subse
 	podSpec.Tolerations = append(podSpec.Tolerations, subsetConfig.Tolerations...)
 }
 
+// applyOverrides applies the typed overrides of the subset to the pod spec.
+func applyOverrides(podSpec *corev1.PodSpec, subsetConfig *appsv1alpha1.Subset) {
+	overrides := subsetConfig.Overrides
+	if overrides == nil {
+		return
+	}
+
+	for _, containerOverride := range overrides.Containers {
+		for i := range podSpec.Containers {
+			if podSpec.Containers[i].Name == containerOverride.Name {
+				overrideContainer(&podSpec.Containers[i], &containerOverride)
+				break
+			}
+		}
+	}
+
+	if affinity := overrides.NodeAffinity; affinity != nil {
+		if podSpec.Affinity == nil {
+			podSpec.Affinity = &corev1.Affinity{}
+		}
+		if podSpec.Affinity.NodeAffinity == nil {
+			podSpec.Affinity.NodeAffinity = &corev1.NodeAffinity{}
+		}
+		nodeAffinity := podSpec.Affinity.NodeAffinity
+		if required := affinity.RequiredDuringSchedulingIgnoredDuringExecution; required != nil && len(required.NodeSelectorTerms) > 0 {
+			if nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil || len(nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms) == 0 {
+				nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = required.DeepCopy()
+			} else {
+				// terms are ORed, so every existing term has to be combined with every term of the override
+				var terms []corev1.NodeSelectorTerm
+				for _, existing := range nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+					for _, term := range required.NodeSelectorTerms {
+						combined := existing.DeepCopy()
+						combined.MatchExpressions = append(combined.MatchExpressions, term.MatchExpressions...)
+						combined.MatchFields = append(combined.MatchFields, term.MatchFields...)
+						terms = append(terms, *combined)
+					}
+				}
+				nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms = terms
+			}
+		}
+		for _, term := range affinity.PreferredDuringSchedulingIgnoredDuringExecution {
+			nodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(nodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution, *term.DeepCopy())
+		}
+	}
+
+	podSpec.Tolerations = append(podSpec.Tolerations, overrides.Tolerations...)
+}
+
+func overrideContainer(container *corev1.Container, override *appsv1alpha1.ContainerOverride) {
+	if override.Image != "" {
+		container.Image = override.Image
+	}
+	if override.Resources != nil {
+		container.Resources.Limits = mergeResourceList(container.Resources.Limits, override.Resources.Limits)
+		container.Resources.Requests = mergeResourceList(container.Resources.Requests, override.Resources.Requests)
+	}
+	for _, env := range override.Env {
+		found := false
+		for i := range container.Env {
+			if container.Env[i].Name == env.Name {
+				container.Env[i] = *env.DeepCopy()
+				found = true
+				break
+			}
+		}
+		if !found {
+			container.Env = append(container.Env, *env.DeepCopy())
+		}
+	}
+}
+
+// applyVolumeClaimTemplateOverrides applies the volume claim template overrides of the subset.
+func applyVolumeClaimTemplateOverrides(claims []corev1.PersistentVolumeClaim, subsetConfig *appsv1alpha1.Subset) {
+	if subsetConfig.Overrides == nil {
+		return
+	}
+	for _, override := range subsetConfig.Overrides.VolumeClaimTemplates {
+		for i := range claims {
+			if claims[i].Name != override.Name {
+				continue
+			}
+			if override.StorageClassName != nil {
+				claims[i].Spec.StorageClassName = ptr.To(*override.StorageClassName)
+			}
+			if override.Resources != nil {
+				claims[i].Spec.Resources.Limits = mergeResourceList(claims[i].Spec.Resources.Limits, override.Resources.Limits)
+				claims[i].Spec.Resources.Requests = mergeResourceList(claims[i].Spec.Resources.Requests, override.Resources.Requests)
+			}
+			break
+		}
+	}
+}
+
+func mergeResourceList(list, override corev1.ResourceList) corev1.ResourceList {
+	if len(override) == 0 {
+		return list
+	}
+	if list == nil {
+		list = corev1.ResourceList{}
+	}
+	for name, quantity := range override {
+		list[name] = quantity.DeepCopy()
+	}
+	return list
+}
+
+// GetSubsetOverrides returns the overrides of the subset in JSON, or empty if there is no overrides.
+// It is recorded in the subset workload to find out whether the overrides have been changed.
+func GetSubsetOverrides(subsetConfig *appsv1alpha1.Subset) string {
+	if subsetConfig.Overrides == nil {
+		return ""
+	}
+	overrides, _ := json.Marshal(subsetConfig.Overrides)
+	return string(overrides)
+}
+
+// getTemplateHash returns the hash of the effective pod template of the subset.
+func getTemplateHash(template *corev1.PodTemplateSpec) string {
+	return controller.ComputeHash(template, nil)
+}
+
 func getRevision(objMeta metav1.Object) string {
 	if objMeta.GetLabels() == nil {
 		return ""
@@ -18,10 +18,13 @@ package adapter
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
 )
@@ -132,3 +135,102 @@ func TestCalculateUpdatedReplicas(t *testing.T) {
 		t.Errorf("Expected %d updated ready replicas, got %d", readyReplicas, updatedReady)
 	}
 }
+
+func TestApplyOverrides(t *testing.T) {
+	podSpec := &corev1.PodSpec{
+		Containers: []corev1.Container{
+			{
+				Name:  "main",
+				Image: "nginx:1.0",
+				Env:   []corev1.EnvVar{{Name: "A", Value: "1"}, {Name: "B", Value: "2"}},
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1"), corev1.ResourceMemory: resource.MustParse("1Gi")},
+				},
+			},
+			{Name: "sidecar", Image: "sidecar:1.0"},
+		},
+		Affinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{
+				{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"a"}}}},
+			}},
+		}},
+	}
+	subset := &appsv1alpha1.Subset{
+		Name: "subset-a",
+		Overrides: &appsv1alpha1.SubsetOverrides{
+			Containers: []appsv1alpha1.ContainerOverride{
+				{
+					Name:      "main",
+					Image:     "nginx:2.0",
+					Env:       []corev1.EnvVar{{Name: "B", Value: "3"}, {Name: "C", Value: "4"}},
+					Resources: &corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")}},
+				},
+				{Name: "not-exist", Image: "busybox"},
+			},
+			NodeAffinity: &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{
+					{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "disk", Operator: corev1.NodeSelectorOpIn, Values: []string{"ssd"}}}},
+					{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "disk", Operator: corev1.NodeSelectorOpIn, Values: []string{"nvme"}}}},
+				}},
+				PreferredDuringSchedulingIgnoredDuringExecution: []corev1.PreferredSchedulingTerm{
+					{Weight: 10, Preference: corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "gpu", Operator: corev1.NodeSelectorOpExists}}}},
+				},
+			},
+			Tolerations: []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists}},
+			VolumeClaimTemplates: []appsv1alpha1.VolumeClaimTemplateOverride{
+				{
+					Name:             "data",
+					StorageClassName: ptr.To("fast"),
+					Resources:        &corev1.VolumeResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("20Gi")}},
+				},
+			},
+		},
+	}
+
+	applyOverrides(podSpec, subset)
+	main := podSpec.Containers[0]
+	if main.Image != "nginx:2.0" || podSpec.Containers[1].Image != "sidecar:1.0" {
+		t.Errorf("unexpected images %s, %s", main.Image, podSpec.Containers[1].Image)
+	}
+	expectEnv := []corev1.EnvVar{{Name: "A", Value: "1"}, {Name: "B", Value: "3"}, {Name: "C", Value: "4"}}
+	if !reflect.DeepEqual(main.Env, expectEnv) {
+		t.Errorf("expect env %v, got %v", expectEnv, main.Env)
+	}
+	if cpu := main.Resources.Requests[corev1.ResourceCPU]; cpu.String() != "2" {
+		t.Errorf("expect cpu request 2, got %s", cpu.String())
+	}
+	if memory := main.Resources.Requests[corev1.ResourceMemory]; memory.String() != "1Gi" {
+		t.Errorf("expect memory request 1Gi, got %s", memory.String())
+	}
+	terms := podSpec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	if len(terms) != 2 || len(terms[0].MatchExpressions) != 2 || terms[1].MatchExpressions[1].Values[0] != "nvme" {
+		t.Errorf("unexpected required node selector terms %v", terms)
+	}
+	if len(podSpec.Affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution) != 1 {
+		t.Errorf("expect preferred term appended")
+	}
+	if len(podSpec.Tolerations) != 1 {
+		t.Errorf("expect toleration appended")
+	}
+
+	claims := []corev1.PersistentVolumeClaim{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "data"},
+			Spec: corev1.PersistentVolumeClaimSpec{Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+			}},
+		},
+		{ObjectMeta: metav1.ObjectMeta{Name: "log"}},
+	}
+	applyVolumeClaimTemplateOverrides(claims, subset)
+	if storage := claims[0].Spec.Resources.Requests[corev1.ResourceStorage]; storage.String() != "20Gi" {
+		t.Errorf("expect storage request 20Gi, got %s", storage.String())
+	}
+	if claims[0].Spec.StorageClassName == nil || *claims[0].Spec.StorageClassName != "fast" || claims[1].Spec.StorageClassName != nil {
+		t.Errorf("unexpected storage class of claims")
+	}
+
+	if GetSubsetOverrides(&appsv1alpha1.Subset{}) != "" || GetSubsetOverrides(subset) == "" {
+		t.Errorf("unexpected overrides in JSON")
+	}
+}
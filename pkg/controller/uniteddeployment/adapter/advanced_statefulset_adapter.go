@@ -182,6 +182,8 @@ func (a *AdvancedStatefulSetAdapter) ApplySubsetTemplate(ud *alpha1.UnitedDeploy
 
 	attachNodeAffinity(&set.Spec.Template.Spec, subSetConfig)
 	attachTolerations(&set.Spec.Template.Spec, subSetConfig)
+	applyOverrides(&set.Spec.Template.Spec, subSetConfig)
+	applyVolumeClaimTemplateOverrides(set.Spec.VolumeClaimTemplates, subSetConfig)
 	if subSetConfig.Patch.Raw != nil {
 		TemplateSpecBytes, _ := json.Marshal(set.Spec.Template)
 		modified, err := strategicpatch.StrategicMergePatch(TemplateSpecBytes, subSetConfig.Patch.Raw, &corev1.PodTemplateSpec{})
@@ -202,6 +204,8 @@ func (a *AdvancedStatefulSetAdapter) ApplySubsetTemplate(ud *alpha1.UnitedDeploy
 		set.Annotations = make(map[string]string)
 	}
 	set.Annotations[alpha1.AnnotationSubsetPatchKey] = string(subSetConfig.Patch.Raw)
+	set.Annotations[alpha1.AnnotationSubsetOverridesKey] = GetSubsetOverrides(subSetConfig)
+	set.Annotations[alpha1.AnnotationSubsetTemplateHashKey] = getTemplateHash(&set.Spec.Template)
 
 	return nil
 }
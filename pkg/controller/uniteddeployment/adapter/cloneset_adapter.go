@@ -139,6 +139,8 @@ func (a *CloneSetAdapter) ApplySubsetTemplate(ud *alpha1.UnitedDeployment, subse
 
 	attachNodeAffinity(&set.Spec.Template.Spec, subSetConfig)
 	attachTolerations(&set.Spec.Template.Spec, subSetConfig)
+	applyOverrides(&set.Spec.Template.Spec, subSetConfig)
+	applyVolumeClaimTemplateOverrides(set.Spec.VolumeClaimTemplates, subSetConfig)
 	if subSetConfig.Patch.Raw != nil {
 		TemplateSpecBytes, _ := json.Marshal(set.Spec.Template)
 		modified, err := strategicpatch.StrategicMergePatch(TemplateSpecBytes, subSetConfig.Patch.Raw, &corev1.PodTemplateSpec{})
@@ -159,6 +161,8 @@ func (a *CloneSetAdapter) ApplySubsetTemplate(ud *alpha1.UnitedDeployment, subse
 		set.Annotations = make(map[string]string)
 	}
 	set.Annotations[alpha1.AnnotationSubsetPatchKey] = string(subSetConfig.Patch.Raw)
+	set.Annotations[alpha1.AnnotationSubsetOverridesKey] = GetSubsetOverrides(subSetConfig)
+	set.Annotations[alpha1.AnnotationSubsetTemplateHashKey] = getTemplateHash(&set.Spec.Template)
 	return nil
 }
 
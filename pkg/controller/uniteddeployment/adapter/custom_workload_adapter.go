@@ -0,0 +1,309 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utiljson "k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+	"github.com/openkruise/kruise/pkg/util/configuration"
+)
+
+const (
+	defaultReplicasPath            = "spec.replicas"
+	defaultSelectorPath            = "spec.selector"
+	defaultTemplatePath            = "spec.template"
+	defaultStatusReplicasPath      = "status.replicas"
+	defaultStatusReadyReplicasPath = "status.readyReplicas"
+	defaultObservedGenerationPath  = "status.observedGeneration"
+)
+
+// CustomWorkloadAdapter implements the Adapter interface for the custom workloads configured in the whitelist.
+// The fields of workload are read and written by the field paths of configuration.UDCustomWorkload.
+type CustomWorkloadAdapter struct {
+	client.Client
+
+	Scheme   *runtime.Scheme
+	Workload configuration.UDCustomWorkload
+}
+
+// NewResourceObject creates a empty custom workload object.
+func (a *CustomWorkloadAdapter) NewResourceObject() client.Object {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(a.Workload.GroupVersionKind)
+	return obj
+}
+
+// NewResourceListObject creates a empty custom workload list object.
+func (a *CustomWorkloadAdapter) NewResourceListObject() client.ObjectList {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(a.Workload.GroupVersion().WithKind(a.Workload.Kind + "List"))
+	return list
+}
+
+// GetStatusObservedGeneration returns the observed generation of the subset.
+func (a *CustomWorkloadAdapter) GetStatusObservedGeneration(obj metav1.Object) int64 {
+	generation, _, _ := unstructured.NestedInt64(obj.(*unstructured.Unstructured).Object,
+		fieldPath(a.Workload.ObservedGenerationPath, defaultObservedGenerationPath)...)
+	return generation
+}
+
+// GetSubsetPods returns the pods matching the selector of the custom workload. Pods are not claimed by owner
+// references, because the custom workload may manage pods through intermediate objects.
+func (a *CustomWorkloadAdapter) GetSubsetPods(obj metav1.Object) ([]*corev1.Pod, error) {
+	selector, err := a.getSelector(obj.(*unstructured.Unstructured))
+	if err != nil {
+		return nil, err
+	}
+	labelSelector, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	if labelSelector.Empty() {
+		return nil, nil
+	}
+	podList := &corev1.PodList{}
+	if err = a.Client.List(context.TODO(), podList, client.InNamespace(obj.GetNamespace()),
+		&client.ListOptions{LabelSelector: labelSelector}); err != nil {
+		return nil, err
+	}
+	pods := make([]*corev1.Pod, 0, len(podList.Items))
+	for i := range podList.Items {
+		pods = append(pods, &podList.Items[i])
+	}
+	return pods, nil
+}
+
+// GetSpecReplicas returns the replicas of the custom workload.
+func (a *CustomWorkloadAdapter) GetSpecReplicas(obj metav1.Object) *int32 {
+	replicas, found, err := unstructured.NestedInt64(obj.(*unstructured.Unstructured).Object,
+		fieldPath(a.Workload.ReplicasPath, defaultReplicasPath)...)
+	if !found || err != nil {
+		return nil
+	}
+	result := int32(replicas)
+	return &result
+}
+
+// SetMaxUnavailable is not supported for custom workload.
+func (a *CustomWorkloadAdapter) SetMaxUnavailable(obj metav1.Object, _ int32) metav1.Object {
+	return obj
+}
+
+// GetSpecPartition returns the partition of the custom workload if the partition path is configured.
+func (a *CustomWorkloadAdapter) GetSpecPartition(obj metav1.Object, _ []*corev1.Pod) *int32 {
+	if a.Workload.PartitionPath == "" {
+		return nil
+	}
+	partition, found, err := unstructured.NestedInt64(obj.(*unstructured.Unstructured).Object, fieldPath(a.Workload.PartitionPath, "")...)
+	if !found || err != nil {
+		return nil
+	}
+	result := int32(partition)
+	return &result
+}
+
+// GetStatusReplicas returns the replicas from the custom workload status.
+func (a *CustomWorkloadAdapter) GetStatusReplicas(obj metav1.Object) int32 {
+	replicas, _, _ := unstructured.NestedInt64(obj.(*unstructured.Unstructured).Object,
+		fieldPath(a.Workload.StatusReplicasPath, defaultStatusReplicasPath)...)
+	return int32(replicas)
+}
+
+// GetStatusReadyReplicas returns the ready replicas from the custom workload status.
+func (a *CustomWorkloadAdapter) GetStatusReadyReplicas(obj metav1.Object) int32 {
+	readyReplicas, _, _ := unstructured.NestedInt64(obj.(*unstructured.Unstructured).Object,
+		fieldPath(a.Workload.StatusReadyReplicasPath, defaultStatusReadyReplicasPath)...)
+	return int32(readyReplicas)
+}
+
+// GetSubsetFailure returns the failure information of the subset.
+func (a *CustomWorkloadAdapter) GetSubsetFailure() *string {
+	return nil
+}
+
+// ApplySubsetTemplate updates the subset to the latest revision, depending on the CustomWorkloadTemplate.
+func (a *CustomWorkloadAdapter) ApplySubsetTemplate(ud *alpha1.UnitedDeployment, subsetName, revision string, replicas, partition int32, obj runtime.Object) error {
+	set := obj.(*unstructured.Unstructured)
+	template := ud.Spec.Template.CustomWorkloadTemplate
+	if template == nil {
+		return fmt.Errorf("customWorkloadTemplate of UnitedDeployment is nil")
+	}
+
+	var subSetConfig *alpha1.Subset
+	for _, subset := range ud.Spec.Topology.Subsets {
+		if subset.Name == subsetName {
+			subSetConfig = &subset
+			break
+		}
+	}
+	if subSetConfig == nil {
+		return fmt.Errorf("fail to find subset config %s", subsetName)
+	}
+
+	set.SetGroupVersionKind(a.Workload.GroupVersionKind)
+	set.SetNamespace(ud.Namespace)
+
+	labels := set.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	for k, v := range template.Labels {
+		labels[k] = v
+	}
+	for k, v := range ud.Spec.Selector.MatchLabels {
+		labels[k] = v
+	}
+	labels[alpha1.ControllerRevisionHashLabelKey] = revision
+	// record the subset name as a label
+	labels[alpha1.SubSetNameLabelKey] = subsetName
+	set.SetLabels(labels)
+
+	annotations := set.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	for k, v := range template.Annotations {
+		annotations[k] = v
+	}
+	annotations[alpha1.AnnotationSubsetPatchKey] = string(subSetConfig.Patch.Raw)
+	annotations[alpha1.AnnotationSubsetOverridesKey] = GetSubsetOverrides(subSetConfig)
+	set.SetAnnotations(annotations)
+
+	set.SetGenerateName(getSubsetPrefix(ud.Name, subsetName))
+
+	if err := controllerutil.SetControllerReference(ud, set, a.Scheme); err != nil {
+		return err
+	}
+
+	spec := map[string]interface{}{}
+	if len(template.Spec.Raw) > 0 {
+		if err := utiljson.Unmarshal(template.Spec.Raw, &spec); err != nil {
+			return fmt.Errorf("fail to unmarshal spec of customWorkloadTemplate: %v", err)
+		}
+	}
+	if err := unstructured.SetNestedField(set.Object, spec, "spec"); err != nil {
+		return err
+	}
+
+	selector := ud.Spec.Selector.DeepCopy()
+	selector.MatchLabels[alpha1.SubSetNameLabelKey] = subsetName
+	selectorObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(selector)
+	if err != nil {
+		return err
+	}
+	if err = unstructured.SetNestedMap(set.Object, selectorObj, fieldPath(a.Workload.SelectorPath, defaultSelectorPath)...); err != nil {
+		return err
+	}
+	if err = unstructured.SetNestedField(set.Object, int64(replicas), fieldPath(a.Workload.ReplicasPath, defaultReplicasPath)...); err != nil {
+		return err
+	}
+	if a.Workload.PartitionPath != "" {
+		if err = unstructured.SetNestedField(set.Object, int64(partition), fieldPath(a.Workload.PartitionPath, "")...); err != nil {
+			return err
+		}
+	}
+
+	podTemplate, err := a.applyPodTemplate(set, subSetConfig, subsetName, revision)
+	if err != nil {
+		return err
+	}
+	annotations[alpha1.AnnotationSubsetTemplateHashKey] = getTemplateHash(podTemplate)
+	set.SetAnnotations(annotations)
+	podTemplateObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(podTemplate)
+	if err != nil {
+		return err
+	}
+	return unstructured.SetNestedMap(set.Object, podTemplateObj, fieldPath(a.Workload.TemplatePath, defaultTemplatePath)...)
+}
+
+// PostUpdate does some works after subset updated.
+func (a *CustomWorkloadAdapter) PostUpdate(_ *alpha1.UnitedDeployment, _ runtime.Object, _ string, _ int32) error {
+	return nil
+}
+
+// applyPodTemplate returns the pod template of custom workload with subset labels, node affinity, tolerations,
+// overrides and patch applied.
+func (a *CustomWorkloadAdapter) applyPodTemplate(set *unstructured.Unstructured, subSetConfig *alpha1.Subset, subsetName, revision string) (*corev1.PodTemplateSpec, error) {
+	podTemplate := &corev1.PodTemplateSpec{}
+	templateObj, found, err := unstructured.NestedMap(set.Object, fieldPath(a.Workload.TemplatePath, defaultTemplatePath)...)
+	if err != nil {
+		return nil, err
+	} else if found {
+		if err = runtime.DefaultUnstructuredConverter.FromUnstructured(templateObj, podTemplate); err != nil {
+			return nil, err
+		}
+	}
+
+	if podTemplate.Labels == nil {
+		podTemplate.Labels = map[string]string{}
+	}
+	podTemplate.Labels[alpha1.SubSetNameLabelKey] = subsetName
+	podTemplate.Labels[alpha1.ControllerRevisionHashLabelKey] = revision
+
+	attachNodeAffinity(&podTemplate.Spec, subSetConfig)
+	attachTolerations(&podTemplate.Spec, subSetConfig)
+	applyOverrides(&podTemplate.Spec, subSetConfig)
+	if subSetConfig.Patch.Raw != nil {
+		TemplateSpecBytes, _ := json.Marshal(podTemplate)
+		modified, err := strategicpatch.StrategicMergePatch(TemplateSpecBytes, subSetConfig.Patch.Raw, &corev1.PodTemplateSpec{})
+		if err != nil {
+			klog.ErrorS(err, "Failed to merge patch raw", "patch", subSetConfig.Patch.Raw)
+			return nil, err
+		}
+		patchedTemplateSpec := &corev1.PodTemplateSpec{}
+		if err = json.Unmarshal(modified, patchedTemplateSpec); err != nil {
+			klog.ErrorS(err, "Failed to unmarshal modified JSON to podTemplateSpec", "JSON", modified)
+			return nil, err
+		}
+		podTemplate = patchedTemplateSpec
+		klog.V(2).InfoS("Custom workload was patched successfully", "kind", a.Workload.Kind,
+			"workload", klog.KRef(set.GetNamespace(), set.GetGenerateName()), "patch", subSetConfig.Patch.Raw)
+	}
+	return podTemplate, nil
+}
+
+func (a *CustomWorkloadAdapter) getSelector(set *unstructured.Unstructured) (*metav1.LabelSelector, error) {
+	selector := &metav1.LabelSelector{}
+	selectorObj, found, err := unstructured.NestedMap(set.Object, fieldPath(a.Workload.SelectorPath, defaultSelectorPath)...)
+	if err != nil || !found {
+		return selector, err
+	}
+	err = runtime.DefaultUnstructuredConverter.FromUnstructured(selectorObj, selector)
+	return selector, err
+}
+
+// fieldPath splits the dot-separated path into fields, and uses the default path if it is empty.
+func fieldPath(path, defaultPath string) []string {
+	if path == "" {
+		path = defaultPath
+	}
+	return strings.Split(path, ".")
+}
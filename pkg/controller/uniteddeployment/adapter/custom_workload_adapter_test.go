@@ -0,0 +1,103 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+	"github.com/openkruise/kruise/pkg/util/configuration"
+)
+
+func TestCustomWorkloadAdapter(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = appsv1alpha1.AddToScheme(scheme)
+
+	newPod := func(name, subsetName string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{
+			"selector-key":                  "selector-value",
+			appsv1alpha1.SubSetNameLabelKey: subsetName,
+		}}}
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newPod("pod-a", "subset-a"), newPod("pod-b", "subset-b")).Build()
+	adapter := &CustomWorkloadAdapter{
+		Client: fakeClient,
+		Scheme: scheme,
+		Workload: configuration.UDCustomWorkload{
+			GroupVersionKind:   rolloutGVK,
+			TemplatePath:       "spec.workload.template",
+			PartitionPath:      "spec.strategy.partition",
+			StatusReplicasPath: "status.availableReplicas",
+		},
+	}
+	ud := newUnitedDeploymentWithAdapter(adapter)
+	ud.Namespace = "default"
+	ud.Spec.Template.CustomWorkloadTemplate.Spec = runtime.RawExtension{
+		Raw: []byte(`{"minReadySeconds":10,"workload":{"template":{"spec":{"containers":[{"name":"main","image":"nginx"}]}}}}`),
+	}
+
+	subset := adapter.NewResourceObject()
+	if err := adapter.ApplySubsetTemplate(ud, "subset-a", "abcd", 3, 1, subset); err != nil {
+		t.Fatalf("ApplySubsetTemplate() error = %v", err)
+	}
+	obj := subset.(*unstructured.Unstructured)
+	if obj.GroupVersionKind() != rolloutGVK {
+		t.Fatalf("expect gvk %v, but got %v", rolloutGVK, obj.GroupVersionKind())
+	}
+	if replicas := adapter.GetSpecReplicas(obj); replicas == nil || *replicas != 3 {
+		t.Fatalf("expect replicas 3, but got %v", replicas)
+	}
+	if partition := adapter.GetSpecPartition(obj, nil); partition == nil || *partition != 1 {
+		t.Fatalf("expect partition 1, but got %v", partition)
+	}
+	if minReadySeconds, _, _ := unstructured.NestedInt64(obj.Object, "spec", "minReadySeconds"); minReadySeconds != 10 {
+		t.Fatalf("expect minReadySeconds 10 kept in spec, but got %d", minReadySeconds)
+	}
+	image, _, _ := unstructured.NestedSlice(obj.Object, "spec", "workload", "template", "spec", "containers")
+	if len(image) != 1 {
+		t.Fatalf("expect containers kept in pod template, but got %v", image)
+	}
+	podLabels, _, _ := unstructured.NestedStringMap(obj.Object, "spec", "workload", "template", "metadata", "labels")
+	compareMap(podLabels, map[string]string{
+		appsv1alpha1.SubSetNameLabelKey:             "subset-a",
+		appsv1alpha1.ControllerRevisionHashLabelKey: "abcd",
+	}, t)
+
+	_ = unstructured.SetNestedField(obj.Object, int64(2), "status", "availableReplicas")
+	_ = unstructured.SetNestedField(obj.Object, int64(1), "status", "readyReplicas")
+	_ = unstructured.SetNestedField(obj.Object, int64(5), "status", "observedGeneration")
+	if adapter.GetStatusReplicas(obj) != 2 || adapter.GetStatusReadyReplicas(obj) != 1 || adapter.GetStatusObservedGeneration(obj) != 5 {
+		t.Fatalf("unexpected status of %v", obj.Object["status"])
+	}
+
+	pods, err := adapter.GetSubsetPods(obj)
+	if err != nil {
+		t.Fatalf("GetSubsetPods() error = %v", err)
+	}
+	if len(pods) != 1 || pods[0].Name != "pod-a" {
+		t.Fatalf("expect pod-a of subset, but got %v", pods)
+	}
+}
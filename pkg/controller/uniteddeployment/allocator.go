@@ -60,6 +60,9 @@ type ReplicaAllocator interface {
 }
 
 func NewReplicaAllocator(ud *appsv1alpha1.UnitedDeployment) ReplicaAllocator {
+	if ud.Spec.Topology.IsWeighted() {
+		return &weightedAllocator{ud}
+	}
 	if ud.Spec.Topology.ScheduleStrategy.ShouldReserveUnschedulablePods() {
 		return &reservationAllocator{ud}
 	}
@@ -355,6 +358,136 @@ func (ac *adaptiveAllocator) Alloc(existingSubsets map[string]*Subset) (map[stri
 	return nextReplicas, nil
 }
 
+// weightedAllocator is the allocator for subsets with weights. It distributes the replicas in proportion to
+// the weights of subsets within their min/max replicas. With adaptive strategy, the unschedulable subsets are
+// limited to their schedulable replicas, and the replicas beyond are distributed to other subsets by weights.
+// Once the unschedulable subsets recover, the replicas converge back to the weights.
+type weightedAllocator struct {
+	*appsv1alpha1.UnitedDeployment
+}
+
+func (ac *weightedAllocator) Alloc(existingSubsets map[string]*Subset) (map[string]int32, error) {
+	var replicas int32
+	if ac.Spec.Replicas != nil {
+		replicas = *ac.Spec.Replicas
+	}
+	minReplicasMap, maxReplicasMap, err := calculateRawMinMaxMap(replicas, ac.Spec.Topology.Subsets)
+	if err != nil {
+		return nil, err
+	}
+	weights := make(map[string]int32, len(ac.Spec.Topology.Subsets))
+	for _, subset := range ac.Spec.Topology.Subsets {
+		if subset.Weight != nil {
+			weights[subset.Name] = *subset.Weight
+		}
+		if !ac.Spec.Topology.ScheduleStrategy.IsAdaptive() || !isSubSetUnschedulable(subset.Name, existingSubsets) {
+			continue
+		}
+		// the pending pods of unschedulable subset are not counted into its capacity
+		subsetStatus := existingSubsets[subset.Name].Status
+		capacity := max(subsetStatus.Replicas-subsetStatus.UnschedulableStatus.PendingPods, 0)
+		maxReplicasMap[subset.Name] = min(maxReplicasMap[subset.Name], capacity)
+		minReplicasMap[subset.Name] = min(minReplicasMap[subset.Name], maxReplicasMap[subset.Name])
+		klog.V(4).InfoS("adjusted max replicas for unschedulable subset", "subset", subset.Name,
+			"capacity", capacity, "unitedDeployment", klog.KObj(ac.UnitedDeployment))
+	}
+	nextReplicas := allocateByWeights(replicas, weights, minReplicasMap, maxReplicasMap, ac.Spec.Topology.Subsets)
+	klog.V(4).InfoS("got UnitedDeployment next replicas", "unitedDeployment",
+		klog.KObj(ac.UnitedDeployment), "nextReplicas", nextReplicas)
+	return nextReplicas, nil
+}
+
+// allocateByWeights distributes the replicas to subsets in proportion to their weights. The subsets whose
+// proportional replicas exceed maxReplicas or fall below minReplicas are fixed to the bound, and the rest
+// replicas are distributed again among the other subsets, until all the proportional replicas are within bounds.
+// If the sum of minReplicas is not less than replicas, it falls back to satisfy the minReplicas in order.
+func allocateByWeights(replicas int32, weights, minReplicasMap, maxReplicasMap map[string]int32, subsets []appsv1alpha1.Subset) map[string]int32 {
+	var sumMinReplicas int64
+	for _, subset := range subsets {
+		sumMinReplicas += int64(minReplicasMap[subset.Name])
+	}
+	if sumMinReplicas >= int64(replicas) {
+		return allocateByMinMaxMap(replicas, minReplicasMap, minReplicasMap, subsets)
+	}
+
+	fixed := make(map[string]int32, len(subsets))
+	for _, subset := range subsets {
+		if weights[subset.Name] <= 0 {
+			fixed[subset.Name] = minReplicasMap[subset.Name]
+		}
+	}
+	for {
+		rest := replicas
+		var active []string
+		for _, subset := range subsets {
+			if fixedReplicas, ok := fixed[subset.Name]; ok {
+				rest -= fixedReplicas
+			} else {
+				active = append(active, subset.Name)
+			}
+		}
+		shares := splitByWeights(max(rest, 0), active, weights)
+		// fix the subsets exceeding maxReplicas firstly, so that the rest replicas are enough for minReplicas
+		changed := false
+		for _, name := range active {
+			if shares[name] > maxReplicasMap[name] {
+				fixed[name] = maxReplicasMap[name]
+				changed = true
+			}
+		}
+		if !changed {
+			for _, name := range active {
+				if shares[name] < minReplicasMap[name] {
+					fixed[name] = minReplicasMap[name]
+					changed = true
+				}
+			}
+		}
+		if !changed {
+			for name, share := range shares {
+				fixed[name] = share
+			}
+			return fixed
+		}
+	}
+}
+
+// splitByWeights splits the replicas in proportion to the weights with the largest remainder method,
+// and the remainder ties are broken by the order of subsets.
+func splitByWeights(replicas int32, names []string, weights map[string]int32) map[string]int32 {
+	shares := make(map[string]int32, len(names))
+	var sumWeights int64
+	for _, name := range names {
+		sumWeights += int64(weights[name])
+	}
+	if sumWeights == 0 {
+		return shares
+	}
+	allocated := int32(0)
+	remainders := make([]int64, len(names))
+	for i, name := range names {
+		product := int64(replicas) * int64(weights[name])
+		shares[name] = int32(product / sumWeights)
+		remainders[i] = product % sumWeights
+		allocated += shares[name]
+	}
+	indexes := make([]int, len(names))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(i, j int) bool {
+		return remainders[indexes[i]] > remainders[indexes[j]]
+	})
+	for _, i := range indexes {
+		if allocated >= replicas {
+			break
+		}
+		shares[names[i]]++
+		allocated++
+	}
+	return shares
+}
+
 func allocateByMinMaxMap(replicas int32, minReplicasMap, maxReplicasMap map[string]int32, subsets []appsv1alpha1.Subset) map[string]int32 {
 	allocated := int32(0)
 	// Step 1: satisfy the minimum replicas of each subset firstly.
@@ -876,3 +876,120 @@ func createSubset(name string, replicas int32) *nameToReplicas {
 		SubsetName: name,
 	}
 }
+
+func TestWeightedAllocation(t *testing.T) {
+	cases := []struct {
+		name            string
+		replicas        int32
+		weights         []int32
+		maxReplicas     []int32
+		subsetReplicas  []int32
+		pendingPods     []int32
+		adaptive        bool
+		desiredReplicas []int32
+	}{
+		{
+			name:            "distribute by weights",
+			replicas:        10,
+			weights:         []int32{1, 2, 2},
+			maxReplicas:     []int32{-1, -1, -1},
+			desiredReplicas: []int32{2, 4, 4},
+		},
+		{
+			name:            "remainder goes to the former subsets",
+			replicas:        10,
+			weights:         []int32{1, 1, 1},
+			maxReplicas:     []int32{-1, -1, -1},
+			desiredReplicas: []int32{4, 3, 3},
+		},
+		{
+			name:            "limited by max replicas",
+			replicas:        10,
+			weights:         []int32{1, 1, 3},
+			maxReplicas:     []int32{-1, -1, 4},
+			desiredReplicas: []int32{3, 3, 4},
+		},
+		{
+			name:            "zero weight",
+			replicas:        10,
+			weights:         []int32{0, 1, 1},
+			maxReplicas:     []int32{-1, -1, -1},
+			desiredReplicas: []int32{0, 5, 5},
+		},
+		{
+			name:            "pending pods are ignored in fixed strategy",
+			replicas:        10,
+			weights:         []int32{1, 1},
+			maxReplicas:     []int32{-1, -1},
+			subsetReplicas:  []int32{5, 5},
+			pendingPods:     []int32{3, 0},
+			desiredReplicas: []int32{5, 5},
+		},
+		{
+			name:            "unschedulable subset is limited to its capacity",
+			replicas:        10,
+			weights:         []int32{1, 1},
+			maxReplicas:     []int32{-1, -1},
+			subsetReplicas:  []int32{5, 5},
+			pendingPods:     []int32{3, 0},
+			adaptive:        true,
+			desiredReplicas: []int32{2, 8},
+		},
+		{
+			name:            "converge back to weights after recovering",
+			replicas:        10,
+			weights:         []int32{1, 1},
+			maxReplicas:     []int32{-1, -1},
+			subsetReplicas:  []int32{2, 8},
+			pendingPods:     []int32{0, 0},
+			adaptive:        true,
+			desiredReplicas: []int32{5, 5},
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			ud := &appsv1alpha1.UnitedDeployment{}
+			ud.Spec.Replicas = pointer.Int32(cs.replicas)
+			if cs.adaptive {
+				ud.Spec.Topology.ScheduleStrategy.Type = appsv1alpha1.AdaptiveUnitedDeploymentScheduleStrategyType
+			}
+			existingSubsets := map[string]*Subset{}
+			for index := range cs.weights {
+				name := fmt.Sprintf("subset-%d", index)
+				var maxReplicas *intstr.IntOrString
+				if cs.maxReplicas[index] != -1 {
+					m := intstr.FromInt32(cs.maxReplicas[index])
+					maxReplicas = &m
+				}
+				ud.Spec.Topology.Subsets = append(ud.Spec.Topology.Subsets, appsv1alpha1.Subset{
+					Name:        name,
+					Weight:      pointer.Int32(cs.weights[index]),
+					MaxReplicas: maxReplicas,
+				})
+				if cs.subsetReplicas != nil {
+					subset := &Subset{}
+					subset.Spec.Replicas = cs.subsetReplicas[index]
+					subset.Status.Replicas = cs.subsetReplicas[index]
+					subset.Status.UnschedulableStatus.PendingPods = cs.pendingPods[index]
+					subset.Status.UnschedulableStatus.Unschedulable = cs.adaptive && cs.pendingPods[index] > 0
+					existingSubsets[name] = subset
+				}
+			}
+
+			allocator := NewReplicaAllocator(ud)
+			if _, ok := allocator.(*weightedAllocator); !ok {
+				t.Fatalf("expect weightedAllocator, but got %T", allocator)
+			}
+			result, err := allocator.Alloc(existingSubsets)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			for index := range cs.desiredReplicas {
+				if result[fmt.Sprintf("subset-%d", index)] != cs.desiredReplicas[index] {
+					t.Fatalf("expect %v, but got %v", cs.desiredReplicas, result)
+				}
+			}
+		})
+	}
+}
@@ -93,6 +93,7 @@ type SubsetUpdate struct {
 	Replicas  int32
 	Partition int32
 	Patch     string
+	Overrides string
 }
 
 // ResourceRef stores the Subset resource it represents.
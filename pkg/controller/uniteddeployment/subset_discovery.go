@@ -0,0 +1,153 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package uniteddeployment
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+	utilclient "github.com/openkruise/kruise/pkg/util/client"
+)
+
+// discoverSubsets replaces the subsets of ud in memory with the ones generated from the nodes,
+// and records them in the status. It does nothing if SubsetDiscovery is not set.
+func (r *ReconcileUnitedDeployment) discoverSubsets(ud *appsv1alpha1.UnitedDeployment) error {
+	discovery := ud.Spec.Topology.SubsetDiscovery
+	if discovery == nil {
+		ud.Status.DiscoveredSubsets = nil
+		return nil
+	}
+
+	selector := labels.Everything()
+	if discovery.NodeSelector != nil {
+		var err error
+		if selector, err = metav1.LabelSelectorAsSelector(discovery.NodeSelector); err != nil {
+			return err
+		}
+	}
+	nodes := &corev1.NodeList{}
+	if err := r.List(context.TODO(), nodes, &client.ListOptions{LabelSelector: selector}); err != nil {
+		return err
+	}
+	values := sets.NewString()
+	for i := range nodes.Items {
+		if value := getDiscoveredTopologyValue(discovery, &nodes.Items[i]); value != "" {
+			values.Insert(value)
+		}
+	}
+
+	// keep the subsets whose topology values no longer exist on the nodes until all their pods are gone,
+	// otherwise the pods would be deleted along with the subset workloads.
+	for _, previous := range ud.Status.DiscoveredSubsets {
+		if values.Has(previous.TopologyValue) {
+			continue
+		}
+		owned, err := r.subsetOwnsPods(ud, previous.Name)
+		if err != nil {
+			return err
+		}
+		if owned {
+			klog.InfoS("Kept discovered subset which still owns pods", "unitedDeployment", klog.KObj(ud), "subset", previous.Name, "value", previous.TopologyValue)
+			values.Insert(previous.TopologyValue)
+		}
+	}
+
+	ud.Spec.Topology.Subsets, ud.Status.DiscoveredSubsets = generateDiscoveredSubsets(discovery, values)
+	klog.V(4).InfoS("Discovered subsets of UnitedDeployment", "unitedDeployment", klog.KObj(ud), "subsets", ud.Status.DiscoveredSubsets)
+	return nil
+}
+
+// subsetOwnsPods returns true if there are pods of ud in the subset.
+func (r *ReconcileUnitedDeployment) subsetOwnsPods(ud *appsv1alpha1.UnitedDeployment, subsetName string) (bool, error) {
+	selector, err := metav1.LabelSelectorAsSelector(ud.Spec.Selector)
+	if err != nil {
+		return false, err
+	}
+	requirement, err := labels.NewRequirement(appsv1alpha1.SubSetNameLabelKey, selection.Equals, []string{subsetName})
+	if err != nil {
+		return false, err
+	}
+	pods := &corev1.PodList{}
+	if err = r.List(context.TODO(), pods, client.InNamespace(ud.Namespace),
+		client.MatchingLabelsSelector{Selector: selector.Add(*requirement)}, utilclient.DisableDeepCopy); err != nil {
+		return false, err
+	}
+	return len(pods.Items) > 0, nil
+}
+
+// generateDiscoveredSubsets generates one subset for each distinct topology value, sorted by the value.
+func generateDiscoveredSubsets(discovery *appsv1alpha1.SubsetDiscovery, values sets.String) ([]appsv1alpha1.Subset, []appsv1alpha1.DiscoveredSubset) {
+	var subsets []appsv1alpha1.Subset
+	var discovered []appsv1alpha1.DiscoveredSubset
+	names := sets.NewString()
+	for _, value := range values.List() {
+		name := subsetNameForTopologyValue(value)
+		if errs := validation.IsDNS1123Label(name); len(errs) > 0 {
+			klog.InfoS("Skipped topology value which can not be used as subset name", "topologyKey", discovery.TopologyKey, "value", value, "reason", strings.Join(errs, ", "))
+			continue
+		}
+		if names.Has(name) {
+			klog.InfoS("Skipped topology value whose subset name conflicts with another value", "topologyKey", discovery.TopologyKey, "value", value, "subset", name)
+			continue
+		}
+		names.Insert(name)
+
+		subsets = append(subsets, appsv1alpha1.Subset{
+			Name: name,
+			NodeSelectorTerm: corev1.NodeSelectorTerm{
+				MatchExpressions: []corev1.NodeSelectorRequirement{
+					{Key: discovery.TopologyKey, Operator: corev1.NodeSelectorOpIn, Values: []string{value}},
+				},
+			},
+			Tolerations: discovery.Tolerations,
+			Patch:       *discovery.Patch.DeepCopy(),
+		})
+		discovered = append(discovered, appsv1alpha1.DiscoveredSubset{Name: name, TopologyValue: value})
+	}
+	sort.SliceStable(discovered, func(i, j int) bool { return discovered[i].Name < discovered[j].Name })
+	sort.SliceStable(subsets, func(i, j int) bool { return subsets[i].Name < subsets[j].Name })
+	return subsets, discovered
+}
+
+// getDiscoveredTopologyValue returns the value of topology key on the node, or empty if the node is not matched
+// by the node selector of discovery. Unschedulable or not ready nodes still count, so that a cordoned or failed
+// topology is not removed with its pods, which are left to the failover of subsets.
+func getDiscoveredTopologyValue(discovery *appsv1alpha1.SubsetDiscovery, node *corev1.Node) string {
+	if discovery.NodeSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(discovery.NodeSelector)
+		if err != nil || !selector.Matches(labels.Set(node.Labels)) {
+			return ""
+		}
+	}
+	return node.Labels[discovery.TopologyKey]
+}
+
+// subsetNameForTopologyValue converts a label value to a DNS label.
+func subsetNameForTopologyValue(value string) string {
+	return strings.NewReplacer(".", "-", "_", "-").Replace(strings.ToLower(value))
+}
@@ -0,0 +1,247 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package uniteddeployment
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+)
+
+func TestGenerateDiscoveredSubsets(t *testing.T) {
+	const zoneKey = "topology.kubernetes.io/zone"
+	newNode := func(name, zone string) corev1.Node {
+		return *newDiscoveryNode(name, zoneKey, zone, true)
+	}
+	discovery := &appsv1alpha1.SubsetDiscovery{
+		TopologyKey: zoneKey,
+		Tolerations: []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists}},
+		Patch:       runtime.RawExtension{Raw: []byte(`{"metadata":{"labels":{"a":"b"}}}`)},
+	}
+	nodes := []corev1.Node{
+		newNode("n1", "cn-hangzhou-b"),
+		newNode("n2", "cn-hangzhou-a"),
+		newNode("n3", "cn-hangzhou-b"),
+		newNode("n4", ""),
+		newNode("n5", "Zone_C"),
+		newNode("n6", "zone.c"),
+	}
+
+	values := sets.NewString()
+	for i := range nodes {
+		if value := getDiscoveredTopologyValue(discovery, &nodes[i]); value != "" {
+			values.Insert(value)
+		}
+	}
+	subsets, discovered := generateDiscoveredSubsets(discovery, values)
+	expectDiscovered := []appsv1alpha1.DiscoveredSubset{
+		{Name: "cn-hangzhou-a", TopologyValue: "cn-hangzhou-a"},
+		{Name: "cn-hangzhou-b", TopologyValue: "cn-hangzhou-b"},
+		{Name: "zone-c", TopologyValue: "Zone_C"},
+	}
+	if !reflect.DeepEqual(discovered, expectDiscovered) {
+		t.Fatalf("expect discovered subsets %v, got %v", expectDiscovered, discovered)
+	}
+	if len(subsets) != len(expectDiscovered) {
+		t.Fatalf("expect %d subsets, got %d", len(expectDiscovered), len(subsets))
+	}
+	for i, subset := range subsets {
+		if subset.Name != expectDiscovered[i].Name {
+			t.Errorf("expect subset %s, got %s", expectDiscovered[i].Name, subset.Name)
+		}
+		expectTerm := corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{
+			{Key: zoneKey, Operator: corev1.NodeSelectorOpIn, Values: []string{expectDiscovered[i].TopologyValue}},
+		}}
+		if !reflect.DeepEqual(subset.NodeSelectorTerm, expectTerm) {
+			t.Errorf("subset %s: expect node selector term %v, got %v", subset.Name, expectTerm, subset.NodeSelectorTerm)
+		}
+		if !reflect.DeepEqual(subset.Tolerations, discovery.Tolerations) || string(subset.Patch.Raw) != string(discovery.Patch.Raw) {
+			t.Errorf("subset %s: shared tolerations or patch not applied", subset.Name)
+		}
+		if subset.Replicas != nil {
+			t.Errorf("subset %s: replicas should be distributed evenly", subset.Name)
+		}
+	}
+}
+
+func TestDiscoverSubsets(t *testing.T) {
+	const zoneKey = "topology.kubernetes.io/zone"
+	cordoned := newDiscoveryNode("n2", zoneKey, "zone-b", true)
+	cordoned.Spec.Unschedulable = true
+	newPod := func(name, subset string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name,
+			Labels: map[string]string{"app": "demo", appsv1alpha1.SubSetNameLabelKey: subset}}}
+	}
+
+	cases := []struct {
+		name             string
+		nodes            []client.Object
+		pods             []client.Object
+		previous         []appsv1alpha1.DiscoveredSubset
+		expectDiscovered []string
+	}{
+		{
+			name: "cordoned and not ready zones are discovered",
+			nodes: []client.Object{
+				newDiscoveryNode("n1", zoneKey, "zone-a", true),
+				cordoned,
+				newDiscoveryNode("n3", zoneKey, "zone-c", false),
+			},
+			expectDiscovered: []string{"zone-a", "zone-b", "zone-c"},
+		},
+		{
+			name:  "subsets that still own pods are kept",
+			nodes: []client.Object{newDiscoveryNode("n1", zoneKey, "zone-a", true)},
+			pods:  []client.Object{newPod("pod-b", "zone-b"), newPod("pod-a", "zone-a")},
+			previous: []appsv1alpha1.DiscoveredSubset{
+				{Name: "zone-a", TopologyValue: "zone-a"},
+				{Name: "zone-b", TopologyValue: "zone-b"},
+				{Name: "zone-c", TopologyValue: "zone-c"},
+			},
+			expectDiscovered: []string{"zone-a", "zone-b"},
+		},
+		{
+			name: "subsets are kept when no node is listed",
+			pods: []client.Object{newPod("pod-a", "zone-a"), newPod("pod-b", "zone-b")},
+			previous: []appsv1alpha1.DiscoveredSubset{
+				{Name: "zone-a", TopologyValue: "zone-a"},
+				{Name: "zone-b", TopologyValue: "zone-b"},
+			},
+			expectDiscovered: []string{"zone-a", "zone-b"},
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			ud := &appsv1alpha1.UnitedDeployment{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "discovery"}}
+			ud.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"app": "demo"}}
+			ud.Spec.Topology.SubsetDiscovery = &appsv1alpha1.SubsetDiscovery{TopologyKey: zoneKey}
+			ud.Status.DiscoveredSubsets = cs.previous
+			c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(cs.nodes...).WithObjects(cs.pods...).Build()
+			r := &ReconcileUnitedDeployment{Client: c}
+			if err := r.discoverSubsets(ud); err != nil {
+				t.Fatalf("failed to discover subsets: %v", err)
+			}
+			var discovered, subsets []string
+			for _, subset := range ud.Status.DiscoveredSubsets {
+				discovered = append(discovered, subset.Name)
+			}
+			for _, subset := range ud.Spec.Topology.Subsets {
+				subsets = append(subsets, subset.Name)
+			}
+			if !reflect.DeepEqual(discovered, cs.expectDiscovered) || !reflect.DeepEqual(subsets, cs.expectDiscovered) {
+				t.Fatalf("expect discovered subsets %v, got %v and subsets %v", cs.expectDiscovered, discovered, subsets)
+			}
+		})
+	}
+}
+
+func newDiscoveryNode(name, topologyKey, value string, ready bool) *corev1.Node {
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{}}}
+	if value != "" {
+		node.Labels[topologyKey] = value
+	}
+	status := corev1.ConditionTrue
+	if !ready {
+		status = corev1.ConditionFalse
+	}
+	node.Status.Conditions = []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}}
+	return node
+}
+
+func TestNodeEventHandler(t *testing.T) {
+	const zoneKey = "topology.kubernetes.io/zone"
+	discoveryUD := &appsv1alpha1.UnitedDeployment{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "discovery"}}
+	discoveryUD.Spec.Topology.SubsetDiscovery = &appsv1alpha1.SubsetDiscovery{TopologyKey: zoneKey}
+	staticUD := &appsv1alpha1.UnitedDeployment{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "static"}}
+	reader := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(discoveryUD, staticUD).Build()
+	h := &nodeEventHandler{reader: reader}
+
+	oldNode := newDiscoveryNode("node", zoneKey, "zone-a", true)
+	cases := []struct {
+		name          string
+		mutate        func(node *corev1.Node)
+		expectEnqueue bool
+	}{
+		{
+			name: "heartbeat of node",
+			mutate: func(node *corev1.Node) {
+				node.Status.Conditions[0].LastHeartbeatTime = metav1.Now()
+			},
+		},
+		{
+			name: "other labels changed",
+			mutate: func(node *corev1.Node) {
+				node.Labels["foo"] = "bar"
+			},
+		},
+		{
+			name: "topology label changed",
+			mutate: func(node *corev1.Node) {
+				node.Labels[zoneKey] = "zone-b"
+			},
+			expectEnqueue: true,
+		},
+		{
+			name: "node becomes unschedulable",
+			mutate: func(node *corev1.Node) {
+				node.Spec.Unschedulable = true
+			},
+		},
+		{
+			name: "node becomes not ready",
+			mutate: func(node *corev1.Node) {
+				node.Status.Conditions[0].Status = corev1.ConditionFalse
+			},
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			q := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+			defer q.ShutDown()
+			newNode := oldNode.DeepCopy()
+			cs.mutate(newNode)
+			h.Update(context.TODO(), event.TypedUpdateEvent[*corev1.Node]{ObjectOld: oldNode, ObjectNew: newNode}, q)
+			expectLen := 0
+			if cs.expectEnqueue {
+				expectLen = 1
+			}
+			if q.Len() != expectLen {
+				t.Fatalf("expect %d UnitedDeployments enqueued, got %d", expectLen, q.Len())
+			}
+			if cs.expectEnqueue {
+				if req, _ := q.Get(); req.Name != discoveryUD.Name {
+					t.Fatalf("expect UnitedDeployment %s enqueued, got %s", discoveryUD.Name, req.Name)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package uniteddeployment
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+	"github.com/openkruise/kruise/pkg/util"
+)
+
+const (
+	subsetHealthReasonHealthy    = "Healthy"
+	subsetHealthReasonDegraded   = "Degraded"
+	subsetHealthReasonFailedOver = "FailedOver"
+	subsetHealthReasonRecovering = "Recovering"
+)
+
+// applySubsetFailover shifts the unready replicas of failed-over subsets to the healthy subsets. The failed-over
+// subsets keep their replicas, so that their pods are not deleted and can be used to tell whether they recover,
+// and the replicas shifted to the healthy subsets are bounded by failover.maxSurge.
+func applySubsetFailover(ud *appsv1alpha1.UnitedDeployment, existingSubsets map[string]*Subset, nextReplicas map[string]int32, now time.Time) map[string]int32 {
+	var extra int32
+	var receivers []string
+	for _, subsetDef := range ud.Spec.Topology.Subsets {
+		subset, ok := existingSubsets[subsetDef.Name]
+		if !ok {
+			receivers = append(receivers, subsetDef.Name)
+			continue
+		}
+		failedOver, healthy := calculateSubsetHealthForFailover(subsetDef.Name, subset, ud, now)
+		if failedOver {
+			extra += max(nextReplicas[subsetDef.Name]-subset.Status.ReadyReplicas, 0)
+		} else if healthy {
+			receivers = append(receivers, subsetDef.Name)
+		}
+	}
+	if extra == 0 || len(receivers) == 0 {
+		return nextReplicas
+	}
+
+	var replicas int32
+	if ud.Spec.Replicas != nil {
+		replicas = *ud.Spec.Replicas
+	}
+	maxSurge, err := util.GetScaledValueFromIntOrPercent(ud.Spec.Topology.ScheduleStrategy.Failover.GetMaxSurge(), int(replicas), true)
+	if err != nil {
+		klog.ErrorS(err, "Failed to calculate max surge for failover", "unitedDeployment", klog.KObj(ud))
+		return nextReplicas
+	}
+	shifted := min(extra, int32(maxSurge))
+	if shifted <= 0 {
+		return nextReplicas
+	}
+	_, maxReplicasMap, err := calculateRawMinMaxMap(replicas, ud.Spec.Topology.Subsets)
+	if err != nil {
+		klog.ErrorS(err, "Failed to calculate max replicas of subsets for failover", "unitedDeployment", klog.KObj(ud))
+		return nextReplicas
+	}
+
+	result := make(map[string]int32, len(nextReplicas))
+	for name, r := range nextReplicas {
+		result[name] = r
+	}
+	// distribute the shifted replicas one by one, so that the healthy subsets share them evenly
+	unassigned := shifted
+	for unassigned > 0 {
+		assigned := false
+		for _, name := range receivers {
+			if unassigned == 0 {
+				break
+			}
+			if result[name] >= maxReplicasMap[name] {
+				continue
+			}
+			result[name]++
+			unassigned--
+			assigned = true
+		}
+		if !assigned {
+			break
+		}
+	}
+	klog.V(4).InfoS("Shifted replicas of failed-over subsets", "unitedDeployment", klog.KObj(ud),
+		"nextReplicas", nextReplicas, "failoverReplicas", result, "unreadyReplicas", extra, "maxSurge", maxSurge, "unassigned", unassigned)
+	return result
+}
+
+// calculateSubsetHealthForFailover updates the Healthy condition of the subset by its ready percentage,
+// and returns whether the subset is failed over and whether it is healthy enough to take over replicas.
+func calculateSubsetHealthForFailover(name string, subset *Subset, ud *appsv1alpha1.UnitedDeployment, now time.Time) (failedOver, healthy bool) {
+	failover := ud.Spec.Topology.ScheduleStrategy.Failover
+	status := ud.Status.GetSubsetStatus(name)
+	if status == nil {
+		klog.ErrorS(nil, "SubsetStatus not found", "subset", name, "unitedDeployment", klog.KObj(ud))
+		return false, false
+	}
+	unitedDeploymentKey := getUnitedDeploymentKey(ud)
+
+	var readyPercent int32 = 100
+	if subset.Spec.Replicas > 0 {
+		readyPercent = int32(int64(subset.Status.ReadyReplicas) * 100 / int64(subset.Spec.Replicas))
+	}
+	message := fmt.Sprintf("%d%% replicas of subset are ready", readyPercent)
+
+	condition := status.GetCondition(appsv1alpha1.UnitedDeploymentSubsetHealthy)
+	switch {
+	case condition == nil || condition.Status == corev1.ConditionTrue:
+		if readyPercent < failover.GetUnhealthyPercent() {
+			klog.InfoS("subset becomes unhealthy", "subset", name, "readyPercent", readyPercent, "unitedDeployment", klog.KObj(ud))
+			status.SetCondition(appsv1alpha1.UnitedDeploymentSubsetHealthy, corev1.ConditionUnknown, subsetHealthReasonDegraded, message)
+			durationStore.Push(unitedDeploymentKey, failover.GetUnhealthyDuration())
+			return false, false
+		}
+		status.SetCondition(appsv1alpha1.UnitedDeploymentSubsetHealthy, corev1.ConditionTrue, subsetHealthReasonHealthy, "")
+		return false, true
+
+	case condition.Status == corev1.ConditionFalse:
+		if readyPercent >= failover.GetRecoveryPercent() {
+			klog.InfoS("failed-over subset starts recovering", "subset", name, "readyPercent", readyPercent, "unitedDeployment", klog.KObj(ud))
+			status.SetCondition(appsv1alpha1.UnitedDeploymentSubsetHealthy, corev1.ConditionUnknown, subsetHealthReasonRecovering, message)
+			durationStore.Push(unitedDeploymentKey, failover.GetRecoveryDuration())
+		}
+		return true, false
+
+	case condition.Reason == subsetHealthReasonRecovering:
+		if readyPercent < failover.GetRecoveryPercent() {
+			klog.InfoS("failed-over subset stops recovering", "subset", name, "readyPercent", readyPercent, "unitedDeployment", klog.KObj(ud))
+			status.SetCondition(appsv1alpha1.UnitedDeploymentSubsetHealthy, corev1.ConditionFalse, subsetHealthReasonFailedOver, message)
+			return true, false
+		}
+		if recoverTime := condition.LastTransitionTime.Add(failover.GetRecoveryDuration()); now.Before(recoverTime) {
+			durationStore.Push(unitedDeploymentKey, recoverTime.Sub(now))
+			return true, false
+		}
+		klog.InfoS("failed-over subset recovered", "subset", name, "unitedDeployment", klog.KObj(ud))
+		status.SetCondition(appsv1alpha1.UnitedDeploymentSubsetHealthy, corev1.ConditionTrue, subsetHealthReasonHealthy, "")
+		return false, true
+
+	default:
+		// the subset is degraded but not failed over yet
+		if readyPercent >= failover.GetUnhealthyPercent() {
+			status.SetCondition(appsv1alpha1.UnitedDeploymentSubsetHealthy, corev1.ConditionTrue, subsetHealthReasonHealthy, "")
+			return false, true
+		}
+		if failoverTime := condition.LastTransitionTime.Add(failover.GetUnhealthyDuration()); now.Before(failoverTime) {
+			durationStore.Push(unitedDeploymentKey, failoverTime.Sub(now))
+			return false, false
+		}
+		klog.InfoS("subset failed over", "subset", name, "readyPercent", readyPercent, "unitedDeployment", klog.KObj(ud))
+		status.SetCondition(appsv1alpha1.UnitedDeploymentSubsetHealthy, corev1.ConditionFalse, subsetHealthReasonFailedOver, message)
+		return true, false
+	}
+}
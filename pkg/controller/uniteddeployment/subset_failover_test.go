@@ -0,0 +1,184 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package uniteddeployment
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+)
+
+func TestApplySubsetFailover(t *testing.T) {
+	now := time.Now()
+	newSubset := func(replicas, ready int32) *Subset {
+		subset := &Subset{}
+		subset.Spec.Replicas = replicas
+		subset.Status.Replicas = replicas
+		subset.Status.ReadyReplicas = ready
+		return subset
+	}
+	healthCondition := func(status corev1.ConditionStatus, reason string, since time.Duration) *appsv1alpha1.UnitedDeploymentSubsetCondition {
+		return &appsv1alpha1.UnitedDeploymentSubsetCondition{
+			Type:               appsv1alpha1.UnitedDeploymentSubsetHealthy,
+			Status:             status,
+			Reason:             reason,
+			LastTransitionTime: metav1.NewTime(now.Add(-since)),
+		}
+	}
+
+	tests := []struct {
+		name             string
+		subsetA          *Subset
+		conditionA       *appsv1alpha1.UnitedDeploymentSubsetCondition
+		maxReplicasC     *intstr.IntOrString
+		maxSurge         *intstr.IntOrString
+		expectReplicas   map[string]int32
+		expectStatusA    corev1.ConditionStatus
+		expectReasonA    string
+		expectFailedOver bool
+	}{
+		{
+			name:           "healthy subset",
+			subsetA:        newSubset(4, 4),
+			expectReplicas: map[string]int32{"a": 4, "b": 4, "c": 4},
+			expectStatusA:  corev1.ConditionTrue,
+			expectReasonA:  subsetHealthReasonHealthy,
+		},
+		{
+			name:           "subset becomes unhealthy",
+			subsetA:        newSubset(4, 1),
+			expectReplicas: map[string]int32{"a": 4, "b": 4, "c": 4},
+			expectStatusA:  corev1.ConditionUnknown,
+			expectReasonA:  subsetHealthReasonDegraded,
+		},
+		{
+			name:           "unhealthy subset is failed over after duration",
+			subsetA:        newSubset(4, 1),
+			conditionA:     healthCondition(corev1.ConditionUnknown, subsetHealthReasonDegraded, 10*time.Minute),
+			expectReplicas: map[string]int32{"a": 4, "b": 6, "c": 5},
+			expectStatusA:  corev1.ConditionFalse,
+			expectReasonA:  subsetHealthReasonFailedOver,
+		},
+		{
+			name:           "shifted replicas are bounded by max surge",
+			subsetA:        newSubset(4, 0),
+			conditionA:     healthCondition(corev1.ConditionFalse, subsetHealthReasonFailedOver, time.Hour),
+			maxSurge:       ptr.To(intstr.FromInt32(1)),
+			expectReplicas: map[string]int32{"a": 4, "b": 5, "c": 4},
+			expectStatusA:  corev1.ConditionFalse,
+			expectReasonA:  subsetHealthReasonFailedOver,
+		},
+		{
+			name:           "shifted replicas are bounded by default max surge",
+			subsetA:        newSubset(4, 0),
+			conditionA:     healthCondition(corev1.ConditionFalse, subsetHealthReasonFailedOver, time.Hour),
+			expectReplicas: map[string]int32{"a": 4, "b": 6, "c": 5},
+			expectStatusA:  corev1.ConditionFalse,
+			expectReasonA:  subsetHealthReasonFailedOver,
+		},
+		{
+			name:           "no replicas are shifted with zero max surge",
+			subsetA:        newSubset(4, 0),
+			conditionA:     healthCondition(corev1.ConditionFalse, subsetHealthReasonFailedOver, time.Hour),
+			maxSurge:       ptr.To(intstr.FromString("0%")),
+			expectReplicas: map[string]int32{"a": 4, "b": 4, "c": 4},
+			expectStatusA:  corev1.ConditionFalse,
+			expectReasonA:  subsetHealthReasonFailedOver,
+		},
+		{
+			name:           "unhealthy subset recovers before duration",
+			subsetA:        newSubset(4, 3),
+			conditionA:     healthCondition(corev1.ConditionUnknown, subsetHealthReasonDegraded, time.Minute),
+			expectReplicas: map[string]int32{"a": 4, "b": 4, "c": 4},
+			expectStatusA:  corev1.ConditionTrue,
+			expectReasonA:  subsetHealthReasonHealthy,
+		},
+		{
+			name:           "failed-over subset above unhealthy but below recovery percent keeps failed over",
+			subsetA:        newSubset(4, 3),
+			conditionA:     healthCondition(corev1.ConditionFalse, subsetHealthReasonFailedOver, time.Hour),
+			maxReplicasC:   ptr.To(intstr.FromInt32(4)),
+			expectReplicas: map[string]int32{"a": 4, "b": 5, "c": 4},
+			expectStatusA:  corev1.ConditionFalse,
+			expectReasonA:  subsetHealthReasonFailedOver,
+		},
+		{
+			name:           "failed-over subset starts recovering",
+			subsetA:        newSubset(4, 4),
+			conditionA:     healthCondition(corev1.ConditionFalse, subsetHealthReasonFailedOver, time.Hour),
+			expectReplicas: map[string]int32{"a": 4, "b": 4, "c": 4},
+			expectStatusA:  corev1.ConditionUnknown,
+			expectReasonA:  subsetHealthReasonRecovering,
+		},
+		{
+			name:           "recovering subset drops again",
+			subsetA:        newSubset(4, 2),
+			conditionA:     healthCondition(corev1.ConditionUnknown, subsetHealthReasonRecovering, time.Minute),
+			expectReplicas: map[string]int32{"a": 4, "b": 5, "c": 5},
+			expectStatusA:  corev1.ConditionFalse,
+			expectReasonA:  subsetHealthReasonFailedOver,
+		},
+		{
+			name:           "recovering subset is failed back after duration",
+			subsetA:        newSubset(4, 4),
+			conditionA:     healthCondition(corev1.ConditionUnknown, subsetHealthReasonRecovering, time.Hour),
+			expectReplicas: map[string]int32{"a": 4, "b": 4, "c": 4},
+			expectStatusA:  corev1.ConditionTrue,
+			expectReasonA:  subsetHealthReasonHealthy,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ud := &appsv1alpha1.UnitedDeployment{
+				Spec: appsv1alpha1.UnitedDeploymentSpec{
+					Replicas: ptr.To(int32(12)),
+					Topology: appsv1alpha1.Topology{
+						Subsets: []appsv1alpha1.Subset{{Name: "a"}, {Name: "b"}, {Name: "c", MaxReplicas: tt.maxReplicasC}},
+						ScheduleStrategy: appsv1alpha1.UnitedDeploymentScheduleStrategy{
+							Failover: &appsv1alpha1.SubsetFailoverStrategy{MaxSurge: tt.maxSurge},
+						},
+					},
+				},
+				Status: appsv1alpha1.UnitedDeploymentStatus{
+					SubsetStatuses: []appsv1alpha1.UnitedDeploymentSubsetStatus{{Name: "a"}, {Name: "b"}, {Name: "c"}},
+				},
+			}
+			if tt.conditionA != nil {
+				ud.Status.SubsetStatuses[0].Conditions = []appsv1alpha1.UnitedDeploymentSubsetCondition{*tt.conditionA}
+			}
+			existingSubsets := map[string]*Subset{"a": tt.subsetA, "b": newSubset(4, 4), "c": newSubset(4, 4)}
+			nextReplicas := map[string]int32{"a": 4, "b": 4, "c": 4}
+
+			result := applySubsetFailover(ud, existingSubsets, nextReplicas, now)
+			if !reflect.DeepEqual(result, tt.expectReplicas) {
+				t.Errorf("expect replicas %v, got %v", tt.expectReplicas, result)
+			}
+			condition := ud.Status.SubsetStatuses[0].GetCondition(appsv1alpha1.UnitedDeploymentSubsetHealthy)
+			if condition == nil || condition.Status != tt.expectStatusA || condition.Reason != tt.expectReasonA {
+				t.Errorf("expect condition %s/%s, got %+v", tt.expectStatusA, tt.expectReasonA, condition)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package uniteddeployment
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+)
+
+const defaultSubsetRollingProgressDeadlineSeconds = 600
+
+// getSubsetRollingOrder returns the names of all subsets in the order they should be updated.
+// Subsets listed in the strategy come first, the others follow in the order of the topology.
+func getSubsetRollingOrder(ud *appsv1alpha1.UnitedDeployment) []string {
+	inTopology := sets.NewString()
+	for _, subset := range ud.Spec.Topology.Subsets {
+		inTopology.Insert(subset.Name)
+	}
+
+	ordered := sets.NewString()
+	var order []string
+	if rolling := ud.Spec.UpdateStrategy.SubsetRollingUpdate; rolling != nil {
+		for _, name := range rolling.Order {
+			if inTopology.Has(name) && !ordered.Has(name) {
+				ordered.Insert(name)
+				order = append(order, name)
+			}
+		}
+	}
+	for _, subset := range ud.Spec.Topology.Subsets {
+		if !ordered.Has(subset.Name) {
+			order = append(order, subset.Name)
+		}
+	}
+	return order
+}
+
+// isSubsetRollingAvailable checks whether the subset has been fully updated to the expected revision
+// and all of its replicas are ready.
+func isSubsetRollingAvailable(subset *Subset, replicas int32, expectedRevision string) bool {
+	if replicas == 0 {
+		return true
+	}
+	if subset == nil || subset.GetLabels()[appsv1alpha1.ControllerRevisionHashLabelKey] != expectedRevision {
+		return false
+	}
+	if subset.Status.ObservedGeneration < subset.Generation {
+		return false
+	}
+	return subset.Spec.Replicas == replicas && subset.Spec.UpdateStrategy.Partition == 0 &&
+		subset.Status.Replicas == replicas && subset.Status.UpdatedReadyReplicas >= replicas
+}
+
+// calcSubsetRollingPartitions calculates the partitions of the SubsetRolling update strategy and records its
+// progress in the status of ud. Subsets which have finished or are updating get a zero partition, while the
+// others keep all their replicas at the old revision. It returns the duration after which the progress should
+// be checked again, or zero if there is no need to.
+func calcSubsetRollingPartitions(ud *appsv1alpha1.UnitedDeployment, existingSubsets map[string]*Subset, nextReplicas map[string]int32,
+	currentRevision, expectedRevision string, control ControlInterface, now time.Time) (map[string]int32, time.Duration) {
+	partitions := map[string]int32{}
+	if ud.Status.UpdateStatus == nil {
+		ud.Status.UpdateStatus = &appsv1alpha1.UpdateStatus{}
+	}
+	if currentRevision == expectedRevision {
+		ud.Status.UpdateStatus.SubsetRolling = nil
+		RemoveUnitedDeploymentCondition(&ud.Status, appsv1alpha1.SubsetRollingPaused)
+		for _, subset := range ud.Spec.Topology.Subsets {
+			partitions[subset.Name] = 0
+		}
+		return partitions, 0
+	}
+
+	strategy := ud.Spec.UpdateStrategy.SubsetRollingUpdate
+	if strategy == nil {
+		strategy = &appsv1alpha1.SubsetRollingUpdate{}
+	}
+	progressDeadline := time.Duration(defaultSubsetRollingProgressDeadlineSeconds) * time.Second
+	if strategy.ProgressDeadlineSeconds != nil {
+		progressDeadline = time.Duration(*strategy.ProgressDeadlineSeconds) * time.Second
+	}
+
+	// start over once the target revision changes
+	rolling := ud.Status.UpdateStatus.SubsetRolling
+	if rolling == nil || ud.Status.UpdateStatus.UpdatedRevision != expectedRevision {
+		rolling = &appsv1alpha1.SubsetRollingStatus{}
+	}
+	ud.Status.UpdateStatus.SubsetRolling = rolling
+
+	var requeueAfter time.Duration
+	var failure string
+	updated := sets.NewString(rolling.UpdatedSubsets...)
+	for _, name := range getSubsetRollingOrder(ud) {
+		if updated.Has(name) {
+			continue
+		}
+		// the start time may be missing if the status is modified by others
+		if rolling.CurrentSubset != name || rolling.StartTime == nil {
+			if strategy.Paused {
+				break
+			}
+			rolling.CurrentSubset = name
+			rolling.StartTime = &metav1.Time{Time: now}
+			rolling.AvailableTime = nil
+		}
+
+		subset := existingSubsets[name]
+		if !isSubsetRollingAvailable(subset, nextReplicas[name], expectedRevision) {
+			rolling.AvailableTime = nil
+			if subset != nil {
+				if message := control.GetSubsetFailure(subset); message != nil {
+					failure = fmt.Sprintf("subset %s failed: %s", name, *message)
+					break
+				}
+			}
+			if elapsed := now.Sub(rolling.StartTime.Time); elapsed >= progressDeadline {
+				failure = fmt.Sprintf("subset %s has not been fully updated and available in %s", name, progressDeadline)
+			} else {
+				requeueAfter = progressDeadline - elapsed
+			}
+			break
+		}
+
+		if rolling.AvailableTime == nil {
+			rolling.AvailableTime = &metav1.Time{Time: now}
+		}
+		if soak := time.Duration(strategy.SoakSeconds)*time.Second - now.Sub(rolling.AvailableTime.Time); soak > 0 {
+			requeueAfter = soak
+			break
+		}
+		if strategy.Paused {
+			break
+		}
+		klog.InfoS("UnitedDeployment subset rolling update finished subset", "unitedDeployment", klog.KObj(ud), "subset", name)
+		updated.Insert(name)
+		rolling.UpdatedSubsets = append(rolling.UpdatedSubsets, name)
+		rolling.CurrentSubset = ""
+		rolling.StartTime = nil
+		rolling.AvailableTime = nil
+	}
+
+	if failure != "" {
+		klog.InfoS("UnitedDeployment subset rolling update paused", "unitedDeployment", klog.KObj(ud), "reason", failure)
+		SetUnitedDeploymentCondition(&ud.Status, NewUnitedDeploymentCondition(appsv1alpha1.SubsetRollingPaused, corev1.ConditionTrue, "SubsetUpdateFailed", failure))
+	} else {
+		RemoveUnitedDeploymentCondition(&ud.Status, appsv1alpha1.SubsetRollingPaused)
+	}
+
+	for _, subset := range ud.Spec.Topology.Subsets {
+		if updated.Has(subset.Name) || rolling.CurrentSubset == subset.Name {
+			partitions[subset.Name] = 0
+		} else {
+			partitions[subset.Name] = nextReplicas[subset.Name]
+		}
+	}
+	return partitions, requeueAfter
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package uniteddeployment
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+	"github.com/openkruise/kruise/pkg/controller/uniteddeployment/adapter"
+)
+
+func newRollingSubset(name, revision string, replicas, partition, updatedReady int32) *Subset {
+	subset := &Subset{}
+	subset.Name = name
+	subset.Labels = map[string]string{appsv1alpha1.ControllerRevisionHashLabelKey: revision}
+	subset.Spec.Replicas = replicas
+	subset.Spec.UpdateStrategy.Partition = partition
+	subset.Status.Replicas = replicas
+	subset.Status.UpdatedReadyReplicas = updatedReady
+	return subset
+}
+
+func TestCalcSubsetRollingPartitions(t *testing.T) {
+	now := time.Now()
+	control := &SubsetControl{adapter: &adapter.CloneSetAdapter{}}
+	nextReplicas := map[string]int32{"a": 2, "b": 3, "c": 4}
+
+	tests := []struct {
+		name            string
+		strategy        *appsv1alpha1.SubsetRollingUpdate
+		rolling         *appsv1alpha1.SubsetRollingStatus
+		updatedRevision string
+		currentRevision string
+		subsets         map[string]*Subset
+		expectPartition map[string]int32
+		expectRolling   *appsv1alpha1.SubsetRollingStatus
+		expectRequeue   time.Duration
+		expectPaused    bool
+	}{
+		{
+			name:            "no update",
+			currentRevision: "r2",
+			updatedRevision: "r2",
+			expectPartition: map[string]int32{"a": 0, "b": 0, "c": 0},
+		},
+		{
+			name:            "new revision starts from the first subset in order",
+			strategy:        &appsv1alpha1.SubsetRollingUpdate{Order: []string{"b"}},
+			rolling:         &appsv1alpha1.SubsetRollingStatus{UpdatedSubsets: []string{"a", "c"}},
+			currentRevision: "r1",
+			updatedRevision: "r1",
+			subsets: map[string]*Subset{
+				"a": newRollingSubset("a", "r1", 2, 2, 0),
+				"b": newRollingSubset("b", "r1", 3, 3, 0),
+				"c": newRollingSubset("c", "r1", 4, 4, 0),
+			},
+			expectPartition: map[string]int32{"a": 2, "b": 0, "c": 4},
+			expectRolling:   &appsv1alpha1.SubsetRollingStatus{CurrentSubset: "b", StartTime: &metav1.Time{Time: now}},
+			expectRequeue:   600 * time.Second,
+		},
+		{
+			name:            "current subset without start time restarts the timer",
+			rolling:         &appsv1alpha1.SubsetRollingStatus{CurrentSubset: "a"},
+			currentRevision: "r1",
+			updatedRevision: "r2",
+			subsets: map[string]*Subset{
+				"a": newRollingSubset("a", "r2", 2, 2, 0),
+				"b": newRollingSubset("b", "r2", 3, 3, 0),
+				"c": newRollingSubset("c", "r2", 4, 4, 0),
+			},
+			expectPartition: map[string]int32{"a": 0, "b": 3, "c": 4},
+			expectRolling:   &appsv1alpha1.SubsetRollingStatus{CurrentSubset: "a", StartTime: &metav1.Time{Time: now}},
+			expectRequeue:   600 * time.Second,
+		},
+		{
+			name:            "current subset available and soaking",
+			strategy:        &appsv1alpha1.SubsetRollingUpdate{SoakSeconds: 60},
+			rolling:         &appsv1alpha1.SubsetRollingStatus{CurrentSubset: "a", StartTime: &metav1.Time{Time: now.Add(-time.Minute)}},
+			currentRevision: "r1",
+			updatedRevision: "r2",
+			subsets: map[string]*Subset{
+				"a": newRollingSubset("a", "r2", 2, 0, 2),
+				"b": newRollingSubset("b", "r2", 3, 3, 0),
+				"c": newRollingSubset("c", "r2", 4, 4, 0),
+			},
+			expectPartition: map[string]int32{"a": 0, "b": 3, "c": 4},
+			expectRolling: &appsv1alpha1.SubsetRollingStatus{CurrentSubset: "a", StartTime: &metav1.Time{Time: now.Add(-time.Minute)},
+				AvailableTime: &metav1.Time{Time: now}},
+			expectRequeue: 60 * time.Second,
+		},
+		{
+			name:     "soak finished and move to next subset",
+			strategy: &appsv1alpha1.SubsetRollingUpdate{SoakSeconds: 60},
+			rolling: &appsv1alpha1.SubsetRollingStatus{CurrentSubset: "a", StartTime: &metav1.Time{Time: now.Add(-2 * time.Minute)},
+				AvailableTime: &metav1.Time{Time: now.Add(-time.Minute)}},
+			currentRevision: "r1",
+			updatedRevision: "r2",
+			subsets: map[string]*Subset{
+				"a": newRollingSubset("a", "r2", 2, 0, 2),
+				"b": newRollingSubset("b", "r2", 3, 3, 0),
+				"c": newRollingSubset("c", "r2", 4, 4, 0),
+			},
+			expectPartition: map[string]int32{"a": 0, "b": 0, "c": 4},
+			expectRolling:   &appsv1alpha1.SubsetRollingStatus{UpdatedSubsets: []string{"a"}, CurrentSubset: "b", StartTime: &metav1.Time{Time: now}},
+			expectRequeue:   600 * time.Second,
+		},
+		{
+			name:     "paused strategy does not start next subset",
+			strategy: &appsv1alpha1.SubsetRollingUpdate{Paused: true},
+			rolling: &appsv1alpha1.SubsetRollingStatus{UpdatedSubsets: []string{"a"}, CurrentSubset: "b", StartTime: &metav1.Time{Time: now.Add(-time.Minute)},
+				AvailableTime: &metav1.Time{Time: now.Add(-time.Minute)}},
+			currentRevision: "r1",
+			updatedRevision: "r2",
+			subsets: map[string]*Subset{
+				"a": newRollingSubset("a", "r2", 2, 0, 2),
+				"b": newRollingSubset("b", "r2", 3, 0, 3),
+				"c": newRollingSubset("c", "r2", 4, 4, 0),
+			},
+			expectPartition: map[string]int32{"a": 0, "b": 0, "c": 4},
+			expectRolling: &appsv1alpha1.SubsetRollingStatus{UpdatedSubsets: []string{"a"}, CurrentSubset: "b", StartTime: &metav1.Time{Time: now.Add(-time.Minute)},
+				AvailableTime: &metav1.Time{Time: now.Add(-time.Minute)}},
+		},
+		{
+			name:            "progress deadline exceeded",
+			strategy:        &appsv1alpha1.SubsetRollingUpdate{ProgressDeadlineSeconds: ptr.To(int32(60))},
+			rolling:         &appsv1alpha1.SubsetRollingStatus{UpdatedSubsets: []string{"a"}, CurrentSubset: "b", StartTime: &metav1.Time{Time: now.Add(-2 * time.Minute)}},
+			currentRevision: "r1",
+			updatedRevision: "r2",
+			subsets: map[string]*Subset{
+				"a": newRollingSubset("a", "r2", 2, 0, 2),
+				"b": newRollingSubset("b", "r2", 3, 0, 1),
+				"c": newRollingSubset("c", "r2", 4, 4, 0),
+			},
+			expectPartition: map[string]int32{"a": 0, "b": 0, "c": 4},
+			expectRolling:   &appsv1alpha1.SubsetRollingStatus{UpdatedSubsets: []string{"a"}, CurrentSubset: "b", StartTime: &metav1.Time{Time: now.Add(-2 * time.Minute)}},
+			expectPaused:    true,
+		},
+		{
+			name:            "all subsets updated",
+			rolling:         &appsv1alpha1.SubsetRollingStatus{UpdatedSubsets: []string{"a", "b"}, CurrentSubset: "c", StartTime: &metav1.Time{Time: now.Add(-time.Minute)}},
+			currentRevision: "r1",
+			updatedRevision: "r2",
+			subsets: map[string]*Subset{
+				"a": newRollingSubset("a", "r2", 2, 0, 2),
+				"b": newRollingSubset("b", "r2", 3, 0, 3),
+				"c": newRollingSubset("c", "r2", 4, 0, 4),
+			},
+			expectPartition: map[string]int32{"a": 0, "b": 0, "c": 0},
+			expectRolling:   &appsv1alpha1.SubsetRollingStatus{UpdatedSubsets: []string{"a", "b", "c"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ud := &appsv1alpha1.UnitedDeployment{
+				Spec: appsv1alpha1.UnitedDeploymentSpec{
+					Topology: appsv1alpha1.Topology{
+						Subsets: []appsv1alpha1.Subset{{Name: "a"}, {Name: "b"}, {Name: "c"}},
+					},
+					UpdateStrategy: appsv1alpha1.UnitedDeploymentUpdateStrategy{
+						Type:                appsv1alpha1.SubsetRollingUpdateStrategyType,
+						SubsetRollingUpdate: tt.strategy,
+					},
+				},
+				Status: appsv1alpha1.UnitedDeploymentStatus{
+					UpdateStatus: &appsv1alpha1.UpdateStatus{UpdatedRevision: tt.updatedRevision, SubsetRolling: tt.rolling},
+				},
+			}
+			partitions, requeue := calcSubsetRollingPartitions(ud, tt.subsets, nextReplicas, tt.currentRevision, "r2", control, now)
+			if !reflect.DeepEqual(partitions, tt.expectPartition) {
+				t.Errorf("expect partitions %v, got %v", tt.expectPartition, partitions)
+			}
+			if !reflect.DeepEqual(ud.Status.UpdateStatus.SubsetRolling, tt.expectRolling) {
+				t.Errorf("expect rolling status %+v, got %+v", tt.expectRolling, ud.Status.UpdateStatus.SubsetRolling)
+			}
+			if requeue != tt.expectRequeue {
+				t.Errorf("expect requeue after %v, got %v", tt.expectRequeue, requeue)
+			}
+			cond := GetUnitedDeploymentCondition(&ud.Status, appsv1alpha1.SubsetRollingPaused)
+			if paused := cond != nil && cond.Status == corev1.ConditionTrue; paused != tt.expectPaused {
+				t.Errorf("expect paused %v, got %v", tt.expectPaused, paused)
+			}
+		})
+	}
+}
@@ -29,6 +29,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
@@ -45,6 +46,7 @@ import (
 	utilcontroller "github.com/openkruise/kruise/pkg/controller/util"
 	"github.com/openkruise/kruise/pkg/util"
 	utilclient "github.com/openkruise/kruise/pkg/util/client"
+	"github.com/openkruise/kruise/pkg/util/configuration"
 	utildiscovery "github.com/openkruise/kruise/pkg/util/discovery"
 	"github.com/openkruise/kruise/pkg/util/ratelimiter"
 	"github.com/openkruise/kruise/pkg/util/requeueduration"
@@ -68,6 +70,7 @@ const (
 	eventTypeDupSubsetsDelete      = "DeleteDuplicatedSubsets"
 	eventTypeSubsetsUpdate         = "UpdateSubset"
 	eventTypeSpecifySubsetReplicas = "SpecifySubsetReplicas"
+	eventTypeDiscoverSubsets       = "DiscoverSubsets"
 
 	slowStartInitialBatchSize = 1
 )
@@ -79,6 +82,7 @@ const (
 	advancedStatefulSetSubSetType subSetType = "AdvancedStatefulSet"
 	cloneSetSubSetType            subSetType = "CloneSet"
 	deploymentSubSetType          subSetType = "Deployment"
+	customWorkloadSubSetType      subSetType = "CustomWorkload"
 )
 
 // Add creates a new UnitedDeployment Controller and adds it to the Manager with default RBAC. The Manager will set fields on the Controller
@@ -147,6 +151,28 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
+	// Watch for topology changes of nodes for UnitedDeployments discovering subsets
+	err = c.Watch(source.Kind(mgr.GetCache(), &corev1.Node{}, &nodeEventHandler{reader: mgr.GetCache()}))
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to custom workloads in whitelist
+	whiteList, err := configuration.GetUDWatchCustomWorkloadWhiteList(mgr.GetClient())
+	if err != nil {
+		return err
+	}
+	for _, workload := range whiteList.Workloads {
+		workloadHandler := handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &appsv1alpha1.UnitedDeployment{}, handler.OnlyControllerOwner())
+		if _, err := utilcontroller.AddWatcherDynamically(mgr, c, workloadHandler, workload.GroupVersionKind, "UnitedDeployment"); err != nil {
+			return err
+		}
+		// the subsets of custom workloads not found in cluster can't be listed for cleaning
+		if reconciler, ok := r.(*ReconcileUnitedDeployment); ok && utilcontroller.DiscoverGVK(workload.GroupVersionKind) {
+			reconciler.registerCustomWorkloadControl(workload)
+		}
+	}
+
 	return nil
 }
 
@@ -170,6 +196,7 @@ type ReconcileUnitedDeployment struct {
 // +kubebuilder:rbac:groups=apps,resources=deployments/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=apps,resources=replicasets,verbs=get;list;watch
 // +kubebuilder:rbac:groups=apps,resources=replicasets/status,verbs=get
+// +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
 
 // Reconcile reads that state of the cluster for a UnitedDeployment object and makes changes based on the state read
 // and what is in the UnitedDeployment.Spec
@@ -197,6 +224,11 @@ func (r *ReconcileUnitedDeployment) Reconcile(_ context.Context, request reconci
 	klog.V(5).InfoS("Latest Resource observed", "unitedDeployment", klog.KObj(instance), "ResourceVersion", instance.GetResourceVersion())
 
 	oldStatus := instance.Status.DeepCopy()
+	if err = r.discoverSubsets(instance); err != nil {
+		klog.ErrorS(err, "Failed to discover subsets of UnitedDeployment", "unitedDeployment", klog.KObj(instance))
+		r.recorder.Event(instance, corev1.EventTypeWarning, fmt.Sprintf("Failed%s", eventTypeDiscoverSubsets), err.Error())
+		return reconcile.Result{}, err
+	}
 	initStatus(instance)
 	currentRevision, updatedRevision, _, _, err := r.constructUnitedDeploymentRevisions(instance)
 	if err != nil {
@@ -205,7 +237,12 @@ func (r *ReconcileUnitedDeployment) Reconcile(_ context.Context, request reconci
 		return reconcile.Result{}, err
 	}
 
-	control, subsetType := r.getSubsetControls(instance)
+	control, subsetType, err := r.getSubsetControls(instance)
+	if err != nil {
+		klog.ErrorS(err, "Failed to get subset control of UnitedDeployment", "unitedDeployment", klog.KObj(instance))
+		r.recorder.Event(instance, corev1.EventTypeWarning, fmt.Sprintf("Failed%s", eventTypeFindSubsets), err.Error())
+		return reconcile.Result{}, err
+	}
 
 	klog.V(4).InfoS("Got all subsets of UnitedDeployment", "unitedDeployment", klog.KObj(instance))
 	expectedRevision := currentRevision.Name
@@ -253,11 +290,24 @@ func (r *ReconcileUnitedDeployment) Reconcile(_ context.Context, request reconci
 		}
 	}
 
-	nextPartitions := calcNextPartitions(instance, nextReplicas)
+	if instance.Spec.Topology.ScheduleStrategy.Failover != nil {
+		nextReplicas = applySubsetFailover(instance, existingSubsets, nextReplicas, now)
+	}
+
+	var nextPartitions map[string]int32
+	if instance.Spec.UpdateStrategy.Type == appsv1alpha1.SubsetRollingUpdateStrategyType {
+		var rollingRequeue time.Duration
+		nextPartitions, rollingRequeue = calcSubsetRollingPartitions(instance, existingSubsets, nextReplicas, currentRevision.Name, expectedRevision, control, now)
+		if rollingRequeue > 0 {
+			durationStore.Push(getUnitedDeploymentKey(instance), rollingRequeue)
+		}
+	} else {
+		nextPartitions = calcNextPartitions(instance, nextReplicas)
+	}
 	nextUpdate := getNextUpdate(instance, nextReplicas, nextPartitions)
 	klog.V(4).InfoS("Got UnitedDeployment next update", "unitedDeployment", klog.KObj(instance), "nextUpdate", nextUpdate)
 
-	newStatus, err := r.manageSubsets(instance, existingSubsets, nextUpdate, currentRevision, updatedRevision, subsetType)
+	newStatus, err := r.manageSubsets(instance, existingSubsets, nextUpdate, currentRevision, updatedRevision, control, subsetType)
 	if err != nil {
 		klog.ErrorS(err, "Failed to update UnitedDeployment", "unitedDeployment", klog.KObj(instance))
 		r.recorder.Event(instance, corev1.EventTypeWarning, fmt.Sprintf("Failed%s", eventTypeSubsetsUpdate), err.Error())
@@ -511,6 +561,7 @@ func getNextUpdate(ud *appsv1alpha1.UnitedDeployment, nextReplicas map[string]in
 		t.Replicas = nextReplicas[subset.Name]
 		t.Partition = nextPartitions[subset.Name]
 		t.Patch = string(subset.Patch.Raw)
+		t.Overrides = adapter.GetSubsetOverrides(&subset)
 
 		next[subset.Name] = t
 	}
@@ -541,25 +592,50 @@ func (r *ReconcileUnitedDeployment) deleteDupSubset(allSubsets map[string][]*Sub
 	return existingSubsets, nil
 }
 
-func (r *ReconcileUnitedDeployment) getSubsetControls(instance *appsv1alpha1.UnitedDeployment) (ControlInterface, subSetType) {
+func (r *ReconcileUnitedDeployment) getSubsetControls(instance *appsv1alpha1.UnitedDeployment) (ControlInterface, subSetType, error) {
 	if instance.Spec.Template.StatefulSetTemplate != nil {
-		return r.subSetControls[statefulSetSubSetType], statefulSetSubSetType
+		return r.subSetControls[statefulSetSubSetType], statefulSetSubSetType, nil
 	}
 
 	if instance.Spec.Template.AdvancedStatefulSetTemplate != nil {
-		return r.subSetControls[advancedStatefulSetSubSetType], advancedStatefulSetSubSetType
+		return r.subSetControls[advancedStatefulSetSubSetType], advancedStatefulSetSubSetType, nil
 	}
 
 	if instance.Spec.Template.CloneSetTemplate != nil {
-		return r.subSetControls[cloneSetSubSetType], cloneSetSubSetType
+		return r.subSetControls[cloneSetSubSetType], cloneSetSubSetType, nil
 	}
 
 	if instance.Spec.Template.DeploymentTemplate != nil {
-		return r.subSetControls[deploymentSubSetType], deploymentSubSetType
+		return r.subSetControls[deploymentSubSetType], deploymentSubSetType, nil
+	}
+
+	if template := instance.Spec.Template.CustomWorkloadTemplate; template != nil {
+		gv, err := schema.ParseGroupVersion(template.APIVersion)
+		if err != nil {
+			return nil, customWorkloadSubSetType, err
+		}
+		t := customWorkloadSubSetTypeOf(gv.WithKind(template.Kind))
+		control, ok := r.subSetControls[t]
+		if !ok {
+			return nil, t, fmt.Errorf("custom workload %s/%s is not in the whitelist", template.APIVersion, template.Kind)
+		}
+		return control, t, nil
 	}
 
 	// unexpected
-	return nil, statefulSetSubSetType
+	return nil, statefulSetSubSetType, fmt.Errorf("no subset template found in UnitedDeployment")
+}
+
+// registerCustomWorkloadControl registers the subset control of the custom workload in whitelist, which is
+// also used to clean the subsets of this kind when the UnitedDeployment changes to another kind of workload.
+func (r *ReconcileUnitedDeployment) registerCustomWorkloadControl(workload configuration.UDCustomWorkload) {
+	r.subSetControls[customWorkloadSubSetTypeOf(workload.GroupVersionKind)] = &SubsetControl{
+		Client: r.Client, scheme: r.scheme, adapter: &adapter.CustomWorkloadAdapter{Client: r.Client, Scheme: r.scheme, Workload: workload}}
+}
+
+// customWorkloadSubSetTypeOf returns the subset type of the custom workload, it differs for each kind of custom workloads.
+func customWorkloadSubSetTypeOf(gvk schema.GroupVersionKind) subSetType {
+	return subSetType(fmt.Sprintf("%s(%s)", customWorkloadSubSetType, gvk.String()))
 }
 
 func (r *ReconcileUnitedDeployment) classifySubsetBySubsetName(subsets []*Subset) map[string][]*Subset {
@@ -614,6 +690,7 @@ func (r *ReconcileUnitedDeployment) calculateStatus(newStatus *appsv1alpha1.Unit
 		ss.ReadyReplicas = subset.Status.ReadyReplicas
 		ss.Partition = nextPartition[name]
 		ss.ReservedPods = subset.Status.UnschedulableStatus.ReservedPods
+		ss.TemplateHash = subset.GetAnnotations()[appsv1alpha1.AnnotationSubsetTemplateHashKey]
 	}
 
 	// Legacy field "SubsetReplicas" status still exists in ud status, consider remove them in v1beta1.
@@ -673,7 +750,8 @@ func (r *ReconcileUnitedDeployment) updateUnitedDeployment(ud *appsv1alpha1.Unit
 		reflect.DeepEqual(oldStatus.SubsetReplicas, newStatus.SubsetReplicas) &&
 		reflect.DeepEqual(oldStatus.UpdateStatus, newStatus.UpdateStatus) &&
 		reflect.DeepEqual(oldStatus.Conditions, newStatus.Conditions) &&
-		reflect.DeepEqual(oldStatus.SubsetStatuses, newStatus.SubsetStatuses) {
+		reflect.DeepEqual(oldStatus.SubsetStatuses, newStatus.SubsetStatuses) &&
+		reflect.DeepEqual(oldStatus.DiscoveredSubsets, newStatus.DiscoveredSubsets) {
 		return ud, nil
 	}
 
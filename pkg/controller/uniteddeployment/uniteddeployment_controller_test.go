@@ -26,6 +26,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -36,6 +37,7 @@ import (
 
 	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
 	utilclient "github.com/openkruise/kruise/pkg/util/client"
+	"github.com/openkruise/kruise/pkg/util/configuration"
 )
 
 var expectedRequest = reconcile.Request{NamespacedName: types.NamespacedName{Name: "foo", Namespace: "default"}}
@@ -609,3 +611,32 @@ func TestPatchStagingChangedPods(t *testing.T) {
 		})
 	}
 }
+
+func TestGetCustomWorkloadSubsetControls(t *testing.T) {
+	workload := configuration.UDCustomWorkload{
+		GroupVersionKind: schema.GroupVersionKind{Group: "example.io", Version: "v1", Kind: "Foo"},
+	}
+	r := &ReconcileUnitedDeployment{
+		Client: fake.NewClientBuilder().Build(),
+		subSetControls: map[subSetType]ControlInterface{
+			cloneSetSubSetType: &SubsetControl{},
+		},
+	}
+	r.registerCustomWorkloadControl(workload)
+
+	instance := &appsv1alpha1.UnitedDeployment{}
+	instance.Spec.Template.CustomWorkloadTemplate = &appsv1alpha1.CustomWorkloadTemplateSpec{APIVersion: "example.io/v1", Kind: "Foo"}
+	control, subsetType, err := r.getSubsetControls(instance)
+	if err != nil {
+		t.Fatalf("get subset controls failed: %v", err)
+	}
+	// the custom workload control is registered, so that its subsets are cleaned when changing to other kinds
+	if registered := r.subSetControls[subsetType]; registered == nil || registered != control {
+		t.Fatalf("expect custom workload control registered as %s", subsetType)
+	}
+
+	instance.Spec.Template.CustomWorkloadTemplate.Kind = "Bar"
+	if _, _, err = r.getSubsetControls(instance); err == nil {
+		t.Fatalf("expect error for custom workload not in whitelist")
+	}
+}
@@ -18,9 +18,13 @@ package uniteddeployment
 
 import (
 	"context"
+	"reflect"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -43,3 +47,51 @@ func (e *eventHandler) Update(ctx context.Context, evt event.TypedUpdateEvent[*a
 	ResourceVersionExpectation.Observe(evt.ObjectNew)
 	e.TypedEnqueueRequestForObject.Update(ctx, evt, q)
 }
+
+var _ handler.TypedEventHandler[*corev1.Node, reconcile.Request] = &nodeEventHandler{}
+
+// nodeEventHandler enqueues the UnitedDeployments discovering subsets whose topology values
+// may be changed by the node event.
+type nodeEventHandler struct {
+	reader client.Reader
+}
+
+func (e *nodeEventHandler) Create(ctx context.Context, evt event.TypedCreateEvent[*corev1.Node], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	e.enqueue(q, func(discovery *appsv1alpha1.SubsetDiscovery) bool {
+		return getDiscoveredTopologyValue(discovery, evt.Object) != ""
+	})
+}
+
+func (e *nodeEventHandler) Update(ctx context.Context, evt event.TypedUpdateEvent[*corev1.Node], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	// only the labels of nodes affect the discovered subsets
+	if reflect.DeepEqual(evt.ObjectOld.Labels, evt.ObjectNew.Labels) {
+		return
+	}
+	e.enqueue(q, func(discovery *appsv1alpha1.SubsetDiscovery) bool {
+		return getDiscoveredTopologyValue(discovery, evt.ObjectOld) != getDiscoveredTopologyValue(discovery, evt.ObjectNew)
+	})
+}
+
+func (e *nodeEventHandler) Delete(ctx context.Context, evt event.TypedDeleteEvent[*corev1.Node], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	e.enqueue(q, func(discovery *appsv1alpha1.SubsetDiscovery) bool {
+		return getDiscoveredTopologyValue(discovery, evt.Object) != ""
+	})
+}
+
+func (e *nodeEventHandler) Generic(ctx context.Context, evt event.TypedGenericEvent[*corev1.Node], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+}
+
+func (e *nodeEventHandler) enqueue(q workqueue.TypedRateLimitingInterface[reconcile.Request], topologyChanged func(discovery *appsv1alpha1.SubsetDiscovery) bool) {
+	udList := &appsv1alpha1.UnitedDeploymentList{}
+	if err := e.reader.List(context.TODO(), udList); err != nil {
+		klog.ErrorS(err, "Failed to list UnitedDeployments for node event")
+		return
+	}
+	for i := range udList.Items {
+		ud := &udList.Items[i]
+		if discovery := ud.Spec.Topology.SubsetDiscovery; discovery == nil || !topologyChanged(discovery) {
+			continue
+		}
+		q.Add(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: ud.Namespace, Name: ud.Name}})
+	}
+}
@@ -33,9 +33,9 @@ import (
 
 func (r *ReconcileUnitedDeployment) manageSubsets(ud *appsv1alpha1.UnitedDeployment, existingSubsets map[string]*Subset,
 	nextUpdate map[string]SubsetUpdate, currentRevision, updatedRevision *appsv1.ControllerRevision,
-	subsetType subSetType) (newStatus *appsv1alpha1.UnitedDeploymentStatus, allErrors error) {
+	control ControlInterface, subsetType subSetType) (newStatus *appsv1alpha1.UnitedDeploymentStatus, allErrors error) {
 	newStatus = ud.Status.DeepCopy()
-	exists, provisioned, err := r.manageSubsetProvision(ud, existingSubsets, nextUpdate, currentRevision, updatedRevision, subsetType)
+	exists, provisioned, err := r.manageSubsetProvision(ud, existingSubsets, nextUpdate, currentRevision, updatedRevision, control, subsetType)
 	if err != nil {
 		SetUnitedDeploymentCondition(newStatus, NewUnitedDeploymentCondition(appsv1alpha1.SubsetProvisioned, corev1.ConditionFalse, "Error", err.Error()))
 		return newStatus, fmt.Errorf("fail to manage Subset provision: %s", err)
@@ -73,6 +73,11 @@ func (r *ReconcileUnitedDeployment) manageSubsets(ud *appsv1alpha1.UnitedDeploym
 				"unitedDeployment", klog.KObj(ud), "subset", klog.KObj(subset),
 				"current", subset.GetAnnotations()[appsv1alpha1.AnnotationSubsetPatchKey], "updated", nextUpdate[name].Patch)
 			needUpdate = append(needUpdate, name)
+		} else if subset.GetAnnotations()[appsv1alpha1.AnnotationSubsetOverridesKey] != nextUpdate[name].Overrides {
+			klog.V(5).InfoS("UnitedDeployment subset needs update: overrides changed",
+				"unitedDeployment", klog.KObj(ud), "subset", klog.KObj(subset),
+				"current", subset.GetAnnotations()[appsv1alpha1.AnnotationSubsetOverridesKey], "updated", nextUpdate[name].Overrides)
+			needUpdate = append(needUpdate, name)
 		} else if subset.Status.UpdatedReplicas < subset.Status.Replicas {
 			klog.V(5).InfoS("UnitedDeployment subset needs update: still in updating progress",
 				"unitedDeployment", klog.KObj(ud), "subset", klog.KObj(subset))
@@ -90,7 +95,7 @@ func (r *ReconcileUnitedDeployment) manageSubsets(ud *appsv1alpha1.UnitedDeploym
 			klog.InfoS("UnitedDeployment needed to update Subset with revision, replicas and partition",
 				"unitedDeployment", klog.KObj(ud), "subsetType", subsetType, "subset", klog.KObj(subset),
 				"expectedRevisionName", expectedRevision.Name, "replicas", replicas, "partition", partition)
-			updateSubsetErr := r.subSetControls[subsetType].UpdateSubset(subset, ud, expectedRevision.Name, replicas, partition)
+			updateSubsetErr := control.UpdateSubset(subset, ud, expectedRevision.Name, replicas, partition)
 			if updateSubsetErr != nil {
 				r.recorder.Event(ud.DeepCopy(), corev1.EventTypeWarning, fmt.Sprintf("Failed%s", eventTypeSubsetsUpdate), fmt.Sprintf("Error updating PodSet (%s) %s when updating: %s", subsetType, subset.Name, updateSubsetErr))
 			}
@@ -118,7 +123,7 @@ func (r *ReconcileUnitedDeployment) manageSubsets(ud *appsv1alpha1.UnitedDeploym
 	return
 }
 
-func (r *ReconcileUnitedDeployment) manageSubsetProvision(ud *appsv1alpha1.UnitedDeployment, existingSubsets map[string]*Subset, nextUpdate map[string]SubsetUpdate, currentRevision, updatedRevision *appsv1.ControllerRevision, subsetType subSetType) (sets.String, bool, error) {
+func (r *ReconcileUnitedDeployment) manageSubsetProvision(ud *appsv1alpha1.UnitedDeployment, existingSubsets map[string]*Subset, nextUpdate map[string]SubsetUpdate, currentRevision, updatedRevision *appsv1.ControllerRevision, control ControlInterface, subsetType subSetType) (sets.String, bool, error) {
 	expectedSubsets := sets.String{}
 	gotSubsets := sets.String{}
 
@@ -156,7 +161,7 @@ func (r *ReconcileUnitedDeployment) manageSubsetProvision(ud *appsv1alpha1.Unite
 
 			replicas := nextUpdate[subsetName].Replicas
 			partition := nextUpdate[subsetName].Partition
-			err := r.subSetControls[subsetType].CreateSubset(ud, subsetName, revision, replicas, partition)
+			err := control.CreateSubset(ud, subsetName, revision, replicas, partition)
 			if err != nil {
 				if !apierrors.IsTimeout(err) {
 					return fmt.Errorf("fail to create Subset (%s) %s: %s", subsetType, subsetName, err.Error())
@@ -183,7 +188,7 @@ func (r *ReconcileUnitedDeployment) manageSubsetProvision(ud *appsv1alpha1.Unite
 		var deleteErrs []error
 		for _, subsetName := range deletes {
 			subset := existingSubsets[subsetName]
-			if err := r.subSetControls[subsetType].DeleteSubset(subset); err != nil {
+			if err := control.DeleteSubset(subset); err != nil {
 				deleteErrs = append(deleteErrs, fmt.Errorf("fail to delete Subset (%s) %s/%s for %s: %s", subsetType, subset.Namespace, subset.Name, subsetName, err))
 			}
 		}
@@ -197,12 +202,12 @@ func (r *ReconcileUnitedDeployment) manageSubsetProvision(ud *appsv1alpha1.Unite
 
 	// clean the other kind of subsets
 	cleaned := false
-	for t, control := range r.subSetControls {
+	for t, otherControl := range r.subSetControls {
 		if t == subsetType {
 			continue
 		}
 
-		subsets, err := control.GetAllSubsets(ud, revision)
+		subsets, err := otherControl.GetAllSubsets(ud, revision)
 		if err != nil {
 			errs = append(errs, fmt.Errorf("fail to list Subset of other type %s for UnitedDeployment %s/%s: %s", t, ud.Namespace, ud.Name, err))
 			continue
@@ -210,7 +215,7 @@ func (r *ReconcileUnitedDeployment) manageSubsetProvision(ud *appsv1alpha1.Unite
 
 		for _, subset := range subsets {
 			cleaned = true
-			if err := control.DeleteSubset(subset); err != nil {
+			if err := otherControl.DeleteSubset(subset); err != nil {
 				errs = append(errs, fmt.Errorf("fail to delete Subset %s of other type %s for UnitedDeployment %s/%s: %s", subset.Name, t, ud.Namespace, ud.Name, err))
 				continue
 			}
@@ -0,0 +1,159 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workloadspread
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	kubecontroller "k8s.io/kubernetes/pkg/controller"
+
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+)
+
+const (
+	evictionPurposeRebalance   = "Rebalance"
+	evictionPurposeMigrateBack = "MigrateBack"
+
+	rebalanceMessageNoCapacity = "no schedulable subset has capacity for the evicted pods"
+	rebalanceMessageWaiting    = "waiting for the evicted pods to be recreated"
+)
+
+// planRebalance finds the pods in over-populated subsets, and the pods that do not match any subset, and records
+// the rebalance progress in status. It returns the pods that should be evicted in this round, limited by the
+// rebalance strategy. The pods in each subset are picked in the same order as the workload deletes them.
+func planRebalance(ws *appsv1alpha1.WorkloadSpread, status *appsv1alpha1.WorkloadSpreadStatus,
+	subsetPodMap map[string][]*corev1.Pod, workloadReplicas int32, now time.Time) []*corev1.Pod {
+	strategy := ws.Spec.ScheduleStrategy.Rebalance
+	if strategy == nil || ws.Spec.TargetReference == nil || !isEffectiveKindForDeletionCost(ws.Spec.TargetReference) ||
+		len(status.SubsetStatuses) != len(ws.Spec.Subsets) {
+		status.Rebalance = nil
+		return nil
+	}
+
+	rebalance := ws.Status.Rebalance.DeepCopy()
+	if rebalance == nil {
+		rebalance = &appsv1alpha1.WorkloadSpreadRebalanceStatus{}
+	}
+	status.Rebalance = rebalance
+	rebalance.Message = ""
+
+	// the pods that do not match any subset are evicted first.
+	excessPods := getActivePods(subsetPodMap[FakeSubsetName])
+	var activeReplicas int32
+	inFlight, hasCapacity := false, false
+	for i := range ws.Spec.Subsets {
+		subset := &ws.Spec.Subsets[i]
+		subsetStatus := &status.SubsetStatuses[i]
+		activeReplicas += subsetStatus.Replicas
+		if len(subsetStatus.CreatingPods) > 0 || len(subsetStatus.DeletingPods) > 0 {
+			inFlight = true
+		}
+		cond := GetWorkloadSpreadSubsetCondition(subsetStatus, appsv1alpha1.SubsetSchedulable)
+		if subsetStatus.MissingReplicas != 0 && (cond == nil || cond.Status != corev1.ConditionFalse) {
+			hasCapacity = true
+		}
+
+		maxReplicas, err := getSubsetMaxReplicas(ws, subset, workloadReplicas)
+		if err != nil || maxReplicas < 0 {
+			continue
+		}
+		activePods := getActivePods(subsetPodMap[subset.Name])
+		if excess := len(activePods) - maxReplicas; excess > 0 {
+			indexes := sortDeleteIndexes(activePods)
+			for _, index := range indexes[:excess] {
+				excessPods = append(excessPods, activePods[index])
+			}
+		}
+	}
+	activeReplicas += int32(len(getActivePods(subsetPodMap[FakeSubsetName])))
+
+	// a new rebalance starts when subsets become unbalanced again.
+	if rebalance.ExcessReplicas == 0 && len(excessPods) > 0 {
+		rebalance.EvictedReplicas = 0
+	}
+	rebalance.ExcessReplicas = int32(len(excessPods))
+	if len(excessPods) == 0 {
+		return nil
+	}
+
+	if !hasCapacity {
+		rebalance.Message = rebalanceMessageNoCapacity
+		return nil
+	}
+	if inFlight || activeReplicas < workloadReplicas {
+		rebalance.Message = rebalanceMessageWaiting
+		return nil
+	}
+	if rebalance.LastEvictionTime != nil {
+		if next := rebalance.LastEvictionTime.Add(strategy.GetInterval()); now.Before(next) {
+			durationStore.Push(getWorkloadSpreadKey(ws), next.Sub(now))
+			return nil
+		}
+	}
+
+	if evictions := int(strategy.GetMaxEvictionsPerInterval()); len(excessPods) > evictions {
+		excessPods = excessPods[:evictions]
+	}
+	rebalance.EvictedReplicas += int32(len(excessPods))
+	rebalance.LastEvictionTime = &metav1.Time{Time: now}
+	durationStore.Push(getWorkloadSpreadKey(ws), strategy.GetInterval())
+	return excessPods
+}
+
+// evictPods evicts the pods through the eviction API, so that PodDisruptionBudget and PodUnavailableBudget
+// are honored. It stops once an eviction is rejected by the budgets.
+func (r *ReconcileWorkloadSpread) evictPods(ws *appsv1alpha1.WorkloadSpread, pods []*corev1.Pod, purpose string) error {
+	for _, pod := range pods {
+		eviction := &policyv1.Eviction{
+			ObjectMeta: metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace},
+		}
+		err := r.SubResource("eviction").Create(context.TODO(), pod, eviction)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if errors.IsTooManyRequests(err) {
+			r.recorder.Eventf(ws, corev1.EventTypeWarning, purpose+"EvictionBlocked",
+				"Eviction of Pod %s/%s for %s is blocked: %v", pod.Namespace, pod.Name, purpose, err)
+			return nil
+		}
+		if err != nil {
+			r.recorder.Eventf(ws, corev1.EventTypeWarning, purpose+"EvictionFailed",
+				"Failed to evict Pod %s/%s for %s: %v", pod.Namespace, pod.Name, purpose, err)
+			return err
+		}
+		r.recorder.Eventf(ws, corev1.EventTypeNormal, purpose+"Evicted",
+			"Evicted Pod %s/%s for %s", pod.Namespace, pod.Name, purpose)
+		klog.V(3).InfoS("WorkloadSpread evicted Pod", "workloadSpread", klog.KObj(ws), "pod", klog.KObj(pod), "purpose", purpose)
+	}
+	return nil
+}
+
+func getActivePods(pods []*corev1.Pod) []*corev1.Pod {
+	activePods := make([]*corev1.Pod, 0, len(pods))
+	for i := range pods {
+		if kubecontroller.IsPodActive(pods[i]) {
+			activePods = append(activePods, pods[i])
+		}
+	}
+	return activePods
+}
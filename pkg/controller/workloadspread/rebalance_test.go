@@ -0,0 +1,212 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workloadspread
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+)
+
+func newRebalancePods(subset string, num int) []*corev1.Pod {
+	pods := make([]*corev1.Pod, num)
+	for i := range pods {
+		pods[i] = podDemo.DeepCopy()
+		pods[i].Name = fmt.Sprintf("%s-pod-%d", subset, i)
+	}
+	return pods
+}
+
+func TestPlanRebalance(t *testing.T) {
+	now := time.Now()
+	cases := []struct {
+		name            string
+		rebalance       *appsv1alpha1.WorkloadSpreadRebalanceStrategy
+		maxReplicasB    *intstr.IntOrString
+		oldStatus       *appsv1alpha1.WorkloadSpreadRebalanceStatus
+		creatingB       bool
+		podNumA         int
+		podNumFake      int
+		expectPods      []string
+		expectRebalance *appsv1alpha1.WorkloadSpreadRebalanceStatus
+	}{
+		{
+			name:    "rebalance is not enabled",
+			podNumA: 4,
+		},
+		{
+			name:       "evict the first batch",
+			rebalance:  &appsv1alpha1.WorkloadSpreadRebalanceStrategy{},
+			podNumA:    4,
+			expectPods: []string{"subset-a-pod-0"},
+			expectRebalance: &appsv1alpha1.WorkloadSpreadRebalanceStatus{
+				ExcessReplicas: 2, EvictedReplicas: 1, LastEvictionTime: &metav1.Time{Time: now},
+			},
+		},
+		{
+			name:       "pods not in any subset are evicted first",
+			rebalance:  &appsv1alpha1.WorkloadSpreadRebalanceStrategy{MaxEvictionsPerInterval: ptr.To(int32(2))},
+			podNumA:    3,
+			podNumFake: 1,
+			expectPods: []string{"fake-pod-0", "subset-a-pod-0"},
+			expectRebalance: &appsv1alpha1.WorkloadSpreadRebalanceStatus{
+				ExcessReplicas: 2, EvictedReplicas: 2, LastEvictionTime: &metav1.Time{Time: now},
+			},
+		},
+		{
+			name:      "within the interval",
+			rebalance: &appsv1alpha1.WorkloadSpreadRebalanceStrategy{IntervalSeconds: ptr.To(int32(30))},
+			oldStatus: &appsv1alpha1.WorkloadSpreadRebalanceStatus{
+				ExcessReplicas: 2, EvictedReplicas: 1, LastEvictionTime: &metav1.Time{Time: now.Add(-10 * time.Second)},
+			},
+			podNumA: 3,
+			expectRebalance: &appsv1alpha1.WorkloadSpreadRebalanceStatus{
+				ExcessReplicas: 1, EvictedReplicas: 1, LastEvictionTime: &metav1.Time{Time: now.Add(-10 * time.Second)},
+			},
+		},
+		{
+			name:      "after the interval",
+			rebalance: &appsv1alpha1.WorkloadSpreadRebalanceStrategy{IntervalSeconds: ptr.To(int32(30))},
+			oldStatus: &appsv1alpha1.WorkloadSpreadRebalanceStatus{
+				ExcessReplicas: 2, EvictedReplicas: 1, LastEvictionTime: &metav1.Time{Time: now.Add(-time.Minute)},
+			},
+			podNumA:    3,
+			expectPods: []string{"subset-a-pod-0"},
+			expectRebalance: &appsv1alpha1.WorkloadSpreadRebalanceStatus{
+				ExcessReplicas: 1, EvictedReplicas: 2, LastEvictionTime: &metav1.Time{Time: now},
+			},
+		},
+		{
+			name:      "waiting for evicted pods to be recreated",
+			rebalance: &appsv1alpha1.WorkloadSpreadRebalanceStrategy{},
+			podNumA:   4,
+			creatingB: true,
+			expectRebalance: &appsv1alpha1.WorkloadSpreadRebalanceStatus{
+				ExcessReplicas: 2, Message: rebalanceMessageWaiting,
+			},
+		},
+		{
+			name:         "no subset has capacity",
+			rebalance:    &appsv1alpha1.WorkloadSpreadRebalanceStrategy{},
+			maxReplicasB: ptr.To(intstr.FromInt32(2)),
+			podNumA:      4,
+			expectRebalance: &appsv1alpha1.WorkloadSpreadRebalanceStatus{
+				ExcessReplicas: 2, Message: rebalanceMessageNoCapacity,
+			},
+		},
+		{
+			name:      "subsets are balanced",
+			rebalance: &appsv1alpha1.WorkloadSpreadRebalanceStrategy{},
+			oldStatus: &appsv1alpha1.WorkloadSpreadRebalanceStatus{
+				ExcessReplicas: 1, EvictedReplicas: 2, LastEvictionTime: &metav1.Time{Time: now.Add(-time.Minute)},
+			},
+			podNumA: 2,
+			expectRebalance: &appsv1alpha1.WorkloadSpreadRebalanceStatus{
+				EvictedReplicas: 2, LastEvictionTime: &metav1.Time{Time: now.Add(-time.Minute)},
+			},
+		},
+		{
+			name:      "new rebalance resets evicted replicas",
+			rebalance: &appsv1alpha1.WorkloadSpreadRebalanceStrategy{},
+			oldStatus: &appsv1alpha1.WorkloadSpreadRebalanceStatus{
+				EvictedReplicas: 2, LastEvictionTime: &metav1.Time{Time: now.Add(-time.Hour)},
+			},
+			podNumA:    3,
+			expectPods: []string{"subset-a-pod-0"},
+			expectRebalance: &appsv1alpha1.WorkloadSpreadRebalanceStatus{
+				ExcessReplicas: 1, EvictedReplicas: 1, LastEvictionTime: &metav1.Time{Time: now},
+			},
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			ws := workloadSpreadDemo.DeepCopy()
+			ws.Spec.ScheduleStrategy.Rebalance = cs.rebalance
+			ws.Spec.Subsets = []appsv1alpha1.WorkloadSpreadSubset{
+				{Name: "subset-a", MaxReplicas: ptr.To(intstr.FromInt32(2))},
+				{Name: "subset-b", MaxReplicas: cs.maxReplicasB},
+			}
+			ws.Status.Rebalance = cs.oldStatus
+
+			podMap := map[string][]*corev1.Pod{
+				"subset-a":     newRebalancePods("subset-a", cs.podNumA),
+				"subset-b":     newRebalancePods("subset-b", 2),
+				FakeSubsetName: newRebalancePods("fake", cs.podNumFake),
+			}
+			missingB := int32(-1)
+			if cs.maxReplicasB != nil {
+				missingB = 0
+			}
+			status := &appsv1alpha1.WorkloadSpreadStatus{
+				SubsetStatuses: []appsv1alpha1.WorkloadSpreadSubsetStatus{
+					{Name: "subset-a", Replicas: int32(cs.podNumA)},
+					{Name: "subset-b", Replicas: 2, MissingReplicas: missingB},
+				},
+			}
+			if cs.creatingB {
+				status.SubsetStatuses[1].CreatingPods = map[string]metav1.Time{"subset-b-pod-2": {Time: now}}
+			}
+			workloadReplicas := int32(cs.podNumA + cs.podNumFake + 2)
+
+			pods := planRebalance(ws, status, podMap, workloadReplicas, now)
+			var podNames []string
+			for _, pod := range pods {
+				podNames = append(podNames, pod.Name)
+			}
+			if !reflect.DeepEqual(podNames, cs.expectPods) {
+				t.Fatalf("expect pods %v, but got %v", cs.expectPods, podNames)
+			}
+			if !reflect.DeepEqual(status.Rebalance, cs.expectRebalance) {
+				t.Fatalf("expect rebalance status %+v, but got %+v", cs.expectRebalance, status.Rebalance)
+			}
+		})
+	}
+}
+
+func TestEvictPods(t *testing.T) {
+	pods := newRebalancePods("subset-a", 2)
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pods[0].DeepCopy(), pods[1].DeepCopy()).Build()
+	r := ReconcileWorkloadSpread{
+		Client:   fakeClient,
+		recorder: record.NewFakeRecorder(10),
+	}
+	if err := r.evictPods(workloadSpreadDemo.DeepCopy(), pods[:1], evictionPurposeRebalance); err != nil {
+		t.Fatalf("failed to evict pods: %v", err)
+	}
+
+	err := fakeClient.Get(context.TODO(), client.ObjectKeyFromObject(pods[0]), &corev1.Pod{})
+	if !errors.IsNotFound(err) {
+		t.Fatalf("expect pod %s to be evicted, but got %v", pods[0].Name, err)
+	}
+	if err = fakeClient.Get(context.TODO(), client.ObjectKeyFromObject(pods[1]), &corev1.Pod{}); err != nil {
+		t.Fatalf("expect pod %s to be kept, but got %v", pods[1].Name, err)
+	}
+}
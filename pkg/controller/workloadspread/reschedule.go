@@ -18,14 +18,22 @@ package workloadspread
 
 import (
 	"context"
+	"math"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	resourcehelper "k8s.io/component-helpers/resource"
 	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/openkruise/kruise/pkg/controller/util"
 
 	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+	utilclient "github.com/openkruise/kruise/pkg/util/client"
+	"github.com/openkruise/kruise/pkg/util/fieldindex"
 	wsutil "github.com/openkruise/kruise/pkg/webhook/workloadspread/validating"
 )
 
@@ -119,3 +127,303 @@ func PodUnscheduledTimeout(ws *appsv1alpha1.WorkloadSpread, pod *corev1.Pod) boo
 	}
 	return timeouted
 }
+
+// planMigrateBack finds the pods in the latter subsets which can be migrated back to the former subsets that regain
+// capacity, and records the migration progress in status. A former subset is regarded to regain capacity only if its
+// nodes can accept more pods, which is given by nodeCapacity, because the schedulable condition of a subset is
+// recovered after a fixed duration without any evidence. If a subset becomes unschedulable after a migration, the
+// migration is regarded as failed and the next one is backed off. It returns the pods that should be evicted in this
+// round, and the pods of the last subset are picked first.
+func planMigrateBack(ws *appsv1alpha1.WorkloadSpread, status *appsv1alpha1.WorkloadSpreadStatus,
+	subsetPodMap map[string][]*corev1.Pod, nodeCapacity map[string]int32, workloadReplicas int32, now time.Time) []*corev1.Pod {
+	if !isMigrateBackEnabled(ws) || len(status.SubsetStatuses) != len(ws.Spec.Subsets) {
+		status.MigrateBack = nil
+		return nil
+	}
+	strategy := ws.Spec.ScheduleStrategy.Adaptive.MigrateBack
+
+	migrateBack := ws.Status.MigrateBack.DeepCopy()
+	if migrateBack == nil {
+		migrateBack = &appsv1alpha1.WorkloadSpreadMigrateBackStatus{}
+	}
+	status.MigrateBack = migrateBack
+
+	// capacity is the number of pods that the former subsets can accept.
+	// limit is the capacity for the pods of the last subset which has pods to migrate.
+	var capacity, limit int32
+	var activeReplicas int32
+	inFlight, lackCapacity, waitCapacity := false, false, false
+	subsetCandidates := make([][]*corev1.Pod, len(ws.Spec.Subsets))
+	for i := range ws.Spec.Subsets {
+		subsetStatus := &status.SubsetStatuses[i]
+		activePods := getActivePods(subsetPodMap[ws.Spec.Subsets[i].Name])
+		activeReplicas += int32(len(activePods))
+		if len(subsetStatus.CreatingPods) > 0 || len(subsetStatus.DeletingPods) > 0 {
+			inFlight = true
+		}
+		for _, pod := range activePods {
+			if pod.Spec.NodeName == "" {
+				// wait for the pods to be scheduled, which may be migrated in last round.
+				inFlight = true
+			}
+		}
+
+		if lackCapacity && len(activePods) > 0 {
+			waitCapacity = true
+		}
+		if capacity > 0 && len(activePods) > 0 {
+			limit = capacity
+			indexes := sortDeleteIndexes(activePods)
+			for _, index := range indexes {
+				subsetCandidates[i] = append(subsetCandidates[i], activePods[index])
+			}
+		}
+
+		cond := GetWorkloadSpreadSubsetCondition(subsetStatus, appsv1alpha1.SubsetSchedulable)
+		if cond != nil && cond.Status == corev1.ConditionFalse {
+			// the pods migrated in last round failed to be scheduled to this subset.
+			if migrateBack.LastMigrationTime != nil && cond.LastTransitionTime.After(migrateBack.LastMigrationTime.Time) &&
+				(migrateBack.LastFailureTime == nil || cond.LastTransitionTime.After(migrateBack.LastFailureTime.Time)) {
+				klog.InfoS("WorkloadSpread subset became unschedulable after migration", "workloadSpread", klog.KObj(ws), "subsetName", subsetStatus.Name)
+				migrateBack.FailedMigrations++
+				migrateBack.LastFailureTime = cond.LastTransitionTime.DeepCopy()
+			}
+			continue
+		}
+		if subsetStatus.MissingReplicas == 0 {
+			continue
+		}
+		free := nodeCapacity[subsetStatus.Name]
+		if subsetStatus.MissingReplicas > 0 {
+			free = min(free, subsetStatus.MissingReplicas)
+		}
+		if free <= 0 {
+			lackCapacity = true
+		}
+		capacity += max(free, 0)
+	}
+	activeReplicas += int32(len(getActivePods(subsetPodMap[FakeSubsetName])))
+	if waitCapacity {
+		// nodes are not watched, so check the capacity of the former subsets periodically if there are pods to migrate.
+		durationStore.Push(getWorkloadSpreadKey(ws), strategy.GetInterval())
+	}
+
+	// the pods of the last subset are migrated first.
+	var candidates []*corev1.Pod
+	for i := len(subsetCandidates) - 1; i >= 0; i-- {
+		candidates = append(candidates, subsetCandidates[i]...)
+	}
+	if len(candidates) > int(limit) {
+		candidates = candidates[:limit]
+	}
+
+	// a new migration starts when some pods become migratable again.
+	if migrateBack.MigratableReplicas == 0 && len(candidates) > 0 {
+		migrateBack.MigratedReplicas = 0
+	}
+	migrateBack.MigratableReplicas = int32(len(candidates))
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	// rebalance goes first, and wait for the evicted pods to be recreated and scheduled.
+	if (status.Rebalance != nil && status.Rebalance.ExcessReplicas > 0) || inFlight || activeReplicas < workloadReplicas {
+		return nil
+	}
+	if next := nextMigrationTime(strategy, migrateBack); next != nil && now.Before(*next) {
+		durationStore.Push(getWorkloadSpreadKey(ws), next.Sub(now))
+		return nil
+	}
+
+	if migrations := int(strategy.GetMaxMigrationsPerInterval()); len(candidates) > migrations {
+		candidates = candidates[:migrations]
+	}
+	// no subset became unschedulable after last migration, so it succeeded.
+	if migrateBack.LastFailureTime == nil || migrateBack.LastMigrationTime == nil ||
+		migrateBack.LastFailureTime.Before(migrateBack.LastMigrationTime) {
+		migrateBack.FailedMigrations = 0
+	}
+	migrateBack.MigratedReplicas += int32(len(candidates))
+	migrateBack.LastMigrationTime = &metav1.Time{Time: now}
+	durationStore.Push(getWorkloadSpreadKey(ws), strategy.GetInterval())
+	return candidates
+}
+
+// maxMigrateBackBackoffExponent limits the backoff after failed migrations to 32 times of the interval.
+const maxMigrateBackBackoffExponent = 5
+
+// nextMigrationTime returns the earliest time of the next migration, which is backed off exponentially
+// from the last failure if the last migrations failed.
+func nextMigrationTime(strategy *appsv1alpha1.WorkloadSpreadMigrateBackStrategy, migrateBack *appsv1alpha1.WorkloadSpreadMigrateBackStatus) *time.Time {
+	if migrateBack.LastMigrationTime == nil {
+		return nil
+	}
+	next := migrateBack.LastMigrationTime.Add(strategy.GetInterval())
+	if migrateBack.FailedMigrations > 0 && migrateBack.LastFailureTime != nil {
+		backoff := strategy.GetInterval() << min(migrateBack.FailedMigrations, maxMigrateBackBackoffExponent)
+		next = migrateBack.LastFailureTime.Add(backoff)
+	}
+	return &next
+}
+
+func isMigrateBackEnabled(ws *appsv1alpha1.WorkloadSpread) bool {
+	adaptive := ws.Spec.ScheduleStrategy.Adaptive
+	return ws.Spec.ScheduleStrategy.Type == appsv1alpha1.AdaptiveWorkloadSpreadScheduleStrategyType && adaptive != nil &&
+		adaptive.MigrateBack != nil && ws.Spec.TargetReference != nil && isEffectiveKindForDeletionCost(ws.Spec.TargetReference) &&
+		!ws.Spec.IsWeighted()
+}
+
+// calculateNodeCapacity returns the number of pods that the Ready and schedulable nodes of each subset can accept
+// according to their allocatable resources. The resource requests of the pods to migrate are calculated from a
+// pod of the workload. Only the required node selector term and tolerations of the subsets are used to match nodes.
+// The capacity is only calculated for the former subsets that pods of the latter subsets could migrate back to,
+// and only the nodes matching the required node selector terms of these subsets are listed.
+func (r *ReconcileWorkloadSpread) calculateNodeCapacity(ws *appsv1alpha1.WorkloadSpread, status *appsv1alpha1.WorkloadSpreadStatus,
+	subsetPodMap map[string][]*corev1.Pod) (map[string]int32, error) {
+	if !isMigrateBackEnabled(ws) || len(status.SubsetStatuses) != len(ws.Spec.Subsets) {
+		return nil, nil
+	}
+	// last is the index of the last subset with pods
+	var samplePod *corev1.Pod
+	last := -1
+	for i := len(ws.Spec.Subsets) - 1; i >= 0; i-- {
+		if activePods := getActivePods(subsetPodMap[ws.Spec.Subsets[i].Name]); len(activePods) > 0 {
+			samplePod, last = activePods[0], i
+			break
+		}
+	}
+	if samplePod == nil {
+		return nil, nil
+	}
+
+	// the subsets before the last one with pods, which lack replicas and are not known to be unschedulable.
+	var subsets []*appsv1alpha1.WorkloadSpreadSubset
+	for i := 0; i < last; i++ {
+		subsetStatus := &status.SubsetStatuses[i]
+		if subsetStatus.MissingReplicas == 0 {
+			continue
+		}
+		if cond := GetWorkloadSpreadSubsetCondition(subsetStatus, appsv1alpha1.SubsetSchedulable); cond != nil && cond.Status == corev1.ConditionFalse {
+			continue
+		}
+		subsets = append(subsets, &ws.Spec.Subsets[i])
+	}
+	if len(subsets) == 0 {
+		return nil, nil
+	}
+	podRequests := resourcehelper.PodRequests(samplePod, resourcehelper.PodResourcesOptions{})
+
+	nodeCapacity := make(map[string]int32, len(subsets))
+	nodesBySelector := make(map[string][]corev1.Node)
+	freeOfNodes := make(map[string]int32)
+	for _, subset := range subsets {
+		selector := nodeSelectorForSubset(subset)
+		nodes, ok := nodesBySelector[selector.String()]
+		if !ok {
+			nodeList := &corev1.NodeList{}
+			if err := r.List(context.TODO(), nodeList, &client.ListOptions{LabelSelector: selector}); err != nil {
+				return nil, err
+			}
+			nodes = nodeList.Items
+			nodesBySelector[selector.String()] = nodes
+		}
+		for j := range nodes {
+			node := &nodes[j]
+			if node.Spec.Unschedulable || !isNodeReady(node) {
+				continue
+			}
+			if matched, err := matchesSubsetRequiredAndToleration(&corev1.Pod{}, node, subset); err != nil || !matched {
+				continue
+			}
+			free, ok := freeOfNodes[node.Name]
+			if !ok {
+				var err error
+				if free, err = r.calculateFreeOfNode(node, podRequests); err != nil {
+					return nil, err
+				}
+				freeOfNodes[node.Name] = free
+			}
+			nodeCapacity[subset.Name] = min(nodeCapacity[subset.Name]+free, math.MaxInt32/2)
+		}
+	}
+	return nodeCapacity, nil
+}
+
+// nodeSelectorForSubset converts the match expressions of the required node selector term of subset to a label
+// selector to list the nodes, or returns labels.Everything() if they can not be converted.
+func nodeSelectorForSubset(subset *appsv1alpha1.WorkloadSpreadSubset) labels.Selector {
+	if subset.RequiredNodeSelectorTerm == nil {
+		return labels.Everything()
+	}
+	selector := labels.NewSelector()
+	for _, expr := range subset.RequiredNodeSelectorTerm.MatchExpressions {
+		var op selection.Operator
+		switch expr.Operator {
+		case corev1.NodeSelectorOpIn:
+			op = selection.In
+		case corev1.NodeSelectorOpNotIn:
+			op = selection.NotIn
+		case corev1.NodeSelectorOpExists:
+			op = selection.Exists
+		case corev1.NodeSelectorOpDoesNotExist:
+			op = selection.DoesNotExist
+		case corev1.NodeSelectorOpGt:
+			op = selection.GreaterThan
+		case corev1.NodeSelectorOpLt:
+			op = selection.LessThan
+		default:
+			return labels.Everything()
+		}
+		requirement, err := labels.NewRequirement(expr.Key, op, expr.Values)
+		if err != nil {
+			return labels.Everything()
+		}
+		selector = selector.Add(*requirement)
+	}
+	return selector
+}
+
+// calculateFreeOfNode returns how many pods with the given requests can still be placed on the node.
+func (r *ReconcileWorkloadSpread) calculateFreeOfNode(node *corev1.Node, podRequests corev1.ResourceList) (int32, error) {
+	podList := &corev1.PodList{}
+	if err := r.List(context.TODO(), podList, client.MatchingFields{fieldindex.IndexNameForPodNodeName: node.Name}, utilclient.DisableDeepCopy); err != nil {
+		return 0, err
+	}
+	requested := corev1.ResourceList{}
+	var podCount int64
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		podCount++
+		for name, quantity := range resourcehelper.PodRequests(pod, resourcehelper.PodResourcesOptions{}) {
+			total := requested[name]
+			total.Add(quantity)
+			requested[name] = total
+		}
+	}
+
+	free := int64(math.MaxInt32)
+	if allocatable, ok := node.Status.Allocatable[corev1.ResourcePods]; ok {
+		free = allocatable.Value() - podCount
+	}
+	for name, request := range podRequests {
+		if request.IsZero() {
+			continue
+		}
+		allocatable := node.Status.Allocatable[name]
+		used := requested[name]
+		free = min(free, (allocatable.MilliValue()-used.MilliValue())/request.MilliValue())
+	}
+	return int32(max(free, 0)), nil
+}
+
+func isNodeReady(node *corev1.Node) bool {
+	for _, cond := range node.Status.Conditions {
+		if cond.Type == corev1.NodeReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
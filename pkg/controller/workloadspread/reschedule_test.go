@@ -19,10 +19,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"reflect"
 	"testing"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/tools/record"
@@ -327,3 +329,397 @@ func TestRescheduleSubset(t *testing.T) {
 		})
 	}
 }
+
+func TestPlanMigrateBack(t *testing.T) {
+	now := time.Now()
+	schedulable := func(since time.Duration) []appsv1alpha1.WorkloadSpreadSubsetCondition {
+		return []appsv1alpha1.WorkloadSpreadSubsetCondition{{
+			Type: appsv1alpha1.SubsetSchedulable, Status: corev1.ConditionTrue, LastTransitionTime: metav1.Time{Time: now.Add(-since)},
+		}}
+	}
+	unschedulable := []appsv1alpha1.WorkloadSpreadSubsetCondition{{
+		Type: appsv1alpha1.SubsetSchedulable, Status: corev1.ConditionFalse, LastTransitionTime: metav1.Time{Time: now.Add(-time.Minute)},
+	}}
+	cases := []struct {
+		name              string
+		migrateBack       *appsv1alpha1.WorkloadSpreadMigrateBackStrategy
+		conditionsA       []appsv1alpha1.WorkloadSpreadSubsetCondition
+		oldStatus         *appsv1alpha1.WorkloadSpreadMigrateBackStatus
+		rebalance         *appsv1alpha1.WorkloadSpreadRebalanceStatus
+		nodeCapacityA     *int32
+		creatingA         bool
+		pendingA          bool
+		podNumA           int
+		expectPods        []string
+		expectMigrateBack *appsv1alpha1.WorkloadSpreadMigrateBackStatus
+	}{
+		{
+			name:        "migrateBack is not enabled",
+			conditionsA: schedulable(time.Hour),
+			podNumA:     2,
+		},
+		{
+			name:        "migrate pods back to the former subset",
+			migrateBack: &appsv1alpha1.WorkloadSpreadMigrateBackStrategy{},
+			conditionsA: schedulable(time.Hour),
+			podNumA:     2,
+			expectPods:  []string{"subset-b-pod-0"},
+			expectMigrateBack: &appsv1alpha1.WorkloadSpreadMigrateBackStatus{
+				MigratableReplicas: 2, MigratedReplicas: 1, LastMigrationTime: &metav1.Time{Time: now},
+			},
+		},
+		{
+			name:        "migrations are limited by the capacity of the former subset",
+			migrateBack: &appsv1alpha1.WorkloadSpreadMigrateBackStrategy{MaxMigrationsPerInterval: pointer.Int32(5)},
+			conditionsA: schedulable(time.Hour),
+			podNumA:     2,
+			expectPods:  []string{"subset-b-pod-0", "subset-b-pod-1"},
+			expectMigrateBack: &appsv1alpha1.WorkloadSpreadMigrateBackStatus{
+				MigratableReplicas: 2, MigratedReplicas: 2, LastMigrationTime: &metav1.Time{Time: now},
+			},
+		},
+		{
+			name:          "migrations are limited by the capacity of nodes in the former subset",
+			migrateBack:   &appsv1alpha1.WorkloadSpreadMigrateBackStrategy{MaxMigrationsPerInterval: pointer.Int32(5)},
+			conditionsA:   schedulable(time.Minute),
+			nodeCapacityA: pointer.Int32(1),
+			podNumA:       2,
+			expectPods:    []string{"subset-b-pod-0"},
+			expectMigrateBack: &appsv1alpha1.WorkloadSpreadMigrateBackStatus{
+				MigratableReplicas: 1, MigratedReplicas: 1, LastMigrationTime: &metav1.Time{Time: now},
+			},
+		},
+		{
+			name:              "former subset recovered schedulable without node capacity",
+			migrateBack:       &appsv1alpha1.WorkloadSpreadMigrateBackStrategy{},
+			conditionsA:       schedulable(time.Hour),
+			nodeCapacityA:     pointer.Int32(0),
+			podNumA:           2,
+			expectMigrateBack: &appsv1alpha1.WorkloadSpreadMigrateBackStatus{},
+		},
+		{
+			name:              "former subset is unschedulable",
+			migrateBack:       &appsv1alpha1.WorkloadSpreadMigrateBackStrategy{},
+			conditionsA:       unschedulable,
+			podNumA:           2,
+			expectMigrateBack: &appsv1alpha1.WorkloadSpreadMigrateBackStatus{},
+		},
+		{
+			name:        "former subset becomes unschedulable after migration",
+			migrateBack: &appsv1alpha1.WorkloadSpreadMigrateBackStrategy{},
+			conditionsA: unschedulable,
+			oldStatus: &appsv1alpha1.WorkloadSpreadMigrateBackStatus{
+				MigratableReplicas: 2, MigratedReplicas: 1, LastMigrationTime: &metav1.Time{Time: now.Add(-10 * time.Minute)},
+			},
+			podNumA: 2,
+			expectMigrateBack: &appsv1alpha1.WorkloadSpreadMigrateBackStatus{
+				MigratedReplicas: 1, LastMigrationTime: &metav1.Time{Time: now.Add(-10 * time.Minute)},
+				FailedMigrations: 1, LastFailureTime: &metav1.Time{Time: now.Add(-time.Minute)},
+			},
+		},
+		{
+			name:        "migration is backed off after failure",
+			migrateBack: &appsv1alpha1.WorkloadSpreadMigrateBackStrategy{},
+			conditionsA: schedulable(time.Minute),
+			oldStatus: &appsv1alpha1.WorkloadSpreadMigrateBackStatus{
+				MigratedReplicas: 1, LastMigrationTime: &metav1.Time{Time: now.Add(-time.Hour)},
+				FailedMigrations: 1, LastFailureTime: &metav1.Time{Time: now.Add(-6 * time.Minute)},
+			},
+			podNumA: 2,
+			expectMigrateBack: &appsv1alpha1.WorkloadSpreadMigrateBackStatus{
+				MigratableReplicas: 2, LastMigrationTime: &metav1.Time{Time: now.Add(-time.Hour)},
+				FailedMigrations: 1, LastFailureTime: &metav1.Time{Time: now.Add(-6 * time.Minute)},
+			},
+		},
+		{
+			name:        "migrate again after backoff",
+			migrateBack: &appsv1alpha1.WorkloadSpreadMigrateBackStrategy{},
+			conditionsA: schedulable(time.Minute),
+			oldStatus: &appsv1alpha1.WorkloadSpreadMigrateBackStatus{
+				MigratableReplicas: 2, MigratedReplicas: 1, LastMigrationTime: &metav1.Time{Time: now.Add(-time.Hour)},
+				FailedMigrations: 1, LastFailureTime: &metav1.Time{Time: now.Add(-11 * time.Minute)},
+			},
+			podNumA:    2,
+			expectPods: []string{"subset-b-pod-0"},
+			expectMigrateBack: &appsv1alpha1.WorkloadSpreadMigrateBackStatus{
+				MigratableReplicas: 2, MigratedReplicas: 2, LastMigrationTime: &metav1.Time{Time: now},
+				FailedMigrations: 1, LastFailureTime: &metav1.Time{Time: now.Add(-11 * time.Minute)},
+			},
+		},
+		{
+			name:        "failures are reset after a successful migration",
+			migrateBack: &appsv1alpha1.WorkloadSpreadMigrateBackStrategy{},
+			conditionsA: schedulable(time.Hour),
+			oldStatus: &appsv1alpha1.WorkloadSpreadMigrateBackStatus{
+				MigratableReplicas: 2, MigratedReplicas: 1, LastMigrationTime: &metav1.Time{Time: now.Add(-time.Hour)},
+				FailedMigrations: 2, LastFailureTime: &metav1.Time{Time: now.Add(-2 * time.Hour)},
+			},
+			podNumA:    2,
+			expectPods: []string{"subset-b-pod-0"},
+			expectMigrateBack: &appsv1alpha1.WorkloadSpreadMigrateBackStatus{
+				MigratableReplicas: 2, MigratedReplicas: 2, LastMigrationTime: &metav1.Time{Time: now},
+				LastFailureTime: &metav1.Time{Time: now.Add(-2 * time.Hour)},
+			},
+		},
+		{
+			name:        "former subset is full",
+			migrateBack: &appsv1alpha1.WorkloadSpreadMigrateBackStrategy{},
+			conditionsA: schedulable(time.Hour),
+			oldStatus: &appsv1alpha1.WorkloadSpreadMigrateBackStatus{
+				MigratableReplicas: 1, MigratedReplicas: 2, LastMigrationTime: &metav1.Time{Time: now.Add(-time.Hour)},
+			},
+			podNumA: 4,
+			expectMigrateBack: &appsv1alpha1.WorkloadSpreadMigrateBackStatus{
+				MigratedReplicas: 2, LastMigrationTime: &metav1.Time{Time: now.Add(-time.Hour)},
+			},
+		},
+		{
+			name:        "within the interval",
+			migrateBack: &appsv1alpha1.WorkloadSpreadMigrateBackStrategy{IntervalSeconds: pointer.Int32(60)},
+			conditionsA: schedulable(time.Hour),
+			oldStatus: &appsv1alpha1.WorkloadSpreadMigrateBackStatus{
+				MigratableReplicas: 3, MigratedReplicas: 1, LastMigrationTime: &metav1.Time{Time: now.Add(-30 * time.Second)},
+			},
+			podNumA: 2,
+			expectMigrateBack: &appsv1alpha1.WorkloadSpreadMigrateBackStatus{
+				MigratableReplicas: 2, MigratedReplicas: 1, LastMigrationTime: &metav1.Time{Time: now.Add(-30 * time.Second)},
+			},
+		},
+		{
+			name:        "waiting for the migrated pods to be created",
+			migrateBack: &appsv1alpha1.WorkloadSpreadMigrateBackStrategy{},
+			conditionsA: schedulable(time.Hour),
+			creatingA:   true,
+			podNumA:     2,
+			expectMigrateBack: &appsv1alpha1.WorkloadSpreadMigrateBackStatus{
+				MigratableReplicas: 1,
+			},
+		},
+		{
+			name:        "waiting for the migrated pods to be scheduled",
+			migrateBack: &appsv1alpha1.WorkloadSpreadMigrateBackStrategy{},
+			conditionsA: schedulable(time.Hour),
+			pendingA:    true,
+			podNumA:     2,
+			expectMigrateBack: &appsv1alpha1.WorkloadSpreadMigrateBackStatus{
+				MigratableReplicas: 2,
+			},
+		},
+		{
+			name:        "waiting for rebalance",
+			migrateBack: &appsv1alpha1.WorkloadSpreadMigrateBackStrategy{},
+			conditionsA: schedulable(time.Hour),
+			rebalance:   &appsv1alpha1.WorkloadSpreadRebalanceStatus{ExcessReplicas: 1},
+			podNumA:     2,
+			expectMigrateBack: &appsv1alpha1.WorkloadSpreadMigrateBackStatus{
+				MigratableReplicas: 2,
+			},
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			ws := workloadSpreadDemo.DeepCopy()
+			ws.Spec.ScheduleStrategy = appsv1alpha1.WorkloadSpreadScheduleStrategy{
+				Type: appsv1alpha1.AdaptiveWorkloadSpreadScheduleStrategyType,
+				Adaptive: &appsv1alpha1.AdaptiveWorkloadSpreadStrategy{
+					RescheduleCriticalSeconds: pointer.Int32(30),
+					MigrateBack:               cs.migrateBack,
+				},
+			}
+			ws.Spec.Subsets = []appsv1alpha1.WorkloadSpreadSubset{
+				{Name: "subset-a", MaxReplicas: &intstr.IntOrString{Type: intstr.Int, IntVal: 4}},
+				{Name: "subset-b"},
+			}
+			ws.Status.MigrateBack = cs.oldStatus
+
+			podsA := newRebalancePods("subset-a", cs.podNumA)
+			if cs.pendingA {
+				podsA[0].Spec.NodeName = ""
+			}
+			podMap := map[string][]*corev1.Pod{
+				"subset-a": podsA,
+				"subset-b": newRebalancePods("subset-b", 3),
+			}
+			status := &appsv1alpha1.WorkloadSpreadStatus{
+				SubsetStatuses: []appsv1alpha1.WorkloadSpreadSubsetStatus{
+					{Name: "subset-a", Replicas: int32(cs.podNumA), MissingReplicas: int32(4 - cs.podNumA), Conditions: cs.conditionsA},
+					{Name: "subset-b", Replicas: 3, MissingReplicas: -1, Conditions: schedulable(time.Hour)},
+				},
+				Rebalance: cs.rebalance,
+			}
+			if cs.creatingA {
+				status.SubsetStatuses[0].CreatingPods = map[string]metav1.Time{"subset-a-pod-2": {Time: now}}
+				status.SubsetStatuses[0].MissingReplicas--
+			}
+			workloadReplicas := int32(cs.podNumA + 3)
+
+			nodeCapacity := map[string]int32{"subset-a": 10, "subset-b": 10}
+			if cs.nodeCapacityA != nil {
+				nodeCapacity["subset-a"] = *cs.nodeCapacityA
+			}
+			pods := planMigrateBack(ws, status, podMap, nodeCapacity, workloadReplicas, now)
+			var podNames []string
+			for _, pod := range pods {
+				podNames = append(podNames, pod.Name)
+			}
+			if !reflect.DeepEqual(podNames, cs.expectPods) {
+				t.Fatalf("expect pods %v, but got %v", cs.expectPods, podNames)
+			}
+			if !reflect.DeepEqual(status.MigrateBack, cs.expectMigrateBack) {
+				t.Fatalf("expect migrateBack status %+v, but got %+v", cs.expectMigrateBack, status.MigrateBack)
+			}
+		})
+	}
+}
+
+func TestCalculateNodeCapacity(t *testing.T) {
+	newNode := func(name, zone string, ready, unschedulable bool) *corev1.Node {
+		node := &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"zone": zone}},
+			Spec:       corev1.NodeSpec{Unschedulable: unschedulable},
+			Status: corev1.NodeStatus{
+				Allocatable: corev1.ResourceList{
+					corev1.ResourceCPU:  resource.MustParse("4"),
+					corev1.ResourcePods: resource.MustParse("110"),
+				},
+				Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionFalse}},
+			},
+		}
+		if ready {
+			node.Status.Conditions[0].Status = corev1.ConditionTrue
+		}
+		return node
+	}
+	newPod := func(name, nodeName, cpu string) *corev1.Pod {
+		pod := podDemo.DeepCopy()
+		pod.Name = name
+		pod.Spec.NodeName = nodeName
+		pod.Spec.Containers[0].Resources.Requests = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)}
+		return pod
+	}
+	zoneTerm := func(zone string) *corev1.NodeSelectorTerm {
+		return &corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{
+			{Key: "zone", Operator: corev1.NodeSelectorOpIn, Values: []string{zone}},
+		}}
+	}
+
+	ws := workloadSpreadDemo.DeepCopy()
+	ws.Spec.ScheduleStrategy = appsv1alpha1.WorkloadSpreadScheduleStrategy{
+		Type: appsv1alpha1.AdaptiveWorkloadSpreadScheduleStrategyType,
+		Adaptive: &appsv1alpha1.AdaptiveWorkloadSpreadStrategy{
+			RescheduleCriticalSeconds: pointer.Int32(30),
+			MigrateBack:               &appsv1alpha1.WorkloadSpreadMigrateBackStrategy{},
+		},
+	}
+	ws.Spec.Subsets = []appsv1alpha1.WorkloadSpreadSubset{
+		{Name: "subset-a", RequiredNodeSelectorTerm: zoneTerm("a")},
+		{Name: "subset-b", RequiredNodeSelectorTerm: zoneTerm("b")},
+	}
+	podB := newPod("pod-b", "node-b", "1")
+	objects := []client.Object{
+		newNode("node-a", "a", true, false),
+		newNode("node-a-not-ready", "a", false, false),
+		newNode("node-a-unschedulable", "a", true, true),
+		newNode("node-b", "b", true, false),
+		newPod("pod-a", "node-a", "2.5"),
+		podB,
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).
+		WithIndex(&corev1.Pod{}, fieldindex.IndexNameForPodNodeName, func(obj client.Object) []string {
+			return []string{obj.(*corev1.Pod).Spec.NodeName}
+		}).Build()
+	reconciler := ReconcileWorkloadSpread{Client: fakeClient}
+
+	cases := []struct {
+		name           string
+		missing        []int32
+		unschedulable  bool
+		subsetPodMap   map[string][]*corev1.Pod
+		expectCapacity map[string]int32
+	}{
+		{
+			name:           "former subset lacks replicas",
+			missing:        []int32{2, 0},
+			subsetPodMap:   map[string][]*corev1.Pod{"subset-b": {podB}},
+			expectCapacity: map[string]int32{"subset-a": 1},
+		},
+		{
+			name:         "former subset has no missing replicas",
+			missing:      []int32{0, -1},
+			subsetPodMap: map[string][]*corev1.Pod{"subset-b": {podB}},
+		},
+		{
+			name:          "former subset is unschedulable",
+			missing:       []int32{-1, -1},
+			unschedulable: true,
+			subsetPodMap:  map[string][]*corev1.Pod{"subset-b": {podB}},
+		},
+		{
+			name:    "no pods in the latter subsets",
+			missing: []int32{-1, -1},
+			subsetPodMap: map[string][]*corev1.Pod{
+				"subset-a": {newPod("pod-a", "node-a", "2.5")},
+			},
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			status := &appsv1alpha1.WorkloadSpreadStatus{}
+			for i := range ws.Spec.Subsets {
+				status.SubsetStatuses = append(status.SubsetStatuses, appsv1alpha1.WorkloadSpreadSubsetStatus{
+					Name: ws.Spec.Subsets[i].Name, MissingReplicas: cs.missing[i],
+				})
+			}
+			if cs.unschedulable {
+				status.SubsetStatuses[0].Conditions = []appsv1alpha1.WorkloadSpreadSubsetCondition{
+					{Type: appsv1alpha1.SubsetSchedulable, Status: corev1.ConditionFalse},
+				}
+			}
+			nodeCapacity, err := reconciler.calculateNodeCapacity(ws, status, cs.subsetPodMap)
+			if err != nil {
+				t.Fatalf("calculate node capacity failed: %s", err.Error())
+			}
+			if len(nodeCapacity) != len(cs.expectCapacity) || (len(cs.expectCapacity) > 0 && !reflect.DeepEqual(nodeCapacity, cs.expectCapacity)) {
+				t.Fatalf("expect node capacity %v, but got %v", cs.expectCapacity, nodeCapacity)
+			}
+		})
+	}
+}
+
+func TestNodeSelectorForSubset(t *testing.T) {
+	cases := []struct {
+		name   string
+		term   *corev1.NodeSelectorTerm
+		expect string
+	}{
+		{
+			name:   "no required term",
+			expect: "",
+		},
+		{
+			name: "match expressions",
+			term: &corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{
+				{Key: "zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"a", "b"}},
+				{Key: "spot", Operator: corev1.NodeSelectorOpDoesNotExist},
+			}},
+			expect: "!spot,zone in (a,b)",
+		},
+		{
+			name: "match fields only",
+			term: &corev1.NodeSelectorTerm{MatchFields: []corev1.NodeSelectorRequirement{
+				{Key: "metadata.name", Operator: corev1.NodeSelectorOpIn, Values: []string{"node-a"}},
+			}},
+			expect: "",
+		},
+	}
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			selector := nodeSelectorForSubset(&appsv1alpha1.WorkloadSpreadSubset{Name: "subset", RequiredNodeSelectorTerm: cs.term})
+			if selector.String() != cs.expect {
+				t.Fatalf("expect selector %q, but got %q", cs.expect, selector.String())
+			}
+		})
+	}
+}
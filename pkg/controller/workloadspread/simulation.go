@@ -0,0 +1,160 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workloadspread
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	kubecontroller "k8s.io/kubernetes/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+	clonesetutils "github.com/openkruise/kruise/pkg/controller/cloneset/utils"
+	"github.com/openkruise/kruise/pkg/util/controllerfinder"
+	wsutil "github.com/openkruise/kruise/pkg/util/workloadspread"
+)
+
+// ErrScaleInSimulationNotSupported means that the deletion-cost is not effective for the workload, so the Pods to
+// be deleted on scale-in can not be predicted.
+var ErrScaleInSimulationNotSupported = errors.New("scale-in simulation only supports ReplicaSet, Deployment and CloneSet")
+
+// PodDeletion is a Pod that would be deleted when the workload scales in.
+type PodDeletion struct {
+	Pod *corev1.Pod
+	// Subset is the subset that the Pod belongs to, or empty if the Pod doesn't belong to any subset.
+	Subset string
+	// DeletionCost is the deletion-cost that the controller expects for the Pod.
+	DeletionCost int
+}
+
+// SimulatePodDeletions returns the first count Pods that would be deleted when the workload managed by WorkloadSpread
+// scales in, and the current replicas of the workload. The deletion-cost of Pods is calculated in the same way as the
+// controller does, and the Pods are ordered as the workload controller picks Pods to delete. Pods which are not
+// injected with any subset yet are regarded as not belonging to any subset. Nothing is changed by the simulation.
+func SimulatePodDeletions(c client.Client, ws *appsv1alpha1.WorkloadSpread, count int) ([]PodDeletion, int32, error) {
+	if ws.Spec.TargetReference == nil || !isEffectiveKindForDeletionCost(ws.Spec.TargetReference) {
+		return nil, 0, ErrScaleInSimulationNotSupported
+	}
+	r := &ReconcileWorkloadSpread{Client: c, controllerFinder: controllerfinder.Finder}
+	pods, workloadReplicas, err := r.getPodsForWorkloadSpread(ws)
+	if err != nil {
+		return nil, 0, err
+	}
+	latestVersion, err := r.getWorkloadLatestVersion(ws)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// group Pods by version and subset without patching the Pods that have not been injected.
+	versionedPodMap := map[string]map[string][]*corev1.Pod{}
+	for _, pod := range pods {
+		if !kubecontroller.IsPodActive(pod) {
+			continue
+		}
+		version := wsutil.GetPodVersion(pod)
+		if versionedPodMap[version] == nil {
+			versionedPodMap[version] = map[string][]*corev1.Pod{}
+		}
+		subsetName := FakeSubsetName
+		if injectWS := getInjectWorkloadSpreadFromPod(pod); !isNotMatchedWS(injectWS, ws) {
+			subsetName = injectWS.Subset
+		}
+		versionedPodMap[version][subsetName] = append(versionedPodMap[version][subsetName], pod)
+	}
+
+	var deletions []PodDeletion
+	for version, podMap := range versionedPodMap {
+		deletions = append(deletions, calculatePodDeletionsBySubset(ws, podMap, workloadReplicas, version != latestVersion)...)
+	}
+
+	// the workload controller sorts Pods with the deletion-cost in annotations, so set the expected ones to copies.
+	sortedPods := make([]*corev1.Pod, 0, len(deletions))
+	deletionMap := make(map[*corev1.Pod]PodDeletion, len(deletions))
+	for _, deletion := range deletions {
+		pod := deletion.Pod.DeepCopy()
+		if pod.Annotations == nil {
+			pod.Annotations = map[string]string{}
+		}
+		pod.Annotations[wsutil.PodDeletionCostAnnotation] = strconv.Itoa(deletion.DeletionCost)
+		sortedPods = append(sortedPods, pod)
+		deletionMap[pod] = deletion
+	}
+	sort.Slice(sortedPods, func(i, j int) bool { return sortedPods[i].Name < sortedPods[j].Name })
+	sort.SliceStable(sortedPods, clonesetutils.ActivePodsWithRanks{Pods: sortedPods}.Less)
+
+	result := make([]PodDeletion, 0, min(count, len(sortedPods)))
+	for i := 0; i < count && i < len(sortedPods); i++ {
+		result = append(result, deletionMap[sortedPods[i]])
+	}
+	return result, workloadReplicas, nil
+}
+
+// calculatePodDeletionsBySubset returns the deletion-cost of the active Pods in podMap, see updateDeletionCostBySubset.
+func calculatePodDeletionsBySubset(ws *appsv1alpha1.WorkloadSpread,
+	podMap map[string][]*corev1.Pod, workloadReplicas int32, reverseOrder bool) []PodDeletion {
+	var deletions []PodDeletion
+	appendDeletions := func(subsetName string, deletionCosts []podDeletionCost) {
+		for _, deletionCost := range deletionCosts {
+			deletions = append(deletions, PodDeletion{Pod: deletionCost.pod, Subset: subsetName, DeletionCost: deletionCost.cost})
+		}
+	}
+
+	subsetNum := len(ws.Spec.Subsets)
+	for idx := range ws.Spec.Subsets {
+		subset := &ws.Spec.Subsets[idx]
+		if ws.Spec.IsWeighted() {
+			appendDeletions(subset.Name, calculateWeightedSubsetPodDeletionCost(subset, podMap[subset.Name]))
+			continue
+		}
+		subsetIndex := idx
+		if reverseOrder {
+			subsetIndex = subsetNum - idx - 1
+		}
+		deletionCosts, err := calculateSubsetPodDeletionCost(ws, subset, subsetIndex, podMap[subset.Name], workloadReplicas)
+		if err != nil {
+			// the Pods keep the deletion-cost in annotations, as the controller doesn't update them.
+			deletionCosts = make([]podDeletionCost, 0, len(podMap[subset.Name]))
+			for _, pod := range podMap[subset.Name] {
+				cost, _ := strconv.Atoi(pod.Annotations[wsutil.PodDeletionCostAnnotation])
+				deletionCosts = append(deletionCosts, podDeletionCost{pod: pod, cost: cost})
+			}
+		}
+		appendDeletions(subset.Name, deletionCosts)
+	}
+	// the Pods whose subset is not found in spec are also regarded as not belonging to any subset.
+	var fakeSubsetPods []*corev1.Pod
+	for subsetName, pods := range podMap {
+		if subsetName == FakeSubsetName || getSubsetByName(ws, subsetName) == nil {
+			fakeSubsetPods = append(fakeSubsetPods, pods...)
+		}
+	}
+	deletionCosts, _ := calculateSubsetPodDeletionCost(ws, nil, subsetNum, fakeSubsetPods, workloadReplicas)
+	appendDeletions("", deletionCosts)
+	return deletions
+}
+
+func getSubsetByName(ws *appsv1alpha1.WorkloadSpread, name string) *appsv1alpha1.WorkloadSpreadSubset {
+	for i := range ws.Spec.Subsets {
+		if ws.Spec.Subsets[i].Name == name {
+			return &ws.Spec.Subsets[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workloadspread
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+	"github.com/openkruise/kruise/pkg/util/controllerfinder"
+	"github.com/openkruise/kruise/pkg/util/fieldindex"
+	wsutil "github.com/openkruise/kruise/pkg/util/workloadspread"
+)
+
+func TestSimulatePodDeletions(t *testing.T) {
+	baseTime := time.Now().Add(-time.Hour)
+	// the pods created later have greater index and will be deleted preferentially.
+	newPod := func(name, subset string, index int) *corev1.Pod {
+		pod := podDemo.DeepCopy()
+		pod.Name = name
+		pod.CreationTimestamp = metav1.NewTime(baseTime.Add(time.Duration(index) * time.Minute))
+		pod.Labels[appsv1.ControllerRevisionHashLabelKey] = "cloneset-test-6d7b8c9f5"
+		pod.Status.Phase = corev1.PodRunning
+		if subset != "" {
+			injectWS, _ := json.Marshal(&wsutil.InjectWorkloadSpread{Name: workloadSpreadDemo.Name, Subset: subset})
+			pod.Annotations[wsutil.MatchedWorkloadSpreadSubsetAnnotations] = string(injectWS)
+		}
+		return pod
+	}
+	cases := []struct {
+		name            string
+		targetRef       *appsv1alpha1.TargetReference
+		subsets         []appsv1alpha1.WorkloadSpreadSubset
+		pods            []*corev1.Pod
+		count           int
+		expectDeletions []string
+		expectErr       error
+	}{
+		{
+			name: "delete the pods without subset, extra pods and pods in latter subsets in order",
+			subsets: []appsv1alpha1.WorkloadSpreadSubset{
+				{Name: "subset-a", MaxReplicas: ptr.To(intstr.FromInt32(2))},
+				{Name: "subset-b"},
+			},
+			pods: []*corev1.Pod{
+				newPod("pod-a-0", "subset-a", 0),
+				newPod("pod-a-1", "subset-a", 1),
+				newPod("pod-a-2", "subset-a", 2),
+				newPod("pod-b-0", "subset-b", 3),
+				newPod("pod-b-1", "subset-b", 4),
+				newPod("pod-none", "", 0),
+			},
+			count: 4,
+			expectDeletions: []string{
+				"pod-none/-300",
+				"pod-a-2/subset-a/-100",
+				"pod-b-1/subset-b/100",
+				"pod-b-0/subset-b/100",
+			},
+		},
+		{
+			name: "delete the pods in the subset with most pods per weight",
+			subsets: []appsv1alpha1.WorkloadSpreadSubset{
+				{Name: "subset-a", Weight: ptr.To(int32(1))},
+				{Name: "subset-b", Weight: ptr.To(int32(2))},
+			},
+			pods: []*corev1.Pod{
+				newPod("pod-a-0", "subset-a", 0),
+				newPod("pod-b-0", "subset-b", 1),
+				newPod("pod-b-1", "subset-b", 2),
+				newPod("pod-b-2", "subset-b", 3),
+				newPod("pod-b-3", "subset-b", 4),
+			},
+			count: 3,
+			expectDeletions: []string{
+				"pod-b-3/subset-b/5000",
+				"pod-b-2/subset-b/6666",
+				"pod-b-1/subset-b/10000",
+			},
+		},
+		{
+			name: "count is more than the pods",
+			subsets: []appsv1alpha1.WorkloadSpreadSubset{
+				{Name: "subset-a"},
+			},
+			pods: []*corev1.Pod{
+				newPod("pod-a-0", "subset-a", 0),
+			},
+			count:           2,
+			expectDeletions: []string{"pod-a-0/subset-a/100"},
+		},
+		{
+			name:      "deletion-cost is not effective for StatefulSet",
+			targetRef: &appsv1alpha1.TargetReference{APIVersion: "apps/v1", Kind: "StatefulSet", Name: "sts-test"},
+			subsets: []appsv1alpha1.WorkloadSpreadSubset{
+				{Name: "subset-a"},
+			},
+			count:     1,
+			expectErr: ErrScaleInSimulationNotSupported,
+		},
+	}
+
+	defer func(finder *controllerfinder.ControllerFinder) { controllerfinder.Finder = finder }(controllerfinder.Finder)
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			cloneSet := cloneSetDemo.DeepCopy()
+			cloneSet.Spec.Replicas = ptr.To(int32(len(cs.pods)))
+			cloneSet.Status.UpdateRevision = "cloneset-test-6d7b8c9f5"
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cloneSet).
+				WithIndex(&corev1.Pod{}, fieldindex.IndexNameForOwnerRefUID, func(obj client.Object) []string {
+					var owners []string
+					for _, ref := range obj.GetOwnerReferences() {
+						owners = append(owners, string(ref.UID))
+					}
+					return owners
+				}).Build()
+			for _, pod := range cs.pods {
+				if err := fakeClient.Create(context.TODO(), pod.DeepCopy()); err != nil {
+					t.Fatalf("create pod failed: %s", err.Error())
+				}
+			}
+			controllerfinder.Finder = &controllerfinder.ControllerFinder{Client: fakeClient}
+
+			ws := workloadSpreadDemo.DeepCopy()
+			ws.Spec.Subsets = cs.subsets
+			if cs.targetRef != nil {
+				ws.Spec.TargetReference = cs.targetRef
+			}
+			deletions, replicas, err := SimulatePodDeletions(fakeClient, ws, cs.count)
+			if cs.expectErr != nil {
+				if !errors.Is(err, cs.expectErr) {
+					t.Fatalf("expect error %v, but got %v", cs.expectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if replicas != int32(len(cs.pods)) {
+				t.Fatalf("expect replicas %d, but got %d", len(cs.pods), replicas)
+			}
+			var got []string
+			for _, deletion := range deletions {
+				if deletion.Subset == "" {
+					got = append(got, fmt.Sprintf("%s/%d", deletion.Pod.Name, deletion.DeletionCost))
+				} else {
+					got = append(got, fmt.Sprintf("%s/%s/%d", deletion.Pod.Name, deletion.Subset, deletion.DeletionCost))
+				}
+			}
+			if !reflect.DeepEqual(got, cs.expectDeletions) {
+				t.Fatalf("expect deletions %v, but got %v", cs.expectDeletions, got)
+			}
+
+			// the pods should not be patched
+			for _, pod := range cs.pods {
+				current := &corev1.Pod{}
+				if err = fakeClient.Get(context.TODO(), client.ObjectKeyFromObject(pod), current); err != nil {
+					t.Fatalf("get pod failed: %s", err.Error())
+				}
+				_, patched := current.Annotations[wsutil.PodDeletionCostAnnotation]
+				if patched || current.Annotations[wsutil.MatchedWorkloadSpreadSubsetAnnotations] != pod.Annotations[wsutil.MatchedWorkloadSpreadSubsetAnnotations] {
+					t.Fatalf("pod %s should not be changed by simulation", pod.Name)
+				}
+			}
+		})
+	}
+}
@@ -19,6 +19,7 @@ package workloadspread
 import (
 	"context"
 	"fmt"
+	"math"
 	"sort"
 	"strconv"
 
@@ -37,6 +38,9 @@ import (
 const (
 	// RevisionAnnotation is the revision annotation of a deployment's replica sets which records its rollout sequence
 	RevisionAnnotation = "deployment.kubernetes.io/revision"
+
+	// weightedDeletionCostScale is used to keep the precision of deletion-cost for subsets with weights.
+	weightedDeletionCostScale = 10000
 )
 
 func (r *ReconcileWorkloadSpread) getWorkloadLatestVersion(ws *appsv1alpha1.WorkloadSpread) (string, error) {
@@ -93,7 +97,13 @@ func (r *ReconcileWorkloadSpread) updateDeletionCostBySubset(ws *appsv1alpha1.Wo
 	}
 	// update Pod's deletion-cost annotation in each subset
 	for idx, subset := range ws.Spec.Subsets {
-		if err := r.syncSubsetPodDeletionCost(ws, &subset, subsetIndex(idx), podMap[subset.Name], workloadReplicas); err != nil {
+		var err error
+		if ws.Spec.IsWeighted() {
+			err = r.syncWeightedSubsetPodDeletionCost(ws, &subset, podMap[subset.Name])
+		} else {
+			err = r.syncSubsetPodDeletionCost(ws, &subset, subsetIndex(idx), podMap[subset.Name], workloadReplicas)
+		}
+		if err != nil {
 			return err
 		}
 	}
@@ -129,7 +139,32 @@ func (r *ReconcileWorkloadSpread) syncSubsetPodDeletionCost(
 	subsetIndex int,
 	pods []*corev1.Pod,
 	workloadReplicas int32) error {
-	var err error
+	deletionCosts, err := calculateSubsetPodDeletionCost(ws, subset, subsetIndex, pods, workloadReplicas)
+	if err != nil {
+		klog.ErrorS(err, "Failed to get maxReplicas value from subset of WorkloadSpread", "subsetName", subset.Name, "workloadSpread", klog.KObj(ws))
+		return nil
+	}
+	for _, deletionCost := range deletionCosts {
+		if err = r.updateDeletionCostForSubsetPods(ws, subset, []*corev1.Pod{deletionCost.pod}, strconv.Itoa(deletionCost.cost)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// podDeletionCost is the deletion-cost which is expected to be set to the Pod.
+type podDeletionCost struct {
+	pod  *corev1.Pod
+	cost int
+}
+
+// calculateSubsetPodDeletionCost returns the deletion-cost of the active Pods belong to subset, see syncSubsetPodDeletionCost.
+func calculateSubsetPodDeletionCost(
+	ws *appsv1alpha1.WorkloadSpread,
+	subset *appsv1alpha1.WorkloadSpreadSubset,
+	subsetIndex int,
+	pods []*corev1.Pod,
+	workloadReplicas int32) ([]podDeletionCost, error) {
 	// slice that will contain all Pods that want to set deletion-cost a positive value.
 	var positivePods []*corev1.Pod
 	// slice that will contain all Pods that want to set deletion-cost a negative value.
@@ -153,9 +188,11 @@ func (r *ReconcileWorkloadSpread) syncSubsetPodDeletionCost(
 		positivePods = activePods
 	} else {
 		subsetMaxReplicas, err := intstr.GetValueFromIntOrPercent(subset.MaxReplicas, int(workloadReplicas), true)
-		if err != nil || subsetMaxReplicas < 0 {
-			klog.ErrorS(err, "Failed to get maxReplicas value from subset of WorkloadSpread", "subsetName", subset.Name, "workloadSpread", klog.KObj(ws))
-			return nil
+		if err != nil {
+			return nil, err
+		}
+		if subsetMaxReplicas < 0 {
+			return nil, fmt.Errorf("invalid maxReplicas %s", subset.MaxReplicas.String())
 		}
 
 		if replicas <= subsetMaxReplicas {
@@ -180,11 +217,60 @@ func (r *ReconcileWorkloadSpread) syncSubsetPodDeletionCost(
 		}
 	}
 
-	err = r.updateDeletionCostForSubsetPods(ws, subset, positivePods, strconv.Itoa(wsutil.PodDeletionCostPositive*(len(ws.Spec.Subsets)-subsetIndex)))
-	if err != nil {
-		return err
+	deletionCosts := make([]podDeletionCost, 0, len(activePods))
+	for _, pod := range positivePods {
+		deletionCosts = append(deletionCosts, podDeletionCost{pod: pod, cost: wsutil.PodDeletionCostPositive * (len(ws.Spec.Subsets) - subsetIndex)})
+	}
+	for _, pod := range negativePods {
+		deletionCosts = append(deletionCosts, podDeletionCost{pod: pod, cost: wsutil.PodDeletionCostNegative * (subsetIndex + 1)})
+	}
+	return deletionCosts, nil
+}
+
+// syncWeightedSubsetPodDeletionCost calculates the deletion-cost for the Pods belong to the subset with weight.
+// When scaling down, Pods should be deleted from the subset that has the most Pods per weight, so that the
+// proportions between subsets are kept. The k-th (from 0) Pod to be deleted in a subset which has n active Pods
+// gets deletion-cost = weight * 10000 / (n - k), which is the reciprocal of its Pods per weight before the deletion.
+//
+//	name          subset-a          subset-b
+//	weight          1                 2
+//	pods number     3                 4
+//	deletion-cost  (3333,5000,10000)  (5000,6666,10000,20000)
+//
+// Pods that do not match any subset still have negative deletion-cost and will be deleted preferentially.
+func (r *ReconcileWorkloadSpread) syncWeightedSubsetPodDeletionCost(
+	ws *appsv1alpha1.WorkloadSpread,
+	subset *appsv1alpha1.WorkloadSpreadSubset,
+	pods []*corev1.Pod) error {
+	for _, deletionCost := range calculateWeightedSubsetPodDeletionCost(subset, pods) {
+		if err := r.updateDeletionCostForSubsetPods(ws, subset, []*corev1.Pod{deletionCost.pod}, strconv.Itoa(deletionCost.cost)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// calculateWeightedSubsetPodDeletionCost returns the deletion-cost of the active Pods belong to the subset with weight,
+// see syncWeightedSubsetPodDeletionCost.
+func calculateWeightedSubsetPodDeletionCost(subset *appsv1alpha1.WorkloadSpreadSubset, pods []*corev1.Pod) []podDeletionCost {
+	activePods := make([]*corev1.Pod, 0, len(pods))
+	for i := range pods {
+		if kubecontroller.IsPodActive(pods[i]) {
+			activePods = append(activePods, pods[i])
+		}
+	}
+
+	var weight int64
+	if subset.Weight != nil {
+		weight = int64(*subset.Weight)
+	}
+	indexes := sortDeleteIndexes(activePods)
+	deletionCosts := make([]podDeletionCost, 0, len(activePods))
+	for k, index := range indexes {
+		deletionCost := min(weight*weightedDeletionCostScale/int64(len(activePods)-k), math.MaxInt32)
+		deletionCosts = append(deletionCosts, podDeletionCost{pod: activePods[index], cost: int(deletionCost)})
 	}
-	return r.updateDeletionCostForSubsetPods(ws, subset, negativePods, strconv.Itoa(wsutil.PodDeletionCostNegative*(subsetIndex+1)))
+	return deletionCosts
 }
 
 func (r *ReconcileWorkloadSpread) updateDeletionCostForSubsetPods(ws *appsv1alpha1.WorkloadSpread,
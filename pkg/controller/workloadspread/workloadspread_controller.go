@@ -98,6 +98,7 @@ var (
 	controllerKindRS        = appsv1.SchemeGroupVersion.WithKind("ReplicaSet")
 	controllerKindDep       = appsv1.SchemeGroupVersion.WithKind("Deployment")
 	controllerKindJob       = batchv1.SchemeGroupVersion.WithKind("Job")
+	controllerKruiseKindBJ  = appsv1alpha1.SchemeGroupVersion.WithKind("BroadcastJob")
 )
 
 // this is a short cut for any sub-functions to notify the reconcile how long to wait to requeue
@@ -158,6 +159,12 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
+	// Watch for desired changes to BroadcastJob
+	err = c.Watch(source.Kind(mgr.GetCache(), client.Object(&appsv1alpha1.BroadcastJob{}), &workloadEventHandler{Reader: mgr.GetCache()}))
+	if err != nil {
+		return err
+	}
+
 	// Watch for replicas changes to other CRD
 	whiteList, err := configuration.GetWSWatchCustomWorkloadWhiteList(mgr.GetClient())
 	if err != nil {
@@ -202,7 +209,10 @@ type ReconcileWorkloadSpread struct {
 // +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch
 // +kubebuilder:rbac:groups=apps,resources=replicasets,verbs=get;list;watch
 // +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps.kruise.io,resources=broadcastjobs,verbs=get;list;watch
 // +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=pods/eviction,verbs=create
+// +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
 
 func (r *ReconcileWorkloadSpread) Reconcile(_ context.Context, req reconcile.Request) (reconcile.Result, error) {
 	ws := &appsv1alpha1.WorkloadSpread{}
@@ -273,7 +283,42 @@ func (r *ReconcileWorkloadSpread) getPodJob(ref *appsv1alpha1.TargetReference, n
 	for i := range podList.Items {
 		matchedPods = append(matchedPods, &podList.Items[i])
 	}
-	return matchedPods, *(job.Spec.Parallelism), nil
+	return matchedPods, wsutil.GetJobReplicas(job), nil
+}
+
+func (r *ReconcileWorkloadSpread) getPodBroadcastJob(ref *appsv1alpha1.TargetReference, namespace string) ([]*corev1.Pod, int32, error) {
+	ok, err := wsutil.VerifyGroupKind(ref, controllerKruiseKindBJ.Kind, []string{controllerKruiseKindBJ.Group})
+	if err != nil || !ok {
+		return nil, 0, err
+	}
+
+	job := &appsv1alpha1.BroadcastJob{}
+	err = r.Get(context.TODO(), client.ObjectKey{Namespace: namespace, Name: ref.Name}, job)
+	if err != nil {
+		// when error is NotFound, it is ok here.
+		if errors.IsNotFound(err) {
+			klog.V(3).InfoS("Could not find BroadcastJob", "broadcastJob", klog.KRef(namespace, ref.Name))
+			return nil, 0, nil
+		}
+		return nil, 0, err
+	}
+
+	// BroadcastJob has no selector, so the pods are listed by the owner only.
+	podList := &corev1.PodList{}
+	listOption := &client.ListOptions{
+		Namespace:     namespace,
+		FieldSelector: fields.SelectorFromSet(fields.Set{fieldindex.IndexNameForOwnerRefUID: string(job.UID)}),
+	}
+	err = r.List(context.TODO(), podList, listOption)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	matchedPods := make([]*corev1.Pod, 0, len(podList.Items))
+	for i := range podList.Items {
+		matchedPods = append(matchedPods, &podList.Items[i])
+	}
+	return matchedPods, wsutil.GetBroadcastJobReplicas(job), nil
 }
 
 func (r *ReconcileWorkloadSpread) getReplicasPathList(ws *appsv1alpha1.WorkloadSpread) ([]string, error) {
@@ -317,6 +362,8 @@ func (r *ReconcileWorkloadSpread) getPodsForWorkloadSpread(ws *appsv1alpha1.Work
 	switch targetRef.Kind {
 	case controllerKindJob.Kind:
 		pods, workloadReplicas, err = r.getPodJob(targetRef, ws.Namespace)
+	case controllerKruiseKindBJ.Kind:
+		pods, workloadReplicas, err = r.getPodBroadcastJob(targetRef, ws.Namespace)
 	default:
 		pods, workloadReplicas, err = r.controllerFinder.GetPodsForRef(targetRef.APIVersion, targetRef.Kind, ws.Namespace, targetRef.Name, false)
 	}
@@ -385,9 +432,10 @@ func (r *ReconcileWorkloadSpread) filterWorkload(ws *appsv1alpha1.WorkloadSpread
 // syncWorkloadSpread is the main logic of the WorkloadSpread controller. Firstly, we get Pods from workload managed by
 // WorkloadSpread and then classify these Pods to each corresponding subset. Secondly, we set Pod deletion-cost annotation
 // value by compare the number of subset's Pods with the subset's maxReplicas, and then we consider rescheduling failed Pods.
-// Lastly, we update the WorkloadSpread's Status and clean up scheduled failed Pods. controller should collaborate with webhook
-// to maintain WorkloadSpread status together. The controller is responsible for calculating the real status, and the webhook
-// mainly counts missingReplicas and records the creation or deletion entry of Pod into map.
+// Lastly, we update the WorkloadSpread's Status, clean up scheduled failed Pods and evict Pods for rebalance and migration
+// if they are enabled. controller should collaborate with webhook to maintain WorkloadSpread status together. The controller is
+// responsible for calculating the real status, and the webhook mainly counts missingReplicas and records the creation
+// or deletion entry of Pod into map.
 func (r *ReconcileWorkloadSpread) syncWorkloadSpread(ws *appsv1alpha1.WorkloadSpread) error {
 	if ws.Spec.TargetReference == nil {
 		klog.InfoS("WorkloadSpread has no target reference", "workloadSpread", klog.KObj(ws))
@@ -420,6 +468,15 @@ func (r *ReconcileWorkloadSpread) syncWorkloadSpread(ws *appsv1alpha1.WorkloadSp
 		return nil
 	}
 
+	// plan the pods to evict for rebalance and migration, whose progress is recorded in status before evicting
+	now := time.Now()
+	rebalancePods := planRebalance(ws, status, subsetPodMap, workloadReplicas, now)
+	nodeCapacity, err := r.calculateNodeCapacity(ws, status, subsetPodMap)
+	if err != nil {
+		return err
+	}
+	migratePods := planMigrateBack(ws, status, subsetPodMap, nodeCapacity, workloadReplicas, now)
+
 	// update status
 	err = r.UpdateWorkloadSpreadStatus(ws, status)
 	if err != nil {
@@ -427,7 +484,17 @@ func (r *ReconcileWorkloadSpread) syncWorkloadSpread(ws *appsv1alpha1.WorkloadSp
 	}
 
 	// clean up unschedulable Pods
-	return r.cleanupUnscheduledPods(ws, scheduleFailedPodMap)
+	if err = r.cleanupUnscheduledPods(ws, scheduleFailedPodMap); err != nil {
+		return err
+	}
+
+	// evict Pods in over-populated subsets
+	if err = r.evictPods(ws, rebalancePods, evictionPurposeRebalance); err != nil {
+		return err
+	}
+
+	// evict Pods in the latter subsets to migrate them back to the former subsets
+	return r.evictPods(ws, migratePods, evictionPurposeMigrateBack)
 }
 
 func getInjectWorkloadSpreadFromPod(pod *corev1.Pod) *wsutil.InjectWorkloadSpread {
@@ -481,8 +548,11 @@ func (r *ReconcileWorkloadSpread) groupPodBySubset(ws *appsv1alpha1.WorkloadSpre
 			intstr.ValueOrDefault(subset.MaxReplicas, intstr.FromInt32(math.MaxInt32)), int(replicas), true)
 	}
 
-	// count managed pods for each subset
+	// count managed pods for each subset, the completed pods of batch workloads have released their subsets.
 	for i := range pods {
+		if !kubecontroller.IsPodActive(pods[i]) {
+			continue
+		}
 		injectWS := getInjectWorkloadSpreadFromPod(pods[i])
 		if isNotMatchedWS(injectWS, ws) {
 			continue
@@ -514,6 +584,10 @@ func (r *ReconcileWorkloadSpread) groupPodBySubset(ws *appsv1alpha1.WorkloadSpre
 func (r *ReconcileWorkloadSpread) getSuitableSubsetNameForPod(ws *appsv1alpha1.WorkloadSpread, pod *corev1.Pod, subsetMissingReplicas map[string]int) (string, error) {
 	injectWS := getInjectWorkloadSpreadFromPod(pod)
 	if isNotMatchedWS(injectWS, ws) {
+		// the completed pods will not occupy any subset, so there is no need to find one for them.
+		if !kubecontroller.IsPodActive(pod) {
+			return FakeSubsetName, nil
+		}
 		// process the pods that were created before workloadSpread
 		matchedSubset, err := r.getAndUpdateSuitableSubsetName(ws, pod, subsetMissingReplicas)
 		klog.V(3).InfoS("no subset injected to pod, find a suitable one", "pod", klog.KObj(pod), "workloadSpread", klog.KObj(ws), "matchedSubset", matchedSubset)
@@ -725,17 +799,10 @@ func (r *ReconcileWorkloadSpread) calculateWorkloadSpreadSubsetStatus(ws *appsv1
 	subsetStatus.CreatingPods = make(map[string]metav1.Time)
 	subsetStatus.DeletingPods = make(map[string]metav1.Time)
 
-	var err error
-	var subsetMaxReplicas int
-	if subset.MaxReplicas == nil {
-		// MaxReplicas is nil, which means there is no limit for subset replicas, using -1 to represent it.
-		subsetMaxReplicas = -1
-	} else {
-		subsetMaxReplicas, err = intstr.GetScaledValueFromIntOrPercent(subset.MaxReplicas, int(workloadReplicas), true)
-		if err != nil || subsetMaxReplicas < 0 {
-			klog.ErrorS(err, "Failed to get maxReplicas value from subset of WorkloadSpread", "subsetName", subset.Name, "workloadSpread", klog.KObj(ws))
-			return nil
-		}
+	subsetMaxReplicas, err := getSubsetMaxReplicas(ws, subset, workloadReplicas)
+	if err != nil {
+		klog.ErrorS(err, "Failed to get maxReplicas value from subset of WorkloadSpread", "subsetName", subset.Name, "workloadSpread", klog.KObj(ws))
+		return nil
 	}
 	// initialize missingReplicas to subsetMaxReplicas
 	subsetStatus.MissingReplicas = int32(subsetMaxReplicas)
@@ -825,6 +892,26 @@ func (r *ReconcileWorkloadSpread) calculateWorkloadSpreadSubsetStatus(ws *appsv1
 	return subsetStatus
 }
 
+// getSubsetMaxReplicas returns the max replicas of subset, or -1 if there is no limit for subset replicas.
+func getSubsetMaxReplicas(ws *appsv1alpha1.WorkloadSpread, subset *appsv1alpha1.WorkloadSpreadSubset, workloadReplicas int32) (int, error) {
+	if ws.Spec.IsWeighted() {
+		// the weighted share of workload replicas is regarded as the maxReplicas of subset.
+		return int(wsutil.GetWeightedSubsetReplicas(ws, workloadReplicas)[subset.Name]), nil
+	}
+	if subset.MaxReplicas == nil {
+		// MaxReplicas is nil, which means there is no limit for subset replicas, using -1 to represent it.
+		return -1, nil
+	}
+	subsetMaxReplicas, err := intstr.GetScaledValueFromIntOrPercent(subset.MaxReplicas, int(workloadReplicas), true)
+	if err != nil {
+		return 0, err
+	}
+	if subsetMaxReplicas < 0 {
+		return 0, fmt.Errorf("invalid maxReplicas %s", subset.MaxReplicas.String())
+	}
+	return subsetMaxReplicas, nil
+}
+
 func (r *ReconcileWorkloadSpread) UpdateWorkloadSpreadStatus(ws *appsv1alpha1.WorkloadSpread,
 	status *appsv1alpha1.WorkloadSpreadStatus) error {
 	if apiequality.Semantic.DeepEqual(status, ws.Status) {
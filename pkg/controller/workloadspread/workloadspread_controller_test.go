@@ -751,6 +751,62 @@ func TestSubsetPodDeletionCost(t *testing.T) {
 	}
 }
 
+func TestWeightedSubsetPodDeletionCost(t *testing.T) {
+	cases := []struct {
+		name        string
+		weight      int32
+		podNum      int
+		expectCosts []string
+	}{
+		{
+			name:        "weight is 1, pods number is 3",
+			weight:      1,
+			podNum:      3,
+			expectCosts: []string{"3333", "5000", "10000"},
+		},
+		{
+			name:        "weight is 2, pods number is 4",
+			weight:      2,
+			podNum:      4,
+			expectCosts: []string{"5000", "6666", "10000", "20000"},
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			pods := make([]*corev1.Pod, cs.podNum)
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+			for i := range pods {
+				pods[i] = podDemo.DeepCopy()
+				pods[i].Name = fmt.Sprintf("test-pods-%d", i)
+				if err := fakeClient.Create(context.TODO(), pods[i].DeepCopy()); err != nil {
+					t.Fatalf("create pod failed: %s", err.Error())
+				}
+			}
+
+			workloadSpread := workloadSpreadDemo.DeepCopy()
+			workloadSpread.Spec.Subsets = []appsv1alpha1.WorkloadSpreadSubset{{Name: "subset-a", Weight: ptr.To(cs.weight)}}
+			r := ReconcileWorkloadSpread{
+				Client:   fakeClient,
+				recorder: record.NewFakeRecorder(10),
+			}
+			if err := r.syncWeightedSubsetPodDeletionCost(workloadSpread, &workloadSpread.Spec.Subsets[0], pods); err != nil {
+				t.Fatalf("set pod deletion-cost annotation failed: %s", err.Error())
+			}
+
+			latestPods, _ := getLatestPods(fakeClient, workloadSpread)
+			if len(latestPods) != cs.podNum {
+				t.Fatalf("expected %d pods, got %d", cs.podNum, len(latestPods))
+			}
+			for i := range latestPods {
+				if cost := latestPods[i].Annotations[PodDeletionCostAnnotation]; cost != cs.expectCosts[i] {
+					t.Fatalf("expected deletion-cost %s for pod %s, got %s", cs.expectCosts[i], latestPods[i].Name, cost)
+				}
+			}
+		})
+	}
+}
+
 func TestWorkloadSpreadReconcile(t *testing.T) {
 	getTwoPodsWithDifferentLabels := func() []*corev1.Pod {
 		pod1 := podDemo.DeepCopy()
@@ -2505,3 +2561,68 @@ func getLatestPods(c client.Client, workloadSpread *appsv1alpha1.WorkloadSpread)
 	}
 	return matchedPods, err
 }
+
+func TestGroupPodBySubsetWithCompletedPods(t *testing.T) {
+	workloadSpread := workloadSpreadDemo.DeepCopy()
+	workloadSpread.Spec.Subsets = []appsv1alpha1.WorkloadSpreadSubset{
+		{Name: "subset-a", MaxReplicas: ptr.To(intstr.FromInt32(2))},
+		{Name: "subset-b", MaxReplicas: ptr.To(intstr.FromInt32(2))},
+	}
+
+	var pods []*corev1.Pod
+	// the completed pods injected subset-a do not occupy it any more
+	for i := 0; i < 2; i++ {
+		pod := podDemo.DeepCopy()
+		pod.Name = fmt.Sprintf("completed-pod-%d", i)
+		pod.Status.Phase = corev1.PodSucceeded
+		pod.Annotations = map[string]string{
+			wsutil.MatchedWorkloadSpreadSubsetAnnotations: `{"Name":"test-workloadSpread","Subset":"subset-a"}`,
+		}
+		pods = append(pods, pod)
+	}
+	// the completed pod that was created before workloadSpread
+	completedOldPod := podDemo.DeepCopy()
+	completedOldPod.Name = "completed-old-pod"
+	completedOldPod.Spec.NodeName = "node-a"
+	completedOldPod.Status.Phase = corev1.PodFailed
+	pods = append(pods, completedOldPod)
+	// the running pod that was created before workloadSpread
+	runningOldPod := podDemo.DeepCopy()
+	runningOldPod.Name = "running-old-pod"
+	runningOldPod.Spec.NodeName = "node-a"
+	pods = append(pods, runningOldPod)
+
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(node, completedOldPod.DeepCopy(), runningOldPod.DeepCopy()).Build()
+	r := ReconcileWorkloadSpread{
+		Client:   fakeClient,
+		recorder: record.NewFakeRecorder(10),
+	}
+	podMap, err := r.groupPodBySubset(workloadSpread, pods, 2)
+	if err != nil {
+		t.Fatalf("failed to group pods: %v", err)
+	}
+
+	expected := map[string][]string{
+		"subset-a":     {"completed-pod-0", "completed-pod-1", "running-old-pod"},
+		"subset-b":     nil,
+		FakeSubsetName: {"completed-old-pod"},
+	}
+	for subset, expectPods := range expected {
+		var podNames []string
+		for _, pod := range podMap[subset] {
+			podNames = append(podNames, pod.Name)
+		}
+		if !reflect.DeepEqual(podNames, expectPods) {
+			t.Fatalf("expect pods %v in %s, but got %v", expectPods, subset, podNames)
+		}
+	}
+
+	pod := &corev1.Pod{}
+	if err = fakeClient.Get(context.TODO(), client.ObjectKeyFromObject(completedOldPod), pod); err != nil {
+		t.Fatalf("failed to get pod: %v", err)
+	}
+	if _, ok := pod.Annotations[wsutil.MatchedWorkloadSpreadSubsetAnnotations]; ok {
+		t.Fatalf("expect completed pod not to be patched")
+	}
+}
@@ -126,9 +126,13 @@ func (w workloadEventHandler) Update(ctx context.Context, evt event.UpdateEvent,
 		newReplicas = *evt.ObjectNew.(*appsv1.ReplicaSet).Spec.Replicas
 		gvk = controllerKindRS
 	case *batchv1.Job:
-		oldReplicas = *evt.ObjectOld.(*batchv1.Job).Spec.Parallelism
-		newReplicas = *evt.ObjectNew.(*batchv1.Job).Spec.Parallelism
+		oldReplicas = wsutil.GetJobReplicas(evt.ObjectOld.(*batchv1.Job))
+		newReplicas = wsutil.GetJobReplicas(evt.ObjectNew.(*batchv1.Job))
 		gvk = controllerKindJob
+	case *appsv1alpha1.BroadcastJob:
+		oldReplicas = wsutil.GetBroadcastJobReplicas(evt.ObjectOld.(*appsv1alpha1.BroadcastJob))
+		newReplicas = wsutil.GetBroadcastJobReplicas(evt.ObjectNew.(*appsv1alpha1.BroadcastJob))
+		gvk = controllerKruiseKindBJ
 	case *appsv1.StatefulSet:
 		oldReplicas = *evt.ObjectOld.(*appsv1.StatefulSet).Spec.Replicas
 		newReplicas = *evt.ObjectNew.(*appsv1.StatefulSet).Spec.Replicas
@@ -186,6 +190,8 @@ func (w *workloadEventHandler) handleWorkload(q workqueue.TypedRateLimitingInter
 		gvk = controllerKindRS
 	case *batchv1.Job:
 		gvk = controllerKindJob
+	case *appsv1alpha1.BroadcastJob:
+		gvk = controllerKruiseKindBJ
 	case *appsv1.StatefulSet:
 		gvk = controllerKindSts
 	case *appsv1beta1.StatefulSet:
@@ -150,6 +150,21 @@ const (
 	// Under this feature, kruise will think all legal pod-vertical-scaling actions must success.
 	// PodUnavailableBudget will specifically protect the resize actions of individual Pods.
 	InPlacePodVerticalScaling featuregate.Feature = "InPlacePodVerticalScaling"
+
+	// SidecarSetInjectionPreview enables the endpoint on webhook server to preview the SidecarSet injection of a pod.
+	SidecarSetInjectionPreview featuregate.Feature = "SidecarSetInjectionPreview"
+
+	// SidecarSetUpdateAvailabilityAware makes SidecarSet in-place update respect the PodUnavailableBudget of pods,
+	// and skip the pods whose CloneSet or StatefulSet is in rollout.
+	SidecarSetUpdateAvailabilityAware featuregate.Feature = "SidecarSetUpdateAvailabilityAware"
+
+	// SidecarSetHandoffHookExec allows the exec handler of the SidecarSet hot upgrade handoff hook, which needs
+	// kruise-manager to have the permission to create pods/exec in all namespaces.
+	SidecarSetHandoffHookExec featuregate.Feature = "SidecarSetHandoffHookExec"
+
+	// WorkloadSpreadSimulation enables the endpoint on webhook server to simulate the subsets of Pods to be created
+	// and the Pods to be deleted when the workload managed by WorkloadSpread scales.
+	WorkloadSpreadSimulation featuregate.Feature = "WorkloadSpreadSimulation"
 )
 
 var defaultFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
@@ -190,6 +205,10 @@ var defaultFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
 	EnablePodProbeMarkerOnServerless:         {Default: false, PreRelease: featuregate.Alpha},
 	EnableSortSidecarContainerByName:         {Default: false, PreRelease: featuregate.Alpha},
 	InPlacePodVerticalScaling:                {Default: false, PreRelease: featuregate.Alpha},
+	SidecarSetInjectionPreview:               {Default: false, PreRelease: featuregate.Alpha},
+	SidecarSetUpdateAvailabilityAware:        {Default: false, PreRelease: featuregate.Alpha},
+	WorkloadSpreadSimulation:                 {Default: false, PreRelease: featuregate.Alpha},
+	SidecarSetHandoffHookExec:                {Default: false, PreRelease: featuregate.Alpha},
 }
 
 func init() {
@@ -218,6 +237,8 @@ func SetDefaultFeatureGates() {
 		_ = utilfeature.DefaultMutableFeatureGate.Set(fmt.Sprintf("%s=false", SidecarSetPatchPodMetadataDefaultsAllowed))
 		_ = utilfeature.DefaultMutableFeatureGate.Set(fmt.Sprintf("%s=false", EnhancedLivenessProbeGate))
 		_ = utilfeature.DefaultMutableFeatureGate.Set(fmt.Sprintf("%s=false", EnablePodProbeMarkerOnServerless))
+		_ = utilfeature.DefaultMutableFeatureGate.Set(fmt.Sprintf("%s=false", SidecarSetInjectionPreview))
+		_ = utilfeature.DefaultMutableFeatureGate.Set(fmt.Sprintf("%s=false", WorkloadSpreadSimulation))
 	}
 	if !utilfeature.DefaultFeatureGate.Enabled(KruiseDaemon) {
 		_ = utilfeature.DefaultMutableFeatureGate.Set(fmt.Sprintf("%s=false", PreDownloadImageForInPlaceUpdate))
@@ -88,6 +88,24 @@ func GetWSWatchCustomWorkloadWhiteList(client client.Reader) (WSCustomWorkloadWh
 	return whiteList, nil
 }
 
+func GetUDWatchCustomWorkloadWhiteList(client client.Reader) (UDCustomWorkloadWhiteList, error) {
+	whiteList := UDCustomWorkloadWhiteList{}
+	data, err := getKruiseConfiguration(client)
+	if err != nil {
+		return whiteList, err
+	} else if len(data) == 0 {
+		return whiteList, nil
+	}
+	value, ok := data[UDWatchCustomWorkloadWhiteList]
+	if !ok {
+		return whiteList, nil
+	}
+	if err = json.Unmarshal([]byte(value), &whiteList); err != nil {
+		return whiteList, err
+	}
+	return whiteList, nil
+}
+
 func getKruiseConfiguration(c client.Reader) (map[string]string, error) {
 	cfg := &corev1.ConfigMap{}
 	err := c.Get(context.TODO(), client.ObjectKey{Namespace: util.GetKruiseNamespace(), Name: KruiseConfigurationName}, cfg)
@@ -249,3 +249,50 @@ func TestGetWSWatchCustomWorkloadWhiteList(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestGetUDWatchCustomWorkloadWhiteList(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+
+	validWhitelist := UDCustomWorkloadWhiteList{
+		Workloads: []UDCustomWorkload{
+			{
+				GroupVersionKind: schema.GroupVersionKind{Group: "argoproj.io", Version: "v1alpha1", Kind: "Rollout"},
+				ReplicasPath:     "spec.replicas",
+				PartitionPath:    "spec.strategy.partition",
+			},
+		},
+	}
+	validWhitelistJSON, _ := json.Marshal(validWhitelist)
+
+	t.Run("Success: key exists", func(t *testing.T) {
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: util.GetKruiseNamespace(), Name: KruiseConfigurationName},
+			Data:       map[string]string{UDWatchCustomWorkloadWhiteList: string(validWhitelistJSON)},
+		}
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(configMap).Build()
+
+		result, err := GetUDWatchCustomWorkloadWhiteList(fakeClient)
+		assert.NoError(t, err)
+		assert.Equal(t, validWhitelist, result)
+		assert.Equal(t, &validWhitelist.Workloads[0], result.Get("argoproj.io/v1alpha1", "Rollout"))
+		assert.Nil(t, result.Get("argoproj.io/v1beta1", "Rollout"))
+	})
+
+	t.Run("Success: configmap not found", func(t *testing.T) {
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+		result, err := GetUDWatchCustomWorkloadWhiteList(fakeClient)
+		assert.NoError(t, err)
+		assert.Empty(t, result.Workloads)
+	})
+
+	t.Run("Error: invalid json", func(t *testing.T) {
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: util.GetKruiseNamespace(), Name: KruiseConfigurationName},
+			Data:       map[string]string{UDWatchCustomWorkloadWhiteList: `{"invalid`},
+		}
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(configMap).Build()
+		_, err := GetUDWatchCustomWorkloadWhiteList(fakeClient)
+		assert.Error(t, err)
+	})
+}
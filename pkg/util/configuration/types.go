@@ -28,6 +28,7 @@ const (
 	SidecarSetPatchPodMetadataWhiteListKey = "SidecarSet_PatchPodMetadata_WhiteList"
 	PPSWatchCustomWorkloadWhiteList        = "PPS_Watch_Custom_Workload_WhiteList"
 	WSWatchCustomWorkloadWhiteList         = "WorkloadSpread_Watch_Custom_Workload_WhiteList"
+	UDWatchCustomWorkloadWhiteList         = "UnitedDeployment_Watch_Custom_Workload_WhiteList"
 )
 
 type SidecarSetPatchMetadataWhiteList struct {
@@ -88,3 +89,41 @@ type CustomWorkload struct {
 	// ReplicasPath is the replicas field path of this type of workload, such as "spec.replicas"
 	ReplicasPath string `json:"replicasPath,omitempty"`
 }
+
+type UDCustomWorkloadWhiteList struct {
+	Workloads []UDCustomWorkload `json:"workloads,omitempty"`
+}
+
+// Get returns the custom workload of the apiVersion and kind, and nil if it is not in the whitelist.
+func (p *UDCustomWorkloadWhiteList) Get(apiVersion, kind string) *UDCustomWorkload {
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return nil
+	}
+	for i := range p.Workloads {
+		if p.Workloads[i].GroupVersionKind == gv.WithKind(kind) {
+			return &p.Workloads[i]
+		}
+	}
+	return nil
+}
+
+// UDCustomWorkload describes how UnitedDeployment manages a type of custom workload.
+// All the paths are dot-separated field paths, and the default values are used if they are empty.
+type UDCustomWorkload struct {
+	schema.GroupVersionKind `json:",inline"`
+	// ReplicasPath is the replicas field path of this type of workload, defaults to "spec.replicas"
+	ReplicasPath string `json:"replicasPath,omitempty"`
+	// SelectorPath is the pod selector field path of this type of workload, defaults to "spec.selector"
+	SelectorPath string `json:"selectorPath,omitempty"`
+	// TemplatePath is the pod template field path of this type of workload, defaults to "spec.template"
+	TemplatePath string `json:"templatePath,omitempty"`
+	// PartitionPath is the partition field path of this type of workload, partition is not set if it is empty
+	PartitionPath string `json:"partitionPath,omitempty"`
+	// StatusReplicasPath is the status replicas field path, defaults to "status.replicas"
+	StatusReplicasPath string `json:"statusReplicasPath,omitempty"`
+	// StatusReadyReplicasPath is the status ready replicas field path, defaults to "status.readyReplicas"
+	StatusReadyReplicasPath string `json:"statusReadyReplicasPath,omitempty"`
+	// ObservedGenerationPath is the observed generation field path, defaults to "status.observedGeneration"
+	ObservedGenerationPath string `json:"observedGenerationPath,omitempty"`
+}
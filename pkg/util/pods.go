@@ -235,9 +235,13 @@ func IsRunningAndReady(pod *v1.Pod) bool {
 }
 
 func GetPodContainerImageIDs(pod *v1.Pod) map[string]string {
-	cImageIDs := make(map[string]string, len(pod.Status.ContainerStatuses))
-	for i := range pod.Status.ContainerStatuses {
-		c := &pod.Status.ContainerStatuses[i]
+	cImageIDs := make(map[string]string, len(pod.Status.InitContainerStatuses)+len(pod.Status.ContainerStatuses))
+	// the imageIDs of init containers are also included, since k8s native sidecar containers are init containers
+	containerStatuses := make([]v1.ContainerStatus, 0, len(pod.Status.InitContainerStatuses)+len(pod.Status.ContainerStatuses))
+	containerStatuses = append(containerStatuses, pod.Status.InitContainerStatuses...)
+	containerStatuses = append(containerStatuses, pod.Status.ContainerStatuses...)
+	for i := range containerStatuses {
+		c := &containerStatuses[i]
 		//ImageID format: docker-pullable://busybox@sha256:a9286defaba7b3a519d585ba0e37d0b2cbee74ebfe590960b0b1d6a5e97d1e1d
 		imageID := c.ImageID
 		if strings.Contains(imageID, "://") {
@@ -0,0 +1,151 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workloadspread
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	intstrutil "k8s.io/apimachinery/pkg/util/intstr"
+
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+)
+
+// SimulatePodCreations returns the subsets that the next count Pods of the workload would be injected into by the
+// webhook, in creation order, supposing that the workload is scaled out by count replicas and the controller has
+// observed the new replicas. The subset is empty if there is no suitable subset for the Pod. It also returns the
+// current replicas of the workload. Neither the WorkloadSpread nor the workload is changed.
+// The Pods of StatefulSet are assigned to subsets by their ordinals, which are supposed to follow the current replicas.
+func (h *Handler) SimulatePodCreations(ws *appsv1alpha1.WorkloadSpread, count int32) (int32, []string, error) {
+	if ws.Spec.TargetReference == nil {
+		return 0, nil, fmt.Errorf("workloadSpread %s/%s has no targetReference", ws.Namespace, ws.Name)
+	}
+	gvk := schema.FromAPIVersionAndKind(ws.Spec.TargetReference.APIVersion, ws.Spec.TargetReference.Kind)
+	key := types.NamespacedName{Namespace: ws.Namespace, Name: ws.Spec.TargetReference.Name}
+	object := GenerateEmptyWorkloadObject(gvk, key)
+	if err := h.Get(context.TODO(), key, object); err != nil {
+		return 0, nil, err
+	}
+	replicas, err := h.getReplicasOfWorkload(ws, object)
+	if err != nil {
+		return 0, nil, err
+	}
+	if name, ok := getOnlyUnlimitedSubset(ws); ok {
+		subsets := make([]string, count)
+		for i := range subsets {
+			subsets[i] = name
+		}
+		return replicas, subsets, nil
+	}
+	if ws.Spec.TargetReference.Kind == controllerKindSts.Kind {
+		return replicas, simulateStatefulSetPodCreations(ws, replicas, count), nil
+	}
+	// the new Pods are created with the latest version of workload.
+	version, err := GetWorkloadVersion(h.Client, object)
+	if err != nil {
+		return 0, nil, err
+	}
+	subsetStatuses := simulatedSubsetStatuses(ws, ws.Status.VersionedSubsetStatuses[version], replicas+count)
+	return replicas, h.simulatePodCreations(ws, subsetStatuses, replicas+count, count), nil
+}
+
+// simulateStatefulSetPodCreations chooses the subsets for the Pods with ordinals in [replicas, replicas+count),
+// as the webhook does for StatefulSet in acquireSuitableSubset.
+func simulateStatefulSetPodCreations(ws *appsv1alpha1.WorkloadSpread, replicas, count int32) []string {
+	var weightedReplicas map[string]int32
+	if ws.Spec.IsWeighted() {
+		weightedReplicas = GetWeightedSubsetReplicas(ws, replicas+count)
+	}
+	subsets := make([]string, 0, count)
+	for ordinal := replicas; ordinal < replicas+count; ordinal++ {
+		subsets = append(subsets, getSubsetForStatefulSetOrdinal(ws, weightedReplicas, int(ordinal)))
+	}
+	return subsets
+}
+
+// simulatedSubsetStatuses returns a copy of subset statuses whose missingReplicas are recalculated for the workload
+// replicas, in the same way as the controller does.
+func simulatedSubsetStatuses(ws *appsv1alpha1.WorkloadSpread,
+	oldSubsetStatuses []appsv1alpha1.WorkloadSpreadSubsetStatus, workloadReplicas int32) []appsv1alpha1.WorkloadSpreadSubsetStatus {
+	oldSubsetStatusMap := make(map[string]*appsv1alpha1.WorkloadSpreadSubsetStatus, len(oldSubsetStatuses))
+	for i := range oldSubsetStatuses {
+		oldSubsetStatusMap[oldSubsetStatuses[i].Name] = &oldSubsetStatuses[i]
+	}
+	var weightedReplicas map[string]int32
+	if ws.Spec.IsWeighted() {
+		weightedReplicas = GetWeightedSubsetReplicas(ws, workloadReplicas)
+	}
+
+	subsetStatuses := make([]appsv1alpha1.WorkloadSpreadSubsetStatus, 0, len(ws.Spec.Subsets))
+	for _, subset := range ws.Spec.Subsets {
+		subsetStatus := appsv1alpha1.WorkloadSpreadSubsetStatus{Name: subset.Name}
+		if old := oldSubsetStatusMap[subset.Name]; old != nil {
+			subsetStatus = *old.DeepCopy()
+		}
+		var maxReplicas int
+		if weightedReplicas != nil {
+			maxReplicas = int(weightedReplicas[subset.Name])
+		} else if subset.MaxReplicas == nil {
+			subsetStatus.MissingReplicas = -1
+			subsetStatuses = append(subsetStatuses, subsetStatus)
+			continue
+		} else {
+			maxReplicas, _ = intstrutil.GetScaledValueFromIntOrPercent(subset.MaxReplicas, int(workloadReplicas), true)
+		}
+		current := int(subsetStatus.Replicas) + len(subsetStatus.CreatingPods) - len(subsetStatus.DeletingPods)
+		subsetStatus.MissingReplicas = int32(max(maxReplicas-current, 0))
+		subsetStatuses = append(subsetStatuses, subsetStatus)
+	}
+	return subsetStatuses
+}
+
+// simulatePodCreations chooses the subsets for count Pods one by one, as the webhook does in updateSubsetForPod.
+func (h *Handler) simulatePodCreations(ws *appsv1alpha1.WorkloadSpread,
+	subsetStatuses []appsv1alpha1.WorkloadSpreadSubsetStatus, workloadReplicas, count int32) []string {
+	var weightedReplicas map[string]int32
+	if ws.Spec.IsWeighted() {
+		weightedReplicas = GetWeightedSubsetReplicas(ws, workloadReplicas)
+	}
+	subsets := make([]string, 0, count)
+	for i := int32(0); i < count; i++ {
+		var suitableSubset *appsv1alpha1.WorkloadSpreadSubsetStatus
+		if weightedReplicas != nil {
+			suitableSubset = pickWeightedSubset(weightedReplicas, subsetStatuses)
+		} else {
+			suitableSubset = h.getSuitableSubset(subsetStatuses)
+		}
+		if suitableSubset == nil {
+			subsets = append(subsets, "")
+			continue
+		}
+		subsets = append(subsets, suitableSubset.Name)
+		if suitableSubset.MissingReplicas == -1 {
+			continue
+		}
+		if suitableSubset.CreatingPods == nil {
+			suitableSubset.CreatingPods = map[string]metav1.Time{}
+		}
+		suitableSubset.CreatingPods[fmt.Sprintf("simulated-%d", i)] = metav1.Now()
+		if suitableSubset.MissingReplicas > 0 {
+			suitableSubset.MissingReplicas--
+		}
+	}
+	return subsets
+}
@@ -0,0 +1,253 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workloadspread
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+)
+
+func TestSimulatePodCreations(t *testing.T) {
+	unschedulable := []appsv1alpha1.WorkloadSpreadSubsetCondition{
+		{Type: appsv1alpha1.SubsetSchedulable, Status: corev1.ConditionFalse},
+	}
+	cases := []struct {
+		name           string
+		replicas       int32
+		count          int32
+		subsets        []appsv1alpha1.WorkloadSpreadSubset
+		subsetStatuses []appsv1alpha1.WorkloadSpreadSubsetStatus
+		expectSubsets  []string
+	}{
+		{
+			name:     "fill the former subset first",
+			replicas: 4,
+			count:    3,
+			subsets: []appsv1alpha1.WorkloadSpreadSubset{
+				{Name: "subset-a", MaxReplicas: ptr.To(intstr.FromInt32(3))},
+				{Name: "subset-b"},
+			},
+			subsetStatuses: []appsv1alpha1.WorkloadSpreadSubsetStatus{
+				{Name: "subset-a", Replicas: 2, MissingReplicas: 1},
+				{Name: "subset-b", Replicas: 2, MissingReplicas: -1},
+			},
+			expectSubsets: []string{"subset-a", "subset-b", "subset-b"},
+		},
+		{
+			name:     "percent maxReplicas is scaled by the new replicas",
+			replicas: 4,
+			count:    2,
+			subsets: []appsv1alpha1.WorkloadSpreadSubset{
+				{Name: "subset-a", MaxReplicas: ptr.To(intstr.FromString("50%"))},
+				{Name: "subset-b", MaxReplicas: ptr.To(intstr.FromString("50%"))},
+			},
+			subsetStatuses: []appsv1alpha1.WorkloadSpreadSubsetStatus{
+				{Name: "subset-a", Replicas: 2},
+				{Name: "subset-b", Replicas: 2},
+			},
+			expectSubsets: []string{"subset-a", "subset-b"},
+		},
+		{
+			name:     "creating pods are counted",
+			replicas: 3,
+			count:    2,
+			subsets: []appsv1alpha1.WorkloadSpreadSubset{
+				{Name: "subset-a", MaxReplicas: ptr.To(intstr.FromInt32(3))},
+				{Name: "subset-b"},
+			},
+			subsetStatuses: []appsv1alpha1.WorkloadSpreadSubsetStatus{
+				{Name: "subset-a", Replicas: 2, CreatingPods: map[string]metav1.Time{"pod-1": {}}},
+			},
+			expectSubsets: []string{"subset-b", "subset-b"},
+		},
+		{
+			name:     "no suitable subset",
+			replicas: 0,
+			count:    2,
+			subsets: []appsv1alpha1.WorkloadSpreadSubset{
+				{Name: "subset-a"},
+				{Name: "subset-b", MaxReplicas: ptr.To(intstr.FromInt32(1))},
+			},
+			subsetStatuses: []appsv1alpha1.WorkloadSpreadSubsetStatus{
+				{Name: "subset-a", Conditions: unschedulable},
+			},
+			expectSubsets: []string{"subset-b", ""},
+		},
+		{
+			name:     "weighted subsets",
+			replicas: 3,
+			count:    3,
+			subsets: []appsv1alpha1.WorkloadSpreadSubset{
+				{Name: "subset-a", Weight: ptr.To(int32(1))},
+				{Name: "subset-b", Weight: ptr.To(int32(2))},
+			},
+			subsetStatuses: []appsv1alpha1.WorkloadSpreadSubsetStatus{
+				{Name: "subset-a", Replicas: 1},
+				{Name: "subset-b", Replicas: 2},
+			},
+			expectSubsets: []string{"subset-b", "subset-a", "subset-b"},
+		},
+		{
+			name:     "only one subset without maxReplicas",
+			replicas: 2,
+			count:    2,
+			subsets: []appsv1alpha1.WorkloadSpreadSubset{
+				{Name: "subset-a"},
+			},
+			subsetStatuses: []appsv1alpha1.WorkloadSpreadSubsetStatus{
+				{Name: "subset-a", Conditions: unschedulable},
+			},
+			expectSubsets: []string{"subset-a", "subset-a"},
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			cloneSet := &appsv1alpha1.CloneSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"},
+				Spec:       appsv1alpha1.CloneSetSpec{Replicas: ptr.To(cs.replicas)},
+				Status:     appsv1alpha1.CloneSetStatus{UpdateRevision: "test-6d7b8c9f5"},
+			}
+			h := Handler{fake.NewClientBuilder().WithScheme(scheme).WithObjects(cloneSet).Build()}
+			object := &appsv1alpha1.CloneSet{}
+			if err := h.Get(context.TODO(), types.NamespacedName{Namespace: "test", Name: "test"}, object); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			version, err := GetWorkloadVersion(h.Client, object)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			ws := &appsv1alpha1.WorkloadSpread{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"},
+				Spec: appsv1alpha1.WorkloadSpreadSpec{
+					TargetReference: &appsv1alpha1.TargetReference{APIVersion: "apps.kruise.io/v1alpha1", Kind: "CloneSet", Name: "test"},
+					Subsets:         cs.subsets,
+				},
+				Status: appsv1alpha1.WorkloadSpreadStatus{
+					VersionedSubsetStatuses: map[string][]appsv1alpha1.WorkloadSpreadSubsetStatus{version: cs.subsetStatuses},
+				},
+			}
+			origin := ws.DeepCopy()
+
+			replicas, subsets, err := h.SimulatePodCreations(ws, cs.count)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if replicas != cs.replicas {
+				t.Fatalf("expect replicas %d, but got %d", cs.replicas, replicas)
+			}
+			if !reflect.DeepEqual(subsets, cs.expectSubsets) {
+				t.Fatalf("expect subsets %v, but got %v", cs.expectSubsets, subsets)
+			}
+			if !reflect.DeepEqual(ws, origin) {
+				t.Fatalf("workloadSpread should not be changed by simulation")
+			}
+		})
+	}
+}
+
+func TestSimulateStatefulSetPodCreations(t *testing.T) {
+	cases := []struct {
+		name           string
+		replicas       int32
+		count          int32
+		subsets        []appsv1alpha1.WorkloadSpreadSubset
+		subsetStatuses []appsv1alpha1.WorkloadSpreadSubsetStatus
+		expectSubsets  []string
+	}{
+		{
+			name:     "pods are assigned by ordinals",
+			replicas: 2,
+			count:    3,
+			subsets: []appsv1alpha1.WorkloadSpreadSubset{
+				{Name: "subset-a", MaxReplicas: ptr.To(intstr.FromInt32(3))},
+				{Name: "subset-b"},
+			},
+			// the status is ignored for StatefulSet, the pod with ordinal 2 belongs to subset-a in any case.
+			subsetStatuses: []appsv1alpha1.WorkloadSpreadSubsetStatus{
+				{Name: "subset-a", Replicas: 3, MissingReplicas: 0},
+				{Name: "subset-b", MissingReplicas: -1},
+			},
+			expectSubsets: []string{"subset-a", "subset-b", "subset-b"},
+		},
+		{
+			name:     "weighted subsets are scaled by the new replicas",
+			replicas: 0,
+			count:    4,
+			subsets: []appsv1alpha1.WorkloadSpreadSubset{
+				{Name: "subset-a", Weight: ptr.To(int32(1))},
+				{Name: "subset-b", Weight: ptr.To(int32(1))},
+			},
+			expectSubsets: []string{"subset-a", "subset-a", "subset-b", "subset-b"},
+		},
+		{
+			name:     "unschedulable subsets are skipped",
+			replicas: 0,
+			count:    2,
+			subsets: []appsv1alpha1.WorkloadSpreadSubset{
+				{Name: "subset-a", MaxReplicas: ptr.To(intstr.FromInt32(1))},
+				{Name: "subset-b", MaxReplicas: ptr.To(intstr.FromInt32(1))},
+			},
+			subsetStatuses: []appsv1alpha1.WorkloadSpreadSubsetStatus{
+				{Name: "subset-a", Conditions: []appsv1alpha1.WorkloadSpreadSubsetCondition{
+					{Type: appsv1alpha1.SubsetSchedulable, Status: corev1.ConditionFalse},
+				}},
+			},
+			expectSubsets: []string{"subset-b", ""},
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			statefulSet := &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"},
+				Spec:       appsv1.StatefulSetSpec{Replicas: ptr.To(cs.replicas)},
+			}
+			h := Handler{fake.NewClientBuilder().WithScheme(scheme).WithObjects(statefulSet).Build()}
+			ws := &appsv1alpha1.WorkloadSpread{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"},
+				Spec: appsv1alpha1.WorkloadSpreadSpec{
+					TargetReference: &appsv1alpha1.TargetReference{APIVersion: "apps/v1", Kind: "StatefulSet", Name: "test"},
+					Subsets:         cs.subsets,
+				},
+				Status: appsv1alpha1.WorkloadSpreadStatus{SubsetStatuses: cs.subsetStatuses},
+			}
+
+			replicas, subsets, err := h.SimulatePodCreations(ws, cs.count)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if replicas != cs.replicas {
+				t.Fatalf("expect replicas %d, but got %d", cs.replicas, replicas)
+			}
+			if !reflect.DeepEqual(subsets, cs.expectSubsets) {
+				t.Fatalf("expect subsets %v, but got %v", cs.expectSubsets, subsets)
+			}
+		})
+	}
+}
@@ -19,9 +19,13 @@ package workloadspread
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	intstrutil "k8s.io/apimachinery/pkg/util/intstr"
 
@@ -42,6 +46,93 @@ func hasPercentSubset(ws *appsv1alpha1.WorkloadSpread) (has bool) {
 	return false
 }
 
+// GetWeightedSubsetReplicas distributes the replicas to the subsets of ws in proportion to their weights.
+// The remainders are given to the subsets with the largest fractional parts, and to the former subsets on ties.
+func GetWeightedSubsetReplicas(ws *appsv1alpha1.WorkloadSpread, replicas int32) map[string]int32 {
+	result := make(map[string]int32, len(ws.Spec.Subsets))
+	var sumWeights int64
+	for _, subset := range ws.Spec.Subsets {
+		if subset.Weight != nil && *subset.Weight > 0 {
+			sumWeights += int64(*subset.Weight)
+		}
+	}
+	if sumWeights == 0 || replicas <= 0 {
+		for _, subset := range ws.Spec.Subsets {
+			result[subset.Name] = 0
+		}
+		return result
+	}
+
+	remainders := make([]int64, len(ws.Spec.Subsets))
+	allocated := int32(0)
+	for i, subset := range ws.Spec.Subsets {
+		var weight int64
+		if subset.Weight != nil && *subset.Weight > 0 {
+			weight = int64(*subset.Weight)
+		}
+		result[subset.Name] = int32(int64(replicas) * weight / sumWeights)
+		remainders[i] = int64(replicas) * weight % sumWeights
+		allocated += result[subset.Name]
+	}
+	indexes := make([]int, len(ws.Spec.Subsets))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(i, j int) bool {
+		return remainders[indexes[i]] > remainders[indexes[j]]
+	})
+	for i := 0; allocated < replicas; i++ {
+		result[ws.Spec.Subsets[indexes[i]].Name]++
+		allocated++
+	}
+	return result
+}
+
+// GetJobReplicas returns the number of Pods that the Job runs at the same time, which is the parallelism
+// limited by the remaining completions. Completed Pods are not counted, so that they release their subsets.
+func GetJobReplicas(job *batchv1.Job) int32 {
+	replicas := int32(1)
+	if job.Spec.Parallelism != nil {
+		replicas = *job.Spec.Parallelism
+	}
+	if job.Spec.Completions != nil {
+		replicas = min(replicas, *job.Spec.Completions-job.Status.Succeeded)
+	}
+	return max(replicas, 0)
+}
+
+// GetBroadcastJobReplicas returns the number of Pods that the BroadcastJob runs at the same time, which is the
+// number of desired nodes that have not succeeded, limited by the parallelism.
+func GetBroadcastJobReplicas(job *appsv1alpha1.BroadcastJob) int32 {
+	replicas := max(job.Status.Desired-job.Status.Succeeded, 0)
+	if job.Spec.Parallelism != nil {
+		parallelism, err := intstrutil.GetScaledValueFromIntOrPercent(job.Spec.Parallelism, int(job.Status.Desired), true)
+		if err == nil {
+			replicas = min(replicas, int32(parallelism))
+		}
+	}
+	return replicas
+}
+
+// getAssignedNodeName returns the name of node which the Pod is pinned to, such as the Pods of BroadcastJob.
+func getAssignedNodeName(pod *corev1.Pod) string {
+	if pod.Spec.NodeName != "" {
+		return pod.Spec.NodeName
+	}
+	if pod.Spec.Affinity == nil || pod.Spec.Affinity.NodeAffinity == nil ||
+		pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return ""
+	}
+	for _, term := range pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+		for _, req := range term.MatchFields {
+			if req.Key == metav1.ObjectNameField && req.Operator == corev1.NodeSelectorOpIn && len(req.Values) == 1 {
+				return req.Values[0]
+			}
+		}
+	}
+	return ""
+}
+
 func NestedField[T any](obj any, paths ...string) (T, bool, error) {
 	if len(paths) == 0 {
 		val, ok := obj.(T)
@@ -2,8 +2,10 @@ package workloadspread
 
 import (
 	"reflect"
+	"strconv"
 	"testing"
 
+	batchv1 "k8s.io/api/batch/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	intstrutil "k8s.io/apimachinery/pkg/util/intstr"
@@ -241,3 +243,147 @@ func TestIsPodSelected(t *testing.T) {
 		})
 	}
 }
+
+func TestGetWeightedSubsetReplicas(t *testing.T) {
+	cases := []struct {
+		name     string
+		weights  []int32
+		replicas int32
+		want     map[string]int32
+	}{
+		{
+			name:     "divisible replicas",
+			weights:  []int32{1, 2, 3},
+			replicas: 12,
+			want:     map[string]int32{"subset-0": 2, "subset-1": 4, "subset-2": 6},
+		},
+		{
+			name:     "remainders go to the largest fractional parts",
+			weights:  []int32{1, 2, 3},
+			replicas: 5,
+			want:     map[string]int32{"subset-0": 1, "subset-1": 2, "subset-2": 2},
+		},
+		{
+			name:     "remainders go to the former subsets on ties",
+			weights:  []int32{1, 1, 1},
+			replicas: 4,
+			want:     map[string]int32{"subset-0": 2, "subset-1": 1, "subset-2": 1},
+		},
+		{
+			name:     "zero replicas",
+			weights:  []int32{1, 1},
+			replicas: 0,
+			want:     map[string]int32{"subset-0": 0, "subset-1": 0},
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			ws := &appsv1alpha1.WorkloadSpread{}
+			for i, weight := range cs.weights {
+				ws.Spec.Subsets = append(ws.Spec.Subsets, appsv1alpha1.WorkloadSpreadSubset{
+					Name:   "subset-" + strconv.Itoa(i),
+					Weight: ptr.To(weight),
+				})
+			}
+			if got := GetWeightedSubsetReplicas(ws, cs.replicas); !reflect.DeepEqual(got, cs.want) {
+				t.Fatalf("want %v, but got %v", cs.want, got)
+			}
+		})
+	}
+}
+
+func TestGetJobReplicas(t *testing.T) {
+	cases := []struct {
+		name        string
+		parallelism *int32
+		completions *int32
+		succeeded   int32
+		want        int32
+	}{
+		{
+			name: "default parallelism",
+			want: 1,
+		},
+		{
+			name:        "work queue job",
+			parallelism: ptr.To(int32(5)),
+			succeeded:   3,
+			want:        5,
+		},
+		{
+			name:        "limited by remaining completions",
+			parallelism: ptr.To(int32(5)),
+			completions: ptr.To(int32(10)),
+			succeeded:   7,
+			want:        3,
+		},
+		{
+			name:        "completed job",
+			parallelism: ptr.To(int32(5)),
+			completions: ptr.To(int32(10)),
+			succeeded:   10,
+			want:        0,
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			job := &batchv1.Job{
+				Spec:   batchv1.JobSpec{Parallelism: cs.parallelism, Completions: cs.completions},
+				Status: batchv1.JobStatus{Succeeded: cs.succeeded},
+			}
+			if got := GetJobReplicas(job); got != cs.want {
+				t.Fatalf("want %d, but got %d", cs.want, got)
+			}
+		})
+	}
+}
+
+func TestGetBroadcastJobReplicas(t *testing.T) {
+	cases := []struct {
+		name        string
+		parallelism *intstrutil.IntOrString
+		desired     int32
+		succeeded   int32
+		want        int32
+	}{
+		{
+			name:      "no parallelism",
+			desired:   10,
+			succeeded: 4,
+			want:      6,
+		},
+		{
+			name:        "limited by parallelism",
+			parallelism: ptr.To(intstrutil.FromInt32(3)),
+			desired:     10,
+			succeeded:   4,
+			want:        3,
+		},
+		{
+			name:        "limited by percent parallelism",
+			parallelism: ptr.To(intstrutil.FromString("50%")),
+			desired:     10,
+			want:        5,
+		},
+		{
+			name:      "all nodes succeeded",
+			desired:   10,
+			succeeded: 10,
+			want:      0,
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			job := &appsv1alpha1.BroadcastJob{
+				Spec:   appsv1alpha1.BroadcastJobSpec{Parallelism: cs.parallelism},
+				Status: appsv1alpha1.BroadcastJobStatus{Desired: cs.desired, Succeeded: cs.succeeded},
+			}
+			if got := GetBroadcastJobReplicas(job); got != cs.want {
+				t.Fatalf("want %d, but got %d", cs.want, got)
+			}
+		})
+	}
+}
@@ -40,6 +40,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/strategicpatch"
 	"k8s.io/apimachinery/pkg/util/uuid"
 	"k8s.io/client-go/util/retry"
+	schedulecorev1 "k8s.io/component-helpers/scheduling/corev1"
 	"k8s.io/klog/v2"
 	kubecontroller "k8s.io/kubernetes/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -74,6 +75,7 @@ var (
 	controllerKruiseKindAlphaSts = appsv1alpha1.SchemeGroupVersion.WithKind("StatefulSet")
 	controllerKruiseKindBetaSts  = appsv1beta1.SchemeGroupVersion.WithKind("StatefulSet")
 	controllerKindJob            = batchv1.SchemeGroupVersion.WithKind("Job")
+	controllerKruiseKindBJ       = appsv1alpha1.SchemeGroupVersion.WithKind("BroadcastJob")
 	controllerKindRS             = appsv1.SchemeGroupVersion.WithKind("ReplicaSet")
 	controllerKindDep            = appsv1.SchemeGroupVersion.WithKind("Deployment")
 	controllerKindSts            = appsv1.SchemeGroupVersion.WithKind("StatefulSet")
@@ -115,6 +117,7 @@ var (
 		{Kind: controllerKruiseKindCS.Kind, Groups: []string{controllerKruiseKindCS.Group}},
 		{Kind: controllerKindRS.Kind, Groups: []string{controllerKindRS.Group}},
 		{Kind: controllerKindJob.Kind, Groups: []string{controllerKindJob.Group}},
+		{Kind: controllerKruiseKindBJ.Kind, Groups: []string{controllerKruiseKindBJ.Group}},
 		{Kind: controllerKindSts.Kind, Groups: []string{controllerKindSts.Group, controllerKruiseKindAlphaSts.Group, controllerKruiseKindBetaSts.Group}},
 	}
 	workloadsInWhiteListInitialized = false
@@ -335,13 +338,56 @@ func (h *Handler) mutatingPod(matchedWS *appsv1alpha1.WorkloadSpread,
 	return injectErr
 }
 
+// getOnlyUnlimitedSubset returns the name of the only subset if the WorkloadSpread has exactly one subset
+// without maxReplicas, in which case all Pods belong to that subset and no status needs to be recorded.
+func getOnlyUnlimitedSubset(ws *appsv1alpha1.WorkloadSpread) (string, bool) {
+	if len(ws.Spec.Subsets) == 1 && ws.Spec.Subsets[0].MaxReplicas == nil {
+		return ws.Spec.Subsets[0].Name, true
+	}
+	return "", false
+}
+
+// getSubsetForStatefulSetOrdinal returns the subset of the StatefulSet Pod with the given ordinal.
+// For example, suppose that we have the following sub sets config:
+//   - name: subset-a
+//     maxReplicas: 5
+//   - name: subset-b
+//     maxReplicas: 5
+//   - name: subset-c
+//
+// the pods with order within [0, 5) will be assigned to subset-a;
+// the pods with order within [5, 10) will be assigned to subset-b;
+// the pods with order within [10, inf) will be assigned to subset-c.
+// If the subsets have weights, their weighted shares of the replicas are used as the limits,
+// and the last subset is unlimited.
+func getSubsetForStatefulSetOrdinal(ws *appsv1alpha1.WorkloadSpread, weightedReplicas map[string]int32, ordinal int) string {
+	currentThresholdID := int64(0)
+	for i, subset := range ws.Spec.Subsets {
+		cond := getSubsetCondition(ws, subset.Name, appsv1alpha1.SubsetSchedulable)
+		if cond != nil && cond.Status == corev1.ConditionFalse {
+			continue
+		}
+		subsetReplicasLimit := math.MaxInt32
+		if subset.MaxReplicas != nil {
+			subsetReplicasLimit = subset.MaxReplicas.IntValue()
+		} else if weightedReplicas != nil && i < len(ws.Spec.Subsets)-1 {
+			subsetReplicasLimit = int(weightedReplicas[subset.Name])
+		}
+		// currently, we do not support reserveOrdinals feature for advanced statefulSet
+		currentThresholdID += int64(subsetReplicasLimit)
+		if int64(ordinal) < currentThresholdID {
+			return subset.Name
+		}
+	}
+	return ""
+}
+
 func (h *Handler) acquireSuitableSubset(matchedWS *appsv1alpha1.WorkloadSpread,
 	pod *corev1.Pod,
 	injectWS *InjectWorkloadSpread,
 	operation Operation) (string, string, error) {
-	if len(matchedWS.Spec.Subsets) == 1 &&
-		matchedWS.Spec.Subsets[0].MaxReplicas == nil {
-		return matchedWS.Spec.Subsets[0].Name, "", nil
+	if name, ok := getOnlyUnlimitedSubset(matchedWS); ok {
+		return name, "", nil
 	}
 
 	var refresh, changed bool
@@ -355,34 +401,17 @@ func (h *Handler) acquireSuitableSubset(matchedWS *appsv1alpha1.WorkloadSpread,
 
 	switch matchedWS.Spec.TargetReference.Kind {
 	case controllerKindSts.Kind:
-		// StatefulSet has special logic about pod assignment for subsets.
-		// For example, suppose that we have the following sub sets config:
-		//	- name: subset-a
-		//	  maxReplicas: 5
-		//	- name: subset-b
-		//	  maxReplicas: 5
-		//	- name: subset-c
-		// the pods with order within [0, 5) will be assigned to subset-a;
-		// the pods with order within [5, 10) will be assigned to subset-b;
-		// the pods with order within [10, inf) will be assigned to subset-c.
-		currentThresholdID := int64(0)
-		for _, subset := range matchedWS.Spec.Subsets {
-			cond := getSubsetCondition(matchedWS, subset.Name, appsv1alpha1.SubsetSchedulable)
-			if cond != nil && cond.Status == corev1.ConditionFalse {
-				continue
-			}
-			subsetReplicasLimit := math.MaxInt32
-			if subset.MaxReplicas != nil {
-				subsetReplicasLimit = subset.MaxReplicas.IntValue()
-			}
-			// currently, we do not support reserveOrdinals feature for advanced statefulSet
-			currentThresholdID += int64(subsetReplicasLimit)
-			_, orderID := getParentNameAndOrdinal(pod)
-			if int64(orderID) < currentThresholdID {
-				suitableSubsetName = subset.Name
-				break
+		// StatefulSet has special logic about pod assignment for subsets, see getSubsetForStatefulSetOrdinal.
+		var weightedReplicas map[string]int32
+		if matchedWS.Spec.IsWeighted() {
+			replicas, err := h.getWorkloadReplicas(matchedWS)
+			if err != nil {
+				return "", "", err
 			}
+			weightedReplicas = GetWeightedSubsetReplicas(matchedWS, replicas)
 		}
+		_, orderID := getParentNameAndOrdinal(pod)
+		suitableSubsetName = getSubsetForStatefulSetOrdinal(matchedWS, weightedReplicas, orderID)
 
 	default:
 		if err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
@@ -520,7 +549,22 @@ func (h *Handler) updateSubsetForPod(ws *appsv1alpha1.WorkloadSpread,
 			}
 		}
 
-		suitableSubset = h.getSuitableSubset(subsetStatuses)
+		candidates := subsetStatuses
+		if nodeName := getAssignedNodeName(pod); nodeName != "" {
+			// the Pod pinned to a node, such as the Pod of BroadcastJob, can only belong to the subsets of the node.
+			candidates, err = h.getSubsetStatusesForNode(ws, pod, subsetStatuses, nodeName)
+			if err != nil {
+				return false, nil, "", err
+			}
+		}
+		if ws.Spec.IsWeighted() {
+			suitableSubset, err = h.getWeightedSuitableSubset(ws, candidates)
+			if err != nil {
+				return false, nil, "", err
+			}
+		} else {
+			suitableSubset = h.getSuitableSubset(candidates)
+		}
 		if suitableSubset == nil {
 			klog.InfoS("WorkloadSpread doesn't have a suitable subset for Pod when creating",
 				"namespace", ws.Namespace, "wsName", ws.Name, "podName", pod.GetGenerateName())
@@ -711,6 +755,82 @@ func (h *Handler) getSuitableSubset(subsetStatuses []appsv1alpha1.WorkloadSpread
 	return nil
 }
 
+// getWeightedSuitableSubset returns the schedulable subset which is most below its weighted share of the
+// workload replicas. The pods which are being created or deleted are taken into account, so that the
+// concurrent creations are spread, too.
+func (h *Handler) getWeightedSuitableSubset(ws *appsv1alpha1.WorkloadSpread, subsetStatuses []appsv1alpha1.WorkloadSpreadSubsetStatus) (*appsv1alpha1.WorkloadSpreadSubsetStatus, error) {
+	replicas, err := h.getWorkloadReplicas(ws)
+	if err != nil {
+		return nil, err
+	}
+	return pickWeightedSubset(GetWeightedSubsetReplicas(ws, replicas), subsetStatuses), nil
+}
+
+// pickWeightedSubset returns the schedulable subset whose current replicas are most below its weighted replicas.
+func pickWeightedSubset(weightedReplicas map[string]int32, subsetStatuses []appsv1alpha1.WorkloadSpreadSubsetStatus) *appsv1alpha1.WorkloadSpreadSubsetStatus {
+	var suitableSubset *appsv1alpha1.WorkloadSpreadSubsetStatus
+	var maxMissing int32
+	for i := range subsetStatuses {
+		subset := &subsetStatuses[i]
+		canSchedule := true
+		for _, condition := range subset.Conditions {
+			if condition.Type == appsv1alpha1.SubsetSchedulable && condition.Status == corev1.ConditionFalse {
+				canSchedule = false
+				break
+			}
+		}
+		if !canSchedule {
+			continue
+		}
+		current := subset.Replicas + int32(len(subset.CreatingPods)) - int32(len(subset.DeletingPods))
+		missing := weightedReplicas[subset.Name] - current
+		if suitableSubset == nil || missing > maxMissing {
+			suitableSubset = subset
+			maxMissing = missing
+		}
+	}
+	return suitableSubset
+}
+
+// getSubsetStatusesForNode returns the statuses of the subsets whose required node selector term and tolerations
+// match the node.
+func (h *Handler) getSubsetStatusesForNode(ws *appsv1alpha1.WorkloadSpread, pod *corev1.Pod,
+	subsetStatuses []appsv1alpha1.WorkloadSpreadSubsetStatus, nodeName string) ([]appsv1alpha1.WorkloadSpreadSubsetStatus, error) {
+	node := &corev1.Node{}
+	if err := h.Get(context.TODO(), types.NamespacedName{Name: nodeName}, node); err != nil {
+		if errors.IsNotFound(err) {
+			klog.InfoS("Node of Pod not found when choosing subset", "node", nodeName, "workloadSpread", klog.KObj(ws))
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var result []appsv1alpha1.WorkloadSpreadSubsetStatus
+	for _, subsetStatus := range subsetStatuses {
+		for i := range ws.Spec.Subsets {
+			subset := &ws.Spec.Subsets[i]
+			if subset.Name != subsetStatus.Name {
+				continue
+			}
+			tolerations := append(append([]corev1.Toleration{}, pod.Spec.Tolerations...), subset.Tolerations...)
+			if _, untolerated := schedulecorev1.FindMatchingUntoleratedTaint(node.Spec.Taints, tolerations, nil); untolerated {
+				break
+			}
+			if subset.RequiredNodeSelectorTerm != nil {
+				matched, err := schedulecorev1.MatchNodeSelectorTerms(node, &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{*subset.RequiredNodeSelectorTerm},
+				})
+				if err != nil || !matched {
+					break
+				}
+			}
+			result = append(result, subsetStatus)
+			break
+		}
+	}
+	return result, nil
+}
+
 func (h *Handler) isReferenceEqual(target *appsv1alpha1.TargetReference, owner *metav1.OwnerReference, namespace string) (bool, error) {
 	if owner == nil {
 		return false, nil
@@ -819,11 +939,17 @@ func (h *Handler) initializedSubsetStatuses(ws *appsv1alpha1.WorkloadSpread) ([]
 	if err != nil {
 		return nil, err
 	}
+	var weightedReplicas map[string]int32
+	if ws.Spec.IsWeighted() {
+		weightedReplicas = GetWeightedSubsetReplicas(ws, replicas)
+	}
 	var subsetStatuses []appsv1alpha1.WorkloadSpreadSubsetStatus
 	for i := range ws.Spec.Subsets {
 		subset := ws.Spec.Subsets[i]
 		subsetStatus := appsv1alpha1.WorkloadSpreadSubsetStatus{Name: subset.Name}
-		if subset.MaxReplicas == nil {
+		if weightedReplicas != nil {
+			subsetStatus.MissingReplicas = weightedReplicas[subset.Name]
+		} else if subset.MaxReplicas == nil {
 			subsetStatus.MissingReplicas = -1
 		} else {
 			missingReplicas, _ := intstrutil.GetScaledValueFromIntOrPercent(subset.MaxReplicas, int(replicas), true)
@@ -835,7 +961,7 @@ func (h *Handler) initializedSubsetStatuses(ws *appsv1alpha1.WorkloadSpread) ([]
 }
 
 func (h *Handler) getWorkloadReplicas(ws *appsv1alpha1.WorkloadSpread) (int32, error) {
-	if ws.Spec.TargetReference == nil || !hasPercentSubset(ws) {
+	if ws.Spec.TargetReference == nil || (!hasPercentSubset(ws) && !ws.Spec.IsWeighted()) {
 		return 0, nil
 	}
 	gvk := schema.FromAPIVersionAndKind(ws.Spec.TargetReference.APIVersion, ws.Spec.TargetReference.Kind)
@@ -847,7 +973,10 @@ func (h *Handler) getWorkloadReplicas(ws *appsv1alpha1.WorkloadSpread) (int32, e
 	if err != nil {
 		return 0, client.IgnoreNotFound(err)
 	}
+	return h.getReplicasOfWorkload(ws, object)
+}
 
+func (h *Handler) getReplicasOfWorkload(ws *appsv1alpha1.WorkloadSpread, object client.Object) (int32, error) {
 	if ws.Spec.TargetFilter != nil && len(ws.Spec.TargetFilter.ReplicasPathList) > 0 {
 		return GetReplicasFromWorkloadWithTargetFilter(object, ws.Spec.TargetFilter)
 	}
@@ -860,7 +989,9 @@ func (h *Handler) getWorkloadReplicas(ws *appsv1alpha1.WorkloadSpread) (int32, e
 	case *appsv1.StatefulSet:
 		return *o.Spec.Replicas, nil
 	case *batchv1.Job:
-		return *o.Spec.Parallelism, nil
+		return GetJobReplicas(o), nil
+	case *appsv1alpha1.BroadcastJob:
+		return GetBroadcastJobReplicas(o), nil
 	case *appsv1alpha1.CloneSet:
 		return *o.Spec.Replicas, nil
 	case *appsv1beta1.StatefulSet:
@@ -881,6 +1012,8 @@ func GenerateEmptyWorkloadObject(gvk schema.GroupVersionKind, key types.Namespac
 		object = &appsv1.StatefulSet{}
 	case controllerKindJob:
 		object = &batchv1.Job{}
+	case controllerKruiseKindBJ:
+		object = &appsv1alpha1.BroadcastJob{}
 	case controllerKruiseKindCS:
 		object = &appsv1alpha1.CloneSet{}
 	case controllerKruiseKindAlphaSts, controllerKruiseKindBetaSts:
@@ -2341,3 +2341,159 @@ func TestGetReplicasFromWorkloadWithTargetFilter(t *testing.T) {
 		})
 	}
 }
+
+func TestGetWeightedSuitableSubset(t *testing.T) {
+	unschedulable := []appsv1alpha1.WorkloadSpreadSubsetCondition{
+		{Type: appsv1alpha1.SubsetSchedulable, Status: corev1.ConditionFalse},
+	}
+	cases := []struct {
+		name           string
+		subsetStatuses []appsv1alpha1.WorkloadSpreadSubsetStatus
+		expectSubset   string
+	}{
+		{
+			name: "subset most below its weighted replicas",
+			subsetStatuses: []appsv1alpha1.WorkloadSpreadSubsetStatus{
+				{Name: "subset-a", Replicas: 1},
+				{Name: "subset-b", Replicas: 2},
+			},
+			expectSubset: "subset-b",
+		},
+		{
+			name: "creating and deleting pods are counted",
+			subsetStatuses: []appsv1alpha1.WorkloadSpreadSubsetStatus{
+				{Name: "subset-a", Replicas: 1, DeletingPods: map[string]metav1.Time{"pod-1": {}}},
+				{Name: "subset-b", Replicas: 2, CreatingPods: map[string]metav1.Time{"pod-2": {}, "pod-3": {}}},
+			},
+			expectSubset: "subset-a",
+		},
+		{
+			name: "all subsets reach weighted replicas",
+			subsetStatuses: []appsv1alpha1.WorkloadSpreadSubsetStatus{
+				{Name: "subset-a", Replicas: 4},
+				{Name: "subset-b", Replicas: 6},
+			},
+			expectSubset: "subset-b",
+		},
+		{
+			name: "unschedulable subset is skipped",
+			subsetStatuses: []appsv1alpha1.WorkloadSpreadSubsetStatus{
+				{Name: "subset-a", Replicas: 0},
+				{Name: "subset-b", Replicas: 6, Conditions: unschedulable},
+			},
+			expectSubset: "subset-a",
+		},
+		{
+			name: "all subsets are unschedulable",
+			subsetStatuses: []appsv1alpha1.WorkloadSpreadSubsetStatus{
+				{Name: "subset-a", Conditions: unschedulable},
+				{Name: "subset-b", Conditions: unschedulable},
+			},
+		},
+	}
+
+	h := Handler{fake.NewClientBuilder().WithScheme(scheme).WithObjects(&appsv1alpha1.CloneSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"},
+		Spec:       appsv1alpha1.CloneSetSpec{Replicas: ptr.To(int32(9))},
+	}).Build()}
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			ws := &appsv1alpha1.WorkloadSpread{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"},
+				Spec: appsv1alpha1.WorkloadSpreadSpec{
+					TargetReference: &appsv1alpha1.TargetReference{APIVersion: "apps.kruise.io/v1alpha1", Kind: "CloneSet", Name: "test"},
+					Subsets: []appsv1alpha1.WorkloadSpreadSubset{
+						{Name: "subset-a", Weight: ptr.To(int32(1))},
+						{Name: "subset-b", Weight: ptr.To(int32(2))},
+					},
+				},
+			}
+			subset, err := h.getWeightedSuitableSubset(ws, cs.subsetStatuses)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var subsetName string
+			if subset != nil {
+				subsetName = subset.Name
+			}
+			if subsetName != cs.expectSubset {
+				t.Fatalf("expect subset %q, but got %q", cs.expectSubset, subsetName)
+			}
+		})
+	}
+}
+
+func TestGetSubsetStatusesForNode(t *testing.T) {
+	zoneTerm := func(zone string) *corev1.NodeSelectorTerm {
+		return &corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{
+			{Key: "topology.kubernetes.io/zone", Operator: corev1.NodeSelectorOpIn, Values: []string{zone}},
+		}}
+	}
+	taint := corev1.Taint{Key: "dedicated", Value: "batch", Effect: corev1.TaintEffectNoSchedule}
+	nodes := []client.Object{
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a", Labels: map[string]string{"topology.kubernetes.io/zone": "a"}}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-b", Labels: map[string]string{"topology.kubernetes.io/zone": "b"}}},
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-tainted", Labels: map[string]string{"topology.kubernetes.io/zone": "a"}},
+			Spec:       corev1.NodeSpec{Taints: []corev1.Taint{taint}},
+		},
+	}
+	ws := &appsv1alpha1.WorkloadSpread{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"},
+		Spec: appsv1alpha1.WorkloadSpreadSpec{
+			Subsets: []appsv1alpha1.WorkloadSpreadSubset{
+				{Name: "subset-a", RequiredNodeSelectorTerm: zoneTerm("a")},
+				{Name: "subset-b", RequiredNodeSelectorTerm: zoneTerm("b")},
+				{Name: "subset-batch", RequiredNodeSelectorTerm: zoneTerm("a"), Tolerations: []corev1.Toleration{
+					{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "batch", Effect: corev1.TaintEffectNoSchedule},
+				}},
+			},
+		},
+	}
+	subsetStatuses := []appsv1alpha1.WorkloadSpreadSubsetStatus{
+		{Name: "subset-a"}, {Name: "subset-b"}, {Name: "subset-batch"},
+	}
+
+	cases := []struct {
+		name          string
+		nodeName      string
+		expectSubsets []string
+	}{
+		{
+			name:          "node matches subsets by node selector",
+			nodeName:      "node-a",
+			expectSubsets: []string{"subset-a", "subset-batch"},
+		},
+		{
+			name:          "node matches one subset",
+			nodeName:      "node-b",
+			expectSubsets: []string{"subset-b"},
+		},
+		{
+			name:          "tainted node matches subset which tolerates it",
+			nodeName:      "node-tainted",
+			expectSubsets: []string{"subset-batch"},
+		},
+		{
+			name:     "node not found",
+			nodeName: "node-x",
+		},
+	}
+
+	h := Handler{fake.NewClientBuilder().WithScheme(scheme).WithObjects(nodes...).Build()}
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			statuses, err := h.getSubsetStatusesForNode(ws, &corev1.Pod{}, subsetStatuses, cs.nodeName)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var subsets []string
+			for _, status := range statuses {
+				subsets = append(subsets, status.Name)
+			}
+			if !reflect.DeepEqual(subsets, cs.expectSubsets) {
+				t.Fatalf("expect subsets %v, but got %v", cs.expectSubsets, subsets)
+			}
+		})
+	}
+}
@@ -40,6 +40,14 @@ func init() {
 		return true
 	})
 
+	addHTTPHandlersWithGate(mutating.HTTPHandlerGetterMap, func() (enabled bool) {
+		if !utilfeature.DefaultFeatureGate.Enabled(features.PodWebhook) ||
+			!utilfeature.DefaultFeatureGate.Enabled(features.SidecarSetInjectionPreview) {
+			return false
+		}
+		return utildiscovery.DiscoverObject(&appsv1alpha1.SidecarSet{})
+	})
+
 	addHandlersWithGate(validating.HandlerGetterMap, func() (enabled bool) {
 		if !utilfeature.DefaultFeatureGate.Enabled(features.PodWebhook) {
 			return false
@@ -17,9 +17,16 @@ limitations under the License.
 package webhook
 
 import (
+	"github.com/openkruise/kruise/pkg/features"
+	utilfeature "github.com/openkruise/kruise/pkg/util/feature"
+	"github.com/openkruise/kruise/pkg/webhook/workloadspread/simulation"
 	"github.com/openkruise/kruise/pkg/webhook/workloadspread/validating"
 )
 
 func init() {
 	addHandlers(validating.HandlerGetterMap)
+	addHTTPHandlersWithGate(simulation.HTTPHandlerGetterMap, func() (enabled bool) {
+		return utilfeature.DefaultFeatureGate.Enabled(features.WorkloadSpread) &&
+			utilfeature.DefaultFeatureGate.Enabled(features.WorkloadSpreadSimulation)
+	})
 }
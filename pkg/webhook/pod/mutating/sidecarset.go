@@ -111,6 +111,11 @@ func (h *PodCreateHandler) sidecarsetMutatingPod(ctx context.Context, req admiss
 	if len(matchedSidecarSets) == 0 {
 		return true, nil
 	}
+	// resolve the conflicts between the matched sidecarSets by priority
+	matchedSidecarSets, err = h.resolveSidecarSetConflicts(pod, matchedSidecarSets, isUpdated)
+	if err != nil {
+		return false, err
+	}
 
 	// check pod
 	if isUpdated {
@@ -155,7 +160,7 @@ func (h *PodCreateHandler) sidecarsetMutatingPod(ctx context.Context, req admiss
 		"namespace", pod.Namespace, "name", pod.Name)
 	klog.V(4).InfoS("before mutating", "func", "sidecar inject", "pod", klog.KObj(pod))
 	// apply sidecar set info into pod
-	// 1. inject init containers, sort by their name, after the original init containers
+	// 1. inject init containers, sort by their name, after the original init containers unless podInjectPolicy is BeforeAppContainer
 	sort.SliceStable(sidecarInitContainers, func(i, j int) bool {
 		return sidecarInitContainers[i].Name < sidecarInitContainers[j].Name
 	})
@@ -179,6 +184,68 @@ func (h *PodCreateHandler) sidecarsetMutatingPod(ctx context.Context, req admiss
 	return false, nil
 }
 
+// resolveSidecarSetConflicts returns the sidecarSets to inject after the conflicts are resolved by priority.
+// The conflicts are recorded in pod annotations, and the pod creation is rejected when conflicts between sidecarSets
+// with the same priority are found. When the pod is updated, the sidecarSets skipped during creation are still skipped.
+func (h *PodCreateHandler) resolveSidecarSetConflicts(pod *corev1.Pod, matchedSidecarSets []sidecarcontrol.SidecarControl, isUpdated bool) ([]sidecarcontrol.SidecarControl, error) {
+	if isUpdated {
+		conflictsInPod := sidecarcontrol.GetPodSidecarSetConflicts(pod)
+		if len(conflictsInPod) == 0 {
+			return matchedSidecarSets, nil
+		}
+		injectedNames := sets.NewString(strings.Split(pod.Annotations[sidecarcontrol.SidecarSetListAnnotation], ",")...)
+		var injected []sidecarcontrol.SidecarControl
+		for _, control := range matchedSidecarSets {
+			name := control.GetSidecarset().Name
+			if _, ok := conflictsInPod[name]; ok && !injectedNames.Has(name) {
+				continue
+			}
+			injected = append(injected, control)
+		}
+		return injected, nil
+	}
+
+	injected, conflicts := sidecarcontrol.ResolveSidecarSetConflicts(matchedSidecarSets)
+	if len(conflicts) == 0 {
+		return injected, nil
+	}
+	var rejected []string
+	for _, conflict := range conflicts {
+		klog.InfoS("SidecarSets conflicted in pod", "namespace", pod.Namespace, "podName", pod.Name,
+			"sidecarSet", conflict.SidecarSet, "conflict", conflict.String(), "result", conflict.Result)
+		h.recordSidecarSetConflict(matchedSidecarSets, conflict, pod)
+		if conflict.Result == sidecarcontrol.SidecarSetConflictRejected {
+			rejected = append(rejected, conflict.String())
+		}
+	}
+	if len(rejected) > 0 {
+		return nil, fmt.Errorf("pod(%s/%s) sidecarSets conflict with the same priority: %s", pod.Namespace, pod.Name, strings.Join(rejected, "; "))
+	}
+	if pod.Annotations == nil {
+		pod.Annotations = make(map[string]string)
+	}
+	pod.Annotations[sidecarcontrol.SidecarSetInjectionConflictAnnotation] = sidecarcontrol.EncodeSidecarSetConflicts(conflicts)
+	return injected, nil
+}
+
+// recordSidecarSetConflict records the conflict in the events of both sidecarSets.
+func (h *PodCreateHandler) recordSidecarSetConflict(matchedSidecarSets []sidecarcontrol.SidecarControl, conflict sidecarcontrol.SidecarSetConflict, pod *corev1.Pod) {
+	if h.Recorder == nil {
+		return
+	}
+	podName := pod.Name
+	if podName == "" {
+		podName = pod.GenerateName
+	}
+	for _, control := range matchedSidecarSets {
+		sidecarSet := control.GetSidecarset()
+		if sidecarSet.Name == conflict.SidecarSet || sidecarSet.Name == conflict.With {
+			h.Recorder.Eventf(sidecarSet, corev1.EventTypeWarning, "InjectionConflict", "SidecarSets conflict in pod %s/%s(%s): %s",
+				pod.Namespace, podName, conflict.Result, conflict.String())
+		}
+	}
+}
+
 func (h *PodCreateHandler) getSuitableRevisionSidecarSet(sidecarSet *appsv1alpha1.SidecarSet, oldPod, newPod *corev1.Pod, operation admissionv1.Operation) (*appsv1alpha1.SidecarSet, error) {
 	switch operation {
 	case admissionv1.Update:
@@ -424,6 +491,11 @@ func buildSidecars(isUpdated bool, pod *corev1.Pod, oldPod *corev1.Pod, matchedS
 		if !isUpdated {
 			for i := range sidecarSet.Spec.InitContainers {
 				initContainer := &sidecarSet.Spec.InitContainers[i]
+				// render the pod metadata templates in initContainer and the volumes
+				containerVolumesMap, err := renderSidecarTemplates(initContainer, volumesMap, pod)
+				if err != nil {
+					return nil, nil, nil, nil, nil, err
+				}
 				// only insert k8s native sidecar container for in-place update
 				if sidecarcontrol.IsSidecarContainer(initContainer.Container) {
 					sidecarList.Insert(initContainer.Name)
@@ -437,14 +509,14 @@ func buildSidecars(isUpdated bool, pod *corev1.Pod, oldPod *corev1.Pod, matchedS
 				transferEnvs = util.MergeEnvVar(transferEnvs, injectedEnvs)
 				// insert volumes that initContainers used
 				for _, mount := range initContainer.VolumeMounts {
-					if vol, ok := volumesMap[mount.Name]; ok {
+					if vol, ok := containerVolumesMap[mount.Name]; ok {
 						volumesInSidecars = append(volumesInSidecars, *vol)
 					} else {
 						klog.Warningf("InitContainer volumeMount %s cannot be found in volumes of sidecarSet %s", mount.Name, sidecarSet.Name)
 					}
 				}
 				for _, mount := range initContainer.VolumeDevices {
-					if vol, ok := volumesMap[mount.Name]; ok {
+					if vol, ok := containerVolumesMap[mount.Name]; ok {
 						volumesInSidecars = append(volumesInSidecars, *vol)
 					} else {
 						klog.Warningf("InitContainer volumeDevice %s cannot be found in volumes of sidecarSet %s", mount.Name, sidecarSet.Name)
@@ -461,6 +533,10 @@ func buildSidecars(isUpdated bool, pod *corev1.Pod, oldPod *corev1.Pod, matchedS
 				// merge volumeDevice
 				injectedDevices := sidecarcontrol.GetInjectedVolumeDevices(initContainer, pod)
 				initContainer.VolumeDevices = util.MergeVolumeDevices(initContainer.Container, injectedDevices)
+				// calculate resources from the containers in pod
+				if initContainer.ResourcePolicy != nil {
+					initContainer.Resources = sidecarcontrol.GetSidecarResources(initContainer, pod)
+				}
 				klog.InfoS("try to inject initContainer sidecar",
 					"containerName", initContainer.Name, "namespace", pod.Namespace, "podName", pod.Name, "envs", transferEnvs, "volumeMounts", injectedMounts, "volumeDevices", injectedDevices)
 				// when sidecar container UpgradeStrategy is HotUpgrade
@@ -507,16 +583,21 @@ func buildSidecars(isUpdated bool, pod *corev1.Pod, oldPod *corev1.Pod, matchedS
 					"containerName", sidecarContainer.Name, "namespace", pod.Namespace, "podName", pod.Name)
 			}
 			isInjecting = true
+			// render the pod metadata templates in sidecar container and the volumes
+			containerVolumesMap, err := renderSidecarTemplates(sidecarContainer, volumesMap, pod)
+			if err != nil {
+				return nil, nil, nil, nil, nil, err
+			}
 			// insert volume that sidecar container used
 			for _, mount := range sidecarContainer.VolumeMounts {
-				if vol, ok := volumesMap[mount.Name]; ok {
+				if vol, ok := containerVolumesMap[mount.Name]; ok {
 					volumesInSidecars = append(volumesInSidecars, *vol)
 				} else {
 					klog.Warningf("Container volumeMount %s cannot be found in volumes of sidecarSet %s", mount.Name, sidecarSet.Name)
 				}
 			}
 			for _, mount := range sidecarContainer.VolumeDevices {
-				if vol, ok := volumesMap[mount.Name]; ok {
+				if vol, ok := containerVolumesMap[mount.Name]; ok {
 					volumesInSidecars = append(volumesInSidecars, *vol)
 				} else {
 					klog.Warningf("Container volumeDevice %s cannot be found in volumes of sidecarSet %s", mount.Name, sidecarSet.Name)
@@ -532,6 +613,10 @@ func buildSidecars(isUpdated bool, pod *corev1.Pod, oldPod *corev1.Pod, matchedS
 			// merge volumeDevice
 			injectedDevices := sidecarcontrol.GetInjectedVolumeDevices(sidecarContainer, pod)
 			sidecarContainer.VolumeDevices = util.MergeVolumeDevices(sidecarContainer.Container, injectedDevices)
+			// calculate resources from the containers in pod
+			if sidecarContainer.ResourcePolicy != nil {
+				sidecarContainer.Resources = sidecarcontrol.GetSidecarResources(sidecarContainer, pod)
+			}
 			klog.InfoS("try to inject Container sidecar",
 				"containerName", sidecarContainer.Name, "namespace", pod.Namespace, "podName", pod.Name, "envs", transferEnvs, "volumeMounts", injectedMounts, "volumeDevices", injectedDevices)
 			// when sidecar container UpgradeStrategy is HotUpgrade
@@ -565,6 +650,26 @@ func buildSidecars(isUpdated bool, pod *corev1.Pod, oldPod *corev1.Pod, matchedS
 	return sidecarContainers, sidecarInitContainers, sidecarSecrets, volumesInSidecars, injectedAnnotations, nil
 }
 
+// renderSidecarTemplates renders the pod metadata templates in the sidecar container if enabled,
+// and returns the volumes map whose volume sources are rendered for it.
+func renderSidecarTemplates(sidecarContainer *appsv1alpha1.SidecarContainer, volumesMap map[string]*corev1.Volume, pod *corev1.Pod) (map[string]*corev1.Volume, error) {
+	if !sidecarContainer.PodMetadataTemplate {
+		return volumesMap, nil
+	}
+	if err := sidecarcontrol.RenderSidecarContainerTemplates(sidecarContainer, pod); err != nil {
+		return nil, fmt.Errorf("sidecar container %s: %v", sidecarContainer.Name, err)
+	}
+	renderedVolumesMap := make(map[string]*corev1.Volume, len(volumesMap))
+	for name, volume := range volumesMap {
+		rendered, err := sidecarcontrol.RenderVolumeTemplates(volume, pod)
+		if err != nil {
+			return nil, fmt.Errorf("volume %s of sidecar container %s: %v", name, sidecarContainer.Name, err)
+		}
+		renderedVolumesMap[name] = rendered
+	}
+	return renderedVolumesMap, nil
+}
+
 func getVolumesMapInSidecarSet(sidecarSet *appsv1alpha1.SidecarSet) map[string]*corev1.Volume {
 	volumesMap := make(map[string]*corev1.Volume)
 	for idx, volume := range sidecarSet.Spec.Volumes {
@@ -0,0 +1,366 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mutating
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"gomodules.xyz/jsonpatch/v2"
+	admissionv1 "k8s.io/api/admission/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+	extclient "github.com/openkruise/kruise/pkg/client"
+	"github.com/openkruise/kruise/pkg/control/sidecarcontrol"
+	"github.com/openkruise/kruise/pkg/util"
+	utilclient "github.com/openkruise/kruise/pkg/util/client"
+	"github.com/openkruise/kruise/pkg/util/fieldindex"
+	"github.com/openkruise/kruise/pkg/webhook/types"
+	"github.com/openkruise/kruise/pkg/webhook/util/authorizer"
+)
+
+const (
+	// SidecarSetInjectionPreviewPath is the path of endpoint to preview the SidecarSet injection of a pod.
+	SidecarSetInjectionPreviewPath = "/sidecarset-injection-preview"
+	// SidecarSetPreviewVerb is the verb on sidecarsets that the requester must be allowed to preview the injection.
+	SidecarSetPreviewVerb = "preview"
+	// maxPreviewRequestBytes is the max size of the pod in request body to preview.
+	maxPreviewRequestBytes = 3 * 1024 * 1024
+)
+
+// Reasons why a SidecarSet is not injected into the pod in the preview.
+const (
+	PreviewReasonPodInactive                 = "PodInactive"
+	PreviewReasonInjectionPaused             = "InjectionPaused"
+	PreviewReasonNamespaceNotMatched         = "NamespaceNotMatched"
+	PreviewReasonNamespaceSelectorNotMatched = "NamespaceSelectorNotMatched"
+	PreviewReasonSelectorNotMatched          = "SelectorNotMatched"
+	PreviewReasonSidecarSetInactive          = "SidecarSetInactive"
+	PreviewReasonConflictSkipped             = "ConflictSkipped"
+	PreviewReasonConflictRejected            = "ConflictRejected"
+)
+
+var (
+	// HTTPHandlerGetterMap contains the non-admission handlers on webhook server
+	HTTPHandlerGetterMap = map[string]types.HTTPHandlerGetter{
+		SidecarSetInjectionPreviewPath: func(mgr manager.Manager) http.Handler {
+			return &SidecarSetInjectionPreviewHandler{
+				Client:     mgr.GetClient(),
+				Decoder:    admission.NewDecoder(mgr.GetScheme()),
+				Authorizer: authorizer.New(extclient.GetGenericClientWithName("sidecarset-preview").KubeClient),
+			}
+		},
+	}
+)
+
+// SidecarSetInjectionPreview is the result of previewing the SidecarSet injection of a pod.
+type SidecarSetInjectionPreview struct {
+	// SidecarSets contains the preview result of each SidecarSet that may be injected into the pod
+	SidecarSets []SidecarSetPreview `json:"sidecarSets"`
+	// Containers are the sidecar containers that would be injected into the pod
+	Containers []SidecarContainerPreview `json:"containers,omitempty"`
+	// InitContainers are the sidecar initContainers that would be injected into the pod
+	InitContainers []SidecarContainerPreview `json:"initContainers,omitempty"`
+	// Volumes are the names of volumes that would be injected into the pod
+	Volumes []string `json:"volumes,omitempty"`
+	// Patches are the json patches that the pod mutating webhook would apply to the pod
+	Patches []jsonpatch.JsonPatchOperation `json:"patches,omitempty"`
+	// Message is the error message when the pod would be rejected
+	Message string `json:"message,omitempty"`
+}
+
+// SidecarSetPreview is the preview result of a SidecarSet.
+type SidecarSetPreview struct {
+	Name string `json:"name"`
+	// Injected indicates whether the SidecarSet would be injected into the pod
+	Injected bool `json:"injected"`
+	// Revision is the hash of the SidecarSet revision that would be injected
+	Revision string `json:"revision,omitempty"`
+	// ControllerRevision is the name of the SidecarSet controllerRevision that would be injected
+	ControllerRevision string `json:"controllerRevision,omitempty"`
+	// Reason is the reason why the SidecarSet would not be injected
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// SidecarContainerPreview is the preview of a sidecar container injected into the pod.
+type SidecarContainerPreview struct {
+	Name       string `json:"name"`
+	SidecarSet string `json:"sidecarSet,omitempty"`
+	// PodInjectPolicy is the position the container would be injected in, or ReplaceExisting
+	// if a container with the same name exists in the pod
+	PodInjectPolicy string `json:"podInjectPolicy,omitempty"`
+}
+
+// SidecarSetInjectionPreviewHandler previews which SidecarSets would be injected into a pod and the resulting diff,
+// by running the injection of pod mutating webhook in dry-run mode.
+// The requester must be allowed to preview sidecarsets, e.g. with the rule:
+// {apiGroups: ["apps.kruise.io"], resources: ["sidecarsets"], verbs: ["preview"]}.
+type SidecarSetInjectionPreviewHandler struct {
+	Client     client.Client
+	Decoder    admission.Decoder
+	Authorizer authorizer.Authorizer
+}
+
+var _ http.Handler = &SidecarSetInjectionPreviewHandler{}
+
+func (h *SidecarSetInjectionPreviewHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST method is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if code, err := h.Authorizer.Authorize(r.Context(), r, authorizationv1.ResourceAttributes{
+		Group:    appsv1alpha1.GroupVersion.Group,
+		Resource: "sidecarsets",
+		Verb:     SidecarSetPreviewVerb,
+	}); err != nil {
+		http.Error(w, err.Error(), code)
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPreviewRequestBytes))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	pod := &corev1.Pod{}
+	if err = json.Unmarshal(body, pod); err != nil {
+		http.Error(w, fmt.Sprintf("failed to decode pod: %v", err), http.StatusBadRequest)
+		return
+	}
+	if pod.Namespace == "" {
+		pod.Namespace = r.URL.Query().Get("namespace")
+	}
+
+	preview, err := h.preview(r.Context(), pod)
+	if err != nil {
+		klog.ErrorS(err, "Failed to preview SidecarSet injection", "pod", klog.KObj(pod))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(preview)
+}
+
+func (h *SidecarSetInjectionPreviewHandler) preview(ctx context.Context, pod *corev1.Pod) (*SidecarSetInjectionPreview, error) {
+	preview := &SidecarSetInjectionPreview{SidecarSets: []SidecarSetPreview{}}
+	podNamespace := pod.Namespace
+	if podNamespace == "" {
+		podNamespace = "default"
+	}
+	sidecarSetList := &appsv1alpha1.SidecarSetList{}
+	sidecarSetList2 := &appsv1alpha1.SidecarSetList{}
+	if err := h.Client.List(ctx, sidecarSetList, client.MatchingFields{fieldindex.IndexNameForSidecarSetNamespace: podNamespace}, utilclient.DisableDeepCopy); err != nil {
+		return nil, err
+	}
+	if err := h.Client.List(ctx, sidecarSetList2, client.MatchingFields{fieldindex.IndexNameForSidecarSetNamespace: fieldindex.IndexValueSidecarSetClusterScope}, utilclient.DisableDeepCopy); err != nil {
+		return nil, err
+	}
+
+	podActive := sidecarcontrol.IsActivePod(pod)
+	var matched []sidecarcontrol.SidecarControl
+	for _, sidecarSet := range append(sidecarSetList.Items, sidecarSetList2.Items...) {
+		item := SidecarSetPreview{Name: sidecarSet.Name}
+		reason, message, err := h.getSkippedReason(pod, podNamespace, &sidecarSet)
+		if err != nil {
+			return nil, err
+		}
+		if reason == "" && !podActive {
+			reason, message = PreviewReasonPodInactive, "pod is not active"
+		}
+		if reason == "" {
+			podHandler := &PodCreateHandler{Client: h.Client, Decoder: h.Decoder}
+			suitableSidecarSet, err := podHandler.getSuitableRevisionSidecarSet(&sidecarSet, nil, pod, admissionv1.Create)
+			if err != nil {
+				return nil, err
+			}
+			control := sidecarcontrol.New(suitableSidecarSet)
+			if control.IsActiveSidecarSet() {
+				matched = append(matched, control)
+			} else {
+				reason, message = PreviewReasonSidecarSetInactive, "sidecarSet is not active"
+			}
+			item.Revision = sidecarcontrol.GetSidecarSetRevision(suitableSidecarSet)
+			item.ControllerRevision = suitableSidecarSet.Status.LatestRevision
+		}
+		item.Reason, item.Message = reason, message
+		preview.SidecarSets = append(preview.SidecarSets, item)
+	}
+	if len(matched) == 0 {
+		return preview, nil
+	}
+
+	// resolve the conflicts in the same way as the pod mutating webhook
+	_, conflicts := sidecarcontrol.ResolveSidecarSetConflicts(matched)
+	rejected := false
+	for _, conflict := range conflicts {
+		for i := range preview.SidecarSets {
+			item := &preview.SidecarSets[i]
+			if item.Name != conflict.SidecarSet || conflict.Result == sidecarcontrol.SidecarSetConflictIgnored {
+				continue
+			}
+			if conflict.Result == sidecarcontrol.SidecarSetConflictRejected {
+				rejected = true
+				item.Reason = PreviewReasonConflictRejected
+			} else if item.Reason == "" {
+				item.Reason = PreviewReasonConflictSkipped
+			}
+			item.Message = conflict.String()
+		}
+	}
+
+	// run the injection in dry-run mode, events are not recorded without recorder
+	mutated := pod.DeepCopy()
+	podHandler := &PodCreateHandler{Client: h.Client, Decoder: h.Decoder}
+	req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		Resource:  metav1.GroupVersionResource{Group: corev1.SchemeGroupVersion.Group, Version: corev1.SchemeGroupVersion.Version, Resource: "pods"},
+		Operation: admissionv1.Create,
+		Namespace: pod.Namespace,
+		Name:      pod.Name,
+		DryRun:    ptr.To(true),
+	}}
+	if _, err := podHandler.sidecarsetMutatingPod(ctx, req, mutated); err != nil {
+		if !rejected {
+			return nil, err
+		}
+		preview.Message = err.Error()
+		return preview, nil
+	}
+
+	injectedSidecarSets := sets.NewString(strings.Split(mutated.Annotations[sidecarcontrol.SidecarSetListAnnotation], ",")...)
+	for i := range preview.SidecarSets {
+		item := &preview.SidecarSets[i]
+		if item.Reason != "" || !injectedSidecarSets.Has(item.Name) {
+			continue
+		}
+		item.Injected = true
+		if revision := sidecarcontrol.GetPodSidecarSetRevision(item.Name, mutated); revision != "" {
+			item.Revision = revision
+			item.ControllerRevision = sidecarcontrol.GetPodSidecarSetControllerRevision(item.Name, mutated)
+		}
+	}
+	preview.Containers = diffSidecarContainers(pod.Spec.Containers, mutated.Spec.Containers, matched, false)
+	preview.InitContainers = diffSidecarContainers(pod.Spec.InitContainers, mutated.Spec.InitContainers, matched, true)
+	volumesInPod := make(map[string]corev1.Volume, len(pod.Spec.Volumes))
+	for _, volume := range pod.Spec.Volumes {
+		volumesInPod[volume.Name] = volume
+	}
+	for _, volume := range mutated.Spec.Volumes {
+		if origin, ok := volumesInPod[volume.Name]; !ok || !reflect.DeepEqual(origin, volume) {
+			preview.Volumes = append(preview.Volumes, volume.Name)
+		}
+	}
+
+	original, err := json.Marshal(pod)
+	if err != nil {
+		return nil, err
+	}
+	current, err := json.Marshal(mutated)
+	if err != nil {
+		return nil, err
+	}
+	preview.Patches = admission.PatchResponseFromRaw(original, current).Patches
+	return preview, nil
+}
+
+// getSkippedReason returns the reason why the sidecarSet doesn't match the pod, it follows sidecarcontrol.PodMatchedSidecarSet.
+func (h *SidecarSetInjectionPreviewHandler) getSkippedReason(pod *corev1.Pod, podNamespace string, sidecarSet *appsv1alpha1.SidecarSet) (string, string, error) {
+	if sidecarSet.Spec.InjectionStrategy.Paused {
+		return PreviewReasonInjectionPaused, "injectionStrategy of sidecarSet is paused", nil
+	}
+	if sidecarSet.Spec.Namespace != "" && sidecarSet.Spec.Namespace != podNamespace {
+		return PreviewReasonNamespaceNotMatched, fmt.Sprintf("sidecarSet only matches pods in namespace %s", sidecarSet.Spec.Namespace), nil
+	}
+	if sidecarSet.Spec.NamespaceSelector != nil &&
+		!sidecarcontrol.IsSelectorNamespace(h.Client, podNamespace, sidecarSet.Spec.NamespaceSelector) {
+		return PreviewReasonNamespaceSelectorNotMatched, fmt.Sprintf("namespace %s doesn't match the namespaceSelector", podNamespace), nil
+	}
+	selector, err := util.ValidatedLabelSelectorAsSelector(sidecarSet.Spec.Selector)
+	if err != nil {
+		return "", "", err
+	}
+	if selector.Empty() || !selector.Matches(labels.Set(pod.Labels)) {
+		return PreviewReasonSelectorNotMatched, "pod labels don't match the selector", nil
+	}
+	return "", "", nil
+}
+
+// diffSidecarContainers returns the containers that are added or replaced by the sidecarSets.
+func diffSidecarContainers(origins, mutated []corev1.Container, matched []sidecarcontrol.SidecarControl, isInitContainer bool) []SidecarContainerPreview {
+	containersInPod := make(map[string]*corev1.Container, len(origins))
+	for i := range origins {
+		containersInPod[origins[i].Name] = &origins[i]
+	}
+	// container name -> sidecarSet name and podInjectPolicy
+	sidecarContainers := make(map[string]SidecarContainerPreview)
+	for _, control := range matched {
+		sidecarSet := control.GetSidecarset()
+		containers := sidecarSet.Spec.Containers
+		if isInitContainer {
+			containers = sidecarSet.Spec.InitContainers
+		}
+		for _, sidecar := range containers {
+			policy := string(sidecar.PodInjectPolicy)
+			if policy == "" {
+				policy = string(appsv1alpha1.AfterAppContainerType)
+			}
+			sidecarContainers[sidecar.Name] = SidecarContainerPreview{SidecarSet: sidecarSet.Name, PodInjectPolicy: policy}
+			// the hot upgrade containers are named with suffix -1 and -2
+			if sidecarcontrol.IsHotUpgradeContainer(&sidecar) {
+				name1, name2 := sidecarcontrol.GetHotUpgradeContainerName(sidecar.Name)
+				sidecarContainers[name1] = sidecarContainers[sidecar.Name]
+				sidecarContainers[name2] = sidecarContainers[sidecar.Name]
+			}
+		}
+	}
+
+	var diff []SidecarContainerPreview
+	for i := range mutated {
+		container := &mutated[i]
+		origin, exists := containersInPod[container.Name]
+		if exists && reflect.DeepEqual(origin, container) {
+			continue
+		}
+		item := sidecarContainers[container.Name]
+		item.Name = container.Name
+		if exists {
+			item.PodInjectPolicy = "ReplaceExisting"
+		}
+		diff = append(diff, item)
+	}
+	return diff
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mutating
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+	"github.com/openkruise/kruise/pkg/util/fieldindex"
+)
+
+func TestSidecarSetInjectionPreview(t *testing.T) {
+	sidecarSetIn := sidecarSet1.DeepCopy()
+	pausedSidecarSet := sidecarSet1.DeepCopy()
+	pausedSidecarSet.Name = "sidecarset-paused"
+	pausedSidecarSet.Spec.InjectionStrategy.Paused = true
+	otherNsSidecarSet := sidecarSet1.DeepCopy()
+	otherNsSidecarSet.Name = "sidecarset-other-ns"
+	otherNsSidecarSet.Spec.Namespace = "other"
+	notMatchedSidecarSet := sidecarSet1.DeepCopy()
+	notMatchedSidecarSet.Name = "sidecarset-not-matched"
+	notMatchedSidecarSet.Spec.Selector.MatchLabels = map[string]string{"app": "doesnt-match"}
+
+	c := fake.NewClientBuilder().WithObjects(sidecarSetIn, pausedSidecarSet, otherNsSidecarSet, notMatchedSidecarSet).WithIndex(
+		&appsv1alpha1.SidecarSet{}, fieldindex.IndexNameForSidecarSetNamespace, fieldindex.IndexSidecarSet,
+	).Build()
+	authz := &fakeAuthorizer{code: http.StatusOK}
+	handler := &SidecarSetInjectionPreviewHandler{Client: c, Decoder: admission.NewDecoder(scheme.Scheme), Authorizer: authz}
+
+	podIn := pod1.DeepCopy()
+	body, _ := json.Marshal(podIn)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, SidecarSetInjectionPreviewPath, bytes.NewReader(body)))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expect status 200, but got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	preview := &SidecarSetInjectionPreview{}
+	if err := json.Unmarshal(recorder.Body.Bytes(), preview); err != nil {
+		t.Fatalf("failed to decode preview: %s", err.Error())
+	}
+
+	reasons := map[string]string{}
+	for _, item := range preview.SidecarSets {
+		reasons[item.Name] = item.Reason
+		if item.Name == sidecarSetIn.Name && (!item.Injected || item.Revision != "c4k2dbb95d") {
+			t.Fatalf("expect sidecarSet %s injected with revision c4k2dbb95d, but got %+v", item.Name, item)
+		}
+	}
+	// sidecarSet in other namespace is filtered by the namespace index
+	expectReasons := map[string]string{
+		sidecarSetIn.Name:         "",
+		pausedSidecarSet.Name:     PreviewReasonInjectionPaused,
+		notMatchedSidecarSet.Name: PreviewReasonSelectorNotMatched,
+	}
+	if !reflect.DeepEqual(reasons, expectReasons) {
+		t.Fatalf("expect reasons %v, but got %v", expectReasons, reasons)
+	}
+	expectContainers := []SidecarContainerPreview{
+		{Name: "dns-f", SidecarSet: sidecarSetIn.Name, PodInjectPolicy: string(appsv1alpha1.BeforeAppContainerType)},
+		{Name: "log-agent", SidecarSet: sidecarSetIn.Name, PodInjectPolicy: string(appsv1alpha1.AfterAppContainerType)},
+	}
+	if !reflect.DeepEqual(preview.Containers, expectContainers) {
+		t.Fatalf("expect containers %+v, but got %+v", expectContainers, preview.Containers)
+	}
+	if len(preview.InitContainers) != 2 || len(preview.Patches) == 0 {
+		t.Fatalf("expect 2 initContainers and patches, but got %+v", preview)
+	}
+
+	// the mutating of pod doesn't change the original pod
+	if !reflect.DeepEqual(podIn, pod1) {
+		t.Fatalf("expect pod unchanged")
+	}
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, SidecarSetInjectionPreviewPath, nil))
+	if recorder.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expect status 405, but got %d", recorder.Code)
+	}
+
+	// the request body is too large
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, SidecarSetInjectionPreviewPath,
+		bytes.NewReader(make([]byte, maxPreviewRequestBytes+1))))
+	if recorder.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expect status 413, but got %d", recorder.Code)
+	}
+
+	// the requester is not allowed to preview sidecarsets
+	authz.code, authz.err = http.StatusForbidden, fmt.Errorf("forbidden")
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, SidecarSetInjectionPreviewPath, bytes.NewReader(body)))
+	if recorder.Code != http.StatusForbidden {
+		t.Fatalf("expect status 403, but got %d", recorder.Code)
+	}
+	if authz.attrs.Resource != "sidecarsets" || authz.attrs.Verb != SidecarSetPreviewVerb {
+		t.Fatalf("expect authorizing preview sidecarsets, but got %+v", authz.attrs)
+	}
+}
+
+type fakeAuthorizer struct {
+	code  int
+	err   error
+	attrs authorizationv1.ResourceAttributes
+}
+
+func (a *fakeAuthorizer) Authorize(_ context.Context, _ *http.Request, attrs authorizationv1.ResourceAttributes) (int, error) {
+	a.attrs = attrs
+	return a.code, a.err
+}
@@ -29,11 +29,15 @@ import (
 	admissionv1 "k8s.io/api/admission/v1"
 	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	intstrutil "k8s.io/apimachinery/pkg/util/intstr"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/envtest"
@@ -1005,6 +1009,60 @@ func testSidecarVolumesAppend(t *testing.T, sidecarSetIn *appsv1alpha1.SidecarSe
 	}
 }
 
+func TestSidecarPodMetadataTemplate(t *testing.T) {
+	sidecarSetIn := sidecarSet1.DeepCopy()
+	sidecarSetIn.Spec.InitContainers = nil
+	sidecarSetIn.Spec.Containers = sidecarSetIn.Spec.Containers[1:]
+	sidecarSetIn.Spec.Containers[0].PodMetadataTemplate = true
+	sidecarSetIn.Spec.Containers[0].Args = []string{"--service={{ metadata.labels['app'] }}", "--version={{ metadata.labels['version'] }}"}
+	sidecarSetIn.Spec.Containers[0].Env = []corev1.EnvVar{{Name: "POD_NAMESPACE", Value: "{{ metadata.namespace }}"}}
+	sidecarSetIn.Spec.Containers[0].VolumeMounts = []corev1.VolumeMount{{Name: "log", MountPath: "/var/log"}}
+	sidecarSetIn.Spec.Volumes = []corev1.Volume{
+		{
+			Name: "log",
+			VolumeSource: corev1.VolumeSource{
+				HostPath: &corev1.HostPathVolumeSource{Path: "/var/log/{{ metadata.namespace }}/{{ metadata.labels['app'] }}"},
+			},
+		},
+	}
+	podIn := pod1.DeepCopy()
+
+	decoder := admission.NewDecoder(scheme.Scheme)
+	c := fake.NewClientBuilder().WithObjects(sidecarSetIn).WithIndex(
+		&appsv1alpha1.SidecarSet{}, fieldindex.IndexNameForSidecarSetNamespace, fieldindex.IndexSidecarSet,
+	).Build()
+	podOut := podIn.DeepCopy()
+	podHandler := &PodCreateHandler{Decoder: decoder, Client: c}
+	req := newAdmission(admissionv1.Create, runtime.RawExtension{}, runtime.RawExtension{}, "")
+	if _, err := podHandler.sidecarsetMutatingPod(context.Background(), req, podOut); err != nil {
+		t.Fatalf("inject sidecar into pod failed, err: %v", err)
+	}
+
+	sidecar := util.GetContainer("log-agent", podOut)
+	if sidecar == nil {
+		t.Fatalf("expect sidecar log-agent injected")
+	}
+	if !reflect.DeepEqual(sidecar.Args, []string{"--service=suxing-test", "--version="}) {
+		t.Fatalf("expect args rendered, but got %v", sidecar.Args)
+	}
+	if util.GetContainerEnvValue(sidecar, "POD_NAMESPACE") != defaultNs {
+		t.Fatalf("expect env rendered, but got %v", sidecar.Env)
+	}
+	var volume *corev1.Volume
+	for i := range podOut.Spec.Volumes {
+		if podOut.Spec.Volumes[i].Name == "log" {
+			volume = &podOut.Spec.Volumes[i]
+		}
+	}
+	if volume == nil || volume.HostPath.Path != fmt.Sprintf("/var/log/%s/%s", defaultNs, podIn.Labels["app"]) {
+		t.Fatalf("expect volume rendered, but got %v", volume)
+	}
+	// the templates in sidecarSet are not changed
+	if sidecarSetIn.Spec.Volumes[0].HostPath.Path != "/var/log/{{ metadata.namespace }}/{{ metadata.labels['app'] }}" {
+		t.Fatalf("expect sidecarSet unchanged")
+	}
+}
+
 func TestPodSidecarSetHashCompatibility(t *testing.T) {
 	podIn := pod1.DeepCopy()
 	podIn.Annotations = map[string]string{}
@@ -1197,6 +1255,115 @@ func testSidecarSetTransferEnv(t *testing.T, sidecarSetIn *appsv1alpha1.SidecarS
 	}
 }
 
+func TestSidecarSetResourcePolicy(t *testing.T) {
+	sidecarSetIn := sidecarSet1.DeepCopy()
+	sidecarSetIn.Spec.Containers[1].Resources = corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("64Mi")},
+	}
+	sidecarSetIn.Spec.Containers[1].ResourcePolicy = &appsv1alpha1.SidecarResourcePolicy{
+		Requests: map[corev1.ResourceName]appsv1alpha1.SidecarResourceRule{
+			corev1.ResourceCPU: {Percent: 10, Min: ptr.To(resource.MustParse("100m"))},
+		},
+		Limits: map[corev1.ResourceName]appsv1alpha1.SidecarResourceRule{
+			corev1.ResourceCPU: {Percent: 50, Max: ptr.To(resource.MustParse("1"))},
+		},
+	}
+	podIn := pod1.DeepCopy()
+	podIn.Spec.Containers[0].Resources = corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+		Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+	}
+
+	decoder := admission.NewDecoder(scheme.Scheme)
+	c := fake.NewClientBuilder().WithObjects(sidecarSetIn).WithIndex(
+		&appsv1alpha1.SidecarSet{}, fieldindex.IndexNameForSidecarSetNamespace, fieldindex.IndexSidecarSet,
+	).Build()
+	podOut := podIn.DeepCopy()
+	podHandler := &PodCreateHandler{Decoder: decoder, Client: c}
+	req := newAdmission(admissionv1.Create, runtime.RawExtension{}, runtime.RawExtension{}, "")
+	_, err := podHandler.sidecarsetMutatingPod(context.Background(), req, podOut)
+	if err != nil {
+		t.Fatalf("inject sidecar into pod failed, err: %v", err)
+	}
+
+	expected := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("200m"), corev1.ResourceMemory: resource.MustParse("64Mi")},
+		Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+	}
+	sidecar := util.GetContainer("log-agent", podOut)
+	if sidecar == nil {
+		t.Fatalf("expect sidecar log-agent injected, but not found")
+	}
+	if !apiequality.Semantic.DeepEqual(sidecar.Resources, expected) {
+		t.Fatalf("expect sidecar resources %v, but got %v", expected, sidecar.Resources)
+	}
+	if dns := util.GetContainer("dns-f", podOut); dns == nil || len(dns.Resources.Requests) != 0 || len(dns.Resources.Limits) != 0 {
+		t.Fatalf("expect sidecar dns-f without resources, but got %v", dns)
+	}
+}
+
+func TestSidecarSetConflictPriority(t *testing.T) {
+	cases := []struct {
+		name           string
+		priority1      *int32
+		priority3      *int32
+		expectErr      bool
+		expectInjected string
+		expectConflict string
+	}{
+		{
+			name:           "conflicts without priority",
+			expectInjected: "sidecarset1,sidecarset3",
+			expectConflict: `{"sidecarset3":["sidecarset1"]}`,
+		},
+		{
+			name:           "conflicts resolved by priority",
+			priority3:      ptr.To[int32](10),
+			expectInjected: "sidecarset3",
+			expectConflict: `{"sidecarset1":["sidecarset3"]}`,
+		},
+		{
+			name:      "conflicts with the same priority",
+			priority1: ptr.To[int32](10),
+			priority3: ptr.To[int32](10),
+			expectErr: true,
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			sidecarSetIn1 := sidecarSet1.DeepCopy()
+			sidecarSetIn1.Spec.Priority = cs.priority1
+			sidecarSetIn3 := sidecarSet3.DeepCopy()
+			sidecarSetIn3.Spec.Priority = cs.priority3
+			decoder := admission.NewDecoder(scheme.Scheme)
+			c := fake.NewClientBuilder().WithObjects(sidecarSetIn1, sidecarSetIn3).WithIndex(
+				&appsv1alpha1.SidecarSet{}, fieldindex.IndexNameForSidecarSetNamespace, fieldindex.IndexSidecarSet,
+			).Build()
+			recorder := record.NewFakeRecorder(10)
+			podOut := pod1.DeepCopy()
+			podHandler := &PodCreateHandler{Decoder: decoder, Client: c, Recorder: recorder}
+			req := newAdmission(admissionv1.Create, runtime.RawExtension{}, runtime.RawExtension{}, "")
+			_, err := podHandler.sidecarsetMutatingPod(context.Background(), req, podOut)
+			if (err != nil) != cs.expectErr {
+				t.Fatalf("expect error(%v), but got %v", cs.expectErr, err)
+			}
+			if len(recorder.Events) == 0 {
+				t.Fatalf("expect conflict events, but got nothing")
+			}
+			if cs.expectErr {
+				return
+			}
+			if podOut.Annotations[sidecarcontrol.SidecarSetListAnnotation] != cs.expectInjected {
+				t.Fatalf("expect injected sidecarSets %s, but got %s", cs.expectInjected, podOut.Annotations[sidecarcontrol.SidecarSetListAnnotation])
+			}
+			if podOut.Annotations[sidecarcontrol.SidecarSetInjectionConflictAnnotation] != cs.expectConflict {
+				t.Fatalf("expect conflicts %s, but got %s", cs.expectConflict, podOut.Annotations[sidecarcontrol.SidecarSetInjectionConflictAnnotation])
+			}
+		})
+	}
+}
+
 func TestSidecarSetHashInject(t *testing.T) {
 	sidecarSetIn1 := sidecarSet1.DeepCopy()
 	testSidecarSetHashInject(t, sidecarSetIn1)
@@ -30,8 +30,9 @@ var (
 	HandlerGetterMap = map[string]types.HandlerGetter{
 		"mutate-pod": func(mgr manager.Manager) admission.Handler {
 			return &PodCreateHandler{
-				Client:  mgr.GetClient(),
-				Decoder: admission.NewDecoder(mgr.GetScheme()),
+				Client:   mgr.GetClient(),
+				Decoder:  admission.NewDecoder(mgr.GetScheme()),
+				Recorder: mgr.GetEventRecorderFor("pod-mutating-webhook"),
 			}
 		},
 	}
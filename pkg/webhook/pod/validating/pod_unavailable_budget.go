@@ -61,8 +61,12 @@ func (p *PodCreateHandler) podUnavailableBudgetValidatingPod(ctx context.Context
 		// check whether could inplace update
 		canResizeInplace := pubcontrol.PubControl.CanResizeInplace(oldPod, newPod)
 
+		pub, err := pubcontrol.PubControl.GetPubForPod(newPod)
+		if err != nil {
+			return false, "", err
+		}
 		// the change will not cause pod unavailability, then pass
-		if !pubcontrol.PubControl.IsPodUnavailableChanged(oldPod, newPod) {
+		if !pubcontrol.PubControl.IsPodUnavailableChanged(oldPod, newPod, pub) {
 			klog.V(6).InfoS("validate pod changed can not cause unavailability, then don't need check pub", "namespace", newPod.Namespace, "name", newPod.Name)
 			return true, "", nil
 		}
@@ -470,6 +470,57 @@ func TestValidateUpdatePodForPub(t *testing.T) {
 				return pubStatus
 			},
 		},
+		{
+			name: "update pod labels to be unavailable by availability policy, reject",
+			oldPod: func() *corev1.Pod {
+				pod := podDemo.DeepCopy()
+				pod.Labels["serving"] = "true"
+				return pod
+			},
+			newPod: func() *corev1.Pod {
+				pod := podDemo.DeepCopy()
+				pod.Labels["serving"] = "false"
+				return pod
+			},
+			pub: func() *policyv1alpha1.PodUnavailableBudget {
+				pub := pubDemo.DeepCopy()
+				pub.Spec.AvailabilityPolicy = &policyv1alpha1.PodUnavailableBudgetAvailabilityPolicy{
+					LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"serving": "true"}},
+				}
+				return pub
+			},
+			expectAllow: false,
+			expectPubStatus: func() *policyv1alpha1.PodUnavailableBudgetStatus {
+				pubStatus := pubDemo.Status.DeepCopy()
+				return pubStatus
+			},
+		},
+		{
+			name: "update pod labels still available by availability policy, allow",
+			oldPod: func() *corev1.Pod {
+				pod := podDemo.DeepCopy()
+				pod.Labels["serving"] = "true"
+				return pod
+			},
+			newPod: func() *corev1.Pod {
+				pod := podDemo.DeepCopy()
+				pod.Labels["serving"] = "true"
+				pod.Labels["version"] = "v2"
+				return pod
+			},
+			pub: func() *policyv1alpha1.PodUnavailableBudget {
+				pub := pubDemo.DeepCopy()
+				pub.Spec.AvailabilityPolicy = &policyv1alpha1.PodUnavailableBudgetAvailabilityPolicy{
+					LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"serving": "true"}},
+				}
+				return pub
+			},
+			expectAllow: true,
+			expectPubStatus: func() *policyv1alpha1.PodUnavailableBudgetStatus {
+				pubStatus := pubDemo.Status.DeepCopy()
+				return pubStatus
+			},
+		},
 	}
 
 	for _, cs := range cases {
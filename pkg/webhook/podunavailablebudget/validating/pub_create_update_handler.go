@@ -23,16 +23,20 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	policyv1alpha1 "github.com/openkruise/kruise/apis/policy/v1alpha1"
 	"github.com/openkruise/kruise/pkg/features"
 	"github.com/openkruise/kruise/pkg/util"
 	utilfeature "github.com/openkruise/kruise/pkg/util/feature"
 
+	"github.com/robfig/cron/v3"
 	admissionv1 "k8s.io/api/admission/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	metavalidation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	appsvalidation "k8s.io/kubernetes/pkg/apis/apps/validation"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -163,6 +167,87 @@ func validatePodUnavailableBudgetSpec(obj *policyv1alpha1.PodUnavailableBudget,
 		allErrs = append(allErrs, appsvalidation.ValidatePositiveIntOrPercent(*spec.MinAvailable, fldPath.Child("minAvailable"))...)
 		allErrs = append(allErrs, appsvalidation.IsNotMoreThan100Percent(*spec.MinAvailable, fldPath.Child("minAvailable"))...)
 	}
+
+	topologyKeys := sets.NewString()
+	for i, budget := range spec.TopologyBudgets {
+		budgetPath := fldPath.Child("topologyBudgets").Index(i)
+		if budget.TopologyKey == "" {
+			allErrs = append(allErrs, field.Required(budgetPath.Child("topologyKey"), "topologyKey is required"))
+		} else if topologyKeys.Has(budget.TopologyKey) {
+			allErrs = append(allErrs, field.Duplicate(budgetPath.Child("topologyKey"), budget.TopologyKey))
+		} else {
+			allErrs = append(allErrs, metavalidation.ValidateLabelName(budget.TopologyKey, budgetPath.Child("topologyKey"))...)
+		}
+		topologyKeys.Insert(budget.TopologyKey)
+		allErrs = append(allErrs, appsvalidation.ValidatePositiveIntOrPercent(budget.MaxUnavailable, budgetPath.Child("maxUnavailable"))...)
+		allErrs = append(allErrs, appsvalidation.IsNotMoreThan100Percent(budget.MaxUnavailable, budgetPath.Child("maxUnavailable"))...)
+	}
+
+	budgetNames := sets.NewString()
+	for i := range spec.ScheduledBudgets {
+		budgetPath := fldPath.Child("scheduledBudgets").Index(i)
+		budget := &spec.ScheduledBudgets[i]
+		if budget.Name == "" {
+			allErrs = append(allErrs, field.Required(budgetPath.Child("name"), "name is required"))
+		} else if budgetNames.Has(budget.Name) {
+			allErrs = append(allErrs, field.Duplicate(budgetPath.Child("name"), budget.Name))
+		}
+		budgetNames.Insert(budget.Name)
+		allErrs = append(allErrs, validateScheduledBudget(budget, budgetPath)...)
+	}
+
+	if spec.AvailabilityPolicy != nil {
+		allErrs = append(allErrs, validateAvailabilityPolicy(spec.AvailabilityPolicy, fldPath.Child("availabilityPolicy"))...)
+	}
+	return allErrs
+}
+
+func validateAvailabilityPolicy(policy *policyv1alpha1.PodUnavailableBudgetAvailabilityPolicy, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(policy.Conditions) == 0 && policy.LabelSelector == nil {
+		allErrs = append(allErrs, field.Required(fldPath, "no conditions or labelSelector defined in availabilityPolicy"))
+	}
+	for i, conditionType := range policy.Conditions {
+		for _, msg := range validation.IsQualifiedName(string(conditionType)) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("conditions").Index(i), conditionType, msg))
+		}
+	}
+	if policy.LabelSelector != nil {
+		allErrs = append(allErrs, metavalidation.ValidateLabelSelector(policy.LabelSelector, metavalidation.LabelSelectorValidationOptions{}, fldPath.Child("labelSelector"))...)
+	}
+	return allErrs
+}
+
+func validateScheduledBudget(budget *policyv1alpha1.PodUnavailableBudgetScheduledBudget, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if _, err := cron.ParseStandard(budget.Schedule); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("schedule"), budget.Schedule, err.Error()))
+	}
+	if budget.TimeZone != nil {
+		if strings.Contains(budget.Schedule, "TZ") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("schedule"), budget.Schedule, "cannot use both timeZone field and TZ or CRON_TZ in schedule"))
+		}
+		if *budget.TimeZone == "" || strings.EqualFold(*budget.TimeZone, "Local") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("timeZone"), *budget.TimeZone, "timeZone must be an explicit time zone as defined in https://www.iana.org/time-zones"))
+		} else if _, err := time.LoadLocation(*budget.TimeZone); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("timeZone"), *budget.TimeZone, err.Error()))
+		}
+	}
+	if budget.Duration.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("duration"), budget.Duration.String(), "duration must be positive"))
+	}
+
+	if budget.MaxUnavailable == nil && budget.MinAvailable == nil {
+		allErrs = append(allErrs, field.Required(fldPath.Child("maxUnavailable, minAvailable"), "no maxUnavailable or minAvailable defined in scheduled budget"))
+	} else if budget.MaxUnavailable != nil && budget.MinAvailable != nil {
+		allErrs = append(allErrs, field.Required(fldPath.Child("maxUnavailable, minAvailable"), "maxUnavailable and minAvailable are mutually exclusive"))
+	} else if budget.MaxUnavailable != nil {
+		allErrs = append(allErrs, appsvalidation.ValidatePositiveIntOrPercent(*budget.MaxUnavailable, fldPath.Child("maxUnavailable"))...)
+		allErrs = append(allErrs, appsvalidation.IsNotMoreThan100Percent(*budget.MaxUnavailable, fldPath.Child("maxUnavailable"))...)
+	} else {
+		allErrs = append(allErrs, appsvalidation.ValidatePositiveIntOrPercent(*budget.MinAvailable, fldPath.Child("minAvailable"))...)
+		allErrs = append(allErrs, appsvalidation.IsNotMoreThan100Percent(*budget.MinAvailable, fldPath.Child("minAvailable"))...)
+	}
 	return allErrs
 }
 
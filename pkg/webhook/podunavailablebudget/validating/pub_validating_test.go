@@ -19,11 +19,14 @@ package validating
 import (
 	"context"
 	"testing"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
@@ -181,6 +184,153 @@ func TestValidatingPub(t *testing.T) {
 			},
 			expectErrList: 0,
 		},
+		{
+			name: "valid pub, TopologyBudgets",
+			pub: func() *policyv1alpha1.PodUnavailableBudget {
+				pub := pubDemo.DeepCopy()
+				pub.Spec.Selector = nil
+				pub.Spec.MinAvailable = nil
+				pub.Spec.TopologyBudgets = []policyv1alpha1.PodUnavailableBudgetTopologyBudget{
+					{TopologyKey: corev1.LabelTopologyZone, MaxUnavailable: intstr.FromInt32(1)},
+					{TopologyKey: corev1.LabelHostname, MaxUnavailable: intstr.FromString("50%")},
+				}
+				return pub
+			},
+			expectErrList: 0,
+		},
+		{
+			name: "invalid pub, TopologyBudgets with empty or duplicate topologyKey",
+			pub: func() *policyv1alpha1.PodUnavailableBudget {
+				pub := pubDemo.DeepCopy()
+				pub.Spec.Selector = nil
+				pub.Spec.MinAvailable = nil
+				pub.Spec.TopologyBudgets = []policyv1alpha1.PodUnavailableBudgetTopologyBudget{
+					{TopologyKey: corev1.LabelTopologyZone, MaxUnavailable: intstr.FromInt32(1)},
+					{TopologyKey: corev1.LabelTopologyZone, MaxUnavailable: intstr.FromInt32(1)},
+					{MaxUnavailable: intstr.FromInt32(1)},
+				}
+				return pub
+			},
+			expectErrList: 2,
+		},
+		{
+			name: "invalid pub, TopologyBudgets with invalid maxUnavailable",
+			pub: func() *policyv1alpha1.PodUnavailableBudget {
+				pub := pubDemo.DeepCopy()
+				pub.Spec.Selector = nil
+				pub.Spec.MinAvailable = nil
+				pub.Spec.TopologyBudgets = []policyv1alpha1.PodUnavailableBudgetTopologyBudget{
+					{TopologyKey: corev1.LabelTopologyZone, MaxUnavailable: intstr.FromString("120%")},
+				}
+				return pub
+			},
+			expectErrList: 1,
+		},
+		{
+			name: "valid pub, ScheduledBudgets",
+			pub: func() *policyv1alpha1.PodUnavailableBudget {
+				pub := pubDemo.DeepCopy()
+				pub.Spec.Selector = nil
+				pub.Spec.MinAvailable = nil
+				pub.Spec.ScheduledBudgets = []policyv1alpha1.PodUnavailableBudgetScheduledBudget{
+					{
+						Name:           "nightly",
+						Schedule:       "0 1 * * *",
+						TimeZone:       ptr.To("Asia/Shanghai"),
+						Duration:       metav1.Duration{Duration: 2 * time.Hour},
+						MaxUnavailable: ptr.To(intstr.FromString("50%")),
+					},
+					{
+						Name:         "weekend",
+						Schedule:     "CRON_TZ=UTC 0 0 * * 6",
+						Duration:     metav1.Duration{Duration: 48 * time.Hour},
+						MinAvailable: ptr.To(intstr.FromInt32(1)),
+					},
+				}
+				return pub
+			},
+			expectErrList: 0,
+		},
+		{
+			name: "invalid pub, ScheduledBudgets with duplicate name, invalid schedule, timeZone and duration",
+			pub: func() *policyv1alpha1.PodUnavailableBudget {
+				pub := pubDemo.DeepCopy()
+				pub.Spec.Selector = nil
+				pub.Spec.MinAvailable = nil
+				pub.Spec.ScheduledBudgets = []policyv1alpha1.PodUnavailableBudgetScheduledBudget{
+					{
+						Name:           "nightly",
+						Schedule:       "0 1 * *",
+						Duration:       metav1.Duration{Duration: 2 * time.Hour},
+						MaxUnavailable: ptr.To(intstr.FromString("50%")),
+					},
+					{
+						Name:           "nightly",
+						Schedule:       "0 1 * * *",
+						TimeZone:       ptr.To("Local"),
+						MaxUnavailable: ptr.To(intstr.FromString("50%")),
+					},
+				}
+				return pub
+			},
+			expectErrList: 4,
+		},
+		{
+			name: "invalid pub, ScheduledBudgets without maxUnavailable and minAvailable",
+			pub: func() *policyv1alpha1.PodUnavailableBudget {
+				pub := pubDemo.DeepCopy()
+				pub.Spec.Selector = nil
+				pub.Spec.MinAvailable = nil
+				pub.Spec.ScheduledBudgets = []policyv1alpha1.PodUnavailableBudgetScheduledBudget{
+					{
+						Name:     "nightly",
+						Schedule: "0 1 * * *",
+						Duration: metav1.Duration{Duration: 2 * time.Hour},
+					},
+				}
+				return pub
+			},
+			expectErrList: 1,
+		},
+		{
+			name: "valid pub, AvailabilityPolicy",
+			pub: func() *policyv1alpha1.PodUnavailableBudget {
+				pub := pubDemo.DeepCopy()
+				pub.Spec.Selector = nil
+				pub.Spec.MinAvailable = nil
+				pub.Spec.AvailabilityPolicy = &policyv1alpha1.PodUnavailableBudgetAvailabilityPolicy{
+					Conditions:    []corev1.PodConditionType{"game.io/idle", corev1.PodReady},
+					LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"serving": "true"}},
+				}
+				return pub
+			},
+			expectErrList: 0,
+		},
+		{
+			name: "invalid pub, empty AvailabilityPolicy",
+			pub: func() *policyv1alpha1.PodUnavailableBudget {
+				pub := pubDemo.DeepCopy()
+				pub.Spec.Selector = nil
+				pub.Spec.MinAvailable = nil
+				pub.Spec.AvailabilityPolicy = &policyv1alpha1.PodUnavailableBudgetAvailabilityPolicy{}
+				return pub
+			},
+			expectErrList: 1,
+		},
+		{
+			name: "invalid pub, AvailabilityPolicy with invalid condition type and labelSelector",
+			pub: func() *policyv1alpha1.PodUnavailableBudget {
+				pub := pubDemo.DeepCopy()
+				pub.Spec.Selector = nil
+				pub.Spec.MinAvailable = nil
+				pub.Spec.AvailabilityPolicy = &policyv1alpha1.PodUnavailableBudgetAvailabilityPolicy{
+					Conditions:    []corev1.PodConditionType{"game.io/idle?"},
+					LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"serving": "true?"}},
+				}
+				return pub
+			},
+			expectErrList: 2,
+		},
 	}
 
 	decoder := admission.NewDecoder(scheme)
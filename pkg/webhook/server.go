@@ -41,6 +41,12 @@ var (
 	// HandlerGetterMap contains all admission webhook handlers.
 	HandlerMap   = HandlerPath2GetterMap{}
 	handlerGates = map[string]GateFunc{}
+
+	// httpHandlerMap contains the non-admission handlers, e.g. debugging endpoints.
+	// They are not protected by the apiserver, so the handlers must authenticate and authorize
+	// the requests by themselves, see pkg/webhook/util/authorizer.
+	httpHandlerMap   = map[string]types.HTTPHandlerGetter{}
+	httpHandlerGates = map[string]GateFunc{}
 )
 
 func addHandlers(m HandlerPath2GetterMap) {
@@ -67,6 +73,18 @@ func addHandlersWithGate(m HandlerPath2GetterMap, fn GateFunc) {
 	}
 }
 
+func addHTTPHandlersWithGate(m map[string]types.HTTPHandlerGetter, fn GateFunc) {
+	for path, handler := range m {
+		if len(path) == 0 || path[0] != '/' {
+			path = "/" + path
+		}
+		httpHandlerMap[path] = handler
+		if fn != nil {
+			httpHandlerGates[path] = fn
+		}
+	}
+}
+
 func filterActiveHandlers() {
 	disablePaths := sets.NewString()
 	for path := range HandlerMap {
@@ -91,6 +109,15 @@ func SetupWithManager(mgr manager.Manager) error {
 		klog.V(3).InfoS("Registered webhook handler", "path", path)
 	}
 
+	// register non-admission handlers
+	for path, handlerGetter := range httpHandlerMap {
+		if fn, ok := httpHandlerGates[path]; ok && !fn() {
+			continue
+		}
+		server.Register(path, handlerGetter(mgr))
+		klog.V(3).InfoS("Registered http handler", "path", path)
+	}
+
 	// register conversion webhook
 	server.Register("/convert", conversion.NewWebhookHandler(mgr.GetScheme()))
 
@@ -103,6 +130,8 @@ func SetupWithManager(mgr manager.Manager) error {
 // +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=mutatingwebhookconfigurations,verbs=get;list;watch;update;patch
 // +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=validatingwebhookconfigurations,verbs=get;list;watch;update;patch
 // +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
+// +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
 
 func Initialize(ctx context.Context, cfg *rest.Config, webhookInitializeTime time.Duration) error {
 	c, err := webhookcontroller.New(cfg, HandlerMap)
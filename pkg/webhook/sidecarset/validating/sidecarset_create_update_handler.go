@@ -28,7 +28,9 @@ import (
 
 	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
 	"github.com/openkruise/kruise/pkg/control/sidecarcontrol"
+	"github.com/openkruise/kruise/pkg/features"
 	"github.com/openkruise/kruise/pkg/util"
+	utilfeature "github.com/openkruise/kruise/pkg/util/feature"
 	webhookutil "github.com/openkruise/kruise/pkg/webhook/util"
 
 	admissionv1 "k8s.io/api/admission/v1"
@@ -117,10 +119,11 @@ func validateSidecarSetName(name string, _ bool) (allErrs []string) {
 func (h *SidecarSetCreateUpdateHandler) validateSidecarSetSpec(obj *appsv1alpha1.SidecarSet, fldPath *field.Path) field.ErrorList {
 	spec := &obj.Spec
 	allErrs := field.ErrorList{}
-	// currently when initContainer restartPolicy = Always, kruise don't support in-place update
+	// when initContainer restartPolicy = Always, kruise only supports in-place update of its image, not hot upgrade
 	for _, c := range obj.Spec.InitContainers {
-		if sidecarcontrol.IsSidecarContainer(c.Container) && obj.Spec.UpdateStrategy.Type == appsv1alpha1.RollingUpdateSidecarSetStrategyType {
-			allErrs = append(allErrs, field.Required(fldPath.Child("updateStrategy"), "The initContainer in-place upgrade is not currently supported."))
+		if sidecarcontrol.IsSidecarContainer(c.Container) && sidecarcontrol.IsHotUpgradeContainer(&c) &&
+			obj.Spec.UpdateStrategy.Type == appsv1alpha1.RollingUpdateSidecarSetStrategyType {
+			allErrs = append(allErrs, field.Required(fldPath.Child("updateStrategy"), "The initContainer in-place hot upgrade is not currently supported."))
 		}
 	}
 
@@ -148,6 +151,7 @@ func (h *SidecarSetCreateUpdateHandler) validateSidecarSetSpec(obj *appsv1alpha1
 		allErrs = append(allErrs, field.Required(fldPath.Root(), "no initContainer or container defined for SidecarSet"))
 	} else {
 		allErrs = append(allErrs, validateContainersForSidecarSet(spec.InitContainers, spec.Containers, vols, fldPath.Root())...)
+		allErrs = append(allErrs, validatePodMetadataTemplates(spec, fldPath)...)
 	}
 	// validating metadata
 	annotationKeys := sets.NewString()
@@ -250,6 +254,57 @@ func validateSidecarSetUpdateStrategy(strategy *appsv1alpha1.SidecarSetUpdateStr
 				allErrs = append(allErrs, field.Required(fldPath.Child("scatterStrategy"), err.Error()))
 			}
 		}
+		if len(strategy.Waves) > 0 {
+			if intStrIsSet(strategy.Partition) {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("partition"), strategy.Partition.String(), "Partition and Waves cannot be used together, use the partition of each wave instead"))
+			}
+			allErrs = append(allErrs, validateSidecarSetUpdateWaves(strategy.Waves, fldPath.Child("waves"))...)
+		}
+		if strategy.FailurePolicy != nil {
+			allErrs = append(allErrs, validateSidecarSetUpdateFailurePolicy(strategy.FailurePolicy, fldPath.Child("failurePolicy"))...)
+		}
+	}
+	return allErrs
+}
+
+func validateSidecarSetUpdateFailurePolicy(policy *appsv1alpha1.SidecarSetUpdateFailurePolicy, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if policy.FailureThreshold != nil {
+		allErrs = append(allErrs, appsvalidation.ValidatePositiveIntOrPercent(*(policy.FailureThreshold), fldPath.Child("failureThreshold"))...)
+	}
+	if policy.RestartThreshold != nil && *policy.RestartThreshold < 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("restartThreshold"), *policy.RestartThreshold, "restartThreshold must be greater than 0"))
+	}
+	if policy.NotReadySeconds != nil && *policy.NotReadySeconds < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("notReadySeconds"), *policy.NotReadySeconds, "notReadySeconds must be non-negative"))
+	}
+	return allErrs
+}
+
+func validateSidecarSetUpdateWaves(waves []appsv1alpha1.SidecarSetUpdateWave, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	names := sets.NewString()
+	for i, wave := range waves {
+		idxPath := fldPath.Index(i)
+		if wave.Name == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("name"), "wave name cannot be empty"))
+		} else if names.Has(wave.Name) {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), wave.Name))
+		} else {
+			names.Insert(wave.Name)
+		}
+		if wave.NamespaceSelector != nil {
+			allErrs = append(allErrs, metavalidation.ValidateLabelSelector(wave.NamespaceSelector,
+				metavalidation.LabelSelectorValidationOptions{}, idxPath.Child("namespaceSelector"))...)
+		}
+		for j, workload := range wave.Workloads {
+			if workload.Kind == "" {
+				allErrs = append(allErrs, field.Required(idxPath.Child("workloads").Index(j).Child("kind"), "workload kind cannot be empty"))
+			}
+		}
+		if wave.Partition != nil {
+			allErrs = append(allErrs, appsvalidation.ValidatePositiveIntOrPercent(*(wave.Partition), idxPath.Child("partition"))...)
+		}
 	}
 	return allErrs
 }
@@ -261,7 +316,10 @@ func validateContainersForSidecarSet(
 	allErrs := field.ErrorList{}
 	//validating initContainer
 	var coreInitContainers []core.Container
-	for _, container := range initContainers {
+	for i, container := range initContainers {
+		if container.ResourcePolicy != nil {
+			allErrs = append(allErrs, validateSidecarResourcePolicy(container.ResourcePolicy, fldPath.Child("initContainers").Index(i).Child("resourcePolicy"))...)
+		}
 		coreContainer := core.Container{}
 		if err := corev1.Convert_v1_Container_To_core_Container(&container.Container, &coreContainer, nil); err != nil {
 			allErrs = append(allErrs, field.Invalid(fldPath.Child("initContainer"), container.Container, fmt.Sprintf("Convert_v1_Container_To_core_Container failed: %v", err)))
@@ -281,6 +339,12 @@ func validateContainersForSidecarSet(
 			allErrs = append(allErrs, field.Invalid(fldPath.Child("container").Child("shareVolumePolicy"), container.ShareVolumePolicy, "unsupported share volume policy"))
 		}
 		allErrs = append(allErrs, validateDownwardAPI(container.TransferEnv, idxPath.Child("transferEnv"))...)
+		if container.ResourcePolicy != nil {
+			allErrs = append(allErrs, validateSidecarResourcePolicy(container.ResourcePolicy, fldPath.Child("containers").Index(i).Child("resourcePolicy"))...)
+		}
+		if container.UpgradeStrategy.HotUpgradeHandoffHook != nil {
+			allErrs = append(allErrs, validateHotUpgradeHandoffHook(&container, fldPath.Child("containers").Index(i).Child("upgradeStrategy", "hotUpgradeHandoffHook"))...)
+		}
 		coreContainer := core.Container{}
 		if err := corev1.Convert_v1_Container_To_core_Container(&container.Container, &coreContainer, nil); err != nil {
 			allErrs = append(allErrs, field.Invalid(fldPath.Child("container"), container.Container, fmt.Sprintf("Convert_v1_Container_To_core_Container failed: %v", err)))
@@ -321,6 +385,87 @@ func validateContainersForSidecarSet(
 	return allErrs
 }
 
+func validateSidecarResourcePolicy(policy *appsv1alpha1.SidecarResourcePolicy, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	switch policy.TargetContainerMode {
+	case "", appsv1alpha1.SidecarResourceTargetContainerModeSum, appsv1alpha1.SidecarResourceTargetContainerModeMax:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("targetContainerMode"), policy.TargetContainerMode,
+			[]string{string(appsv1alpha1.SidecarResourceTargetContainerModeSum), string(appsv1alpha1.SidecarResourceTargetContainerModeMax)}))
+	}
+	validateRules := func(rules map[v1.ResourceName]appsv1alpha1.SidecarResourceRule, rulesPath *field.Path) {
+		for name, rule := range rules {
+			rulePath := rulesPath.Key(string(name))
+			for _, msg := range validationutil.IsQualifiedName(string(name)) {
+				allErrs = append(allErrs, field.Invalid(rulePath, name, msg))
+			}
+			if rule.Percent < 0 {
+				allErrs = append(allErrs, field.Invalid(rulePath.Child("percent"), rule.Percent, "must be greater than or equal to 0"))
+			}
+			if rule.Min != nil && rule.Min.Sign() < 0 {
+				allErrs = append(allErrs, field.Invalid(rulePath.Child("min"), rule.Min.String(), "must be greater than or equal to 0"))
+			}
+			if rule.Max != nil && rule.Max.Sign() < 0 {
+				allErrs = append(allErrs, field.Invalid(rulePath.Child("max"), rule.Max.String(), "must be greater than or equal to 0"))
+			}
+			if rule.Min != nil && rule.Max != nil && rule.Min.Cmp(*rule.Max) > 0 {
+				allErrs = append(allErrs, field.Invalid(rulePath.Child("min"), rule.Min.String(), "must be less than or equal to max"))
+			}
+		}
+	}
+	validateRules(policy.Requests, fldPath.Child("requests"))
+	validateRules(policy.Limits, fldPath.Child("limits"))
+	return allErrs
+}
+
+func validateHotUpgradeHandoffHook(container *appsv1alpha1.SidecarContainer, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	hook := container.UpgradeStrategy.HotUpgradeHandoffHook
+	if !sidecarcontrol.IsHotUpgradeContainer(container) {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "handoff hook is only supported in HotUpgrade"))
+	}
+	switch {
+	case hook.Exec == nil && hook.HTTPGet == nil:
+		allErrs = append(allErrs, field.Required(fldPath, "must specify a handler type"))
+	case hook.Exec != nil && hook.HTTPGet != nil:
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("httpGet"), "may not specify more than 1 handler type"))
+	case hook.Exec != nil:
+		if !utilfeature.DefaultFeatureGate.Enabled(features.SidecarSetHandoffHookExec) {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("exec"),
+				fmt.Sprintf("exec handler requires the feature-gate %s to be enabled", features.SidecarSetHandoffHookExec)))
+		}
+		if len(hook.Exec.Command) == 0 {
+			allErrs = append(allErrs, field.Required(fldPath.Child("exec", "command"), ""))
+		}
+	case hook.HTTPGet != nil:
+		// the request is always sent to the pod IP
+		if hook.HTTPGet.Host != "" {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("httpGet", "host"), "host is not allowed, the request is sent to the pod IP"))
+		}
+		port := hook.HTTPGet.Port
+		portPath := fldPath.Child("httpGet", "port")
+		if port.Type == intstr.Int {
+			for _, msg := range validationutil.IsValidPortNum(port.IntValue()) {
+				allErrs = append(allErrs, field.Invalid(portPath, port.IntValue(), msg))
+			}
+		} else {
+			for _, msg := range validationutil.IsValidPortName(port.StrVal) {
+				allErrs = append(allErrs, field.Invalid(portPath, port.StrVal, msg))
+			}
+		}
+		switch hook.HTTPGet.Scheme {
+		case "", v1.URISchemeHTTP, v1.URISchemeHTTPS:
+		default:
+			allErrs = append(allErrs, field.NotSupported(fldPath.Child("httpGet", "scheme"), hook.HTTPGet.Scheme,
+				[]string{string(v1.URISchemeHTTP), string(v1.URISchemeHTTPS)}))
+		}
+	}
+	if hook.TimeoutSeconds < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("timeoutSeconds"), hook.TimeoutSeconds, "must be greater than or equal to 0"))
+	}
+	return allErrs
+}
+
 func validateSidecarContainerConflict(newContainers, oldContainers []appsv1alpha1.SidecarContainer, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
@@ -369,6 +514,10 @@ func validateSidecarConflict(c client.Client, sidecarSets *appsv1alpha1.SidecarS
 		if set.Name == sidecarSet.Name {
 			continue
 		}
+		// the conflicts with different priorities are resolved when injected
+		if sidecarcontrol.GetSidecarSetPriority(set) != sidecarcontrol.GetSidecarSetPriority(sidecarSet) {
+			continue
+		}
 		for _, container := range set.Spec.InitContainers {
 			initContainerInOthers[container.Name] = set
 		}
@@ -452,6 +601,78 @@ func validateDownwardAPI(envs []appsv1alpha1.TransferEnvVar, fldPath *field.Path
 	return allErrs
 }
 
+// metadata.name is not supported, for it is empty when the pod created with generateName is injected
+var validPodMetadataTemplateFieldPathExpressions = sets.NewString(
+	"metadata.namespace")
+
+// validatePodMetadataTemplates validates the pod metadata templates in the sidecar containers that enable podMetadataTemplate,
+// and the volumes they mount.
+func validatePodMetadataTemplates(spec *appsv1alpha1.SidecarSetSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	validateValues := func(values []string, fldPath *field.Path) {
+		for _, value := range values {
+			paths, err := sidecarcontrol.GetPodMetadataTemplateFieldPaths(value)
+			if err != nil {
+				allErrs = append(allErrs, field.Invalid(fldPath, value, err.Error()))
+				continue
+			}
+			for _, path := range paths {
+				allErrs = append(allErrs, validatePodMetadataTemplateFieldPath(path, fldPath)...)
+			}
+		}
+	}
+
+	templateVolumes := sets.NewString()
+	for _, containers := range []struct {
+		path       *field.Path
+		containers []appsv1alpha1.SidecarContainer
+	}{
+		{fldPath.Child("initContainers"), spec.InitContainers},
+		{fldPath.Child("containers"), spec.Containers},
+	} {
+		for i := range containers.containers {
+			container := &containers.containers[i]
+			if !container.PodMetadataTemplate {
+				continue
+			}
+			validateValues(sidecarcontrol.GetSidecarContainerTemplateValues(container), containers.path.Index(i))
+			for _, mount := range container.VolumeMounts {
+				templateVolumes.Insert(mount.Name)
+			}
+			for _, device := range container.VolumeDevices {
+				templateVolumes.Insert(device.Name)
+			}
+		}
+	}
+	for i := range spec.Volumes {
+		if templateVolumes.Has(spec.Volumes[i].Name) {
+			validateValues(sidecarcontrol.GetVolumeTemplateValues(&spec.Volumes[i]), fldPath.Child("volumes").Index(i))
+		}
+	}
+	return allErrs
+}
+
+func validatePodMetadataTemplateFieldPath(path string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if path, subscript, ok := fieldpath.SplitMaybeSubscriptedPath(path); ok {
+		switch path {
+		case "metadata.annotations":
+			for _, msg := range validationutil.IsQualifiedName(strings.ToLower(subscript)) {
+				allErrs = append(allErrs, field.Invalid(fldPath, subscript, msg))
+			}
+		case "metadata.labels":
+			for _, msg := range validationutil.IsQualifiedName(subscript) {
+				allErrs = append(allErrs, field.Invalid(fldPath, subscript, msg))
+			}
+		default:
+			allErrs = append(allErrs, field.Invalid(fldPath, path, "does not support subscript"))
+		}
+	} else if !validPodMetadataTemplateFieldPathExpressions.Has(path) {
+		allErrs = append(allErrs, field.NotSupported(fldPath, path, validPodMetadataTemplateFieldPathExpressions.List()))
+	}
+	return allErrs
+}
+
 func validateObjectFieldSelector(fs *v1.ObjectFieldSelector, expressions *sets.String, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
@@ -15,8 +15,10 @@ import (
 
 	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -648,7 +650,7 @@ func TestValidateSidecarSet(t *testing.T) {
 			expectErrs: 1,
 		},
 		{
-			caseName: "The initContainer in-place upgrade is not currently supported.",
+			caseName: "native sidecar initContainer in-place upgrade",
 			sidecarSet: appsv1alpha1.SidecarSet{
 				ObjectMeta: metav1.ObjectMeta{Name: "test-sidecarset"},
 				Spec: appsv1alpha1.SidecarSetSpec{
@@ -678,8 +680,279 @@ func TestValidateSidecarSet(t *testing.T) {
 					},
 				},
 			},
+			expectErrs: 0,
+		},
+		{
+			caseName: "The initContainer in-place hot upgrade is not currently supported.",
+			sidecarSet: appsv1alpha1.SidecarSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-sidecarset"},
+				Spec: appsv1alpha1.SidecarSetSpec{
+					Selector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"a": "b"},
+					},
+					UpdateStrategy: appsv1alpha1.SidecarSetUpdateStrategy{
+						Type: appsv1alpha1.RollingUpdateSidecarSetStrategyType,
+					},
+					InitContainers: []appsv1alpha1.SidecarContainer{
+						{
+							PodInjectPolicy: appsv1alpha1.BeforeAppContainerType,
+							ShareVolumePolicy: appsv1alpha1.ShareVolumePolicy{
+								Type: appsv1alpha1.ShareVolumePolicyDisabled,
+							},
+							UpgradeStrategy: appsv1alpha1.SidecarContainerUpgradeStrategy{
+								UpgradeType:          appsv1alpha1.SidecarContainerHotUpgrade,
+								HotUpgradeEmptyImage: "empty-image",
+							},
+							Container: corev1.Container{
+								Name:                     "test-sidecar",
+								Image:                    "test-image",
+								ImagePullPolicy:          corev1.PullIfNotPresent,
+								TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+								RestartPolicy:            &always,
+							},
+						},
+					},
+				},
+			},
 			expectErrs: 1,
 		},
+		{
+			caseName: "wrong-updateStrategy-waves",
+			sidecarSet: appsv1alpha1.SidecarSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-sidecarset"},
+				Spec: appsv1alpha1.SidecarSetSpec{
+					Selector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"a": "b"},
+					},
+					UpdateStrategy: appsv1alpha1.SidecarSetUpdateStrategy{
+						Type:      appsv1alpha1.RollingUpdateSidecarSetStrategyType,
+						Partition: &intstr.IntOrString{Type: intstr.Int, IntVal: 1},
+						Waves: []appsv1alpha1.SidecarSetUpdateWave{
+							{
+								Name:      "wave-1",
+								Workloads: []appsv1alpha1.SidecarSetWaveWorkload{{Name: "web"}},
+							},
+							{
+								Name:      "wave-1",
+								Partition: &intstr.IntOrString{Type: intstr.String, StrVal: "10%"},
+							},
+						},
+					},
+					Containers: []appsv1alpha1.SidecarContainer{
+						{
+							PodInjectPolicy: appsv1alpha1.BeforeAppContainerType,
+							ShareVolumePolicy: appsv1alpha1.ShareVolumePolicy{
+								Type: appsv1alpha1.ShareVolumePolicyDisabled,
+							},
+							UpgradeStrategy: appsv1alpha1.SidecarContainerUpgradeStrategy{
+								UpgradeType: appsv1alpha1.SidecarContainerColdUpgrade,
+							},
+							Container: corev1.Container{
+								Name:                     "test-sidecar",
+								Image:                    "test-image",
+								ImagePullPolicy:          corev1.PullIfNotPresent,
+								TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+							},
+						},
+					},
+				},
+			},
+			expectErrs: 3,
+		},
+		{
+			caseName: "wrong-container-resourcePolicy",
+			sidecarSet: appsv1alpha1.SidecarSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-sidecarset"},
+				Spec: appsv1alpha1.SidecarSetSpec{
+					Selector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"a": "b"},
+					},
+					UpdateStrategy: appsv1alpha1.SidecarSetUpdateStrategy{
+						Type: appsv1alpha1.RollingUpdateSidecarSetStrategyType,
+					},
+					Containers: []appsv1alpha1.SidecarContainer{
+						{
+							PodInjectPolicy: appsv1alpha1.BeforeAppContainerType,
+							ShareVolumePolicy: appsv1alpha1.ShareVolumePolicy{
+								Type: appsv1alpha1.ShareVolumePolicyDisabled,
+							},
+							UpgradeStrategy: appsv1alpha1.SidecarContainerUpgradeStrategy{
+								UpgradeType: appsv1alpha1.SidecarContainerColdUpgrade,
+							},
+							Container: corev1.Container{
+								Name:                     "test-sidecar",
+								Image:                    "test-image",
+								ImagePullPolicy:          corev1.PullIfNotPresent,
+								TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+							},
+							ResourcePolicy: &appsv1alpha1.SidecarResourcePolicy{
+								TargetContainerMode: "Avg",
+								Requests: map[corev1.ResourceName]appsv1alpha1.SidecarResourceRule{
+									corev1.ResourceCPU: {Percent: -1},
+								},
+								Limits: map[corev1.ResourceName]appsv1alpha1.SidecarResourceRule{
+									corev1.ResourceMemory: {Percent: 10, Min: ptr.To(resource.MustParse("1Gi")), Max: ptr.To(resource.MustParse("512Mi"))},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectErrs: 3,
+		},
+		{
+			caseName: "wrong-container-hotUpgradeHandoffHook",
+			sidecarSet: appsv1alpha1.SidecarSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-sidecarset"},
+				Spec: appsv1alpha1.SidecarSetSpec{
+					Selector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"a": "b"},
+					},
+					UpdateStrategy: appsv1alpha1.SidecarSetUpdateStrategy{
+						Type: appsv1alpha1.RollingUpdateSidecarSetStrategyType,
+					},
+					Containers: []appsv1alpha1.SidecarContainer{
+						{
+							PodInjectPolicy: appsv1alpha1.BeforeAppContainerType,
+							ShareVolumePolicy: appsv1alpha1.ShareVolumePolicy{
+								Type: appsv1alpha1.ShareVolumePolicyDisabled,
+							},
+							UpgradeStrategy: appsv1alpha1.SidecarContainerUpgradeStrategy{
+								UpgradeType: appsv1alpha1.SidecarContainerColdUpgrade,
+								HotUpgradeHandoffHook: &appsv1alpha1.SidecarHandoffHook{
+									Exec:           &corev1.ExecAction{Command: []string{"/bin/sh", "-c", "handoff"}},
+									HTTPGet:        &corev1.HTTPGetAction{Port: intstr.FromInt32(15000)},
+									TimeoutSeconds: -1,
+								},
+							},
+							Container: corev1.Container{
+								Name:                     "test-sidecar",
+								Image:                    "test-image",
+								ImagePullPolicy:          corev1.PullIfNotPresent,
+								TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+							},
+						},
+					},
+				},
+			},
+			expectErrs: 3,
+		},
+		{
+			caseName: "wrong-container-hotUpgradeHandoffHook-host",
+			sidecarSet: appsv1alpha1.SidecarSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-sidecarset"},
+				Spec: appsv1alpha1.SidecarSetSpec{
+					Selector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"a": "b"},
+					},
+					UpdateStrategy: appsv1alpha1.SidecarSetUpdateStrategy{
+						Type: appsv1alpha1.RollingUpdateSidecarSetStrategyType,
+					},
+					Containers: []appsv1alpha1.SidecarContainer{
+						{
+							PodInjectPolicy: appsv1alpha1.BeforeAppContainerType,
+							ShareVolumePolicy: appsv1alpha1.ShareVolumePolicy{
+								Type: appsv1alpha1.ShareVolumePolicyDisabled,
+							},
+							UpgradeStrategy: appsv1alpha1.SidecarContainerUpgradeStrategy{
+								UpgradeType:          appsv1alpha1.SidecarContainerHotUpgrade,
+								HotUpgradeEmptyImage: "test-empty-image",
+								HotUpgradeHandoffHook: &appsv1alpha1.SidecarHandoffHook{
+									HTTPGet: &corev1.HTTPGetAction{Host: "10.0.0.1", Port: intstr.FromInt32(15000)},
+								},
+							},
+							Container: corev1.Container{
+								Name:                     "test-sidecar",
+								Image:                    "test-image",
+								ImagePullPolicy:          corev1.PullIfNotPresent,
+								TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+							},
+						},
+					},
+				},
+			},
+			expectErrs: 1,
+		},
+		{
+			caseName: "wrong-container-hotUpgradeHandoffHook-exec-disabled",
+			sidecarSet: appsv1alpha1.SidecarSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-sidecarset"},
+				Spec: appsv1alpha1.SidecarSetSpec{
+					Selector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"a": "b"},
+					},
+					UpdateStrategy: appsv1alpha1.SidecarSetUpdateStrategy{
+						Type: appsv1alpha1.RollingUpdateSidecarSetStrategyType,
+					},
+					Containers: []appsv1alpha1.SidecarContainer{
+						{
+							PodInjectPolicy: appsv1alpha1.BeforeAppContainerType,
+							ShareVolumePolicy: appsv1alpha1.ShareVolumePolicy{
+								Type: appsv1alpha1.ShareVolumePolicyDisabled,
+							},
+							UpgradeStrategy: appsv1alpha1.SidecarContainerUpgradeStrategy{
+								UpgradeType:          appsv1alpha1.SidecarContainerHotUpgrade,
+								HotUpgradeEmptyImage: "test-empty-image",
+								HotUpgradeHandoffHook: &appsv1alpha1.SidecarHandoffHook{
+									Exec: &corev1.ExecAction{Command: []string{"/bin/sh", "-c", "handoff"}},
+								},
+							},
+							Container: corev1.Container{
+								Name:                     "test-sidecar",
+								Image:                    "test-image",
+								ImagePullPolicy:          corev1.PullIfNotPresent,
+								TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+							},
+						},
+					},
+				},
+			},
+			expectErrs: 1,
+		},
+		{
+			caseName: "wrong-container-podMetadataTemplate",
+			sidecarSet: appsv1alpha1.SidecarSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-sidecarset"},
+				Spec: appsv1alpha1.SidecarSetSpec{
+					Selector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"a": "b"},
+					},
+					UpdateStrategy: appsv1alpha1.SidecarSetUpdateStrategy{
+						Type: appsv1alpha1.NotUpdateSidecarSetStrategyType,
+					},
+					Containers: []appsv1alpha1.SidecarContainer{
+						{
+							PodInjectPolicy: appsv1alpha1.BeforeAppContainerType,
+							ShareVolumePolicy: appsv1alpha1.ShareVolumePolicy{
+								Type: appsv1alpha1.ShareVolumePolicyDisabled,
+							},
+							UpgradeStrategy: appsv1alpha1.SidecarContainerUpgradeStrategy{
+								UpgradeType: appsv1alpha1.SidecarContainerColdUpgrade,
+							},
+							PodMetadataTemplate: true,
+							Container: corev1.Container{
+								Name:                     "test-sidecar",
+								Image:                    "test-image",
+								Args:                     []string{"--service={{ metadata.labels['app'] }}", "--cluster={{ metadata.labels['cluster']"},
+								Env:                      []corev1.EnvVar{{Name: "POD_NODE", Value: "{{ spec.nodeName }}"}, {Name: "POD_NAME", Value: "{{ metadata.name }}"}},
+								VolumeMounts:             []corev1.VolumeMount{{Name: "log", MountPath: "/var/log"}},
+								ImagePullPolicy:          corev1.PullIfNotPresent,
+								TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: "log",
+							VolumeSource: corev1.VolumeSource{
+								HostPath: &corev1.HostPathVolumeSource{Path: "/var/log/{{ metadata.uid['x'] }}"},
+							},
+						},
+					},
+				},
+			},
+			expectErrs: 4,
+		},
 	}
 
 	SidecarSetRevisions := []client.Object{
@@ -862,6 +1135,45 @@ func TestSidecarSetNameConflict(t *testing.T) {
 			},
 			expect: 0,
 		},
+		{
+			name: "conflicts with different priorities",
+			getSidecarSet: func() *appsv1alpha1.SidecarSet {
+				return &appsv1alpha1.SidecarSet{
+					ObjectMeta: metav1.ObjectMeta{Name: "sidecarset2"},
+					Spec: appsv1alpha1.SidecarSetSpec{
+						Selector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{"a": "b"},
+						},
+						Priority: ptr.To[int32](10),
+						Containers: []appsv1alpha1.SidecarContainer{
+							{
+								Container: corev1.Container{Name: "container-name"},
+							},
+						},
+					},
+				}
+			},
+			getSidecarList: func() *appsv1alpha1.SidecarSetList {
+				return &appsv1alpha1.SidecarSetList{
+					Items: []appsv1alpha1.SidecarSet{
+						{
+							ObjectMeta: metav1.ObjectMeta{Name: "sidecarset1"},
+							Spec: appsv1alpha1.SidecarSetSpec{
+								Selector: &metav1.LabelSelector{
+									MatchLabels: map[string]string{"a": "b"},
+								},
+								Containers: []appsv1alpha1.SidecarContainer{
+									{
+										Container: corev1.Container{Name: "container-name"},
+									},
+								},
+							},
+						},
+					},
+				}
+			},
+			expect: 0,
+		},
 	}
 
 	for _, cs := range cases {
@@ -1,8 +1,13 @@
 package types
 
 import (
+	"net/http"
+
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
 type HandlerGetter = func(manager.Manager) admission.Handler
+
+// HTTPHandlerGetter returns the handler of the non-admission endpoints on webhook server
+type HTTPHandlerGetter = func(manager.Manager) http.Handler
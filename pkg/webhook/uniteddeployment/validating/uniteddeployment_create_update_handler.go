@@ -22,11 +22,14 @@ import (
 	"net/http"
 
 	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
 	"github.com/openkruise/kruise/pkg/util"
+	"github.com/openkruise/kruise/pkg/util/configuration"
 	"github.com/openkruise/kruise/pkg/webhook/util/deletionprotection"
 )
 
@@ -36,7 +39,7 @@ type UnitedDeploymentCreateUpdateHandler struct {
 	// - uncomment it
 	// - import sigs.k8s.io/controller-runtime/pkg/client
 	// - uncomment the InjectClient method at the bottom of this file.
-	// Client  client.Client
+	Client client.Client
 
 	// Decoder decodes objects
 	Decoder admission.Decoder
@@ -54,9 +57,14 @@ func (h *UnitedDeploymentCreateUpdateHandler) Handle(ctx context.Context, req ad
 		if err := h.Decoder.Decode(req, obj); err != nil {
 			return admission.Errored(http.StatusBadRequest, err)
 		}
-		if allErrs := validateUnitedDeployment(obj); len(allErrs) > 0 {
+		allErrs := validateUnitedDeployment(obj)
+		allErrs = append(allErrs, validateUpdateStrategyType(&obj.Spec, field.NewPath("spec", "updateStrategy", "type"))...)
+		if len(allErrs) > 0 {
 			return admission.Errored(http.StatusUnprocessableEntity, allErrs.ToAggregate())
 		}
+		if resp := h.validateCustomWorkloadRolling(obj); !resp.Allowed {
+			return resp
+		}
 	case admissionv1.Update:
 		if err := h.Decoder.Decode(req, obj); err != nil {
 			return admission.Errored(http.StatusBadRequest, err)
@@ -70,6 +78,9 @@ func (h *UnitedDeploymentCreateUpdateHandler) Handle(ctx context.Context, req ad
 		if allErrs := append(validationErrorList, updateErrorList...); len(allErrs) > 0 {
 			return admission.Errored(http.StatusUnprocessableEntity, allErrs.ToAggregate())
 		}
+		if resp := h.validateCustomWorkloadRolling(obj); !resp.Allowed {
+			return resp
+		}
 	case admissionv1.Delete:
 		if len(req.OldObject.Raw) == 0 {
 			klog.InfoS("Skip to validate UnitedDeployment deletion for no old object, maybe because of Kubernetes version < 1.16", "namespace", req.Namespace, "name", req.Name)
@@ -87,3 +98,21 @@ func (h *UnitedDeploymentCreateUpdateHandler) Handle(ctx context.Context, req ad
 
 	return admission.ValidationResponse(true, "")
 }
+
+// validateCustomWorkloadRolling rejects the SubsetRolling update strategy for the custom workload without
+// partitionPath configured in whitelist, whose subsets would be updated all at once.
+func (h *UnitedDeploymentCreateUpdateHandler) validateCustomWorkloadRolling(obj *appsv1alpha1.UnitedDeployment) admission.Response {
+	template := obj.Spec.Template.CustomWorkloadTemplate
+	if template == nil || obj.Spec.UpdateStrategy.Type != appsv1alpha1.SubsetRollingUpdateStrategyType {
+		return admission.ValidationResponse(true, "")
+	}
+	whiteList, err := configuration.GetUDWatchCustomWorkloadWhiteList(h.Client)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if workload := whiteList.Get(template.APIVersion, template.Kind); workload != nil && workload.PartitionPath == "" {
+		return admission.Errored(http.StatusUnprocessableEntity, field.Forbidden(field.NewPath("spec", "updateStrategy", "type"),
+			fmt.Sprintf("SubsetRolling update strategy is not supported by custom workload %s/%s without partitionPath", template.APIVersion, template.Kind)))
+	}
+	return admission.ValidationResponse(true, "")
+}
@@ -17,16 +17,20 @@ limitations under the License.
 package validating
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	unversionedvalidation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	appsvalidation "k8s.io/kubernetes/pkg/apis/apps/validation"
 	"k8s.io/kubernetes/pkg/apis/core"
@@ -36,6 +40,7 @@ import (
 
 	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
 	udctrl "github.com/openkruise/kruise/pkg/controller/uniteddeployment"
+	"github.com/openkruise/kruise/pkg/util"
 	webhookutil "github.com/openkruise/kruise/pkg/webhook/util"
 	"github.com/openkruise/kruise/pkg/webhook/util/convertor"
 )
@@ -57,10 +62,21 @@ func validateUnitedDeploymentSpec(spec *appsv1alpha1.UnitedDeploymentSpec, fldPa
 	}
 
 	if spec.Topology.ScheduleStrategy.ShouldReserveUnschedulablePods() &&
-		(spec.Template.AdvancedStatefulSetTemplate != nil || spec.Template.StatefulSetTemplate != nil) {
+		(spec.Template.AdvancedStatefulSetTemplate != nil || spec.Template.StatefulSetTemplate != nil || spec.Template.CustomWorkloadTemplate != nil) {
 		allErrs = append(allErrs, field.Invalid(fldPath.Child("topology", "scheduleStrategy"), spec.Topology.ScheduleStrategy,
 			"only stateless workloads (Deployment and CloneSet) are supported by reserved rescheduling"))
 	}
+	if failover := spec.Topology.ScheduleStrategy.Failover; failover != nil {
+		allErrs = append(allErrs, validateSubsetFailover(failover, fldPath.Child("topology", "scheduleStrategy", "failover"))...)
+		if spec.Topology.ScheduleStrategy.ShouldReserveUnschedulablePods() {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("topology", "scheduleStrategy", "failover"),
+				"failover is not supported together with reserved rescheduling"))
+		}
+	}
+	if spec.Topology.ScheduleStrategy.ShouldReserveUnschedulablePods() && spec.Topology.IsWeighted() {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("topology", "scheduleStrategy"), spec.Topology.ScheduleStrategy,
+			"reserved rescheduling is not supported for subsets with weights"))
+	}
 
 	selector, err := metav1.LabelSelectorAsSelector(spec.Selector)
 	if err != nil {
@@ -70,6 +86,9 @@ func validateUnitedDeploymentSpec(spec *appsv1alpha1.UnitedDeploymentSpec, fldPa
 	}
 
 	allErrs = append(allErrs, validateSubsetReplicas(spec.Replicas, spec.Topology.Subsets, fldPath.Child("topology", "subsets"))...)
+	if spec.Topology.SubsetDiscovery != nil {
+		allErrs = append(allErrs, validateSubsetDiscovery(&spec.Topology, fldPath.Child("topology"))...)
+	}
 
 	subSetNames := sets.String{}
 	for i, subset := range spec.Topology.Subsets {
@@ -107,12 +126,17 @@ func validateUnitedDeploymentSpec(spec *appsv1alpha1.UnitedDeploymentSpec, fldPa
 			allErrs = append(allErrs, apivalidation.ValidateTolerations(coreTolerations, fldPath.Child("topology", "subsets").Index(i).Child("tolerations"))...)
 		}
 
+		if subset.Overrides != nil {
+			allErrs = append(allErrs, validateSubsetOverrides(subset.Overrides, &spec.Template, fldPath.Child("topology", "subsets").Index(i).Child("overrides"))...)
+		}
+
 		if subset.Replicas != nil && spec.Topology.ScheduleStrategy.IsAdaptive() {
 			allErrs = append(allErrs, field.Forbidden(fldPath.Child("topology", "subsets").Index(i).Child("replicas"), "specify replicas use minReplicas/maxReplicas to enable adaptive strategy"))
 		}
 	}
 
-	if spec.UpdateStrategy.ManualUpdate != nil {
+	// subsets generated by discovery are not known until reconciling
+	if spec.UpdateStrategy.ManualUpdate != nil && spec.Topology.SubsetDiscovery == nil {
 		for subset := range spec.UpdateStrategy.ManualUpdate.Partitions {
 			if !subSetNames.Has(subset) {
 				allErrs = append(allErrs, field.Invalid(fldPath.Child("updateStrategy", "partitions"), spec.UpdateStrategy.ManualUpdate.Partitions, fmt.Sprintf("subset %s does not exist", subset)))
@@ -120,6 +144,226 @@ func validateUnitedDeploymentSpec(spec *appsv1alpha1.UnitedDeploymentSpec, fldPa
 		}
 	}
 
+	if spec.UpdateStrategy.Type == appsv1alpha1.SubsetRollingUpdateStrategyType {
+		allErrs = append(allErrs, validateSubsetRollingUpdate(spec, subSetNames, fldPath.Child("updateStrategy"))...)
+	}
+
+	return allErrs
+}
+
+// validateUpdateStrategyType validates the type of update strategy, it is only validated on creation or when
+// the type changes, so that the existing UnitedDeployments with unknown types can still be updated.
+func validateUpdateStrategyType(spec *appsv1alpha1.UnitedDeploymentSpec, fldPath *field.Path) field.ErrorList {
+	switch spec.UpdateStrategy.Type {
+	case "", appsv1alpha1.ManualUpdateStrategyType, appsv1alpha1.SubsetRollingUpdateStrategyType:
+		return nil
+	}
+	return field.ErrorList{field.NotSupported(fldPath, spec.UpdateStrategy.Type,
+		[]string{string(appsv1alpha1.ManualUpdateStrategyType), string(appsv1alpha1.SubsetRollingUpdateStrategyType)})}
+}
+
+func validateSubsetRollingUpdate(spec *appsv1alpha1.UnitedDeploymentSpec, subSetNames sets.String, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if spec.Template.DeploymentTemplate != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("type"), "SubsetRolling update strategy is not supported by deploymentTemplate which has no partition"))
+	}
+
+	rolling := spec.UpdateStrategy.SubsetRollingUpdate
+	if rolling == nil {
+		return allErrs
+	}
+	ordered := sets.NewString()
+	for i, name := range rolling.Order {
+		if !subSetNames.Has(name) && spec.Topology.SubsetDiscovery == nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("subsetRollingUpdate", "order").Index(i), name, fmt.Sprintf("subset %s does not exist", name)))
+		} else if ordered.Has(name) {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Child("subsetRollingUpdate", "order").Index(i), name))
+		}
+		ordered.Insert(name)
+	}
+	if rolling.SoakSeconds < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("subsetRollingUpdate", "soakSeconds"), rolling.SoakSeconds, "soakSeconds should not be negative"))
+	}
+	if rolling.ProgressDeadlineSeconds != nil && *rolling.ProgressDeadlineSeconds <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("subsetRollingUpdate", "progressDeadlineSeconds"), *rolling.ProgressDeadlineSeconds, "progressDeadlineSeconds should be positive"))
+	}
+	return allErrs
+}
+
+func validateSubsetDiscovery(topology *appsv1alpha1.Topology, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	discovery := topology.SubsetDiscovery
+	if len(topology.Subsets) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("subsets"), "subsets can not be declared together with subsetDiscovery"))
+	}
+	if len(discovery.TopologyKey) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("subsetDiscovery", "topologyKey"), ""))
+	} else {
+		allErrs = append(allErrs, unversionedvalidation.ValidateLabelName(discovery.TopologyKey, fldPath.Child("subsetDiscovery", "topologyKey"))...)
+	}
+	if discovery.NodeSelector != nil {
+		allErrs = append(allErrs, unversionedvalidation.ValidateLabelSelector(discovery.NodeSelector, unversionedvalidation.LabelSelectorValidationOptions{}, fldPath.Child("subsetDiscovery", "nodeSelector"))...)
+	}
+	if len(discovery.Patch.Raw) > 0 {
+		patch := map[string]interface{}{}
+		if err := json.Unmarshal(discovery.Patch.Raw, &patch); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("subsetDiscovery", "patch"), string(discovery.Patch.Raw), fmt.Sprintf("patch should be a json object: %v", err)))
+		}
+	}
+	if discovery.Tolerations != nil {
+		var coreTolerations []core.Toleration
+		for i, toleration := range discovery.Tolerations {
+			coreToleration := &core.Toleration{}
+			if err := corev1.Convert_v1_Toleration_To_core_Toleration(&toleration, coreToleration, nil); err != nil {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("subsetDiscovery", "tolerations").Index(i), toleration, fmt.Sprintf("Convert_v1_Toleration_To_core_Toleration failed: %v", err)))
+			} else {
+				coreTolerations = append(coreTolerations, *coreToleration)
+			}
+		}
+		allErrs = append(allErrs, apivalidation.ValidateTolerations(coreTolerations, fldPath.Child("subsetDiscovery", "tolerations"))...)
+	}
+	return allErrs
+}
+
+func validateSubsetOverrides(overrides *appsv1alpha1.SubsetOverrides, template *appsv1alpha1.SubsetTemplate, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	// the pod template of custom workloads is unknown here
+	var containerNames, claimNames sets.String
+	supportClaims := false
+	switch {
+	case template.StatefulSetTemplate != nil:
+		containerNames = getContainerNames(&template.StatefulSetTemplate.Spec.Template.Spec)
+		claimNames, supportClaims = getClaimNames(template.StatefulSetTemplate.Spec.VolumeClaimTemplates), true
+	case template.AdvancedStatefulSetTemplate != nil:
+		containerNames = getContainerNames(&template.AdvancedStatefulSetTemplate.Spec.Template.Spec)
+		claimNames, supportClaims = getClaimNames(template.AdvancedStatefulSetTemplate.Spec.VolumeClaimTemplates), true
+	case template.CloneSetTemplate != nil:
+		containerNames = getContainerNames(&template.CloneSetTemplate.Spec.Template.Spec)
+		claimNames, supportClaims = getClaimNames(template.CloneSetTemplate.Spec.VolumeClaimTemplates), true
+	case template.DeploymentTemplate != nil:
+		containerNames = getContainerNames(&template.DeploymentTemplate.Spec.Template.Spec)
+	}
+
+	for i, container := range overrides.Containers {
+		idxPath := fldPath.Child("containers").Index(i)
+		if len(container.Name) == 0 {
+			allErrs = append(allErrs, field.Required(idxPath.Child("name"), ""))
+		} else if containerNames != nil && !containerNames.Has(container.Name) {
+			allErrs = append(allErrs, field.NotFound(idxPath.Child("name"), container.Name))
+		}
+		if container.Resources != nil {
+			for name, quantity := range container.Resources.Limits {
+				allErrs = append(allErrs, apivalidation.ValidateNonnegativeQuantity(quantity, idxPath.Child("resources", "limits").Key(string(name)))...)
+			}
+			for name, quantity := range container.Resources.Requests {
+				allErrs = append(allErrs, apivalidation.ValidateNonnegativeQuantity(quantity, idxPath.Child("resources", "requests").Key(string(name)))...)
+			}
+		}
+		for j, env := range container.Env {
+			if len(env.Name) == 0 {
+				allErrs = append(allErrs, field.Required(idxPath.Child("env").Index(j).Child("name"), ""))
+			} else {
+				for _, msg := range validation.IsEnvVarName(env.Name) {
+					allErrs = append(allErrs, field.Invalid(idxPath.Child("env").Index(j).Child("name"), env.Name, msg))
+				}
+			}
+		}
+	}
+
+	if affinity := overrides.NodeAffinity; affinity != nil {
+		if required := affinity.RequiredDuringSchedulingIgnoredDuringExecution; required != nil {
+			for i, term := range required.NodeSelectorTerms {
+				termPath := fldPath.Child("nodeAffinity", "requiredDuringSchedulingIgnoredDuringExecution", "nodeSelectorTerms").Index(i)
+				coreNodeSelectorTerm := &core.NodeSelectorTerm{}
+				if err := corev1.Convert_v1_NodeSelectorTerm_To_core_NodeSelectorTerm(term.DeepCopy(), coreNodeSelectorTerm, nil); err != nil {
+					allErrs = append(allErrs, field.Invalid(termPath, term, fmt.Sprintf("Convert_v1_NodeSelectorTerm_To_core_NodeSelectorTerm failed: %v", err)))
+				} else {
+					allErrs = append(allErrs, apivalidation.ValidateNodeSelectorTerm(*coreNodeSelectorTerm, true, termPath)...)
+				}
+			}
+		}
+		for i, term := range affinity.PreferredDuringSchedulingIgnoredDuringExecution {
+			if term.Weight < 1 || term.Weight > 100 {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("nodeAffinity", "preferredDuringSchedulingIgnoredDuringExecution").Index(i).Child("weight"), term.Weight, "must be in the range 1-100"))
+			}
+		}
+	}
+
+	if overrides.Tolerations != nil {
+		var coreTolerations []core.Toleration
+		for i, toleration := range overrides.Tolerations {
+			coreToleration := &core.Toleration{}
+			if err := corev1.Convert_v1_Toleration_To_core_Toleration(&toleration, coreToleration, nil); err != nil {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("tolerations").Index(i), toleration, fmt.Sprintf("Convert_v1_Toleration_To_core_Toleration failed: %v", err)))
+			} else {
+				coreTolerations = append(coreTolerations, *coreToleration)
+			}
+		}
+		allErrs = append(allErrs, apivalidation.ValidateTolerations(coreTolerations, fldPath.Child("tolerations"))...)
+	}
+
+	if len(overrides.VolumeClaimTemplates) > 0 && !supportClaims {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("volumeClaimTemplates"), "only statefulSetTemplate, advancedStatefulSetTemplate and cloneSetTemplate support volumeClaimTemplates"))
+	} else {
+		for i, claim := range overrides.VolumeClaimTemplates {
+			idxPath := fldPath.Child("volumeClaimTemplates").Index(i)
+			if len(claim.Name) == 0 {
+				allErrs = append(allErrs, field.Required(idxPath.Child("name"), ""))
+			} else if !claimNames.Has(claim.Name) {
+				allErrs = append(allErrs, field.NotFound(idxPath.Child("name"), claim.Name))
+			}
+			if claim.Resources != nil {
+				for name, quantity := range claim.Resources.Requests {
+					allErrs = append(allErrs, apivalidation.ValidateNonnegativeQuantity(quantity, idxPath.Child("resources", "requests").Key(string(name)))...)
+				}
+			}
+		}
+	}
+	return allErrs
+}
+
+func getContainerNames(podSpec *v1.PodSpec) sets.String {
+	names := sets.NewString()
+	for _, container := range podSpec.Containers {
+		names.Insert(container.Name)
+	}
+	return names
+}
+
+func getClaimNames(claims []v1.PersistentVolumeClaim) sets.String {
+	names := sets.NewString()
+	for _, claim := range claims {
+		names.Insert(claim.Name)
+	}
+	return names
+}
+
+func validateSubsetFailover(failover *appsv1alpha1.SubsetFailoverStrategy, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if failover.UnhealthyPercent != nil && (*failover.UnhealthyPercent < 1 || *failover.UnhealthyPercent > 100) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("unhealthyPercent"), *failover.UnhealthyPercent, "must be in the range 1-100"))
+	}
+	if failover.RecoveryPercent != nil && (*failover.RecoveryPercent < 1 || *failover.RecoveryPercent > 100) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("recoveryPercent"), *failover.RecoveryPercent, "must be in the range 1-100"))
+	}
+	if failover.GetRecoveryPercent() < failover.GetUnhealthyPercent() {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("recoveryPercent"), failover.GetRecoveryPercent(), "recoveryPercent must not be less than unhealthyPercent"))
+	}
+	if failover.UnhealthySeconds != nil {
+		allErrs = append(allErrs, apivalidation.ValidateNonnegativeField(int64(*failover.UnhealthySeconds), fldPath.Child("unhealthySeconds"))...)
+	}
+	if failover.RecoverySeconds != nil {
+		allErrs = append(allErrs, apivalidation.ValidateNonnegativeField(int64(*failover.RecoverySeconds), fldPath.Child("recoverySeconds"))...)
+	}
+	if failover.MaxSurge != nil {
+		if maxSurge, err := util.GetScaledValueFromIntOrPercent(failover.MaxSurge, 100, true); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("maxSurge"), failover.MaxSurge.String(),
+				fmt.Sprintf("failed GetScaledValueFromIntOrPercent for maxSurge: %v", err)))
+		} else if maxSurge < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("maxSurge"), failover.MaxSurge.String(), "must be non-negative"))
+		}
+	}
 	return allErrs
 }
 
@@ -131,6 +375,8 @@ func validateSubsetReplicas(expectedReplicas *int32, subsets []appsv1alpha1.Subs
 
 		countReplicas    = 0
 		countMaxReplicas = 0
+		countWeights     = 0
+		sumWeights       = int64(0)
 
 		hasReplicasSettings = false
 		hasCapacitySettings = false
@@ -180,6 +426,31 @@ func validateSubsetReplicas(expectedReplicas *int32, subsets []appsv1alpha1.Subs
 			errList = append(errList, field.Invalid(fldPath.Index(i).Child("minReplicas"), subset.MaxReplicas,
 				fmt.Sprintf("subset[%d].minReplicas must be more than or equal to maxReplicas", i)))
 		}
+
+		if subset.Weight != nil {
+			countWeights++
+			sumWeights += int64(*subset.Weight)
+			errList = append(errList, apivalidation.ValidateNonnegativeField(int64(*subset.Weight), fldPath.Index(i).Child("weight"))...)
+		}
+	}
+
+	if countWeights > 0 {
+		if hasReplicasSettings {
+			errList = append(errList, field.Invalid(fldPath, subsets, "subset.Replicas and subset.Weight are mutually exclusive in a UnitedDeployment"))
+		}
+		if countWeights != len(subsets) {
+			errList = append(errList, field.Invalid(fldPath, countWeights, "weights of all subsets should be provided if any subset.weight is set"))
+		}
+		if sumWeights <= 0 {
+			errList = append(errList, field.Invalid(fldPath, sumWeights, "sum of subset weights must be greater than 0"))
+		}
+		if *expectedReplicas == -1 {
+			errList = append(errList, field.Invalid(fldPath, expectedReplicas, "spec.replicas must be not empty if you set subset.weight"))
+		}
+		if sumMinReplicas > sumMaxReplicas {
+			errList = append(errList, field.Invalid(fldPath, sumMinReplicas, "sum of indicated subset.minReplicas should not be greater than sum of indicated subset.maxReplicas"))
+		}
+		return errList
 	}
 
 	if hasReplicasSettings && hasCapacitySettings {
@@ -235,6 +506,12 @@ func validateUnitedDeploymentSpecUpdate(spec, oldSpec *appsv1alpha1.UnitedDeploy
 	allErrs := field.ErrorList{}
 	allErrs = append(allErrs, validateSubsetTemplateUpdate(&spec.Template, &oldSpec.Template, fldPath.Child("template"))...)
 	allErrs = append(allErrs, validateUnitedDeploymentTopology(&spec.Topology, &oldSpec.Topology, fldPath.Child("topology"))...)
+	if spec.UpdateStrategy.Type != oldSpec.UpdateStrategy.Type {
+		allErrs = append(allErrs, validateUpdateStrategyType(spec, fldPath.Child("updateStrategy", "type"))...)
+	}
+	if spec.Template.StatefulSetTemplate != nil || spec.Template.AdvancedStatefulSetTemplate != nil {
+		allErrs = append(allErrs, validateVolumeClaimTemplateOverridesUpdate(&spec.Topology, &oldSpec.Topology, fldPath.Child("topology"))...)
+	}
 
 	return allErrs
 }
@@ -272,6 +549,29 @@ func validateUnitedDeploymentTopology(topology, oldTopology *appsv1alpha1.Topolo
 	return allErrs
 }
 
+// validateVolumeClaimTemplateOverridesUpdate forbids changing the volume claim templates of existing subsets,
+// which are immutable in StatefulSet.
+func validateVolumeClaimTemplateOverridesUpdate(topology, oldTopology *appsv1alpha1.Topology, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	oldSubsets := map[string]*appsv1alpha1.Subset{}
+	for i, subset := range oldTopology.Subsets {
+		oldSubsets[subset.Name] = &oldTopology.Subsets[i]
+	}
+	getClaims := func(subset *appsv1alpha1.Subset) []appsv1alpha1.VolumeClaimTemplateOverride {
+		if subset.Overrides == nil {
+			return nil
+		}
+		return subset.Overrides.VolumeClaimTemplates
+	}
+	for i := range topology.Subsets {
+		subset := &topology.Subsets[i]
+		if oldSubset, exist := oldSubsets[subset.Name]; exist && !apiequality.Semantic.DeepEqual(getClaims(oldSubset), getClaims(subset)) {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("subsets").Index(i).Child("overrides", "volumeClaimTemplates"), "may not be changed in an update"))
+		}
+	}
+	return allErrs
+}
+
 func validateSubsetTemplateUpdate(template, oldTemplate *appsv1alpha1.SubsetTemplate, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 	if template.StatefulSetTemplate != nil && oldTemplate.StatefulSetTemplate != nil {
@@ -301,10 +601,13 @@ func validateSubsetTemplate(template *appsv1alpha1.SubsetTemplate, selector labe
 	if template.DeploymentTemplate != nil {
 		templateCount++
 	}
+	if template.CustomWorkloadTemplate != nil {
+		templateCount++
+	}
 	if templateCount < 1 {
-		allErrs = append(allErrs, field.Required(fldPath, "should provide one of statefulSetTemplate, advancedStatefulSetTemplate, cloneSetTemplate, or deploymentTemplate"))
+		allErrs = append(allErrs, field.Required(fldPath, "should provide one of statefulSetTemplate, advancedStatefulSetTemplate, cloneSetTemplate, deploymentTemplate, or customWorkloadTemplate"))
 	} else if templateCount > 1 {
-		allErrs = append(allErrs, field.Invalid(fldPath, template, "should provide only one of statefulSetTemplate, advancedStatefulSetTemplate, cloneSetTemplate, or deploymentTemplate"))
+		allErrs = append(allErrs, field.Invalid(fldPath, template, "should provide only one of statefulSetTemplate, advancedStatefulSetTemplate, cloneSetTemplate, deploymentTemplate, or customWorkloadTemplate"))
 	}
 
 	if template.StatefulSetTemplate != nil {
@@ -346,11 +649,34 @@ func validateSubsetTemplate(template *appsv1alpha1.SubsetTemplate, selector labe
 			return allErrs
 		}
 		allErrs = append(allErrs, appsvalidation.ValidatePodTemplateSpecForReplicaSet(coreTemplate, selector, 0, fldPath.Child("deploymentTemplate", "spec", "template"), webhookutil.DefaultPodValidationOptions)...)
+	} else if template.CustomWorkloadTemplate != nil {
+		labels := labels.Set(template.CustomWorkloadTemplate.Labels)
+		if !selector.Matches(labels) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("customWorkloadTemplate", "metadata", "labels"), template.CustomWorkloadTemplate.Labels, "`selector` does not match template `labels`"))
+		}
+		allErrs = append(allErrs, validateCustomWorkload(template.CustomWorkloadTemplate, fldPath.Child("customWorkloadTemplate"))...)
 	}
 
 	return allErrs
 }
 
+func validateCustomWorkload(workload *appsv1alpha1.CustomWorkloadTemplateSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if _, err := schema.ParseGroupVersion(workload.APIVersion); err != nil || workload.APIVersion == "" {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("apiVersion"), workload.APIVersion, "invalid apiVersion of custom workload"))
+	}
+	if workload.Kind == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("kind"), "kind of custom workload is required"))
+	}
+	if len(workload.Spec.Raw) > 0 {
+		spec := map[string]interface{}{}
+		if err := json.Unmarshal(workload.Spec.Raw, &spec); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("spec"), string(workload.Spec.Raw), fmt.Sprintf("spec must be an object: %v", err)))
+		}
+	}
+	return allErrs
+}
+
 func validateStatefulSet(statefulSet *appsv1alpha1.StatefulSetTemplateSpec, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 	if statefulSet.Spec.Replicas != nil {
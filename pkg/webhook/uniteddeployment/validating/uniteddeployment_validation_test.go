@@ -27,9 +27,15 @@ import (
 	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openkruise/kruise/pkg/util"
+	"github.com/openkruise/kruise/pkg/util/configuration"
 )
 
 func TestValidateUnitedDeployment(t *testing.T) {
@@ -1197,9 +1203,432 @@ func Test(t *testing.T) {
 	}
 }
 
+func TestValidateSubsetWeights(t *testing.T) {
+	cases := []struct {
+		name        string
+		replicas    *int32
+		weights     []int32
+		errorHappen bool
+	}{
+		{
+			name:     "valid weights",
+			replicas: pointer.Int32(10),
+			weights:  []int32{1, 2, 0},
+		},
+		{
+			name:        "weights not set for all subsets",
+			replicas:    pointer.Int32(10),
+			weights:     []int32{1, 2, -1},
+			errorHappen: true,
+		},
+		{
+			name:        "negative weight",
+			replicas:    pointer.Int32(10),
+			weights:     []int32{1, -2, 1},
+			errorHappen: true,
+		},
+		{
+			name:        "sum of weights is zero",
+			replicas:    pointer.Int32(10),
+			weights:     []int32{0, 0, 0},
+			errorHappen: true,
+		},
+		{
+			name:        "empty replicas",
+			weights:     []int32{1, 1, 1},
+			errorHappen: true,
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			var subsets []appsv1alpha1.Subset
+			for index, weight := range cs.weights {
+				subset := appsv1alpha1.Subset{Name: fmt.Sprintf("subset-%d", index)}
+				if weight != -1 {
+					subset.Weight = pointer.Int32(weight)
+				}
+				subsets = append(subsets, subset)
+			}
+			errList := validateSubsetReplicas(cs.replicas, subsets, field.NewPath("subset"))
+			if len(errList) > 0 && !cs.errorHappen {
+				t.Errorf("expected success, but got error: %v", errList)
+			} else if len(errList) == 0 && cs.errorHappen {
+				t.Errorf("expected error, but got success")
+			}
+		})
+	}
+}
+
+func TestValidateSubsetRollingUpdate(t *testing.T) {
+	cases := []struct {
+		name        string
+		deployment  bool
+		rolling     *appsv1alpha1.SubsetRollingUpdate
+		errorHappen bool
+	}{
+		{
+			name:    "valid strategy",
+			rolling: &appsv1alpha1.SubsetRollingUpdate{Order: []string{"subset-b", "subset-a"}, SoakSeconds: 30, ProgressDeadlineSeconds: pointer.Int32(60)},
+		},
+		{
+			name: "empty strategy",
+		},
+		{
+			name:        "unknown subset in order",
+			rolling:     &appsv1alpha1.SubsetRollingUpdate{Order: []string{"subset-c"}},
+			errorHappen: true,
+		},
+		{
+			name:        "duplicated subset in order",
+			rolling:     &appsv1alpha1.SubsetRollingUpdate{Order: []string{"subset-a", "subset-a"}},
+			errorHappen: true,
+		},
+		{
+			name:        "negative soak seconds",
+			rolling:     &appsv1alpha1.SubsetRollingUpdate{SoakSeconds: -1},
+			errorHappen: true,
+		},
+		{
+			name:        "zero progress deadline",
+			rolling:     &appsv1alpha1.SubsetRollingUpdate{ProgressDeadlineSeconds: pointer.Int32(0)},
+			errorHappen: true,
+		},
+		{
+			name:        "deployment template",
+			deployment:  true,
+			errorHappen: true,
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			spec := &appsv1alpha1.UnitedDeploymentSpec{
+				UpdateStrategy: appsv1alpha1.UnitedDeploymentUpdateStrategy{
+					Type:                appsv1alpha1.SubsetRollingUpdateStrategyType,
+					SubsetRollingUpdate: cs.rolling,
+				},
+			}
+			if cs.deployment {
+				spec.Template.DeploymentTemplate = &appsv1alpha1.DeploymentTemplateSpec{}
+			}
+			errList := validateSubsetRollingUpdate(spec, sets.NewString("subset-a", "subset-b"), field.NewPath("updateStrategy"))
+			if len(errList) > 0 && !cs.errorHappen {
+				t.Errorf("expected success, but got error: %v", errList)
+			} else if len(errList) == 0 && cs.errorHappen {
+				t.Errorf("expected error, but got success")
+			}
+		})
+	}
+}
+
 func setTestDefault(obj *appsv1alpha1.UnitedDeployment) {
 	if obj.Spec.RevisionHistoryLimit == nil {
 		obj.Spec.RevisionHistoryLimit = new(int32)
 		*obj.Spec.RevisionHistoryLimit = 10
 	}
 }
+
+func TestValidateSubsetDiscovery(t *testing.T) {
+	cases := []struct {
+		name        string
+		topology    appsv1alpha1.Topology
+		errorHappen bool
+	}{
+		{
+			name: "valid discovery",
+			topology: appsv1alpha1.Topology{SubsetDiscovery: &appsv1alpha1.SubsetDiscovery{
+				TopologyKey:  "topology.kubernetes.io/zone",
+				NodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "a"}},
+				Patch:        runtime.RawExtension{Raw: []byte(`{"metadata":{"labels":{"a":"b"}}}`)},
+			}},
+		},
+		{
+			name: "subsets declared",
+			topology: appsv1alpha1.Topology{
+				Subsets:         []appsv1alpha1.Subset{{Name: "subset-a"}},
+				SubsetDiscovery: &appsv1alpha1.SubsetDiscovery{TopologyKey: "topology.kubernetes.io/zone"},
+			},
+			errorHappen: true,
+		},
+		{
+			name:        "empty topology key",
+			topology:    appsv1alpha1.Topology{SubsetDiscovery: &appsv1alpha1.SubsetDiscovery{}},
+			errorHappen: true,
+		},
+		{
+			name:        "invalid topology key",
+			topology:    appsv1alpha1.Topology{SubsetDiscovery: &appsv1alpha1.SubsetDiscovery{TopologyKey: "a/b/c"}},
+			errorHappen: true,
+		},
+		{
+			name: "invalid patch",
+			topology: appsv1alpha1.Topology{SubsetDiscovery: &appsv1alpha1.SubsetDiscovery{
+				TopologyKey: "topology.kubernetes.io/zone",
+				Patch:       runtime.RawExtension{Raw: []byte(`[1]`)},
+			}},
+			errorHappen: true,
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			errList := validateSubsetDiscovery(&cs.topology, field.NewPath("topology"))
+			if len(errList) > 0 && !cs.errorHappen {
+				t.Errorf("expected success, but got error: %v", errList)
+			} else if len(errList) == 0 && cs.errorHappen {
+				t.Errorf("expected error, but got success")
+			}
+		})
+	}
+}
+
+func TestValidateSubsetOverrides(t *testing.T) {
+	template := &appsv1alpha1.SubsetTemplate{
+		StatefulSetTemplate: &appsv1alpha1.StatefulSetTemplateSpec{
+			Spec: apps.StatefulSetSpec{
+				Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "main"}}}},
+				VolumeClaimTemplates: []corev1.PersistentVolumeClaim{
+					{ObjectMeta: metav1.ObjectMeta{Name: "data"}},
+				},
+			},
+		},
+	}
+	deploymentTemplate := &appsv1alpha1.SubsetTemplate{
+		DeploymentTemplate: &appsv1alpha1.DeploymentTemplateSpec{
+			Spec: apps.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "main"}}}},
+			},
+		},
+	}
+
+	cases := []struct {
+		name        string
+		template    *appsv1alpha1.SubsetTemplate
+		overrides   *appsv1alpha1.SubsetOverrides
+		errorHappen bool
+	}{
+		{
+			name:     "valid overrides",
+			template: template,
+			overrides: &appsv1alpha1.SubsetOverrides{
+				Containers:           []appsv1alpha1.ContainerOverride{{Name: "main", Image: "nginx:2.0", Env: []corev1.EnvVar{{Name: "A", Value: "1"}}}},
+				Tolerations:          []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists}},
+				VolumeClaimTemplates: []appsv1alpha1.VolumeClaimTemplateOverride{{Name: "data", StorageClassName: pointer.String("fast")}},
+			},
+		},
+		{
+			name:        "container not found",
+			template:    template,
+			overrides:   &appsv1alpha1.SubsetOverrides{Containers: []appsv1alpha1.ContainerOverride{{Name: "sidecar"}}},
+			errorHappen: true,
+		},
+		{
+			name:        "invalid env name",
+			template:    template,
+			overrides:   &appsv1alpha1.SubsetOverrides{Containers: []appsv1alpha1.ContainerOverride{{Name: "main", Env: []corev1.EnvVar{{Name: "1=A"}}}}},
+			errorHappen: true,
+		},
+		{
+			name:     "invalid preferred weight",
+			template: template,
+			overrides: &appsv1alpha1.SubsetOverrides{NodeAffinity: &corev1.NodeAffinity{
+				PreferredDuringSchedulingIgnoredDuringExecution: []corev1.PreferredSchedulingTerm{{Weight: 0}},
+			}},
+			errorHappen: true,
+		},
+		{
+			name:        "volume claim template not found",
+			template:    template,
+			overrides:   &appsv1alpha1.SubsetOverrides{VolumeClaimTemplates: []appsv1alpha1.VolumeClaimTemplateOverride{{Name: "log"}}},
+			errorHappen: true,
+		},
+		{
+			name:        "volume claim templates of deployment",
+			template:    deploymentTemplate,
+			overrides:   &appsv1alpha1.SubsetOverrides{VolumeClaimTemplates: []appsv1alpha1.VolumeClaimTemplateOverride{{Name: "data"}}},
+			errorHappen: true,
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			errList := validateSubsetOverrides(cs.overrides, cs.template, field.NewPath("overrides"))
+			if len(errList) > 0 && !cs.errorHappen {
+				t.Errorf("expected success, but got error: %v", errList)
+			} else if len(errList) == 0 && cs.errorHappen {
+				t.Errorf("expected error, but got success")
+			}
+		})
+	}
+
+	oldTopology := &appsv1alpha1.Topology{Subsets: []appsv1alpha1.Subset{{Name: "subset-a"}}}
+	newTopology := &appsv1alpha1.Topology{Subsets: []appsv1alpha1.Subset{
+		{Name: "subset-a", Overrides: &appsv1alpha1.SubsetOverrides{VolumeClaimTemplates: []appsv1alpha1.VolumeClaimTemplateOverride{{Name: "data"}}}},
+		{Name: "subset-b", Overrides: &appsv1alpha1.SubsetOverrides{VolumeClaimTemplates: []appsv1alpha1.VolumeClaimTemplateOverride{{Name: "data"}}}},
+	}}
+	if errList := validateVolumeClaimTemplateOverridesUpdate(newTopology, oldTopology, field.NewPath("topology")); len(errList) != 1 {
+		t.Errorf("expected one error for changing volume claim templates of existing subset, got %v", errList)
+	}
+}
+
+func TestValidateSubsetFailover(t *testing.T) {
+	validMaxSurge := intstr.FromString("50%")
+	invalidMaxSurge := intstr.FromString("5")
+	negativeMaxSurge := intstr.FromInt(-1)
+	cases := []struct {
+		name        string
+		failover    *appsv1alpha1.SubsetFailoverStrategy
+		errorHappen bool
+	}{
+		{
+			name:     "default strategy",
+			failover: &appsv1alpha1.SubsetFailoverStrategy{},
+		},
+		{
+			name: "valid strategy",
+			failover: &appsv1alpha1.SubsetFailoverStrategy{UnhealthyPercent: pointer.Int32(60), UnhealthySeconds: pointer.Int32(0),
+				RecoveryPercent: pointer.Int32(100), RecoverySeconds: pointer.Int32(60)},
+		},
+		{
+			name:        "zero unhealthy percent",
+			failover:    &appsv1alpha1.SubsetFailoverStrategy{UnhealthyPercent: pointer.Int32(0)},
+			errorHappen: true,
+		},
+		{
+			name:        "recovery percent out of range",
+			failover:    &appsv1alpha1.SubsetFailoverStrategy{RecoveryPercent: pointer.Int32(101)},
+			errorHappen: true,
+		},
+		{
+			name:        "recovery percent less than unhealthy percent",
+			failover:    &appsv1alpha1.SubsetFailoverStrategy{UnhealthyPercent: pointer.Int32(80), RecoveryPercent: pointer.Int32(70)},
+			errorHappen: true,
+		},
+		{
+			name:        "recovery percent less than default unhealthy percent",
+			failover:    &appsv1alpha1.SubsetFailoverStrategy{RecoveryPercent: pointer.Int32(40)},
+			errorHappen: true,
+		},
+		{
+			name:        "negative unhealthy seconds",
+			failover:    &appsv1alpha1.SubsetFailoverStrategy{UnhealthySeconds: pointer.Int32(-1)},
+			errorHappen: true,
+		},
+		{
+			name:        "negative recovery seconds",
+			failover:    &appsv1alpha1.SubsetFailoverStrategy{RecoverySeconds: pointer.Int32(-1)},
+			errorHappen: true,
+		},
+		{
+			name:     "valid max surge",
+			failover: &appsv1alpha1.SubsetFailoverStrategy{MaxSurge: &validMaxSurge},
+		},
+		{
+			name:        "invalid max surge",
+			failover:    &appsv1alpha1.SubsetFailoverStrategy{MaxSurge: &invalidMaxSurge},
+			errorHappen: true,
+		},
+		{
+			name:        "negative max surge",
+			failover:    &appsv1alpha1.SubsetFailoverStrategy{MaxSurge: &negativeMaxSurge},
+			errorHappen: true,
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			errList := validateSubsetFailover(cs.failover, field.NewPath("failover"))
+			if len(errList) > 0 && !cs.errorHappen {
+				t.Errorf("expected success, but got error: %v", errList)
+			} else if len(errList) == 0 && cs.errorHappen {
+				t.Errorf("expected error, but got success")
+			}
+		})
+	}
+}
+
+func TestValidateUpdateStrategyTypeUpdate(t *testing.T) {
+	cases := []struct {
+		name        string
+		oldType     appsv1alpha1.UpdateStrategyType
+		newType     appsv1alpha1.UpdateStrategyType
+		errorHappen bool
+	}{
+		{
+			name:    "unknown type unchanged",
+			oldType: "Unknown",
+			newType: "Unknown",
+		},
+		{
+			name:    "change to valid type",
+			oldType: "Unknown",
+			newType: appsv1alpha1.ManualUpdateStrategyType,
+		},
+		{
+			name:        "change to unknown type",
+			oldType:     appsv1alpha1.ManualUpdateStrategyType,
+			newType:     "Unknown",
+			errorHappen: true,
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			spec := &appsv1alpha1.UnitedDeploymentSpec{UpdateStrategy: appsv1alpha1.UnitedDeploymentUpdateStrategy{Type: cs.newType}}
+			oldSpec := &appsv1alpha1.UnitedDeploymentSpec{UpdateStrategy: appsv1alpha1.UnitedDeploymentUpdateStrategy{Type: cs.oldType}}
+			errList := validateUnitedDeploymentSpecUpdate(spec, oldSpec, field.NewPath("spec"))
+			if len(errList) > 0 && !cs.errorHappen {
+				t.Errorf("expected success, but got error: %v", errList)
+			} else if len(errList) == 0 && cs.errorHappen {
+				t.Errorf("expected error, but got success")
+			}
+		})
+	}
+}
+
+func TestValidateCustomWorkloadRolling(t *testing.T) {
+	whiteList := `{"workloads":[{"Group":"example.io","Version":"v1","Kind":"Foo","partitionPath":"spec.partition"},` +
+		`{"Group":"example.io","Version":"v1","Kind":"Bar"}]}`
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: util.GetKruiseNamespace(), Name: configuration.KruiseConfigurationName},
+		Data:       map[string]string{configuration.UDWatchCustomWorkloadWhiteList: whiteList},
+	}
+	handler := &UnitedDeploymentCreateUpdateHandler{Client: fake.NewClientBuilder().WithObjects(cm).Build()}
+
+	cases := []struct {
+		name         string
+		kind         string
+		strategyType appsv1alpha1.UpdateStrategyType
+		allowed      bool
+	}{
+		{
+			name:         "custom workload with partitionPath",
+			kind:         "Foo",
+			strategyType: appsv1alpha1.SubsetRollingUpdateStrategyType,
+			allowed:      true,
+		},
+		{
+			name:         "custom workload without partitionPath",
+			kind:         "Bar",
+			strategyType: appsv1alpha1.SubsetRollingUpdateStrategyType,
+		},
+		{
+			name:         "manual update of custom workload without partitionPath",
+			kind:         "Bar",
+			strategyType: appsv1alpha1.ManualUpdateStrategyType,
+			allowed:      true,
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			ud := &appsv1alpha1.UnitedDeployment{}
+			ud.Spec.UpdateStrategy.Type = cs.strategyType
+			ud.Spec.Template.CustomWorkloadTemplate = &appsv1alpha1.CustomWorkloadTemplateSpec{APIVersion: "example.io/v1", Kind: cs.kind}
+			if resp := handler.validateCustomWorkloadRolling(ud); resp.Allowed != cs.allowed {
+				t.Errorf("expected allowed %v, but got %v: %v", cs.allowed, resp.Allowed, resp.Result)
+			}
+		})
+	}
+}
@@ -29,7 +29,10 @@ var (
 	// HandlerGetterMap contains admission webhook handlers
 	HandlerGetterMap = map[string]types.HandlerGetter{
 		"validate-apps-kruise-io-v1alpha1-uniteddeployment": func(mgr manager.Manager) admission.Handler {
-			return &UnitedDeploymentCreateUpdateHandler{Decoder: admission.NewDecoder(mgr.GetScheme())}
+			return &UnitedDeploymentCreateUpdateHandler{
+				Client:  mgr.GetClient(),
+				Decoder: admission.NewDecoder(mgr.GetScheme()),
+			}
 		},
 	}
 )
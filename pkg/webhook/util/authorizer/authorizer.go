@@ -0,0 +1,96 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authorizer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Authorizer authenticates and authorizes the requests to the non-admission endpoints on webhook server,
+// which are not protected by the apiserver.
+type Authorizer interface {
+	// Authorize returns http.StatusOK if the requester of r is allowed to do the action described by attrs,
+	// otherwise it returns the http status code to respond and the error.
+	Authorize(ctx context.Context, r *http.Request, attrs authorizationv1.ResourceAttributes) (int, error)
+}
+
+// New returns an Authorizer that authenticates the bearer token of the request with TokenReview,
+// and authorizes the user with SubjectAccessReview.
+func New(kubeClient kubernetes.Interface) Authorizer {
+	return &delegatingAuthorizer{kubeClient: kubeClient}
+}
+
+type delegatingAuthorizer struct {
+	kubeClient kubernetes.Interface
+}
+
+func (a *delegatingAuthorizer) Authorize(ctx context.Context, r *http.Request, attrs authorizationv1.ResourceAttributes) (int, error) {
+	token, ok := getBearerToken(r)
+	if !ok {
+		return http.StatusUnauthorized, fmt.Errorf("bearer token is required")
+	}
+	tokenReview, err := a.kubeClient.AuthenticationV1().TokenReviews().Create(ctx, &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{Token: token},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to review token: %v", err)
+	}
+	if !tokenReview.Status.Authenticated {
+		return http.StatusUnauthorized, fmt.Errorf("token is not authenticated: %s", tokenReview.Status.Error)
+	}
+
+	user := tokenReview.Status.User
+	extra := make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+	for k, v := range user.Extra {
+		extra[k] = authorizationv1.ExtraValue(v)
+	}
+	sar, err := a.kubeClient.AuthorizationV1().SubjectAccessReviews().Create(ctx, &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			ResourceAttributes: &attrs,
+			User:               user.Username,
+			Groups:             user.Groups,
+			UID:                user.UID,
+			Extra:              extra,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to review subject access: %v", err)
+	}
+	if !sar.Status.Allowed {
+		return http.StatusForbidden, fmt.Errorf("user %q cannot %s resource %q in API group %q in namespace %q",
+			user.Username, attrs.Verb, attrs.Resource, attrs.Group, attrs.Namespace)
+	}
+	return http.StatusOK, nil
+}
+
+func getBearerToken(r *http.Request) (string, bool) {
+	auth := strings.TrimSpace(r.Header.Get("Authorization"))
+	parts := strings.SplitN(auth, " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "bearer") {
+		return "", false
+	}
+	token := strings.TrimSpace(parts[1])
+	return token, len(token) > 0
+}
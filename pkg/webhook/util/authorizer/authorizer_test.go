@@ -0,0 +1,95 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authorizer
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func TestAuthorize(t *testing.T) {
+	attrs := authorizationv1.ResourceAttributes{Group: "apps.kruise.io", Resource: "sidecarsets", Verb: "preview"}
+	cases := []struct {
+		name       string
+		header     string
+		allowed    bool
+		expectCode int
+	}{
+		{
+			name:       "missing bearer token",
+			expectCode: http.StatusUnauthorized,
+		},
+		{
+			name:       "token is not authenticated",
+			header:     "Bearer invalid-token",
+			expectCode: http.StatusUnauthorized,
+		},
+		{
+			name:       "user is not allowed",
+			header:     "Bearer valid-token",
+			expectCode: http.StatusForbidden,
+		},
+		{
+			name:       "user is allowed",
+			header:     "Bearer valid-token",
+			allowed:    true,
+			expectCode: http.StatusOK,
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			kubeClient := fake.NewSimpleClientset()
+			kubeClient.PrependReactor("create", "tokenreviews", func(action clienttesting.Action) (bool, runtime.Object, error) {
+				review := action.(clienttesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+				if review.Spec.Token == "valid-token" {
+					review.Status.Authenticated = true
+					review.Status.User = authenticationv1.UserInfo{Username: "alice", Groups: []string{"dev"}}
+				}
+				return true, review, nil
+			})
+			kubeClient.PrependReactor("create", "subjectaccessreviews", func(action clienttesting.Action) (bool, runtime.Object, error) {
+				sar := action.(clienttesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+				if sar.Spec.User != "alice" || *sar.Spec.ResourceAttributes != attrs {
+					t.Fatalf("unexpected subject access review %+v", sar.Spec)
+				}
+				sar.Status.Allowed = cs.allowed
+				return true, sar, nil
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/test", nil)
+			if cs.header != "" {
+				req.Header.Set("Authorization", cs.header)
+			}
+			code, err := New(kubeClient).Authorize(context.TODO(), req, attrs)
+			if code != cs.expectCode {
+				t.Fatalf("expect code %d, but got %d: %v", cs.expectCode, code, err)
+			}
+			if (err == nil) != (cs.expectCode == http.StatusOK) {
+				t.Fatalf("unexpected error %v", err)
+			}
+		})
+	}
+}
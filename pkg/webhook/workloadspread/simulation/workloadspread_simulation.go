@@ -0,0 +1,193 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+	extclient "github.com/openkruise/kruise/pkg/client"
+	wscontroller "github.com/openkruise/kruise/pkg/controller/workloadspread"
+	wsutil "github.com/openkruise/kruise/pkg/util/workloadspread"
+	"github.com/openkruise/kruise/pkg/webhook/types"
+	"github.com/openkruise/kruise/pkg/webhook/util/authorizer"
+)
+
+const (
+	// WorkloadSpreadSimulationPath is the path of endpoint to simulate the scaling of workload managed by WorkloadSpread.
+	WorkloadSpreadSimulationPath = "/workloadspread-simulation"
+	// WorkloadSpreadSimulateVerb is the verb on workloadspreads that the requester must be allowed to simulate the scaling.
+	WorkloadSpreadSimulateVerb = "simulate"
+)
+
+var (
+	// HTTPHandlerGetterMap contains the non-admission handlers on webhook server
+	HTTPHandlerGetterMap = map[string]types.HTTPHandlerGetter{
+		WorkloadSpreadSimulationPath: func(mgr manager.Manager) http.Handler {
+			return &WorkloadSpreadSimulationHandler{
+				Client:     mgr.GetClient(),
+				Authorizer: authorizer.New(extclient.GetGenericClientWithName("workloadspread-simulation").KubeClient),
+			}
+		},
+	}
+)
+
+// WorkloadSpreadSimulation is the result of simulating the scaling of workload managed by WorkloadSpread.
+type WorkloadSpreadSimulation struct {
+	// Replicas is the current replicas of the workload
+	Replicas int32 `json:"replicas"`
+	// Delta is the change of workload replicas in the simulation
+	Delta int32 `json:"delta"`
+	// SubsetChanges is the change of replicas of each subset, the Pods not belonging to any subset are not counted
+	SubsetChanges map[string]int32 `json:"subsetChanges"`
+	// CreatedPods are the Pods that would be created on scale-out, in creation order
+	CreatedPods []SimulatedPodCreation `json:"createdPods,omitempty"`
+	// DeletedPods are the Pods that would be deleted on scale-in, in deletion order
+	DeletedPods []SimulatedPodDeletion `json:"deletedPods,omitempty"`
+}
+
+// SimulatedPodCreation is a Pod that would be created on scale-out.
+type SimulatedPodCreation struct {
+	Index int32 `json:"index"`
+	// Subset is the subset that the Pod would be injected into, or empty if there is no suitable subset
+	Subset string `json:"subset,omitempty"`
+}
+
+// SimulatedPodDeletion is a Pod that would be deleted on scale-in.
+type SimulatedPodDeletion struct {
+	Name string `json:"name"`
+	// Subset is the subset that the Pod belongs to, or empty if the Pod doesn't belong to any subset
+	Subset string `json:"subset,omitempty"`
+	// DeletionCost is the deletion-cost that the WorkloadSpread controller expects for the Pod
+	DeletionCost int `json:"deletionCost"`
+}
+
+// WorkloadSpreadSimulationHandler simulates which subsets the next Pods of a workload would be injected into on
+// scale-out, and which Pods would be deleted first on scale-in, without changing anything.
+// The requester must be allowed to simulate the WorkloadSpread, e.g. with the rule:
+// {apiGroups: ["apps.kruise.io"], resources: ["workloadspreads"], verbs: ["simulate"]}.
+type WorkloadSpreadSimulationHandler struct {
+	Client     client.Client
+	Authorizer authorizer.Authorizer
+}
+
+var _ http.Handler = &WorkloadSpreadSimulationHandler{}
+
+func (h *WorkloadSpreadSimulationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "only GET method is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	query := r.URL.Query()
+	key := k8stypes.NamespacedName{Namespace: query.Get("namespace"), Name: query.Get("name")}
+	if key.Namespace == "" || key.Name == "" {
+		http.Error(w, "namespace and name of WorkloadSpread are required", http.StatusBadRequest)
+		return
+	}
+	if code, err := h.Authorizer.Authorize(r.Context(), r, authorizationv1.ResourceAttributes{
+		Namespace: key.Namespace,
+		Name:      key.Name,
+		Group:     appsv1alpha1.GroupVersion.Group,
+		Resource:  "workloadspreads",
+		Verb:      WorkloadSpreadSimulateVerb,
+	}); err != nil {
+		http.Error(w, err.Error(), code)
+		return
+	}
+	delta, err := strconv.ParseInt(query.Get("delta"), 10, 32)
+	if err != nil || delta == 0 {
+		http.Error(w, fmt.Sprintf("delta must be a non-zero integer, got %q", query.Get("delta")), http.StatusBadRequest)
+		return
+	}
+
+	ws := &appsv1alpha1.WorkloadSpread{}
+	if err = h.Client.Get(r.Context(), key, ws); err != nil {
+		if apierrors.IsNotFound(err) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if ws.Spec.TargetReference == nil {
+		http.Error(w, "WorkloadSpread has no targetReference", http.StatusBadRequest)
+		return
+	}
+
+	simulation, err := h.simulate(ws, int32(delta))
+	if err != nil {
+		if errors.Is(err, wscontroller.ErrScaleInSimulationNotSupported) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		klog.ErrorS(err, "Failed to simulate WorkloadSpread", "workloadSpread", klog.KObj(ws), "delta", delta)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(simulation)
+}
+
+func (h *WorkloadSpreadSimulationHandler) simulate(ws *appsv1alpha1.WorkloadSpread, delta int32) (*WorkloadSpreadSimulation, error) {
+	simulation := &WorkloadSpreadSimulation{Delta: delta, SubsetChanges: map[string]int32{}}
+	for _, subset := range ws.Spec.Subsets {
+		simulation.SubsetChanges[subset.Name] = 0
+	}
+
+	if delta > 0 {
+		replicas, subsets, err := wsutil.NewWorkloadSpreadHandler(h.Client).SimulatePodCreations(ws, delta)
+		if err != nil {
+			return nil, err
+		}
+		simulation.Replicas = replicas
+		for i, subset := range subsets {
+			simulation.CreatedPods = append(simulation.CreatedPods, SimulatedPodCreation{Index: int32(i), Subset: subset})
+			if subset != "" {
+				simulation.SubsetChanges[subset]++
+			}
+		}
+		return simulation, nil
+	}
+
+	deletions, replicas, err := wscontroller.SimulatePodDeletions(h.Client, ws, int(-delta))
+	if err != nil {
+		return nil, err
+	}
+	simulation.Replicas = replicas
+	for _, deletion := range deletions {
+		simulation.DeletedPods = append(simulation.DeletedPods, SimulatedPodDeletion{
+			Name:         deletion.Pod.Name,
+			Subset:       deletion.Subset,
+			DeletionCost: deletion.DeletionCost,
+		})
+		if deletion.Subset != "" {
+			simulation.SubsetChanges[deletion.Subset]--
+		}
+	}
+	return simulation, nil
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+	wsutil "github.com/openkruise/kruise/pkg/util/workloadspread"
+)
+
+func TestWorkloadSpreadSimulation(t *testing.T) {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(appsv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(appsv1.AddToScheme(scheme))
+	cloneSet := &appsv1alpha1.CloneSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "cloneset-test", Namespace: "default"},
+		Spec:       appsv1alpha1.CloneSetSpec{Replicas: ptr.To(int32(2))},
+	}
+	ws := &appsv1alpha1.WorkloadSpread{
+		ObjectMeta: metav1.ObjectMeta{Name: "ws-test", Namespace: "default"},
+		Spec: appsv1alpha1.WorkloadSpreadSpec{
+			TargetReference: &appsv1alpha1.TargetReference{APIVersion: "apps.kruise.io/v1alpha1", Kind: "CloneSet", Name: "cloneset-test"},
+			Subsets: []appsv1alpha1.WorkloadSpreadSubset{
+				{Name: "subset-a", MaxReplicas: ptr.To(intstr.FromInt32(3))},
+				{Name: "subset-b"},
+			},
+		},
+		Status: appsv1alpha1.WorkloadSpreadStatus{
+			SubsetStatuses: []appsv1alpha1.WorkloadSpreadSubsetStatus{
+				{Name: "subset-a", Replicas: 2, MissingReplicas: 1},
+				{Name: "subset-b", MissingReplicas: -1},
+			},
+		},
+	}
+	ws.Status.VersionedSubsetStatuses = map[string][]appsv1alpha1.WorkloadSpreadSubsetStatus{wsutil.VersionIgnored: ws.Status.SubsetStatuses}
+	stsWS := ws.DeepCopy()
+	stsWS.Name = "ws-sts"
+	stsWS.Spec.TargetReference = &appsv1alpha1.TargetReference{APIVersion: "apps/v1", Kind: "StatefulSet", Name: "sts-test"}
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "sts-test", Namespace: "default"},
+		Spec:       appsv1.StatefulSetSpec{Replicas: ptr.To(int32(2))},
+	}
+	authz := &fakeAuthorizer{}
+	handler := &WorkloadSpreadSimulationHandler{
+		Client:     fake.NewClientBuilder().WithScheme(scheme).WithObjects(cloneSet, ws, stsWS, sts).Build(),
+		Authorizer: authz,
+	}
+
+	cases := []struct {
+		name             string
+		method           string
+		url              string
+		forbidden        bool
+		expectCode       int
+		expectSimulation *WorkloadSpreadSimulation
+	}{
+		{
+			name:       "method not allowed",
+			method:     http.MethodPost,
+			url:        WorkloadSpreadSimulationPath + "?namespace=default&name=ws-test&delta=1",
+			expectCode: http.StatusMethodNotAllowed,
+		},
+		{
+			name:       "name is required",
+			method:     http.MethodGet,
+			url:        WorkloadSpreadSimulationPath + "?namespace=default&delta=1",
+			expectCode: http.StatusBadRequest,
+		},
+		{
+			name:       "requester is not allowed to simulate",
+			method:     http.MethodGet,
+			url:        WorkloadSpreadSimulationPath + "?namespace=default&name=ws-test&delta=1",
+			forbidden:  true,
+			expectCode: http.StatusForbidden,
+		},
+		{
+			name:       "delta is invalid",
+			method:     http.MethodGet,
+			url:        WorkloadSpreadSimulationPath + "?namespace=default&name=ws-test&delta=0",
+			expectCode: http.StatusBadRequest,
+		},
+		{
+			name:       "workloadSpread not found",
+			method:     http.MethodGet,
+			url:        WorkloadSpreadSimulationPath + "?namespace=default&name=not-found&delta=1",
+			expectCode: http.StatusNotFound,
+		},
+		{
+			name:       "scale-in is not supported for StatefulSet",
+			method:     http.MethodGet,
+			url:        WorkloadSpreadSimulationPath + "?namespace=default&name=ws-sts&delta=-1",
+			expectCode: http.StatusBadRequest,
+		},
+		{
+			name:       "scale out",
+			method:     http.MethodGet,
+			url:        WorkloadSpreadSimulationPath + "?namespace=default&name=ws-test&delta=3",
+			expectCode: http.StatusOK,
+			expectSimulation: &WorkloadSpreadSimulation{
+				Replicas:      2,
+				Delta:         3,
+				SubsetChanges: map[string]int32{"subset-a": 1, "subset-b": 2},
+				CreatedPods: []SimulatedPodCreation{
+					{Index: 0, Subset: "subset-a"},
+					{Index: 1, Subset: "subset-b"},
+					{Index: 2, Subset: "subset-b"},
+				},
+			},
+		},
+		{
+			name:       "scale out StatefulSet by ordinals",
+			method:     http.MethodGet,
+			url:        WorkloadSpreadSimulationPath + "?namespace=default&name=ws-sts&delta=2",
+			expectCode: http.StatusOK,
+			expectSimulation: &WorkloadSpreadSimulation{
+				Replicas:      2,
+				Delta:         2,
+				SubsetChanges: map[string]int32{"subset-a": 1, "subset-b": 1},
+				CreatedPods: []SimulatedPodCreation{
+					{Index: 0, Subset: "subset-a"},
+					{Index: 1, Subset: "subset-b"},
+				},
+			},
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			authz.code, authz.err = http.StatusOK, nil
+			if cs.forbidden {
+				authz.code, authz.err = http.StatusForbidden, fmt.Errorf("forbidden")
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(cs.method, cs.url, nil))
+			if recorder.Code != cs.expectCode {
+				t.Fatalf("expect status %d, but got %d: %s", cs.expectCode, recorder.Code, recorder.Body.String())
+			}
+			if cs.forbidden {
+				expectAttrs := authorizationv1.ResourceAttributes{Namespace: "default", Name: "ws-test",
+					Group: appsv1alpha1.GroupVersion.Group, Resource: "workloadspreads", Verb: WorkloadSpreadSimulateVerb}
+				if authz.attrs != expectAttrs {
+					t.Fatalf("expect authorized attributes %+v, but got %+v", expectAttrs, authz.attrs)
+				}
+			}
+			if cs.expectSimulation == nil {
+				return
+			}
+			simulation := &WorkloadSpreadSimulation{}
+			if err := json.Unmarshal(recorder.Body.Bytes(), simulation); err != nil {
+				t.Fatalf("failed to decode simulation: %s", err.Error())
+			}
+			if !reflect.DeepEqual(simulation, cs.expectSimulation) {
+				t.Fatalf("expect simulation %+v, but got %+v", cs.expectSimulation, simulation)
+			}
+		})
+	}
+}
+
+type fakeAuthorizer struct {
+	code  int
+	err   error
+	attrs authorizationv1.ResourceAttributes
+}
+
+func (a *fakeAuthorizer) Authorize(_ context.Context, _ *http.Request, attrs authorizationv1.ResourceAttributes) (int, error) {
+	a.attrs = attrs
+	return a.code, a.err
+}
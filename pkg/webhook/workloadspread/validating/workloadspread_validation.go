@@ -57,6 +57,7 @@ var (
 	controllerKindRS             = appsv1.SchemeGroupVersion.WithKind("ReplicaSet")
 	controllerKindDep            = appsv1.SchemeGroupVersion.WithKind("Deployment")
 	controllerKindJob            = batchv1.SchemeGroupVersion.WithKind("Job")
+	controllerKruiseKindBJ       = appsv1alpha1.SchemeGroupVersion.WithKind("BroadcastJob")
 	controllerKruiseKindBetaSts  = appsvbeta1.SchemeGroupVersion.WithKind("StatefulSet")
 	controllerKruiseKindAlphaSts = appsv1alpha1.SchemeGroupVersion.WithKind("StatefulSet")
 )
@@ -149,6 +150,16 @@ func validateWorkloadSpreadSpec(h *WorkloadSpreadCreateUpdateHandler, obj *appsv
 						workloadTemplate = set
 					}
 				}
+			case controllerKruiseKindBJ.Kind:
+				ok, err := verifyGroupKind(spec.TargetReference, controllerKruiseKindBJ.Kind, []string{controllerKruiseKindBJ.Group})
+				if !ok || err != nil {
+					allErrs = append(allErrs, field.Invalid(fldPath.Child("targetRef"), spec.TargetReference, "TargetReference is not valid for BroadcastJob."))
+				} else {
+					set := &appsv1alpha1.BroadcastJob{}
+					if getErr := h.Client.Get(context.TODO(), client.ObjectKey{Name: spec.TargetReference.Name, Namespace: obj.Namespace}, set); getErr == nil {
+						workloadTemplate = set
+					}
+				}
 			case controllerKindSts.Kind:
 				ok, err := verifyGroupKind(spec.TargetReference, controllerKindSts.Kind, []string{controllerKindSts.Group, controllerKruiseKindAlphaSts.Group, controllerKruiseKindBetaSts.Group})
 				if !ok || err != nil {
@@ -215,6 +226,18 @@ func validateWorkloadSpreadSpec(h *WorkloadSpreadCreateUpdateHandler, obj *appsv
 		}
 	}
 
+	if spec.ScheduleStrategy.Adaptive != nil && spec.ScheduleStrategy.Adaptive.MigrateBack != nil {
+		allErrs = append(allErrs, validateWorkloadSpreadMigrateBack(spec, fldPath.Child("scheduleStrategy").Child("adaptive").Child("migrateBack"))...)
+	}
+
+	if spec.ScheduleStrategy.Rebalance != nil {
+		allErrs = append(allErrs, validateWorkloadSpreadRebalance(spec.ScheduleStrategy.Rebalance, spec.TargetReference, fldPath.Child("scheduleStrategy").Child("rebalance"))...)
+	}
+
+	if spec.TargetReference != nil && spec.TargetReference.Kind == controllerKruiseKindBJ.Kind {
+		allErrs = append(allErrs, validateWorkloadSpreadForBroadcastJob(spec, fldPath)...)
+	}
+
 	// validate targetFilter
 	if spec.TargetFilter != nil {
 		if _, err := metav1.LabelSelectorAsSelector(spec.TargetFilter.Selector); err != nil {
@@ -225,6 +248,63 @@ func validateWorkloadSpreadSpec(h *WorkloadSpreadCreateUpdateHandler, obj *appsv
 	return allErrs
 }
 
+// validateWorkloadSpreadForBroadcastJob rejects the strategies which can not work with BroadcastJob, whose Pods are
+// pinned to nodes, so that the subset of each Pod is decided by its node.
+func validateWorkloadSpreadForBroadcastJob(spec *appsv1alpha1.WorkloadSpreadSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if spec.ScheduleStrategy.Type == appsv1alpha1.AdaptiveWorkloadSpreadScheduleStrategyType {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("scheduleStrategy").Child("type"),
+			spec.ScheduleStrategy.Type, "adaptive scheduleStrategy is not supported for BroadcastJob"))
+	}
+	if spec.IsWeighted() {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("subsets"), spec.Subsets, "weighted subsets are not supported for BroadcastJob"))
+	}
+	return allErrs
+}
+
+func validateWorkloadSpreadMigrateBack(spec *appsv1alpha1.WorkloadSpreadSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	migrateBack := spec.ScheduleStrategy.Adaptive.MigrateBack
+	if spec.TargetReference != nil {
+		switch spec.TargetReference.Kind {
+		case controllerKruiseKindCS.Kind, controllerKindDep.Kind, controllerKindRS.Kind:
+		default:
+			allErrs = append(allErrs, field.Invalid(fldPath, migrateBack, fmt.Sprintf("migrateBack is not supported for %s", spec.TargetReference.Kind)))
+		}
+	}
+	if spec.ScheduleStrategy.Adaptive.RescheduleCriticalSeconds == nil || *spec.ScheduleStrategy.Adaptive.RescheduleCriticalSeconds <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath, migrateBack, "rescheduleCriticalSeconds must be set when using migrateBack"))
+	}
+	if spec.IsWeighted() {
+		allErrs = append(allErrs, field.Invalid(fldPath, migrateBack, "migrateBack is not supported for weighted subsets"))
+	}
+	if migrateBack.MaxMigrationsPerInterval != nil && *migrateBack.MaxMigrationsPerInterval < 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxMigrationsPerInterval"), *migrateBack.MaxMigrationsPerInterval, "maxMigrationsPerInterval must be greater than 0"))
+	}
+	if migrateBack.IntervalSeconds != nil && *migrateBack.IntervalSeconds < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("intervalSeconds"), *migrateBack.IntervalSeconds, "intervalSeconds must not be negative"))
+	}
+	return allErrs
+}
+
+func validateWorkloadSpreadRebalance(rebalance *appsv1alpha1.WorkloadSpreadRebalanceStrategy, targetRef *appsv1alpha1.TargetReference, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if targetRef != nil {
+		switch targetRef.Kind {
+		case controllerKruiseKindCS.Kind, controllerKindDep.Kind, controllerKindRS.Kind:
+		default:
+			allErrs = append(allErrs, field.Invalid(fldPath, rebalance, fmt.Sprintf("rebalance is not supported for %s", targetRef.Kind)))
+		}
+	}
+	if rebalance.MaxEvictionsPerInterval != nil && *rebalance.MaxEvictionsPerInterval < 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxEvictionsPerInterval"), *rebalance.MaxEvictionsPerInterval, "maxEvictionsPerInterval must be greater than 0"))
+	}
+	if rebalance.IntervalSeconds != nil && *rebalance.IntervalSeconds < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("intervalSeconds"), *rebalance.IntervalSeconds, "intervalSeconds must not be negative"))
+	}
+	return allErrs
+}
+
 func validateWorkloadSpreadSubsets(ws *appsv1alpha1.WorkloadSpread, subsets []appsv1alpha1.WorkloadSpreadSubset, workloadTemplate client.Object, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
@@ -241,6 +321,7 @@ func validateWorkloadSpreadSubsets(ws *appsv1alpha1.WorkloadSpread, subsets []ap
 	subSetNames := sets.String{}
 	maxReplicasSum := 0
 	var firstMaxReplicasType *intstr.Type
+	countWeights := 0
 
 	for i, subset := range subsets {
 		subsetName := subset.Name
@@ -310,6 +391,8 @@ func validateWorkloadSpreadSubsets(ws *appsv1alpha1.WorkloadSpread, subsets []ap
 					podSpec = workloadTemplate.(*appsv1.ReplicaSet).Spec.Template
 				case controllerKindJob:
 					podSpec = workloadTemplate.(*batchv1.Job).Spec.Template
+				case controllerKruiseKindBJ:
+					podSpec = workloadTemplate.(*appsv1alpha1.BroadcastJob).Spec.Template
 				case controllerKindSts:
 					sts := workloadTemplate.(*appsv1.StatefulSet)
 					podSpec = withVolumeClaimTemplates(sts.Spec.Template, sts.Spec.VolumeClaimTemplates)
@@ -331,6 +414,16 @@ func validateWorkloadSpreadSubsets(ws *appsv1alpha1.WorkloadSpread, subsets []ap
 			}
 		}
 
+		if subset.Weight != nil {
+			countWeights++
+			if *subset.Weight < 1 {
+				allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("weight"), *subset.Weight, "weight must be greater than 0"))
+			}
+			if subset.MaxReplicas != nil {
+				allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("maxReplicas"), subset.MaxReplicas, "weight and maxReplicas are mutually exclusive"))
+			}
+		}
+
 		//1. All subset maxReplicas must be the same type: int or percent.
 		//2. Adaptive: the last subset must be not specified.
 		//3. If all maxReplicas is specified as percent, the total maxReplicas must equal 1, except the last subset is not specified.
@@ -363,6 +456,10 @@ func validateWorkloadSpreadSubsets(ws *appsv1alpha1.WorkloadSpread, subsets []ap
 		}
 	}
 
+	if countWeights > 0 && countWeights != len(subsets) {
+		allErrs = append(allErrs, field.Invalid(fldPath, countWeights, "weights must be set for all subsets or none of them"))
+	}
+
 	if firstMaxReplicasType != nil && *firstMaxReplicasType == intstr.String && maxReplicasSum < 100 && subsets[len(subsets)-1].MaxReplicas != nil {
 		allErrs = append(allErrs, field.Invalid(fldPath.Index(0).Child("maxReplicas"), subsets[0].MaxReplicas, "maxReplicas sum of all subsets must equal 100% when type is specified as percent"))
 	}
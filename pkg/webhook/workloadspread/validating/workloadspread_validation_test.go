@@ -1114,3 +1114,253 @@ func Test_validateWorkloadSpreadSubsets(t *testing.T) {
 		})
 	}
 }
+
+func Test_validateWorkloadSpreadSubsetWeights(t *testing.T) {
+	maxReplicas := intstr.FromInt32(3)
+	cases := []struct {
+		name        string
+		subsets     []appsv1alpha1.WorkloadSpreadSubset
+		errorHappen bool
+	}{
+		{
+			name: "weights of all subsets",
+			subsets: []appsv1alpha1.WorkloadSpreadSubset{
+				{Name: "subset-a", Weight: ptr.To(int32(1))},
+				{Name: "subset-b", Weight: ptr.To(int32(2))},
+			},
+		},
+		{
+			name: "weight of part of subsets",
+			subsets: []appsv1alpha1.WorkloadSpreadSubset{
+				{Name: "subset-a", Weight: ptr.To(int32(1))},
+				{Name: "subset-b"},
+			},
+			errorHappen: true,
+		},
+		{
+			name: "zero weight",
+			subsets: []appsv1alpha1.WorkloadSpreadSubset{
+				{Name: "subset-a", Weight: ptr.To(int32(0))},
+				{Name: "subset-b", Weight: ptr.To(int32(1))},
+			},
+			errorHappen: true,
+		},
+		{
+			name: "weight together with maxReplicas",
+			subsets: []appsv1alpha1.WorkloadSpreadSubset{
+				{Name: "subset-a", Weight: ptr.To(int32(1)), MaxReplicas: &maxReplicas},
+				{Name: "subset-b", Weight: ptr.To(int32(1))},
+			},
+			errorHappen: true,
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			ws := &appsv1alpha1.WorkloadSpread{Spec: appsv1alpha1.WorkloadSpreadSpec{Subsets: cs.subsets}}
+			errList := validateWorkloadSpreadSubsets(ws, ws.Spec.Subsets, nil, field.NewPath("spec").Child("subsets"))
+			if len(errList) > 0 && !cs.errorHappen {
+				t.Fatalf("expected success, but got error: %v", errList)
+			} else if len(errList) == 0 && cs.errorHappen {
+				t.Fatalf("expected error, but got success")
+			}
+		})
+	}
+}
+
+func Test_validateWorkloadSpreadRebalance(t *testing.T) {
+	cases := []struct {
+		name        string
+		kind        string
+		rebalance   *appsv1alpha1.WorkloadSpreadRebalanceStrategy
+		errorHappen bool
+	}{
+		{
+			name:      "default rebalance for CloneSet",
+			kind:      "CloneSet",
+			rebalance: &appsv1alpha1.WorkloadSpreadRebalanceStrategy{},
+		},
+		{
+			name:      "valid rebalance for Deployment",
+			kind:      "Deployment",
+			rebalance: &appsv1alpha1.WorkloadSpreadRebalanceStrategy{MaxEvictionsPerInterval: ptr.To(int32(2)), IntervalSeconds: ptr.To(int32(0))},
+		},
+		{
+			name:        "rebalance for StatefulSet",
+			kind:        "StatefulSet",
+			rebalance:   &appsv1alpha1.WorkloadSpreadRebalanceStrategy{},
+			errorHappen: true,
+		},
+		{
+			name:        "rebalance for Job",
+			kind:        "Job",
+			rebalance:   &appsv1alpha1.WorkloadSpreadRebalanceStrategy{},
+			errorHappen: true,
+		},
+		{
+			name:        "zero max evictions",
+			kind:        "ReplicaSet",
+			rebalance:   &appsv1alpha1.WorkloadSpreadRebalanceStrategy{MaxEvictionsPerInterval: ptr.To(int32(0))},
+			errorHappen: true,
+		},
+		{
+			name:        "negative interval",
+			kind:        "ReplicaSet",
+			rebalance:   &appsv1alpha1.WorkloadSpreadRebalanceStrategy{IntervalSeconds: ptr.To(int32(-1))},
+			errorHappen: true,
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			targetRef := &appsv1alpha1.TargetReference{Kind: cs.kind, Name: "test"}
+			errList := validateWorkloadSpreadRebalance(cs.rebalance, targetRef, field.NewPath("spec").Child("scheduleStrategy").Child("rebalance"))
+			if len(errList) > 0 && !cs.errorHappen {
+				t.Fatalf("expected success, but got error: %v", errList)
+			} else if len(errList) == 0 && cs.errorHappen {
+				t.Fatalf("expected error, but got success")
+			}
+		})
+	}
+}
+
+func Test_validateWorkloadSpreadForBroadcastJob(t *testing.T) {
+	cases := []struct {
+		name        string
+		getSpec     func() *appsv1alpha1.WorkloadSpreadSpec
+		errorHappen bool
+	}{
+		{
+			name: "fixed subsets",
+			getSpec: func() *appsv1alpha1.WorkloadSpreadSpec {
+				return &appsv1alpha1.WorkloadSpreadSpec{
+					Subsets: []appsv1alpha1.WorkloadSpreadSubset{
+						{Name: "subset-a", MaxReplicas: ptr.To(intstr.FromInt32(2))},
+						{Name: "subset-b"},
+					},
+				}
+			},
+		},
+		{
+			name: "adaptive scheduleStrategy",
+			getSpec: func() *appsv1alpha1.WorkloadSpreadSpec {
+				return &appsv1alpha1.WorkloadSpreadSpec{
+					Subsets: []appsv1alpha1.WorkloadSpreadSubset{{Name: "subset-a"}},
+					ScheduleStrategy: appsv1alpha1.WorkloadSpreadScheduleStrategy{
+						Type: appsv1alpha1.AdaptiveWorkloadSpreadScheduleStrategyType,
+					},
+				}
+			},
+			errorHappen: true,
+		},
+		{
+			name: "weighted subsets",
+			getSpec: func() *appsv1alpha1.WorkloadSpreadSpec {
+				return &appsv1alpha1.WorkloadSpreadSpec{
+					Subsets: []appsv1alpha1.WorkloadSpreadSubset{
+						{Name: "subset-a", Weight: ptr.To(int32(1))},
+						{Name: "subset-b", Weight: ptr.To(int32(1))},
+					},
+				}
+			},
+			errorHappen: true,
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			errList := validateWorkloadSpreadForBroadcastJob(cs.getSpec(), field.NewPath("spec"))
+			if len(errList) > 0 && !cs.errorHappen {
+				t.Fatalf("expected success, but got error: %v", errList)
+			} else if len(errList) == 0 && cs.errorHappen {
+				t.Fatalf("expected error, but got success")
+			}
+		})
+	}
+}
+
+func Test_validateWorkloadSpreadMigrateBack(t *testing.T) {
+	cases := []struct {
+		name                      string
+		kind                      string
+		rescheduleCriticalSeconds *int32
+		weighted                  bool
+		migrateBack               *appsv1alpha1.WorkloadSpreadMigrateBackStrategy
+		errorHappen               bool
+	}{
+		{
+			name:                      "default migrateBack for CloneSet",
+			kind:                      "CloneSet",
+			rescheduleCriticalSeconds: ptr.To(int32(30)),
+			migrateBack:               &appsv1alpha1.WorkloadSpreadMigrateBackStrategy{},
+		},
+		{
+			name:                      "valid migrateBack for Deployment",
+			kind:                      "Deployment",
+			rescheduleCriticalSeconds: ptr.To(int32(30)),
+			migrateBack:               &appsv1alpha1.WorkloadSpreadMigrateBackStrategy{MaxMigrationsPerInterval: ptr.To(int32(2)), IntervalSeconds: ptr.To(int32(0))},
+		},
+		{
+			name:                      "migrateBack for StatefulSet",
+			kind:                      "StatefulSet",
+			rescheduleCriticalSeconds: ptr.To(int32(30)),
+			migrateBack:               &appsv1alpha1.WorkloadSpreadMigrateBackStrategy{},
+			errorHappen:               true,
+		},
+		{
+			name:        "no rescheduleCriticalSeconds",
+			kind:        "CloneSet",
+			migrateBack: &appsv1alpha1.WorkloadSpreadMigrateBackStrategy{},
+			errorHappen: true,
+		},
+		{
+			name:                      "weighted subsets",
+			kind:                      "CloneSet",
+			rescheduleCriticalSeconds: ptr.To(int32(30)),
+			weighted:                  true,
+			migrateBack:               &appsv1alpha1.WorkloadSpreadMigrateBackStrategy{},
+			errorHappen:               true,
+		},
+		{
+			name:                      "zero max migrations",
+			kind:                      "ReplicaSet",
+			rescheduleCriticalSeconds: ptr.To(int32(30)),
+			migrateBack:               &appsv1alpha1.WorkloadSpreadMigrateBackStrategy{MaxMigrationsPerInterval: ptr.To(int32(0))},
+			errorHappen:               true,
+		},
+		{
+			name:                      "negative interval",
+			kind:                      "ReplicaSet",
+			rescheduleCriticalSeconds: ptr.To(int32(30)),
+			migrateBack:               &appsv1alpha1.WorkloadSpreadMigrateBackStrategy{IntervalSeconds: ptr.To(int32(-1))},
+			errorHappen:               true,
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			spec := &appsv1alpha1.WorkloadSpreadSpec{
+				TargetReference: &appsv1alpha1.TargetReference{Kind: cs.kind, Name: "test"},
+				Subsets:         []appsv1alpha1.WorkloadSpreadSubset{{Name: "subset-a"}, {Name: "subset-b"}},
+				ScheduleStrategy: appsv1alpha1.WorkloadSpreadScheduleStrategy{
+					Type: appsv1alpha1.AdaptiveWorkloadSpreadScheduleStrategyType,
+					Adaptive: &appsv1alpha1.AdaptiveWorkloadSpreadStrategy{
+						RescheduleCriticalSeconds: cs.rescheduleCriticalSeconds,
+						MigrateBack:               cs.migrateBack,
+					},
+				},
+			}
+			if cs.weighted {
+				for i := range spec.Subsets {
+					spec.Subsets[i].Weight = ptr.To(int32(1))
+				}
+			}
+			errList := validateWorkloadSpreadMigrateBack(spec, field.NewPath("spec").Child("scheduleStrategy").Child("adaptive").Child("migrateBack"))
+			if len(errList) > 0 && !cs.errorHappen {
+				t.Fatalf("expected success, but got error: %v", errList)
+			} else if len(errList) == 0 && cs.errorHappen {
+				t.Fatalf("expected error, but got success")
+			}
+		})
+	}
+}